@@ -5,11 +5,14 @@ package assert
 
 import (
 	"bytes"
+	"context"
 	"go/ast"
 	"go/printer"
 	"go/token"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/huandu/go-assert/internal/assertion"
 )
@@ -18,159 +21,1351 @@ import (
 type A struct {
 	*testing.T
 
-	vars   map[string]interface{}
+	// t receives failures of assertion methods.
+	// It's the same as T unless A is created by NewT.
+	t assertion.T
+
+	// vars is copy-on-write so that A can be shared by parallel subtests.
+	// It must be accessed with varsLock.
+	varsLock sync.RWMutex
+	vars     map[string]interface{}
+
+	// sources are pointers registered by Use and UseStruct, which are copied by Checkpoint.
+	// It's copy-on-write like vars.
+	sources map[string]interface{}
+
 	parser *assertion.Parser
+	opts   assertion.Options
+
+	// caseName is the name of current test case var in vars set by Cases.
+	caseName string
+}
+
+// New creates an assertion object wraps t.
+// Options customize all assertions made by the assertion object.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t, assert.WithColor(false), assert.WithFatal(false))
+//         a.Equal(1, 2) // Test case continues after the failure.
+//         a.Equal(3, 4)
+//     }
+func New(t *testing.T, opts ...Option) *A {
+	return NewT(t, opts...)
+}
+
+// NewT creates an assertion object reporting failures to t.
+// It's useful to test customized assertions with a FakeT.
+//
+// The embedded *testing.T is nil unless t is a *testing.T.
+// Methods requiring *testing.T, e.g. `Run`, cannot be used in this case.
+func NewT(t T, opts ...Option) *A {
+	tt, _ := t.(*testing.T)
+	a := &A{
+		T:      tt,
+		t:      t,
+		vars:   make(map[string]interface{}),
+		parser: new(assertion.Parser),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// NewPanic creates an assertion object panicking with failure messages.
+// It's designed for code running without a testing.T, e.g. `TestMain` and fixture initializers.
+// The panic value is an error with the failure message.
+//
+// The embedded *testing.T is nil.
+// Methods requiring *testing.T, e.g. `Run`, cannot be used.
+//
+// Sample code.
+//
+//     var loadFixtures = sync.OnceValue(func() *Fixtures {
+//         a := assert.NewPanic()
+//         f, err := parseFixtures("testdata/fixtures.json")
+//         a.NilError(err)
+//         return f
+//     })
+//
+// Panic message:
+//
+//     Assertion failed:
+//     Following expression should return a nil error.
+//         err
+//     Referenced variables are assigned in following statements:
+//         f, err := parseFixtures("testdata/fixtures.json")
+//     The error is:
+//         open testdata/fixtures.json: no such file or directory
+func NewPanic(opts ...Option) *A {
+	return NewT(assertion.NewPanicT(""), opts...)
+}
+
+// Child creates an assertion object wrapping t, which is usually a subtest of a.
+// The child inherits options and variables registered by `Use` in a.
+// Variables registered in the child later are not visible to a.
+//
+// It's safe to call Child and `Use` concurrently, e.g. in parallel subtests.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         config := loadConfig()
+//         a.Use(&config)
+//
+//         a.Run("sub", func(t *testing.T) {
+//             t.Parallel()
+//             a := a.Child(t)
+//             a.Assert(config.Enabled) // config is printed on failure.
+//         })
+//     }
+func (a *A) Child(t *testing.T) *A {
+	return &A{
+		T:        t,
+		t:        t,
+		vars:     a.varsSnapshot(),
+		sources:  a.sourcesSnapshot(),
+		parser:   a.parser,
+		opts:     a.opts,
+		caseName: a.caseName,
+	}
+}
+
+// With returns a copy of a customized by opts, so that options can be set per call
+// without affecting other assertions made by a.
+// Options customizing source parsing, e.g. ExcludeFuncs and WithSourceProvider,
+// are shared by a and the returned A.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.With(assert.IgnoreUnexported()).Equal(user, User{Name: "Alice"})
+//     }
+func (a *A) With(opts ...Option) *A {
+	child := &A{
+		T:        a.T,
+		t:        a.t,
+		vars:     a.varsSnapshot(),
+		sources:  a.sourcesSnapshot(),
+		parser:   a.parser,
+		opts:     a.opts,
+		caseName: a.caseName,
+	}
+
+	for _, opt := range opts {
+		opt(child)
+	}
+
+	return child
+}
+
+// Flaky calls fn until it passes or it has been called maxAttempts times.
+// Failures in a failed attempt don't fail the test case unless all attempts fail.
+// In this case, failures of the last attempt are reported.
+//
+// Flakiness is not hidden. If an attempt passes after failed attempts,
+// the last failure is reported to hooks registered by OnFailure with `Flaky` set to true,
+// so that CI can track flaky tests.
+//
+// All assertions in fn must be made by the A passed to fn.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.Flaky(3, func(a *assert.A) {
+//             resp, err := http.Get(serverURL)
+//             a.NilError(err)
+//             a.Equal(resp.StatusCode, http.StatusOK)
+//         })
+//     }
+func (a *A) Flaky(maxAttempts int, fn func(a *A)) {
+	a.t.Helper()
+	assertion.RunFlaky(a.t, maxAttempts, a.opts, func(t assertion.T, opts assertion.Options) {
+		fn(&A{
+			T:        a.T,
+			t:        t,
+			vars:     a.varsSnapshot(),
+			sources:  a.sourcesSnapshot(),
+			parser:   a.parser,
+			opts:     opts,
+			caseName: a.caseName,
+		})
+	})
+}
+
+// varsSnapshot returns registered vars.
+// The returned map must not be modified.
+func (a *A) varsSnapshot() map[string]interface{} {
+	a.varsLock.RLock()
+	defer a.varsLock.RUnlock()
+	return a.vars
+}
+
+// sourcesSnapshot returns pointers registered by Use and UseStruct.
+// The returned map must not be modified.
+func (a *A) sourcesSnapshot() map[string]interface{} {
+	a.varsLock.RLock()
+	defer a.varsLock.RUnlock()
+	return a.sources
+}
+
+// use registers pointers with names.
+// Values are copied if SnapshotVars is set.
+func (a *A) use(ptrs map[string]interface{}) {
+	a.varsLock.Lock()
+	sources := make(map[string]interface{}, len(a.sources)+len(ptrs))
+
+	for k, v := range a.sources {
+		sources[k] = v
+	}
+
+	for k, v := range ptrs {
+		sources[k] = v
+	}
+
+	a.sources = sources
+	a.varsLock.Unlock()
+
+	if !a.opts.SnapshotVars {
+		a.setVars(ptrs)
+		return
+	}
+
+	values := make(map[string]interface{}, len(ptrs))
+
+	for k, v := range ptrs {
+		values[k] = assertion.Snapshot(v)
+	}
+
+	a.setVars(values)
+}
+
+// setVars registers values with names.
+func (a *A) setVars(values map[string]interface{}) {
+	a.varsLock.Lock()
+	defer a.varsLock.Unlock()
+
+	vars := make(map[string]interface{}, len(a.vars)+len(values))
+
+	for k, v := range a.vars {
+		vars[k] = v
+	}
+
+	for k, v := range values {
+		vars[k] = v
+	}
+
+	a.vars = vars
+}
+
+// Indexes of arguments to be parsed by assertion methods.
+// They are shared by triggers to avoid allocations on the passing path and must not be modified.
+var (
+	argsFirst     = []int{0}
+	argsSecond    = []int{1}
+	argsLast      = []int{-1}
+	argsFirstTwo  = []int{0, 1}
+	argsSecondTwo = []int{1, 2}
+	argsFirstLast = []int{0, -1}
+)
+
+// trigger creates a trigger for the assertion method named funcName.
+// The args are indexes of arguments to be parsed.
+func (a *A) trigger(funcName string, args []int) *assertion.Trigger {
+	return &assertion.Trigger{
+		Parser:   a.parser,
+		FuncName: funcName,
+		Skip:     1,
+		Args:     args,
+		Vars:     a.triggerVars(),
+		Case:     a.caseName,
+		Options:  a.opts,
+	}
+}
+
+// triggerVars records the call site of the assertion method calling trigger if tracing is enabled
+// and returns registered vars.
+// Both are done in one call to keep trigger cheap enough to be inlined,
+// so that the Trigger doesn't escape to heap in passing assertions.
+func (a *A) triggerVars() map[string]interface{} {
+	if a.opts.Tracer != nil {
+		// Skip frames of triggerVars, trigger and the assertion method.
+		a.opts.Tracer.Record(3)
+	}
+
+	return a.varsSnapshot()
+}
+
+// Assert tests expr and call `t.Fatalf` to terminate test case if expr is false-equivalent value.
+// `false`, 0, nil and empty string are false-equivalent values.
+//
+// If expr references a var registered by `Use` and the var is an interface holding a typed nil pointer,
+// e.g. `error((*MyErr)(nil))`, a note is printed to explain why `err == nil` is false.
+// Other vars are not diagnosed, because Assert only receives the value of expr
+// and a typed nil pointer is indistinguishable from a plain nil pointer once stored in an `interface{}`.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         x, y := 1, 2
+//         a.Assert(x > y)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         x > y
+//     Referenced variables are assigned in following statements:
+//         x, y := 1, 2
+func (a *A) Assert(expr interface{}) {
+	a.t.Helper()
+	assertion.Assert(a.t, expr, a.trigger("Assert", argsFirst))
+}
+
+// AssertAll tests all exprs and calls `t.Fatalf` once to terminate test case
+// if any expr is false-equivalent value.
+// Unlike `a.Assert(x && y && z)`, every expr is tested and every false-equivalent expr
+// is printed with its own source and assignments.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         user := loadUser()
+//         a.AssertAll(user.ID > 0, user.Name, user.Err == nil)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.AssertAll(user.ID > 0, user.Name, user.Err == nil)
+//     2 of 3 conditions are false:
+//     [1] user.ID > 0
+//         user := loadUser()
+//     [2] user.Name != ""
+//         user := loadUser()
+func (a *A) AssertAll(exprs ...interface{}) {
+	a.t.Helper()
+	assertion.AssertConditions(a.t, exprs, a.trigger("AssertAll", argsFirst))
+}
+
+// NilError expects a function return a nil error.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+// If the returned error is a non-nil interface holding a typed nil pointer,
+// e.g. `error((*MyErr)(nil))`, a note is printed to explain why it's not nil.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.NilError(os.Open("path/to/a/file"))
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following expression should return a nil error.
+//         os.Open("path/to/a/file")
+//     The error is:
+//         open path/to/a/file: no such file or directory
+func (a *A) NilError(result ...interface{}) {
+	a.t.Helper()
+	assertion.AssertNilError(a.t, result, a.trigger("NilError", argsLast))
+}
+
+// NonNilError expects a function return a non-nil error.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         f := func() (int, error) { return 0, errors.New("expected") }
+//         a.NilError(f())
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following expression should return a nil error.
+//         f()
+//         f := func() (int, error) { return 0, errors.New("expected") }
+//     The error is:
+//         expected
+func (a *A) NonNilError(result ...interface{}) {
+	a.t.Helper()
+	assertion.AssertNonNilError(a.t, result, a.trigger("NonNilError", argsLast))
+}
+
+// NoError expects err is nil.
+// Unlike NilError, it takes an error value instead of all results of a function call.
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with the error and the statement assigning err.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         _, err := os.Open("path/to/a/file")
+//         a.NoError(err)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following error should be nil.
+//         err
+//         _, err := os.Open("path/to/a/file")
+//     The error is:
+//         open path/to/a/file: no such file or directory
+func (a *A) NoError(err error) {
+	a.t.Helper()
+	assertion.AssertNoError(a.t, err, a.trigger("NoError", argsFirst))
+}
+
+// Error expects err is a non-nil error.
+// An error interface holding a typed nil pointer is treated as nil.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         err := validate("valid input")
+//         a.Error(err)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following error should not be nil.
+//         err
+//         err := validate("valid input")
+func (a *A) Error(err error) {
+	a.t.Helper()
+	assertion.AssertError(a.t, err, a.trigger("Error", argsFirst))
+}
+
+// Equal uses `reflect.DeepEqual` to test v1 and v2 equality.
+// Values of sync.Map and container/list.List are compared by their contents
+// instead of internal mutexes and pointers.
+// If v1 and v2 are short single-line strings, the first differing rune is marked by a caret
+// and printed with its code point, so that differences in invisible characters are obvious.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.Equal([]int{1,2}, []int{1})
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.Equal([]int{1, 2}, []int{1})
+//     The value of following expression should equal.
+//     [1] []int{1, 2}
+//     [2] []int{1}
+//     Differences:
+//     Only in [1]:
+//         [1] = (int)2
+func (a *A) Equal(v1, v2 interface{}) {
+	a.t.Helper()
+	assertion.AssertEqual(a.t, v1, v2, a.trigger("Equal", argsFirstTwo))
+}
+
+// NotEqual uses `reflect.DeepEqual` to test v1 and v2 equality.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.NotEqual(t, []int{1}, []int{1})
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.NotEqual(t, []int{1}, []int{1})
+//     The value of following expression should not equal.
+//     [1] []int{1}
+//     [2] []int{1}
+func (a *A) NotEqual(v1, v2 interface{}) {
+	a.t.Helper()
+	assertion.AssertNotEqual(a.t, v1, v2, a.trigger("NotEqual", argsFirstTwo))
+}
+
+// PointerEqual tests got and want equality like Equal, except that nil pointers in want match anything,
+// so that want can be a partially specified object graph.
+// Pointers are compared by their targets.
+// Nil slices and maps in want are not ignored.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         order := LoadOrder(42)
+//
+//         // order.Customer and order.Items[0].Product are not compared.
+//         a.PointerEqual(order, &Order{
+//             ID:    42,
+//             Items: []*Item{{Count: 2}},
+//         })
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.PointerEqual(order, &Order{ID: 42, Items: []*Item{{Count: 2}}})
+//     The value of following expression should equal except parts which are nil pointers in [2].
+//     [1] order
+//         order := LoadOrder(42)
+//     [2] &Order{ID: 42, Items: []*Item{{Count: 2}}}
+//     Differences:
+//     Different values:
+//         .Items[0].Count:
+//             [1] -> (int)1
+//             [2] -> (int)2
+//     (3 equal fields not shown)
+func (a *A) PointerEqual(got, want interface{}) {
+	a.t.Helper()
+	assertion.AssertPointerEqual(a.t, got, want, a.trigger("PointerEqual", argsFirstTwo))
+}
+
+// BigEqual expects x and y are numerically equal.
+// Numbers are compared by their `Cmp` methods instead of `reflect.DeepEqual`,
+// which can report equal big numbers as different and vice versa
+// depending on internal representation.
+//
+// The x and y can be *big.Int, *big.Float, *big.Rat or Go numbers like int and float64.
+// Numbers of different types are compared by value.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         x := new(big.Int).Exp(big.NewInt(2), big.NewInt(64), nil)
+//         a.BigEqual(x, uint64(math.MaxUint64))
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.BigEqual(x, uint64(math.MaxUint64))
+//     The value of following expression should equal.
+//     [1] x
+//         x := new(big.Int).Exp(big.NewInt(2), big.NewInt(64), nil)
+//     [2] uint64(math.MaxUint64)
+//     Difference:
+//         [1] - [2] = 1
+//     Values:
+//     [1] -> (*big.Int)18446744073709551616
+//     [2] -> (uint64)18446744073709551615
+func (a *A) BigEqual(x, y interface{}) {
+	a.t.Helper()
+	assertion.AssertBigEqual(a.t, x, y, a.trigger("BigEqual", argsFirstTwo))
+}
+
+// BigInDelta expects the absolute difference of x and y is not greater than delta.
+// See BigEqual for supported types of x, y and delta.
+func (a *A) BigInDelta(x, y, delta interface{}) {
+	a.t.Helper()
+	assertion.AssertBigInDelta(a.t, x, y, delta, a.trigger("BigInDelta", argsFirstTwo))
+}
+
+// BigInEpsilon expects the relative difference of x and y,
+// which is `|x - y| / max(|x|, |y|)`, is not greater than epsilon.
+// See BigEqual for supported types of x, y and epsilon.
+func (a *A) BigInEpsilon(x, y, epsilon interface{}) {
+	a.t.Helper()
+	assertion.AssertBigInEpsilon(a.t, x, y, epsilon, a.trigger("BigInEpsilon", argsFirstTwo))
+}
+
+// AllInDelta expects x and y have the same length and
+// the absolute difference of every pair of elements is not greater than delta.
+// NaNs are only within delta of NaNs. Infinite numbers are only within delta of the same infinity.
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with indices, values and differences of all elements not within delta.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         got := predict(inputs)
+//         a.AllInDelta(got, []float64{0.1, 0.5, 0.9}, 0.01)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.AllInDelta(got, []float64{0.1, 0.5, 0.9}, 0.01)
+//     Elements of following numbers should be pairwise within 0.01.
+//     [1] got
+//         got := predict(inputs)
+//     [2] []float64{0.1, 0.5, 0.9}
+//     1 of 3 elements are not within the delta:
+//         [1]: [1] -> 0.52, [2] -> 0.5, difference = 0.020000000000000018
+func (a *A) AllInDelta(x, y []float64, delta float64) {
+	a.t.Helper()
+	assertion.AssertAllInDelta(a.t, x, y, delta, a.trigger("AllInDelta", argsFirstTwo))
+}
+
+// AllInDelta2D is the same as AllInDelta except that x and y are matrices.
+// Elements are indexed by row and column in failure message, e.g. "[1][2]".
+func (a *A) AllInDelta2D(x, y [][]float64, delta float64) {
+	a.t.Helper()
+	assertion.AssertAllInDelta2D(a.t, x, y, delta, a.trigger("AllInDelta2D", argsFirstTwo))
+}
+
+// MeanInDelta expects the mean of samples is within delta of want.
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with a summary of samples, including count, min, max, mean, p50 and p99.
+// It always fails if samples is empty.
+func (a *A) MeanInDelta(samples []float64, want, delta float64) {
+	a.t.Helper()
+	assertion.AssertMeanInDelta(a.t, samples, want, delta, a.trigger("MeanInDelta", argsFirst))
+}
+
+// PercentileUnder expects the p-th percentile of samples is not greater than limit.
+// The p must be in range (0, 1], e.g. 0.99 for p99.
+// Percentiles are calculated using the nearest-rank method.
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with a summary of samples, including count, min, max, mean, p50 and p99.
+// It always fails if samples is empty.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         latencies := loadTest(server, 1000)
+//         a.PercentileUnder(latencies, 0.99, 50)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.PercentileUnder(latencies, 0.99, 50)
+//     The p99 of following samples should not be greater than 50. The actual value is 72.
+//         latencies
+//         latencies := loadTest(server, 1000)
+//     Summary:
+//         count = 1000, min = 3, max = 95, mean = 12.5, p50 = 9, p99 = 72
+func (a *A) PercentileUnder(samples []float64, p, limit float64) {
+	a.t.Helper()
+	assertion.AssertPercentileUnder(a.t, samples, p, limit, a.trigger("PercentileUnder", argsFirst))
+}
+
+// That expects v matches m.
+// Matchers can be composed by Not, AnyOf and AllOf.
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with an explanation of every nested matcher.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         blocked := []string{"root", "admin"}
+//         name := "admin"
+//         a.That(name, assert.AnyOf(assert.EqualTo("guest"), assert.Not(assert.InSlice(blocked))))
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following value should match the matcher.
+//         name
+//         name := "admin"
+//     Explanation:
+//         ✗ any of
+//             ✗ equal to (string)guest
+//             ✗ not
+//                 ✓ in ([]string)[root admin]
+//     Value:
+//         (string)admin
+func (a *A) That(v interface{}, m Matcher) {
+	a.t.Helper()
+	assertion.AssertThat(a.t, v, m, a.trigger("That", argsFirst))
+}
+
+// Satisfies expects pred returns true for v.
+// The pred must be a `func(v V) bool` where v is assignable to V.
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with the source code of pred and the dump of v.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         u := User{Name: "foo", Age: 16}
+//         a.Satisfies(u, func(u User) bool { return u.Age > 18 })
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following value should satisfy the predicate.
+//         u
+//         u := User{Name: "foo", Age: 16}
+//     Predicate:
+//         func(u User) bool { return u.Age > 18 }
+//     Value:
+//         (User){Name:(string)foo Age:(int)16}
+func (a *A) Satisfies(v, pred interface{}) {
+	a.t.Helper()
+	assertion.AssertSatisfies(a.t, v, pred, a.trigger("Satisfies", argsFirstTwo))
+}
+
+// Invariant expects the invariant named name holds for v, i.e. pred returns true for v.
+// The pred must be a `func(v V) bool` where v is assignable to V.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+//
+// If pred is a func literal, only fields of v referenced by pred are dumped,
+// so that a failure of a cross-field invariant on a large struct is easy to read.
+// Otherwise, v is dumped as a whole.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         span := tracer.Finish()
+//         a.Invariant(span, "Start before End", func(x Span) bool {
+//             return x.Start.Before(x.End)
+//         })
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Invariant "Start before End" should hold for following expression.
+//         span
+//         span := tracer.Finish()
+//     Predicate:
+//         func(x Span) bool {
+//             return x.Start.Before(x.End)
+//         }
+//     Referenced fields:
+//         x.Start = (time.Time)2024-01-02T03:04:05Z
+//         x.End = (time.Time)2024-01-02T03:04:04Z
+func (a *A) Invariant(v interface{}, name string, pred interface{}) {
+	a.t.Helper()
+	assertion.AssertInvariant(a.t, v, name, pred, a.trigger("Invariant", argsFirstLast))
+}
+
+// ContainsFunc expects at least one element in slice satisfies pred.
+// The slice can be a slice or an array and pred must be a `func(e E) bool`
+// where elements of slice are assignable to E.
+// Otherwise, it will terminate the test case using `t.Fatalf` with dumps of all elements.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         names := []string{"foo", "bar"}
+//         a.ContainsFunc(names, func(s string) bool {
+//             return strings.HasPrefix(s, "z")
+//         })
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     At least one element of following collection should satisfy the predicate.
+//         names
+//         names := []string{"foo", "bar"}
+//     2 of 2 elements fail the predicate at indices:
+//         0, 1
+//     Failed elements:
+//         [0] -> (string)foo
+//         [1] -> (string)bar
+func (a *A) ContainsFunc(slice, pred interface{}) {
+	a.t.Helper()
+	assertion.AssertAnyMatch(a.t, slice, pred, a.trigger("ContainsFunc", argsFirst))
+}
+
+// All expects every element in collection satisfies pred.
+// The collection can be a slice or an array and pred must be a `func(e E) bool`
+// where elements of collection are assignable to E.
+// Otherwise, it will terminate the test case using `t.Fatalf` with indices and dumps of failed elements only.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         ages := []int{18, 7, 30, 3}
+//         a.All(ages, func(age int) bool {
+//             return age >= 18
+//         })
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     All elements of following collection should satisfy the predicate.
+//         ages
+//         ages := []int{18, 7, 30, 3}
+//     2 of 4 elements fail the predicate at indices:
+//         1, 3
+//     Failed elements:
+//         [1] -> (int)7
+//         [3] -> (int)3
+func (a *A) All(collection, pred interface{}) {
+	a.t.Helper()
+	assertion.AssertAll(a.t, collection, pred, a.trigger("All", argsFirst))
+}
+
+// AnyMatch expects at least one element in collection satisfies pred.
+// It's the same as ContainsFunc.
+func (a *A) AnyMatch(collection, pred interface{}) {
+	a.t.Helper()
+	assertion.AssertAnyMatch(a.t, collection, pred, a.trigger("AnyMatch", argsFirst))
+}
+
+// Ordered expects keys of elements in collection are non-decreasing,
+// e.g. timestamps of events emitted by an event store or a log pipeline.
+// The collection can be a slice or an array and key must be a `func(e E) K`
+// where elements of collection are assignable to E.
+// The K can be a number, a string or a type with method `Before(K) bool` like `time.Time`.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the first out-of-order pair of elements.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         events := store.Events()
+//         a.Ordered(events, func(e Event) time.Time {
+//             return e.At
+//         })
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Elements of following expression should be in non-decreasing order of key.
+//         events
+//         events := store.Events()
+//     Key:
+//         func(e Event) time.Time {
+//             return e.At
+//         }
+//     Elements [1] and [2] are out of order:
+//         [1] -> (Event){Name:(string)paid At:(time.Time)2024-01-02T03:04:06Z}
+//             key -> (time.Time)2024-01-02T03:04:06Z
+//         [2] -> (Event){Name:(string)shipped At:(time.Time)2024-01-02T03:04:05Z}
+//             key -> (time.Time)2024-01-02T03:04:05Z
+func (a *A) Ordered(collection, key interface{}) {
+	a.t.Helper()
+	assertion.AssertOrdered(a.t, collection, key, a.trigger("Ordered", argsFirstTwo))
+}
+
+// ContainsAll expects s contains all of subs.
+// It's useful to assert log output and rendered templates.
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with missing substrings and indices of found ones.
+// An empty substring is rejected as an invalid argument.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         log := "level=info msg=started"
+//         a.ContainsAll(log, "msg=started", "level=error")
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following string should contain all substrings.
+//         log
+//         log := "level=info msg=started"
+//     Substrings:
+//         [1] "msg=started" is found at index 11.
+//         [2] "level=error" is missing.
+//     Value:
+//         (string)level=info msg=started
+func (a *A) ContainsAll(s string, subs ...string) {
+	a.t.Helper()
+	assertion.AssertContainsAll(a.t, s, subs, a.trigger("ContainsAll", argsFirst))
+}
+
+// ContainsAny expects s contains at least one of subs.
+// Otherwise, it will terminate the test case using `t.Fatalf` with all substrings.
+// An empty substring is rejected as an invalid argument.
+func (a *A) ContainsAny(s string, subs ...string) {
+	a.t.Helper()
+	assertion.AssertContainsAny(a.t, s, subs, a.trigger("ContainsAny", argsFirst))
+}
+
+// HasKey expects map m has key.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+// If key is a string, existing keys similar to key are suggested to spot typos.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         config := map[string]int{"timeout": 30, "retries": 3}
+//         a.HasKey(config, "tiemout")
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.HasKey(config, "tiemout")
+//     The map should have following key.
+//     [1] config
+//         config := map[string]int{"timeout": 30, "retries": 3}
+//     [2] "tiemout"
+//     Values:
+//     [2] -> (string)tiemout
+//     Did you mean "timeout"?
+func (a *A) HasKey(m, key interface{}) {
+	a.t.Helper()
+	assertion.AssertHasKey(a.t, m, key, a.trigger("HasKey", argsFirstTwo))
 }
 
-// New creates an assertion object wraps t.
-func New(t *testing.T) *A {
-	return &A{
-		T:      t,
-		vars:   make(map[string]interface{}),
-		parser: new(assertion.Parser),
-	}
+// DoesNotBlock expects fn returns within grace.
+// It's useful to check an operation which should not block, e.g. a non-blocking channel send
+// or acquiring a lock which should be free.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the stack of the goroutine running fn.
+//
+// Note that fn is not stopped when assertion fails.
+// The goroutine running fn leaks until fn returns.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         ch := make(chan int)
+//         a.DoesNotBlock(func() { ch <- 1 }, 10*time.Millisecond)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following function should return within 10ms.
+//         func() { ch <- 1 }
+//         ch := make(chan int)
+//     Goroutine stack:
+//         goroutine 7 [chan send]:
+//         ...
+func (a *A) DoesNotBlock(fn func(), grace time.Duration) {
+	a.t.Helper()
+	assertion.AssertDoesNotBlock(a.t, fn, grace, a.trigger("DoesNotBlock", argsFirst))
 }
 
-// Assert tests expr and call `t.Fatalf` to terminate test case if expr is false-equivalent value.
-// `false`, 0, nil and empty string are false-equivalent values.
+// WithinTimeout calls fn in a new goroutine and expects it returns within timeout.
+// It protects a test suite from a single hanging call eating the whole budget of `go test -timeout`.
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with the source of fn and stacks of all goroutines.
+// The stack of the goroutine running fn is printed first.
+//
+// All assertions in fn must be made by the A passed to fn.
+// Their failures are reported after fn returns.
+//
+// Note that fn is not stopped when it times out.
+// The goroutine running fn leaks until fn returns.
 //
 // Sample code.
 //
 //     func TestSomething(t *testing.T) {
 //         a := assert.New(t)
-//         x, y := 1, 2
-//         a.Assert(x > y)
+//         a.WithinTimeout(time.Second, func(a *assert.A) {
+//             resp, err := client.Call(ctx, req)
+//             a.NilError(err)
+//             a.Equal(resp.Code, 0)
+//         })
 //     }
 //
 // Output:
 //
 //     Assertion failed:
-//         x > y
-//     Referenced variables are assigned in following statements:
-//         x, y := 1, 2
-func (a *A) Assert(expr interface{}) {
-	assertion.Assert(a.T, expr, &assertion.Trigger{
-		Parser:   a.parser,
-		FuncName: "Assert",
-		Skip:     1,
-		Args:     []int{0},
-		Vars:     a.vars,
+//     Following block should finish within 1s.
+//         func(a *assert.A) {
+//             resp, err := client.Call(ctx, req)
+//             a.NilError(err)
+//             a.Equal(resp.Code, 0)
+//         }
+//     Goroutine stacks:
+//         goroutine 7 [select]:
+//         ...
+func (a *A) WithinTimeout(timeout time.Duration, fn func(a *A)) {
+	a.t.Helper()
+	assertion.RunWithinTimeout(a.t, timeout, a.opts, func(t assertion.T, opts assertion.Options) {
+		fn(&A{
+			T:        a.T,
+			t:        t,
+			vars:     a.varsSnapshot(),
+			sources:  a.sourcesSnapshot(),
+			parser:   a.parser,
+			opts:     opts,
+			caseName: a.caseName,
+		})
+	}, a.trigger("WithinTimeout", argsSecond))
+}
+
+// Grouped calls fn with an A whose failures are prefixed with label and the location of the call,
+// so that a long scenario test reads like a list of steps.
+// Groups can be nested. Failures list all enclosing groups, outermost first.
+// Labels are also passed to hooks registered by OnFailure in `Failure.Groups`.
+//
+// All assertions in fn must be made by the A passed to fn.
+//
+// Sample code.
+//
+//     func TestSignUp(t *testing.T) {
+//         a := assert.New(t)
+//         a.Grouped("creating user", func(a *assert.A) {
+//             user, err := CreateUser("alice")
+//             a.NilError(err)
+//             a.Equal(user.Name, "Alice")
+//         })
+//     }
+//
+// Output:
+//
+//     [creating user] signup_test.go:12:
+//     signup_test.go:15: Assertion failed:
+//         a.Equal(user.Name, "Alice")
+//     ...
+func (a *A) Grouped(label string, fn func(a *A)) {
+	assertion.RunGrouped(label, 1, a.opts, func(opts assertion.Options) {
+		fn(&A{
+			T:        a.T,
+			t:        a.t,
+			vars:     a.varsSnapshot(),
+			sources:  a.sourcesSnapshot(),
+			parser:   a.parser,
+			opts:     opts,
+			caseName: a.caseName,
+		})
 	})
 }
 
-// NilError expects a function return a nil error.
-// Otherwise, it will terminate the test case using `t.Fatalf`.
+// Completes expects wgOrDoneChan completes within timeout.
+// The wgOrDoneChan can be a *sync.WaitGroup or a channel.
+// A channel completes when it's closed or receives a value.
+// Otherwise, it will terminate the test case using `t.Fatalf` with stacks of all goroutines.
+//
+// Note that, if wgOrDoneChan is a channel, a value may be received from it.
 //
 // Sample code.
 //
 //     func TestSomething(t *testing.T) {
 //         a := assert.New(t)
-//         a.NilError(os.Open("path/to/a/file"))
+//         wg := &sync.WaitGroup{}
+//         wg.Add(1)
+//         go worker(wg)
+//         a.Completes(wg, time.Second)
 //     }
 //
 // Output:
 //
 //     Assertion failed:
-//     Following expression should return a nil error.
-//         os.Open("path/to/a/file")
+//     Following expression should complete within 1s.
+//         wg
+//         wg := &sync.WaitGroup{}
+//     Goroutine stacks:
+//         goroutine 6 [running]:
+//         ...
+func (a *A) Completes(wgOrDoneChan interface{}, timeout time.Duration) {
+	a.t.Helper()
+	assertion.AssertCompletes(a.t, wgOrDoneChan, timeout, a.trigger("Completes", argsFirst))
+}
+
+// Eventually calls condition every interval until it's satisfied.
+// If condition is not satisfied within timeout, it will terminate the test case using `t.Fatalf`
+// with the value or error observed in the last call.
+//
+// The condition can be one of following types.
+//
+//   - `func() bool`: Satisfied if it returns true.
+//   - `func() (bool, interface{})`: Satisfied if it returns true. The value is printed on failure.
+//   - `func() error`: Satisfied if it returns nil. The error is printed on failure.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.Eventually(func() (bool, interface{}) {
+//             status := job.Status()
+//             return status == "done", status
+//         }, time.Second, 100*time.Millisecond)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following condition should be satisfied within 1s.
+//         func() (bool, interface{}) {
+//             status := job.Status()
+//             return status == "done", status
+//         }
+//     The condition was checked 11 times.
+//     Last observed value:
+//         (string)running
+func (a *A) Eventually(condition interface{}, timeout, interval time.Duration) {
+	a.t.Helper()
+	assertion.AssertEventually(a.t, condition, timeout, interval, a.trigger("Eventually", argsFirst))
+}
+
+// EventuallyCtx calls condition every interval until it's satisfied.
+// If ctx is done before condition is satisfied, it will terminate the test case using `t.Fatalf`
+// with the error of ctx and the value or error observed in the last call.
+// It's useful to bound waiting by a test-scoped context, e.g. `t.Context()` in Go 1.24 or later.
+//
+// See Eventually for supported types of condition.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//         defer cancel()
+//         a.EventuallyCtx(ctx, func() error {
+//             return server.Ping()
+//         }, 100*time.Millisecond)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following condition should be satisfied before the context is done.
+//         func() error {
+//             return server.Ping()
+//         }
+//     The condition was checked 11 times.
+//     The context is done with error:
+//         context deadline exceeded
+//     Last observed error:
+//         connection refused
+func (a *A) EventuallyCtx(ctx context.Context, condition interface{}, interval time.Duration) {
+	a.t.Helper()
+	assertion.AssertEventuallyCtx(a.t, ctx, condition, interval, a.trigger("EventuallyCtx", argsSecond))
+}
+
+// EventuallyNoLeak is the same as Eventually except that it also expects condition doesn't leak goroutines.
+// Polling loops are a common hidden source of leaks, e.g. a goroutine started in every call
+// to wait for a ticker or a response which never comes.
+//
+// Goroutines started while polling condition must return within a short grace period
+// after condition is satisfied or timeout.
+// Otherwise, it will terminate the test case using `t.Fatalf` with stacks of leaked goroutines.
+//
+// Leaked tickers and timers are detected through goroutines waiting for them,
+// as the Go runtime doesn't expose running timers.
+// Goroutines started by other tests are reported as leaks as well, so don't use it in parallel tests.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.EventuallyNoLeak(func() bool {
+//             return watcher.Ready()
+//         }, time.Second, 10*time.Millisecond)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following condition should not leak goroutines.
+//         func() bool {
+//             return watcher.Ready()
+//         }
+//     The condition was checked 3 times.
+//     3 goroutines started by the condition are still running:
+//         goroutine 21 [chan receive]:
+//         example.com/watcher.(*Watcher).Ready.func1()
+//             /path/to/watcher.go:42 +0x3c
+//         ...
+func (a *A) EventuallyNoLeak(condition interface{}, timeout, interval time.Duration) {
+	a.t.Helper()
+	assertion.AssertEventuallyNoLeak(a.t, condition, timeout, interval, a.trigger("EventuallyNoLeak", argsFirst))
+}
+
+// ReceivesCtx expects ch receives a value before ctx is done and returns the received value.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the error of ctx and stacks of all goroutines.
+// It also fails if ch is closed.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//         defer cancel()
+//         events := bus.Subscribe("created")
+//         ev := a.ReceivesCtx(ctx, events).(Event)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     Following channel should receive a value before the context is done.
+//         events
+//         events := bus.Subscribe("created")
+//     The context is done with error:
+//         context deadline exceeded
+//     Goroutine stacks:
+//         goroutine 6 [running]:
+//         ...
+func (a *A) ReceivesCtx(ctx context.Context, ch interface{}) interface{} {
+	a.t.Helper()
+	return assertion.AssertReceivesCtx(a.t, ctx, ch, a.trigger("ReceivesCtx", argsSecond))
+}
+
+// PortOpen expects a TCP connection to addr can be established within timeout.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the dial error.
+// It's useful to verify a dependency is listening before running integration tests.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.PortOpen("127.0.0.1:5432", time.Second)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.PortOpen("127.0.0.1:5432", time.Second)
+//     Following address should accept tcp connections.
+//         "127.0.0.1:5432"
 //     The error is:
-//         open path/to/a/file: no such file or directory
-func (a *A) NilError(result ...interface{}) {
-	assertion.AssertNilError(a.T, result, &assertion.Trigger{
-		Parser:   a.parser,
-		FuncName: "NilError",
-		Skip:     1,
-		Args:     []int{-1},
-		Vars:     a.vars,
-	})
+//         dial tcp 127.0.0.1:5432: connect: connection refused
+func (a *A) PortOpen(addr string, timeout time.Duration) {
+	a.t.Helper()
+	assertion.AssertDial(a.t, "tcp", addr, timeout, a.trigger("PortOpen", argsFirst))
 }
 
-// NonNilError expects a function return a non-nil error.
-// Otherwise, it will terminate the test case using `t.Fatalf`.
+// DialSucceeds expects a connection to addr in network can be established.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the dial error.
+// See `net.Dial` for supported networks and addresses.
 //
 // Sample code.
 //
 //     func TestSomething(t *testing.T) {
 //         a := assert.New(t)
-//         f := func() (int, error) { return 0, errors.New("expected") }
-//         a.NilError(f())
+//         a.DialSucceeds("unix", "/var/run/app.sock")
 //     }
 //
 // Output:
 //
 //     Assertion failed:
-//     Following expression should return a nil error.
-//         f()
-//         f := func() (int, error) { return 0, errors.New("expected") }
+//         a.DialSucceeds("unix", "/var/run/app.sock")
+//     Following address should accept unix connections.
+//         "/var/run/app.sock"
 //     The error is:
-//         expected
-func (a *A) NonNilError(result ...interface{}) {
-	assertion.AssertNonNilError(a.T, result, &assertion.Trigger{
-		Parser:   a.parser,
-		FuncName: "NonNilError",
-		Skip:     1,
-		Args:     []int{-1},
-		Vars:     a.vars,
-	})
+//         dial unix /var/run/app.sock: connect: no such file or directory
+func (a *A) DialSucceeds(network, addr string) {
+	a.t.Helper()
+	assertion.AssertDial(a.t, network, addr, 0, a.trigger("DialSucceeds", argsSecond))
 }
 
-// Equal uses `reflect.DeepEqual` to test v1 and v2 equality.
+// HeapGrowthUnder expects heap grows no more than maxBytes after calling fn.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+//
+// The heap growth is the difference of live heap bytes measured after a forced GC
+// before and after calling fn. Memory allocated by fn and released before it returns doesn't count.
+//
+// The result is affected by all goroutines running at the same time,
+// so it's only suitable to catch gross memory regressions.
+// Don't use it in parallel tests and leave enough room in maxBytes for the variance.
 //
 // Sample code.
 //
 //     func TestSomething(t *testing.T) {
 //         a := assert.New(t)
-//         a.Equal([]int{1,2}, []int{1})
+//         cache := NewCache()
+//         a.HeapGrowthUnder(1<<20, func() {
+//             cache.Load("testdata/small.json")
+//         })
 //     }
 //
 // Output:
 //
 //     Assertion failed:
-//         a.Equal([]int{1, 2}, []int{1})
-//     The value of following expression should equal.
-//     [1] []int{1, 2}
-//     [2] []int{1}
-//     Values:
-//     [1] -> ([]int)[1 2]
-//     [2] -> ([]int)[1]
-func (a *A) Equal(v1, v2 interface{}) {
-	assertion.AssertEqual(a.T, v1, v2, &assertion.Trigger{
-		Parser:   a.parser,
-		FuncName: "Equal",
-		Skip:     1,
-		Args:     []int{0, 1},
-		Vars:     a.vars,
-	})
+//     Heap should grow no more than 1048576 bytes after calling following function.
+//         func() {
+//             cache.Load("testdata/small.json")
+//         }
+//     Heap:
+//         before = 181456 bytes
+//         after = 5424512 bytes
+//         growth = 5243056 bytes
+func (a *A) HeapGrowthUnder(maxBytes uint64, fn func()) {
+	a.t.Helper()
+	assertion.AssertHeapGrowthUnder(a.t, maxBytes, fn, a.trigger("HeapGrowthUnder", argsSecond))
 }
 
-// NotEqual uses `reflect.DeepEqual` to test v1 and v2 equality.
+// DurationLess expects d is less than limit.
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with d and how much d exceeds limit in human-readable form.
 //
 // Sample code.
 //
 //     func TestSomething(t *testing.T) {
 //         a := assert.New(t)
-//         a.NotEqual(t, []int{1}, []int{1})
+//         start := time.Now()
+//         client.Get(url)
+//         a.DurationLess(time.Since(start), 100*time.Millisecond)
 //     }
 //
 // Output:
 //
 //     Assertion failed:
-//         a.NotEqual(t, []int{1}, []int{1})
-//     The value of following expression should not equal.
-//     [1] []int{1}
-//     [2] []int{1}
-func (a *A) NotEqual(v1, v2 interface{}) {
-	assertion.AssertNotEqual(a.T, v1, v2, &assertion.Trigger{
-		Parser:   a.parser,
-		FuncName: "NotEqual",
-		Skip:     1,
-		Args:     []int{0, 1},
-		Vars:     a.vars,
-	})
+//     Following duration should be less than 100ms.
+//         time.Since(start)
+//     Duration:
+//         123.5ms, which is 23.46ms over the limit.
+func (a *A) DurationLess(d, limit time.Duration) {
+	a.t.Helper()
+	assertion.AssertDurationLess(a.t, d, limit, a.trigger("DurationLess", argsFirst))
+}
+
+// DurationBetween expects d is in range [lo, hi].
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with d and how much d is out of range in human-readable form.
+func (a *A) DurationBetween(d, lo, hi time.Duration) {
+	a.t.Helper()
+	assertion.AssertDurationBetween(a.t, d, lo, hi, a.trigger("DurationBetween", argsFirst))
+}
+
+// TookLess calls fn and expects fn returns in less than limit.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the time taken by fn.
+//
+// Unlike DoesNotBlock, fn is called in current goroutine and it's never abandoned.
+// The assertion fails after fn returns no matter how long it takes.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.TookLess(50*time.Millisecond, func() {
+//             cache.Get("key")
+//         })
+//     }
+func (a *A) TookLess(limit time.Duration, fn func()) {
+	a.t.Helper()
+	assertion.AssertTookLess(a.t, limit, fn, a.trigger("TookLess", argsSecond))
 }
 
 // Use saves args in context and prints related args automatically in assertion method when referenced.
 //
+// If the expression in `Assert` consists of registered vars, literals, `len` and pure operators only,
+// values of compound sub-expressions, e.g. `x*y` in `x*y > len(s)`, are printed as well.
+//
+// If a registered slice or map is indexed by literals or registered vars, e.g. `items[0]` or `m[key]`,
+// the element is printed right after the container.
+// A dereferenced var, e.g. `*count`, is printed as the value it points to.
+// Results of getters, e.g. `cfg.Timeout()`, are printed if it's enabled by CallGetters.
+//
 // Sample code.
 //
 //     func TestSomething(t *testing.T) {
@@ -231,6 +1426,8 @@ func (a *A) Use(args ...interface{}) {
 		return
 	}
 
+	vars := make(map[string]interface{}, len(f.Args))
+
 	for i, arg := range f.Args {
 		// Arg must be something like `&a` or `&a.b`.
 		// Otherwise, ignore the arg.
@@ -245,8 +1442,171 @@ func (a *A) Use(args ...interface{}) {
 
 		buf := &bytes.Buffer{}
 		printer.Fprint(buf, f.FileSet, expr.X)
-		a.vars[buf.String()] = values[i]
+		vars[buf.String()] = values[i]
+	}
+
+	a.use(vars)
+	a.parser.AddExcluded(f.Caller)
+}
+
+// UseNamed saves a snapshot of value in context with name.
+// If any assertion method references name, the snapshot is printed in assertion message.
+//
+// Unlike Use, value can be any expression, e.g. a map element or a function result.
+// The name must be the way the value is referenced in assertions, e.g. "cfg" or "cfg.Timeout".
+// As value is copied when UseNamed is called, later changes are not printed.
+// Pass a pointer to print the latest value instead.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         configs := loadConfigs()
+//         cfg := configs["default"]
+//         a.UseNamed("cfg", configs["default"])
+//         a.Assert(cfg.Timeout > 0)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         cfg.Timeout > 0
+//     Referenced variables are assigned in following statements:
+//         cfg := configs["default"]
+//     Related variables:
+//         cfg.Timeout = (time.Duration)0
+func (a *A) UseNamed(name string, value interface{}) {
+	a.setVars(map[string]interface{}{
+		name: snapshot(value),
+	})
+}
+
+// snapshot returns a pointer to a copy of value.
+func snapshot(value interface{}) interface{} {
+	if value == nil {
+		return new(interface{})
+	}
+
+	ptr := reflect.New(reflect.TypeOf(value))
+	ptr.Elem().Set(reflect.ValueOf(value))
+	return ptr.Interface()
+}
+
+// Checkpoint copies current values of all vars registered by `Use` and `UseStruct`.
+// Following failures print the copies instead of the latest values,
+// until Checkpoint is called again.
+// It's designed to print vars shared with goroutines without races.
+// Call Checkpoint when no goroutine is mutating the vars.
+// See SnapshotVars to copy vars when they are registered.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         state := newState()
+//         a.Use(&state)
+//
+//         done := state.Start()
+//         <-done
+//         a.Checkpoint()
+//
+//         go state.Stop()
+//         a.Assert(state.Running) // state is printed as it's copied in Checkpoint.
+//     }
+func (a *A) Checkpoint() {
+	sources := a.sourcesSnapshot()
+
+	if len(sources) == 0 {
+		return
+	}
+
+	values := make(map[string]interface{}, len(sources))
+
+	for k, v := range sources {
+		values[k] = assertion.Snapshot(v)
+	}
+
+	a.setVars(values)
+}
+
+// maxUseStructDepth is the max depth of nested fields registered by UseStruct.
+const maxUseStructDepth = 4
+
+// UseStruct saves ptr, which must be a pointer to a struct var, and all its exported fields in context.
+// Fields are registered recursively under their selector names, e.g. "fixture.DB.Name",
+// so that related fields are printed automatically in assertion message like vars registered by Use.
+// Nested fields deeper than 4 levels are not registered.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         fixture := newFixture()
+//         a.UseStruct(&fixture)
+//         a.Assert(fixture.DB.Name == "test")
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         fixture.DB.Name == "test"
+//     Related variables:
+//         fixture.DB.Name = (string)prod
+func (a *A) UseStruct(ptr interface{}) {
+	val := reflect.ValueOf(ptr)
+
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	f, err := a.parser.ParseArgs("UseStruct", 1, []int{0})
+
+	if err != nil || len(f.Args) == 0 {
+		return
+	}
+
+	expr, ok := f.Args[0].(*ast.UnaryExpr)
+
+	if !ok || expr.Op != token.AND || !assertion.IsVar(expr.X) {
+		return
 	}
 
+	buf := &bytes.Buffer{}
+	printer.Fprint(buf, f.FileSet, expr.X)
+	vars := map[string]interface{}{}
+	collectFields(vars, buf.String(), val, 0)
+	a.use(vars)
 	a.parser.AddExcluded(f.Caller)
 }
+
+// collectFields saves ptr with name in vars and
+// saves all exported fields of the struct referenced by ptr recursively.
+func collectFields(vars map[string]interface{}, name string, ptr reflect.Value, depth int) {
+	vars[name] = ptr.Interface()
+
+	if depth >= maxUseStructDepth {
+		return
+	}
+
+	v := ptr.Elem()
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.PkgPath == "" {
+			collectFields(vars, name+"."+field.Name, v.Field(i).Addr(), depth+1)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// FailureDir returns the artifacts directory of current test case, which is created on first call.
+// Write dumps, logs or screenshots in it to debug failures.
+// The directory is printed in every following failure message of the test case,
+// so that artifacts are discoverable from test logs.
+// Hooks registered by OnFailure can read it from `Failure.ArtifactsDir`.
+//
+// If env `GO_ASSERT_ARTIFACTS_DIR` is set, e.g. to a directory uploaded by CI,
+// the directory is created in it and named after the test case, and it's kept after tests.
+// Otherwise, it's created by `t.TempDir()` and removed after the test case completes.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         remove := assert.OnFailure(func(f *assert.Failure) {
+//             if f.ArtifactsDir != "" {
+//                 os.WriteFile(filepath.Join(f.ArtifactsDir, "db.sql"), dumpDB(), 0644)
+//             }
+//         })
+//         code := m.Run()
+//         remove()
+//         os.Exit(code)
+//     }
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         dir := a.FailureDir()
+//         srv := startServer(filepath.Join(dir, "server.log"))
+//         a.Equal(srv.Status(), "ready")
+//     }
+func (a *A) FailureDir() string {
+	a.t.Helper()
+	return assertion.FailureDir(a.t, a.trigger("FailureDir", nil))
+}
@@ -33,10 +33,11 @@ import (
 //     Referenced variables are assigned in following statements:
 //         a, b := 1, 2
 func Assert(t *testing.T, expr interface{}) {
+	t.Helper()
 	assertion.Assert(t, expr, &assertion.Trigger{
 		FuncName: "Assert",
 		Skip:     1,
-		Args:     []int{1},
+		Args:     argsSecond,
 	})
 }
 
@@ -57,14 +58,15 @@ func Assert(t *testing.T, expr interface{}) {
 //     The value of following expression should equal.
 //     [1] []int{1, 2}
 //     [2] []int{1}
-//     Values:
-//     [1] -> ([]int)[1 2]
-//     [2] -> ([]int)[1]
+//     Differences:
+//     Only in [1]:
+//         [1] = (int)2
 func Equal(t *testing.T, v1, v2 interface{}) {
+	t.Helper()
 	assertion.AssertEqual(t, v1, v2, &assertion.Trigger{
 		FuncName: "Equal",
 		Skip:     1,
-		Args:     []int{1, 2},
+		Args:     argsSecondTwo,
 	})
 }
 
@@ -86,10 +88,11 @@ func Equal(t *testing.T, v1, v2 interface{}) {
 //     [1] []int{1}
 //     [2] []int{1}
 func NotEqual(t *testing.T, v1, v2 interface{}) {
+	t.Helper()
 	assertion.AssertNotEqual(t, v1, v2, &assertion.Trigger{
 		FuncName: "NotEqual",
 		Skip:     1,
-		Args:     []int{1, 2},
+		Args:     argsSecondTwo,
 	})
 }
 
@@ -113,14 +116,15 @@ func NotEqual(t *testing.T, v1, v2 interface{}) {
 //     The value of following expression should equal.
 //     [1] []int{1, 2}
 //     [2] []int{1}
-//     Values:
-//     [1] -> ([]int)[1 2]
-//     [2] -> ([]int)[1]
+//     Differences:
+//     Only in [1]:
+//         [1] = (int)2
 func AssertEqual(t *testing.T, v1, v2 interface{}) {
+	t.Helper()
 	assertion.AssertEqual(t, v1, v2, &assertion.Trigger{
 		FuncName: "AssertEqual",
 		Skip:     1,
-		Args:     []int{1, 2},
+		Args:     argsSecondTwo,
 	})
 }
 
@@ -145,9 +149,10 @@ func AssertEqual(t *testing.T, v1, v2 interface{}) {
 //     [1] []int{1}
 //     [2] []int{1}
 func AssertNotEqual(t *testing.T, v1, v2 interface{}) {
+	t.Helper()
 	assertion.AssertNotEqual(t, v1, v2, &assertion.Trigger{
 		FuncName: "AssertNotEqual",
 		Skip:     1,
-		Args:     []int{1, 2},
+		Args:     argsSecondTwo,
 	})
 }
@@ -1,18 +1,29 @@
 // Copyright 2017 Huan Du. All rights reserved.
 // Licensed under the MIT license that can be found in the LICENSE file.
 
+//go:build samples
+
 package assert
 
 import (
 	"errors"
+	"expvar"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // TestMain hacks the testing process and runs cases only if flag -test.run is specified.
-// Due to the nature of this package, all "successful" cases will always fail.
-// With this hack, we can run selected case manually without breaking travis-ci system.
+// Due to the nature of this package, all "successful" cases in files with build tag samples will always fail.
+// With this hack, we can run selected case manually, e.g. `go test -tags samples -run TestUse .`.
 func TestMain(m *testing.M) {
 	for _, arg := range os.Args[1:] {
 		if strings.HasPrefix(arg, "-test.run") {
@@ -176,3 +187,1175 @@ func TestUse(t *testing.T) {
 	v3 = v2[1]
 	a.Assert(v1 > 123 && v3 != "bar")
 }
+
+func TestUseLoopVars(t *testing.T) {
+	a := New(t)
+	cases := []struct {
+		Input  string
+		Output int
+	}{
+		{"foo", 3},
+		{"bar", 4},
+	}
+
+	for i, c := range cases {
+		a.Use(&i, &c)
+
+		// Should fail in case 1 and print the iteration.
+		a.Assert(len(c.Input) == c.Output)
+	}
+}
+
+func TestCases(t *testing.T) {
+	a := New(t)
+	cases := []struct {
+		Name   string
+		Input  string
+		Output int
+	}{
+		{"foo", "foo", 3},
+		{"bar", "bar", 4},
+	}
+
+	// Case "bar" should fail and print the test case.
+	Cases(a, cases, func(a *A, tc struct {
+		Name   string
+		Input  string
+		Output int
+	}) {
+		a.Assert(len(tc.Input) == tc.Output)
+	})
+}
+
+func TestDoesNotBlock(t *testing.T) {
+	a := New(t)
+	ch := make(chan int, 1)
+
+	// Should pass.
+	a.DoesNotBlock(func() { ch <- 1 }, 10*time.Millisecond)
+
+	// Should fail as ch is full.
+	a.DoesNotBlock(func() { ch <- 2 }, 10*time.Millisecond)
+}
+
+func TestCompletes(t *testing.T) {
+	a := New(t)
+	wg := &sync.WaitGroup{}
+	done := make(chan struct{})
+	close(done)
+
+	// Should pass.
+	a.Completes(wg, 10*time.Millisecond)
+	a.Completes(done, 10*time.Millisecond)
+
+	// Should fail as wg is never done.
+	wg.Add(1)
+	a.Completes(wg, 10*time.Millisecond)
+}
+
+func TestEventually(t *testing.T) {
+	a := New(t)
+	count := 0
+
+	// Should pass.
+	a.Eventually(func() bool {
+		count++
+		return count > 2
+	}, time.Second, time.Millisecond)
+
+	// Should fail with last observed error.
+	a.Eventually(func() error {
+		count++
+		return fmt.Errorf("count is %v", count)
+	}, 10*time.Millisecond, time.Millisecond)
+}
+
+func TestNewWithOptions(t *testing.T) {
+	a := New(t, WithColor(false), WithFatal(false))
+
+	// Should fail and continue.
+	a.Equal(1, 2)
+
+	// Should fail again.
+	a.Equal(3, 4)
+}
+
+func TestVerify(t *testing.T) {
+	a := New(t)
+	x, y := 1, 2
+
+	// Should pass.
+	a.NilError(Verify(x < y))
+	a.NilError(VerifyEqual([]int{x}, []int{1}))
+
+	err := Verify(x > y)
+	a.Assert(strings.Contains(err.Error(), "Assertion failed:\n    x > y"))
+
+	err = VerifyEqual(x, y)
+	_, ok := err.(*Failure)
+	a.Assert(ok)
+
+	// Should fail.
+	a.NilError(err)
+}
+
+func TestNewPanic(t *testing.T) {
+	a := New(t)
+	p := NewPanic()
+	x, y := 1, 2
+
+	// Should pass.
+	p.Assert(x < y)
+	p.Equal(x, 1)
+
+	var msg string
+
+	func() {
+		defer func() {
+			msg = recover().(error).Error()
+		}()
+
+		p.Equal(x, y)
+	}()
+
+	a.Assert(strings.Contains(msg, "Assertion failed:\n    p.Equal(x, y)"))
+
+	// Should fail.
+	a.Equal(msg, "")
+}
+
+func TestChild(t *testing.T) {
+	a := New(t)
+	shared := 1
+	a.Use(&shared)
+
+	for i := 0; i < 4; i++ {
+		i := i
+		a.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Parallel()
+			ca := a.Child(t)
+			local := i
+			ca.Use(&local)
+			a.Use(&local)
+
+			// Should pass.
+			ca.Assert(shared == 1)
+
+			// Should fail.
+			ca.Assert(shared+local == 0)
+		})
+	}
+}
+
+func TestUseNamed(t *testing.T) {
+	a := New(t)
+	configs := map[string]struct {
+		Name    string
+		Timeout int
+	}{
+		"default": {Name: "default"},
+	}
+	cfg := configs["default"]
+	a.UseNamed("cfg", configs["default"])
+
+	cfg.Timeout = 1
+
+	// Should pass.
+	a.Assert(cfg.Timeout > 0)
+
+	cfg = configs["default"]
+
+	// Should fail.
+	a.Assert(cfg.Timeout > 0)
+}
+
+func TestUseStruct(t *testing.T) {
+	type DB struct {
+		Name string
+		port int
+	}
+	type Fixture struct {
+		DB    *DB
+		Users []string
+	}
+
+	a := New(t)
+	fixture := Fixture{
+		DB:    &DB{Name: "prod", port: 3306},
+		Users: []string{"alice"},
+	}
+	a.UseStruct(&fixture)
+
+	// Should pass.
+	a.Assert(len(fixture.Users) == 1)
+
+	// Should fail.
+	a.Assert(fixture.DB.Name == "test")
+}
+
+func TestAssertSubExprs(t *testing.T) {
+	a := New(t)
+	width, height := 3, 4
+	items := []int{1, 2, 3}
+	a.Use(&width, &height, &items)
+
+	// Should pass.
+	a.Assert(width*height > len(items))
+
+	// Should fail.
+	a.Assert(width*height <= len(items)*2)
+}
+
+func TestAssertIndexedVars(t *testing.T) {
+	a := New(t, WithFatal(false))
+	items := []int{1, 2, 3}
+	scores := map[string]int{"alice": 90, "bob": 60}
+	name := "bob"
+	a.Use(&items, &scores, &name)
+
+	// Should fail and print items with items[0].
+	a.Assert(items[0] > 1)
+
+	// Should fail and print scores with scores["bob"].
+	a.Assert(scores[name] >= 80 && scores["alice"] > 95)
+}
+
+// retryConfig has a getter with pointer receiver.
+type retryConfig struct {
+	attempts int
+}
+
+func (c *retryConfig) Attempts() int {
+	return c.attempts
+}
+
+func TestAssertDerefAndGetters(t *testing.T) {
+	a := New(t, WithFatal(false), CallGetters())
+	n := 0
+	count := &n
+	cfg := retryConfig{attempts: 1}
+	a.Use(&count, &cfg)
+
+	// Should fail and print *count.
+	a.Assert(*count > 0)
+
+	// Should fail and print cfg.Attempts().
+	a.Assert(cfg.Attempts() > 2)
+}
+
+func makeUser() *testUser {
+	return &testUser{Name: "alice", Age: 17}
+}
+
+type testUser struct {
+	Name string
+	Age  int
+}
+
+func TestInlineHelpers(t *testing.T) {
+	SetInlineHelpers(true)
+	defer SetInlineHelpers(false)
+
+	a := New(t)
+	user := makeUser()
+
+	// Should fail and print the return statement of makeUser.
+	a.Assert(user.Age >= 18)
+}
+
+func TestExcludeFuncs(t *testing.T) {
+	a := New(t, ExcludeFuncs("logValue"))
+	logValue := func(v *int) { t.Log(*v) }
+
+	v := 1
+	logValue(&v)
+
+	// Should fail and print `v := 1` as the assignment of v.
+	a.Assert(v > 1)
+}
+
+func TestHTTPServer(t *testing.T) {
+	a := New(t)
+	srv := a.HTTPServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"name":"foo"}`)
+	}))
+
+	// Should pass.
+	resp := srv.Get("/users/1")
+	resp.AssertStatus(http.StatusOK)
+	resp.AssertHeader("Content-Type", "application/json")
+	resp.AssertBody(`{"name":"foo"}`)
+
+	// Should fail.
+	resp = srv.Post("/users", "application/json", `{"name":"bar"}`)
+	resp.AssertStatus(http.StatusCreated)
+}
+
+func TestPortOpen(t *testing.T) {
+	a := New(t)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	a.NilError(err)
+	addr := l.Addr().String()
+
+	// Should pass.
+	a.PortOpen(addr, time.Second)
+	a.DialSucceeds("tcp", addr)
+
+	// Should fail.
+	l.Close()
+	a.PortOpen(addr, time.Second)
+}
+
+func TestFlaky(t *testing.T) {
+	a := New(t)
+	remove := OnFailure(func(f *Failure) {
+		if f.Flaky {
+			t.Logf("flaky assertion at %v:%v passed after %v attempts", f.Filename, f.Line, f.Attempts)
+		}
+	})
+	defer remove()
+	count := 0
+
+	// Should pass in the second attempt.
+	a.Flaky(3, func(a *A) {
+		count++
+		a.Equal(count, 2)
+	})
+
+	// Should fail after 3 attempts.
+	a.Flaky(3, func(a *A) {
+		count++
+		a.Assert(count < 0)
+	})
+}
+
+var heapGrowthSink [][]byte
+
+func TestHeapGrowthUnder(t *testing.T) {
+	a := New(t)
+
+	// Should pass.
+	a.HeapGrowthUnder(1<<20, func() {
+		_ = make([]byte, 1<<22)
+	})
+
+	// Should fail as the slice is still referenced.
+	a.HeapGrowthUnder(1<<20, func() {
+		heapGrowthSink = append(heapGrowthSink, make([]byte, 1<<22))
+	})
+}
+
+func TestDurations(t *testing.T) {
+	a := New(t, WithFatal(false))
+	elapsed := 1234567891 * time.Nanosecond
+
+	// Should pass.
+	a.DurationLess(elapsed, 2*time.Second)
+	a.DurationBetween(elapsed, time.Second, 2*time.Second)
+	a.TookLess(time.Second, func() {})
+
+	// Should fail and print humanized durations.
+	a.DurationLess(elapsed, time.Second)
+	a.DurationBetween(elapsed, 2*time.Second, 3*time.Second)
+
+	// Should fail with the time taken by the function.
+	a.TookLess(time.Millisecond, func() {
+		time.Sleep(20 * time.Millisecond)
+	})
+}
+
+func TestType(t *testing.T) {
+	a := New(t)
+	var v interface{} = errors.New("foo")
+
+	// Should pass.
+	err := Type[error](a, v)
+	a.Equal(err.Error(), "foo")
+
+	// Should fail.
+	Type[string](a, v)
+}
+
+func TestTypedNil(t *testing.T) {
+	a := New(t)
+	var p *typedNilError
+	var err error = p
+	a.Use(&err)
+
+	// Should fail with a note about typed nil.
+	a.NilError(err)
+}
+
+func TestTypedNilAssert(t *testing.T) {
+	a := New(t)
+	var p *typedNilError
+	var err error = p
+	a.Use(&err)
+
+	// Should fail with a note about typed nil.
+	a.Assert(err)
+}
+
+func TestQuarantine(t *testing.T) {
+	a := New(t)
+	remove := Quarantine(QuarantineRule{
+		File:     "assert_test.go",
+		TestName: "TestQuarantine",
+		Tag:      "ISSUE-123",
+		Mode:     QuarantineLog,
+	})
+	defer remove()
+
+	// Should be logged and continue.
+	a.Equal(1, 2)
+
+	remove()
+
+	// Should fail.
+	a.Equal(3, 4)
+}
+
+func TestAll(t *testing.T) {
+	a := New(t)
+	ages := []int{18, 7, 30, 3}
+	adult := func(age int) bool {
+		return age >= 18
+	}
+
+	// Should pass.
+	a.ContainsFunc(ages, adult)
+	a.AnyMatch(ages, adult)
+	a.All(ages[2:3], adult)
+
+	// Should fail and print elements at index 1 and 3.
+	a.All(ages, adult)
+}
+
+func TestSatisfies(t *testing.T) {
+	type User struct {
+		Name string
+		Age  int
+	}
+
+	a := New(t)
+	adult := func(u User) bool {
+		return u.Age >= 18
+	}
+
+	// Should pass.
+	a.Satisfies(User{Name: "foo", Age: 20}, adult)
+
+	// Should fail and print the predicate.
+	u := User{Name: "bar", Age: 16}
+	a.Satisfies(u, func(u User) bool { return u.Age > 18 })
+}
+
+func TestThat(t *testing.T) {
+	a := New(t)
+	blocked := []string{"root", "admin"}
+	name := "guest"
+
+	// Should pass.
+	a.That(name, Not(InSlice(blocked)))
+
+	// Should fail and explain every matcher.
+	name = "admin"
+	a.That(name, AnyOf(EqualTo("guest"), Not(InSlice(blocked))))
+}
+
+func TestSortSlicesAt(t *testing.T) {
+	type Group struct {
+		Name  string
+		Users []string
+	}
+
+	a := New(t, SortSlicesAt("$.Users", func(u1, u2 string) bool {
+		return u1 < u2
+	}))
+	group := Group{Name: "admin", Users: []string{"foo", "bar"}}
+
+	// Should pass.
+	a.Equal(group, Group{Name: "admin", Users: []string{"bar", "foo"}})
+
+	// Should fail with sorted users.
+	a.Equal(group, Group{Name: "admin", Users: []string{"bar", "baz"}})
+}
+
+func TestNormalizeUnicode(t *testing.T) {
+	a := New(t, NormalizeUnicode(NFC), FoldCase())
+
+	// Should pass.
+	a.Equal("Caf\u00e9", "CAFE\u0301")
+	a.NotEqual("caf\u00e9", "cafe")
+
+	// Should fail and print the first differing rune.
+	a.Equal("Caf\u00e9", "cafe")
+}
+
+func TestCleanupTempDir(t *testing.T) {
+	a := New(t)
+	dir := a.TempDir()
+	a.Cleanup(func() {
+		t.Logf("cleaned up")
+	})
+
+	// Should pass.
+	a.NilError(os.WriteFile(filepath.Join(dir, "foo"), []byte("foo"), 0644))
+
+	// Should fail in a helper and report the line calling the helper.
+	checkFile := func(a *A, name string) {
+		a.Helper()
+		a.NilError(os.Stat(filepath.Join(dir, name)))
+	}
+	checkFile(a, "bar")
+}
+
+func TestCounterIncreasedBy(t *testing.T) {
+	a := New(t, WithFatal(false))
+	requests := expvar.NewMap("TestCounterIncreasedBy")
+	handle := func(code string) {
+		requests.Add(code, 1)
+	}
+
+	// Should pass.
+	a.CounterIncreasedBy(nil, "TestCounterIncreasedBy", 2, func() {
+		handle("200")
+		handle("404")
+	})
+	a.CounterIncreasedBy(requests, "200", 1, func() {
+		handle("200")
+	})
+
+	// Should fail and print all samples of the metric.
+	a.CounterIncreasedBy(nil, "TestCounterIncreasedBy", 2, func() {
+		handle("200")
+	})
+}
+
+func TestInvariant(t *testing.T) {
+	type Span struct {
+		Name  string
+		Start time.Time
+		End   time.Time
+	}
+
+	a := New(t, WithFatal(false))
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	span := Span{Name: "query", Start: at, End: at.Add(-time.Second)}
+
+	// Should pass.
+	a.Invariant(span, "named", func(x Span) bool { return x.Name != "" })
+
+	// Should fail and print x.Start and x.End only.
+	a.Invariant(span, "Start before End", func(x Span) bool {
+		return x.Start.Before(x.End)
+	})
+}
+
+func TestOrdered(t *testing.T) {
+	type Event struct {
+		Name string
+		At   time.Time
+	}
+
+	a := New(t, WithFatal(false))
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	events := []Event{
+		{"created", at},
+		{"paid", at.Add(time.Second)},
+		{"shipped", at},
+	}
+
+	// Should pass.
+	a.Ordered(events[:2], func(e Event) time.Time { return e.At })
+
+	// Should fail and print the out-of-order pair.
+	a.Ordered(events, func(e Event) time.Time {
+		return e.At
+	})
+}
+
+func TestPointerEqual(t *testing.T) {
+	type Customer struct {
+		Name string
+	}
+	type Order struct {
+		ID       int
+		Customer *Customer
+		Tags     []string
+	}
+
+	a := New(t, WithFatal(false))
+	order := &Order{ID: 42, Customer: &Customer{Name: "Alice"}, Tags: []string{"new"}}
+
+	// Should pass.
+	a.PointerEqual(order, &Order{ID: 42, Tags: []string{"new"}})
+
+	// Should fail.
+	a.PointerEqual(order, &Order{ID: 42, Customer: &Customer{Name: "Bob"}, Tags: []string{"new"}})
+}
+
+func TestVisibleWhitespace(t *testing.T) {
+	type Row struct {
+		Cells []string
+	}
+
+	a := New(t, WithFatal(false), VisibleWhitespace())
+
+	// Should fail and print tabs and trailing spaces visibly.
+	a.Equal("name\tage ", "name    age")
+
+	// Should fail and print the CR and the non-breaking space visibly in differences.
+	a.Equal(Row{Cells: []string{"a\r", "b"}}, Row{Cells: []string{"a", "b\u00a0"}})
+}
+
+func TestEqualShortStrings(t *testing.T) {
+	a := New(t)
+
+	// Should fail and mark the non-breaking space by a caret.
+	a.Equal("hello world", "hello\u00a0world")
+}
+
+func TestEqualTime(t *testing.T) {
+	type Job struct {
+		Name      string
+		StartedAt time.Time
+		Timeout   time.Duration
+	}
+
+	a := New(t)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Should fail and print time values in RFC3339 format.
+	a.Equal(Job{"build", at, time.Minute}, Job{"build", at.Add(time.Second), 90 * time.Second})
+}
+
+func TestBigEqual(t *testing.T) {
+	a := New(t)
+	x := new(big.Int).Exp(big.NewInt(2), big.NewInt(64), nil)
+	f, _ := new(big.Float).SetPrec(200).SetString("18446744073709551616")
+
+	// Should pass.
+	a.BigEqual(x, f)
+	a.BigEqual(big.NewRat(1, 2), 0.5)
+	a.BigInDelta(big.NewRat(1, 3), 0.333, 0.001)
+	a.BigInEpsilon(x, uint64(math.MaxUint64), 1e-9)
+
+	// Should fail.
+	a.BigEqual(x, uint64(math.MaxUint64))
+	a.BigInDelta(x, uint64(math.MaxUint64), 0.5)
+	a.BigInEpsilon(big.NewInt(7), 5, 0.1)
+}
+
+// fixedDecimal is a decimal number coef * 10^-scale.
+// Same numbers can have different scales, e.g. 1.5 and 1.50.
+type fixedDecimal struct {
+	coef  int64
+	scale int
+}
+
+func (d fixedDecimal) rat() *big.Rat {
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.scale)), nil)
+	return new(big.Rat).SetFrac(big.NewInt(d.coef), denom)
+}
+
+func (d fixedDecimal) Cmp(other fixedDecimal) int {
+	return d.rat().Cmp(other.rat())
+}
+
+func (d fixedDecimal) String() string {
+	return d.rat().FloatString(d.scale)
+}
+
+func TestRegisterDecimal(t *testing.T) {
+	type Order struct {
+		ID    int
+		Total fixedDecimal
+	}
+
+	remove := RegisterDecimal(fixedDecimal.Cmp, fixedDecimal.String)
+	defer remove()
+
+	a := New(t)
+
+	// Should pass.
+	a.Equal(fixedDecimal{150, 2}, fixedDecimal{15, 1})
+	a.Equal([]Order{{1, fixedDecimal{150, 2}}}, []Order{{1, fixedDecimal{15, 1}}})
+
+	// Should fail and print decimals by String.
+	a.Equal(Order{1, fixedDecimal{150, 2}}, Order{1, fixedDecimal{105, 2}})
+}
+
+func TestNoError(t *testing.T) {
+	a := New(t)
+	_, err := os.Open("path/to/a/file")
+
+	// Should pass.
+	a.Error(err)
+
+	// Should fail.
+	a.NoError(err)
+}
+
+func TestWithinTimeout(t *testing.T) {
+	a := New(t)
+
+	// Should pass.
+	a.WithinTimeout(time.Second, func(a *A) {
+		a.Equal(1+1, 2)
+	})
+
+	// Should fail and print goroutine stacks.
+	a.WithinTimeout(10*time.Millisecond, func(a *A) {
+		time.Sleep(time.Second)
+	})
+}
+
+// insertionOrderedMap keeps keys in insertion order.
+type insertionOrderedMap struct {
+	keys   []string
+	values map[string]int
+}
+
+func (m *insertionOrderedMap) Set(key string, value int) *insertionOrderedMap {
+	if m.values == nil {
+		m.values = map[string]int{}
+	}
+
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+	return m
+}
+
+func TestRegisterCanonical(t *testing.T) {
+	remove := RegisterCanonical(func(m *insertionOrderedMap) map[string]int {
+		return m.values
+	})
+	defer remove()
+
+	a := New(t)
+	counts := (&insertionOrderedMap{}).Set("foo", 1).Set("bar", 2)
+
+	// Should pass.
+	a.Equal(counts, (&insertionOrderedMap{}).Set("bar", 2).Set("foo", 1))
+
+	// Should fail and print differences by keys.
+	a.Equal(counts, (&insertionOrderedMap{}).Set("bar", 3).Set("foo", 1))
+}
+
+func TestEqualSyncMap(t *testing.T) {
+	a := New(t)
+	m1 := &sync.Map{}
+	m1.Store("foo", 1)
+	m1.Store("bar", 2)
+	m2 := &sync.Map{}
+	m2.Store("bar", 2)
+	m2.Store("foo", 1)
+
+	// Should pass.
+	a.Equal(m1, m2)
+
+	// Should fail and print differences by keys.
+	m2.Store("foo", 3)
+	a.Equal(m1, m2)
+}
+
+func TestGrouped(t *testing.T) {
+	a := New(t, WithFatal(false))
+
+	a.Grouped("creating user", func(a *A) {
+		name := "alice"
+
+		// Should pass.
+		a.Equal(len(name), 5)
+
+		a.Grouped("checking name", func(a *A) {
+			// Should fail with both group labels.
+			a.Equal(name, "Alice")
+		})
+	})
+}
+
+// scenario embeds A so that assertion methods are promoted.
+type scenario struct {
+	*A
+}
+
+func TestAssertMethodValue(t *testing.T) {
+	a := New(t, WithFatal(false))
+	x, y := 1, 2
+
+	// Should fail with parsed args.
+	check := a.Assert
+	check(x > y)
+
+	// Should fail with parsed args.
+	equal := a.Equal
+	equal(x, y)
+
+	// Should fail with parsed args.
+	s := scenario{a}
+	s.Equal(x, y)
+}
+
+func FuzzInputs(f *testing.F) {
+	f.Add("hello", 2)
+	f.Add("go", 5)
+
+	Fuzz(f, func(a *A, s string, n int) {
+		prefix := s
+
+		if n < len(s) {
+			prefix = s[:n]
+		}
+
+		// Should fail with s and n dumped when n is larger than len(s).
+		a.Assert(len(prefix) == n)
+	})
+}
+
+func BenchmarkBench(b *testing.B) {
+	Bench(b, func(a *A) {
+		s := strings.Repeat("x", 16)
+
+		// Should pass and be measured.
+		a.Equal(len(s), 16)
+	})
+}
+
+func TestSnapshotVars(t *testing.T) {
+	a := New(t, SnapshotVars(), WithFatal(false))
+	items := []int{1, 2}
+	a.Use(&items)
+	items[0] = 3
+
+	// Should fail and print items as [1 2].
+	a.Assert(len(items) > 2)
+
+	// Should fail and print items as [3 2].
+	a.Checkpoint()
+	a.Assert(len(items) > 2)
+}
+
+func TestStableOutput(t *testing.T) {
+	SetStableOutput(true)
+	defer SetStableOutput(false)
+
+	a := New(t)
+	dir := t.TempDir()
+	timeout := 1500 * time.Millisecond
+
+	// Should fail with dir and timeout replaced by placeholders.
+	a.Equal(dir, timeout.String())
+}
+
+func TestEqualErrors(t *testing.T) {
+	a := New(t, WithFatal(false))
+	errNotFound := errors.New("not found")
+	err := fmt.Errorf("load config: %w", errNotFound)
+
+	// Should fail with a note suggesting errors.Is.
+	a.Equal(err, errNotFound)
+}
+
+func TestHasKey(t *testing.T) {
+	a := New(t, WithFatal(false))
+	config := map[string]int{"timeout": 30, "retries": 3}
+
+	// Should pass.
+	a.HasKey(config, "timeout")
+
+	// Should fail and suggest "timeout".
+	a.HasKey(config, "tiemout")
+
+	// Should fail with a note on the typo.
+	a.Equal(config, map[string]int{"timeuot": 30, "retries": 3})
+}
+
+func TestAllInDelta(t *testing.T) {
+	a := New(t, WithFatal(false))
+	got := []float64{0.1, 0.52, 0.9}
+	matrix := [][]float64{{1, 0}, {0, 1}}
+
+	// Should pass.
+	a.AllInDelta(got, []float64{0.1, 0.5, 0.9}, 0.05)
+
+	// Should fail and print the element at index 1 only.
+	a.AllInDelta(got, []float64{0.1, 0.5, 0.9}, 0.01)
+
+	// Should fail and print the element at [1][0] only.
+	a.AllInDelta2D(matrix, [][]float64{{1, 0}, {0.5, 1}}, 0.01)
+}
+
+func TestSamples(t *testing.T) {
+	a := New(t, WithFatal(false))
+	latencies := []float64{12, 9, 11, 10, 48, 13, 9, 10, 12, 11}
+
+	// Should pass.
+	a.MeanInDelta(latencies, 14, 1)
+	a.PercentileUnder(latencies, 0.5, 11)
+
+	// Should fail and print a summary of samples.
+	a.MeanInDelta(latencies, 10, 1)
+
+	// Should fail and print a summary of samples.
+	a.PercentileUnder(latencies, 0.99, 20)
+
+	// Should fail as there is no sample.
+	a.PercentileUnder(nil, 0.99, 20)
+}
+
+func TestGuard(t *testing.T) {
+	a := New(t, WithFatal(false))
+	done := a.Guard()
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// Should pass.
+	go func() {
+		defer wg.Done()
+		defer done.Recover()
+	}()
+
+	// Should fail with the panic stack.
+	go func() {
+		defer wg.Done()
+		defer done.Recover()
+		var items []int
+		_ = items[len(items)]
+	}()
+
+	wg.Wait()
+	done.Check()
+}
+
+func TestWatchdog(t *testing.T) {
+	a := New(t)
+	a.Watchdog(100 * time.Millisecond)
+
+	// Should pass.
+	a.Equal(1, 1)
+	a.Assert(true)
+
+	// Should fail with stacks and sites of both assertions above.
+	time.Sleep(200 * time.Millisecond)
+}
+
+func TestTraceAssertions(t *testing.T) {
+	a := New(t, TraceAssertions(4), WithFatal(false))
+
+	// Should pass.
+	a.Equal(1, 1)
+	a.Assert(len(a.Trace()) == 1)
+
+	// Should fail with sites of all three assertions.
+	a.Equal(len(a.Trace()), 3)
+
+	// Should pass.
+	a.Assert(true)
+}
+
+// requirePositive is a helper like those in testify's require package.
+func requirePositive(t interface {
+	Errorf(format string, args ...interface{})
+	FailNow()
+}, n int) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	if n <= 0 {
+		t.Errorf("%v should be positive", n)
+		t.FailNow()
+	}
+}
+
+func TestTestifyT(t *testing.T) {
+	a := New(t)
+
+	// Should pass.
+	requirePositive(a.TestifyT(), 1)
+
+	a.Flaky(2, func(a *A) {
+		// Should fail in both attempts.
+		requirePositive(a.TestifyT(), -1)
+	})
+}
+
+// haveLen is a matcher like gomega.HaveLen.
+type haveLen int
+
+func (m haveLen) Match(actual interface{}) (bool, error) {
+	s, ok := actual.([]string)
+
+	if !ok {
+		return false, fmt.Errorf("HaveLen matcher expects a []string. Got: %T", actual)
+	}
+
+	return len(s) == int(m), nil
+}
+
+func (m haveLen) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n    %v\nto have length %v", actual, int(m))
+}
+
+func (m haveLen) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n    %v\nnot to have length %v", actual, int(m))
+}
+
+func TestGinkgo(t *testing.T) {
+	// The fail is ginkgo.Fail in a spec.
+	a := NewT(GinkgoT(func(message string, callerSkip ...int) {
+		t.Fatalf("ginkgo.Fail(callerSkip=%v):\n%v", callerSkip, message)
+	}))
+	names := []string{"alice", "bob"}
+
+	// Should pass.
+	a.That(names, Gomega(haveLen(2)))
+
+	// Should fail with both Gomega failure messages.
+	a.That(names, AnyOf(Gomega(haveLen(1)), Not(Gomega(haveLen(2)))))
+}
+
+func TestMaxRepeatedFailures(t *testing.T) {
+	SetDefault(Config{MaxRepeatedFailures: 2})
+	defer SetDefault(Config{})
+
+	a := New(t, WithFatal(false))
+
+	// Should fail twice and print "...and 3 more failures" after the test.
+	for i := 0; i < 5; i++ {
+		a.Equal(i, -1)
+	}
+}
+
+func TestFailureDir(t *testing.T) {
+	a := New(t)
+	dir := a.FailureDir()
+
+	// Should pass.
+	a.NilError(os.WriteFile(filepath.Join(dir, "server.log"), []byte("started"), 0644))
+
+	// Should fail and print the artifacts directory.
+	a.Equal(dir, "")
+}
+
+type unexportedCache struct {
+	Name  string
+	cache map[string]string
+}
+
+func TestUnexportedFields(t *testing.T) {
+	a := New(t, WithFatal(false))
+	v1 := unexportedCache{Name: "Alice", cache: map[string]string{"role": "admin"}}
+	v2 := unexportedCache{Name: "Alice"}
+
+	// Should pass.
+	a.With(IgnoreUnexported()).Equal(v1, v2)
+
+	// Should fail and label the difference as unexported.
+	a.With(LabelUnexported()).Equal(v1, v2)
+}
+
+func TestEventuallyNoLeak(t *testing.T) {
+	a := New(t, WithFatal(false))
+	stop := make(chan struct{})
+	defer close(stop)
+
+	// Should pass.
+	a.EventuallyNoLeak(func() bool {
+		done := make(chan struct{})
+		go close(done)
+		<-done
+		return true
+	}, time.Second, time.Millisecond)
+
+	// Should fail as a goroutine waiting for a ticker leaks in every call.
+	attempts := 0
+	a.EventuallyNoLeak(func() bool {
+		ticker := time.NewTicker(time.Hour)
+
+		go func() {
+			defer ticker.Stop()
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+			}
+		}()
+
+		attempts++
+		return attempts == 3
+	}, time.Second, time.Millisecond)
+}
+
+type orderStatus int
+
+const (
+	orderStatusUnknown orderStatus = iota
+	orderStatusPaid
+)
+
+func TestRegisterFalsy(t *testing.T) {
+	remove := RegisterFalsy(func(s orderStatus) bool {
+		return s == orderStatusUnknown
+	})
+	defer remove()
+
+	a := New(t, WithFatal(false))
+	paid := orderStatusPaid
+	status := orderStatusUnknown
+
+	// Should pass.
+	a.Assert(paid)
+
+	// Should fail as the status is registered as falsy.
+	a.Assert(status)
+}
+
+func TestAssertAll(t *testing.T) {
+	a := New(t, WithFatal(false))
+	x, y := 1, 2
+	name := ""
+	var err error
+
+	// Should pass.
+	a.AssertAll(x < y, err == nil)
+
+	// Should fail and print the 1st and 3rd conditions only.
+	a.AssertAll(x > y, err == nil, name)
+}
+
+func TestEqualLargeBytes(t *testing.T) {
+	a := New(t)
+	b1 := make([]byte, 1<<20)
+	b2 := make([]byte, 1<<20)
+
+	// Should pass.
+	a.Equal(b1, b2)
+
+	// Should fail and print bytes around offset 0x3f200 only.
+	b2[0x3f200] = 0xde
+	a.Equal(b1, b2)
+}
+
+func TestContainsAll(t *testing.T) {
+	a := New(t)
+	log := "level=info msg=started"
+
+	// Should pass.
+	a.ContainsAll(log, "level=info", "msg=started")
+	a.ContainsAny(log, "level=error", "msg=started")
+
+	// Should fail.
+	a.ContainsAll(log, "msg=started", "level=error")
+}
@@ -0,0 +1,220 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package assertotel records assertion failures of tests as OpenTelemetry span events,
+// so that test observability pipelines can correlate failures with traces of the system under test.
+//
+// It's a separate module to keep OpenTelemetry out of dependencies of go-assert.
+//
+// Sample code.
+//
+//     func TestCheckout(t *testing.T) {
+//         // The span of the test is ended after the test completes.
+//         // Spans created by the system under test with ctx are its children.
+//         ctx := assertotel.Start(t, otel.Tracer("tests"))
+//
+//         a := assert.New(t)
+//         order, err := svc.Checkout(ctx, cart)
+//         a.NilError(err)
+//         a.Equal(order.Total, 42)
+//     }
+//
+// Every failure adds an event named "assert.failure" to the span with following attributes.
+//
+//   - `assert.test`: Name of the test case.
+//   - `assert.func`: Name of the assertion function.
+//   - `assert.file`, `assert.path` and `assert.line`: Location of the assertion.
+//   - `assert.source`: Source code of the assertion.
+//   - `assert.id`: Stable ID of the assertion site.
+//   - `assert.message`: Failure message without colors.
+//   - `assert.values`: Dumps of values checked by the assertion.
+//   - `assert.group`: Labels of groups enclosing the assertion.
+//   - `assert.flaky` and `assert.attempts`: Set if the failure is recovered by `A#Flaky`.
+//   - `assert.quarantine`: Tracking tag of the quarantine rule matching the failure.
+//
+// Attributes with zero values are omitted.
+package assertotel
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/huandu/go-assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EventName is the name of span events recording assertion failures.
+const EventName = "assert.failure"
+
+// Attribute keys of failure events.
+const (
+	KeyTest       = attribute.Key("assert.test")
+	KeyFunc       = attribute.Key("assert.func")
+	KeyFile       = attribute.Key("assert.file")
+	KeyPath       = attribute.Key("assert.path")
+	KeyLine       = attribute.Key("assert.line")
+	KeySource     = attribute.Key("assert.source")
+	KeyID         = attribute.Key("assert.id")
+	KeyMessage    = attribute.Key("assert.message")
+	KeyValues     = attribute.Key("assert.values")
+	KeyGroup      = attribute.Key("assert.group")
+	KeyFlaky      = attribute.Key("assert.flaky")
+	KeyAttempts   = attribute.Key("assert.attempts")
+	KeyQuarantine = attribute.Key("assert.quarantine")
+)
+
+// TB is the subset of `testing.TB` used to bind spans to tests.
+type TB interface {
+	Name() string
+	Failed() bool
+	Cleanup(f func())
+}
+
+var (
+	spansLock  sync.Mutex
+	spans      = map[string]trace.Span{} // Test name to its span.
+	removeHook func()
+)
+
+// Start starts a span named after t with tracer and records assertion failures of t in it.
+// The span is ended after t completes. Its status is set to error if t fails.
+//
+// The returned context carries the span. Pass it to the system under test
+// so that spans created by the system are children of the span.
+func Start(t TB, tracer trace.Tracer, opts ...trace.SpanStartOption) context.Context {
+	ctx, span := tracer.Start(context.Background(), t.Name(), opts...)
+	t.Cleanup(func() {
+		if t.Failed() {
+			span.SetStatus(codes.Error, "test failed")
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+
+		span.End()
+	})
+	Record(t, span)
+	return ctx
+}
+
+// Record records assertion failures of t and its subtests as events in span until t completes.
+// A subtest with its own span records failures in its own span only.
+// The span is not ended by Record.
+func Record(t TB, span trace.Span) {
+	name := t.Name()
+
+	spansLock.Lock()
+	spans[name] = span
+
+	if removeHook == nil {
+		removeHook = assert.OnFailure(addEvent)
+	}
+
+	spansLock.Unlock()
+
+	t.Cleanup(func() {
+		spansLock.Lock()
+		defer spansLock.Unlock()
+
+		if spans[name] != span {
+			return
+		}
+
+		delete(spans, name)
+
+		if len(spans) == 0 {
+			removeHook()
+			removeHook = nil
+		}
+	})
+}
+
+// findSpan returns the span of the test named name or its nearest parent test.
+func findSpan(name string) trace.Span {
+	spansLock.Lock()
+	defer spansLock.Unlock()
+
+	for {
+		if span, ok := spans[name]; ok {
+			return span
+		}
+
+		idx := strings.LastIndexByte(name, '/')
+
+		if idx < 0 {
+			return nil
+		}
+
+		name = name[:idx]
+	}
+}
+
+func addEvent(f *assert.Failure) {
+	span := findSpan(f.TestName)
+
+	if span == nil || !span.IsRecording() {
+		return
+	}
+
+	span.AddEvent(EventName, trace.WithAttributes(Attributes(f)...))
+}
+
+// Attributes returns attributes of the failure event of f.
+func Attributes(f *assert.Failure) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		KeyTest.String(f.TestName),
+		KeyFunc.String(f.FuncName),
+	}
+
+	if f.Filename != "" {
+		attrs = append(attrs, KeyFile.String(f.Filename), KeyLine.Int(f.Line))
+	}
+
+	if f.Path != "" {
+		attrs = append(attrs, KeyPath.String(f.Path))
+	}
+
+	if f.Source != "" {
+		attrs = append(attrs, KeySource.String(f.Source))
+	}
+
+	if f.ID != "" {
+		attrs = append(attrs, KeyID.String(f.ID))
+	}
+
+	attrs = append(attrs, KeyMessage.String(stripANSI(f.Message)))
+
+	if len(f.Values) > 0 {
+		values := make([]string, 0, len(f.Values))
+
+		for _, v := range f.Values {
+			values = append(values, stripANSI(v))
+		}
+
+		attrs = append(attrs, KeyValues.StringSlice(values))
+	}
+
+	if len(f.Groups) > 0 {
+		attrs = append(attrs, KeyGroup.StringSlice(f.Groups))
+	}
+
+	if f.Flaky {
+		attrs = append(attrs, KeyFlaky.Bool(true), KeyAttempts.Int(f.Attempts))
+	}
+
+	if f.Quarantine != "" {
+		attrs = append(attrs, KeyQuarantine.String(f.Quarantine))
+	}
+
+	return attrs
+}
+
+var reANSI = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// stripANSI removes colors in s as colorized output is not readable in trace backends.
+func stripANSI(s string) string {
+	return reANSI.ReplaceAllString(s, "")
+}
@@ -0,0 +1,141 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertotel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/huandu/go-assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fakeT struct {
+	*assert.FakeT
+	cleanups []func()
+}
+
+func newFakeT(name string) *fakeT {
+	return &fakeT{
+		FakeT: assert.NewFakeT(name),
+	}
+}
+
+func (t *fakeT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *fakeT) finish() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func TestStart(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	ft := newFakeT("TestCheckout")
+	ctx := Start(ft, tracer)
+
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		t.Fatalf("context should carry the span of the test.")
+	}
+
+	a := assert.NewT(ft.FakeT)
+	a.Equal(1, 2)
+
+	// A failure in subtest without its own span is recorded in the span of parent test.
+	sub := assert.NewT(assert.NewFakeT("TestCheckout/sub"))
+	sub.Assert(false)
+
+	// A failure of another test is not recorded.
+	other := assert.NewT(assert.NewFakeT("TestOther"))
+	other.Assert(false)
+
+	ft.finish()
+
+	// Failures after the test completes are not recorded.
+	a.Assert(false)
+
+	ended := recorder.Ended()
+
+	if len(ended) != 1 {
+		t.Fatalf("there should be 1 ended span. [actual:%v]", len(ended))
+	}
+
+	span := ended[0]
+
+	if span.Name() != "TestCheckout" || span.Status().Code != codes.Error {
+		t.Fatalf("span should be named after the test and fail. [name:%v] [status:%v]", span.Name(), span.Status())
+	}
+
+	events := span.Events()
+
+	if len(events) != 2 {
+		t.Fatalf("there should be 2 failure events. [actual:%v]", len(events))
+	}
+
+	attrs := attribute.NewSet(events[0].Attributes...)
+
+	for key, expected := range map[attribute.Key]attribute.Value{
+		KeyTest:   attribute.StringValue("TestCheckout"),
+		KeyFunc:   attribute.StringValue("Equal"),
+		KeyFile:   attribute.StringValue("assertotel_test.go"),
+		KeySource: attribute.StringValue("a.Equal(1, 2)"),
+		KeyValues: attribute.StringSliceValue([]string{"(int)1", "(int)2"}),
+	} {
+		if v, _ := attrs.Value(key); v != expected {
+			t.Fatalf("attribute %v is unexpected. [expected:%v] [actual:%v]", key, expected.Emit(), v.Emit())
+		}
+	}
+
+	if v, _ := attrs.Value(KeyMessage); !strings.Contains(v.AsString(), "should equal") || strings.Contains(v.AsString(), "\x1b[") {
+		t.Fatalf("message should be recorded without colors. [actual:%v]", v.AsString())
+	}
+
+	subAttrs := attribute.NewSet(events[1].Attributes...)
+
+	if v, _ := subAttrs.Value(KeyTest); v.AsString() != "TestCheckout/sub" {
+		t.Fatalf("failure of subtest should be recorded. [actual:%v]", v.AsString())
+	}
+
+	if _, ok := attrs.Value(KeyFlaky); ok {
+		t.Fatalf("attribute %v should be omitted.", KeyFlaky)
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	attrs := attribute.NewSet(Attributes(&assert.Failure{
+		TestName:   "TestFoo",
+		FuncName:   "Assert",
+		Message:    "\x1b[31mfailed\x1b[0m",
+		Groups:     []string{"step 1"},
+		Flaky:      true,
+		Attempts:   3,
+		Quarantine: "ISSUE-1",
+	})...)
+
+	for key, expected := range map[attribute.Key]attribute.Value{
+		KeyMessage:    attribute.StringValue("failed"),
+		KeyGroup:      attribute.StringSliceValue([]string{"step 1"}),
+		KeyFlaky:      attribute.BoolValue(true),
+		KeyAttempts:   attribute.IntValue(3),
+		KeyQuarantine: attribute.StringValue("ISSUE-1"),
+	} {
+		if v, _ := attrs.Value(key); v != expected {
+			t.Fatalf("attribute %v is unexpected. [expected:%v] [actual:%v]", key, expected.Emit(), v.Emit())
+		}
+	}
+
+	for _, key := range []attribute.Key{KeyFile, KeyLine, KeyPath, KeySource, KeyID, KeyValues} {
+		if attrs.HasValue(key) {
+			t.Fatalf("attribute %v should be omitted.", key)
+		}
+	}
+}
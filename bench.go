@@ -0,0 +1,37 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"testing"
+)
+
+// Bench resets the timer of b and calls fn b.N times with an A reporting failures to b.
+// Options customize all assertions made by the A passed to fn.
+//
+// The timer of b is stopped while a failure is parsed and formatted,
+// so that failing assertions don't distort measured timings.
+// Passing assertions are cheap and don't allocate, so they are measured as part of fn.
+// If fn stops the timer by itself, a failure in fn restarts it.
+//
+// Sample code.
+//
+//     func BenchmarkEncode(b *testing.B) {
+//         v := loadFixture()
+//
+//         assert.Bench(b, func(a *assert.A) {
+//             data, err := json.Marshal(v)
+//             a.NilError(data, err)
+//         }, assert.WithFatal(false))
+//     }
+func Bench(b *testing.B, fn func(a *A), opts ...Option) {
+	b.Helper()
+	a := NewT(b, opts...)
+	a.opts.Timer = b
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		fn(a)
+	}
+}
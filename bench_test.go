@@ -0,0 +1,72 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"testing"
+)
+
+// Run benchmarks with `go test -run '^$' -bench . -benchmem`.
+// Passing assertions should not allocate, which is verified by TestAllocs.
+
+func TestAllocs(t *testing.T) {
+	a := New(t)
+	x, y := 1, 2
+	s := "go-assert"
+	var err error
+
+	cases := map[string]func(){
+		"Assert":      func() { a.Assert(x < y) },
+		"EqualInt":    func() { a.Equal(x, 1) },
+		"EqualString": func() { a.Equal(s, "go-assert") },
+		"NotEqual":    func() { a.NotEqual(x, y) },
+		"NilError":    func() { a.NilError(err) },
+	}
+
+	for name, fn := range cases {
+		if allocs := testing.AllocsPerRun(100, fn); allocs > 0 {
+			t.Errorf("%v: passing assertion should not allocate. [allocs:%v]", name, allocs)
+		}
+	}
+}
+
+func BenchmarkAAssert(b *testing.B) {
+	b.ReportAllocs()
+	a := NewT(b)
+	x, y := 1, 2
+
+	for i := 0; i < b.N; i++ {
+		a.Assert(x < y)
+	}
+}
+
+func BenchmarkAEqualInt(b *testing.B) {
+	b.ReportAllocs()
+	a := NewT(b)
+	x := 12
+
+	for i := 0; i < b.N; i++ {
+		a.Equal(x, 12)
+	}
+}
+
+func BenchmarkAEqualString(b *testing.B) {
+	b.ReportAllocs()
+	a := NewT(b)
+	s := "go-assert"
+
+	for i := 0; i < b.N; i++ {
+		a.Equal(s, "go-assert")
+	}
+}
+
+func BenchmarkANilError(b *testing.B) {
+	b.ReportAllocs()
+	a := NewT(b)
+	var err error
+
+	for i := 0; i < b.N; i++ {
+		a.NilError(err)
+	}
+}
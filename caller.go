@@ -0,0 +1,48 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// CallerInfo is the structured information of a call to an assertion function.
+// It contains source of selected args, assignments of variables referenced by args,
+// related variables, loop variables and the stable assertion ID.
+type CallerInfo = assertion.Info
+
+// ParseCallerInfo parses the source code calling a customized assertion function named name
+// and returns the structured information of args at argIndex.
+// It's the way for authors of customized assertions to print the same context as built-in ones.
+//
+// The skip is the number of stack frames to skip.
+// If skip is 0, the call to name at the line calling ParseCallerInfo is parsed.
+// In most cases, an assertion function calls ParseCallerInfo directly and sets skip to 1.
+// Negative indexes in argIndex count from the last arg.
+//
+// Sample code.
+//
+//     func AssertPositive(t *testing.T, v int) {
+//         if v > 0 {
+//             return
+//         }
+//
+//         info, err := assert.ParseCallerInfo("AssertPositive", 1, []int{1})
+//
+//         if err != nil {
+//             t.Fatalf("fail to parse caller: %v", err)
+//         }
+//
+//         t.Fatalf("%v should be positive.\n%v", info.Args[0], strings.Join(info.Assignments[0], "\n"))
+//     }
+func ParseCallerInfo(name string, skip int, argIndex []int) (*CallerInfo, error) {
+	p := new(assertion.Parser)
+	f, err := p.ParseArgs(name, skip+1, argIndex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p.ParseInfo(f), nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"testing"
+)
+
+func parsePositive(v int) (*CallerInfo, error) {
+	return ParseCallerInfo("parsePositive", 1, []int{0})
+}
+
+func TestParseCallerInfo(t *testing.T) {
+	a := New(t)
+	width := 3
+	n := width - 4
+	info, err := parsePositive(n)
+
+	// Should pass.
+	a.NilError(err)
+	a.Equal(info.Args, []string{"n"})
+	a.Equal(info.Assignments, [][]string{{"n := width - 4"}})
+	a.Equal(info.RelatedVars, []string{"width"})
+	a.Equal(info.Source, "parsePositive(n)")
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"reflect"
+
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// RegisterCanonical registers canonical to convert values of a container type C,
+// e.g. an ordered map in a third-party package, to a canonical form R like a plain map or a slice.
+// Call remove to unregister it.
+//
+// After registration, wherever values of C appear, Equal and NotEqual compare their canonical forms
+// and failure messages dump and diff their canonical forms instead of container internals.
+// As keys of maps are always sorted in dumps, a map-like container converted to a map
+// is printed in the same way between runs and its differences are reported by keys
+// regardless of iteration order.
+//
+// The R must not be C.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         assert.RegisterCanonical(func(om *orderedmap.OrderedMap[string, int]) map[string]int {
+//             m := map[string]int{}
+//
+//             for pair := om.Oldest(); pair != nil; pair = pair.Next() {
+//                 m[pair.Key] = pair.Value
+//             }
+//
+//             return m
+//         })
+//         os.Exit(m.Run())
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.Equal(counts, expected)
+//     The value of following expression should equal.
+//     [1] counts
+//     [2] expected
+//     Differences:
+//     Different values:
+//         ["bar"]:
+//             [1] -> (int)2
+//             [2] -> (int)3
+func RegisterCanonical[C, R any](canonical func(c C) R) (remove func()) {
+	return assertion.AddComparator(assertion.Comparator{
+		Type: reflect.TypeOf((*C)(nil)).Elem(),
+		Canonical: func(v reflect.Value) interface{} {
+			return canonical(valueAs[C](v))
+		},
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"testing"
+)
+
+// defaultCaseName is the name of test case var when it cannot be parsed from source.
+const defaultCaseName = "c"
+
+// Cases runs fn for every case in cases as a subtest of a.
+//
+// The subtest is named after the `Name` field of a case if it's a struct with a non-empty `Name` string field.
+// Otherwise, the subtest is named after the index of the case, e.g. "#3".
+//
+// The case is registered by `Use` automatically.
+// If any assertion inside fn fails, the full test case is printed in failure message.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         cases := []struct {
+//             Name   string
+//             Input  string
+//             Output int
+//         }{
+//             {"foo", "foo", 3},
+//             {"bar", "bar", 4},
+//         }
+//
+//         assert.Cases(a, cases, func(a *assert.A, c struct {
+//             Name   string
+//             Input  string
+//             Output int
+//         }) {
+//             a.Assert(len(c.Input) == c.Output)
+//         })
+//     }
+//
+// Output:
+//
+//     --- FAIL: TestSomething/bar
+//     Assertion failed:
+//         len(c.Input) == c.Output
+//     Test case:
+//         c = (struct { Name string; Input string; Output int }){Name:(string)bar Input:(string)bar Output:(int)4}
+func Cases[T any](a *A, cases []T, fn func(a *A, c T)) {
+	if a.T == nil {
+		a.t.Fatalf("go-assert: Cases requires A created by New")
+		return
+	}
+
+	a.Helper()
+	name := parseCaseName(a)
+
+	for i := range cases {
+		c := cases[i]
+
+		a.Run(caseTestName(i, c), func(t *testing.T) {
+			ca := a.Child(t)
+			ca.setVars(map[string]interface{}{name: &c})
+			ca.caseName = name
+			fn(ca, c)
+		})
+	}
+}
+
+// parseCaseName parses the source of the call to Cases and returns the name of
+// the case param in the fn literal.
+func parseCaseName(a *A) string {
+	f, err := a.parser.ParseArgs("Cases", 2, []int{2})
+
+	if err != nil || len(f.Args) == 0 {
+		return defaultCaseName
+	}
+
+	lit, ok := f.Args[0].(*ast.FuncLit)
+
+	if !ok || lit.Type.Params == nil {
+		return defaultCaseName
+	}
+
+	names := make([]string, 0, 2)
+
+	for _, field := range lit.Type.Params.List {
+		for _, n := range field.Names {
+			names = append(names, n.Name)
+		}
+	}
+
+	if len(names) < 2 || names[1] == "_" {
+		return defaultCaseName
+	}
+
+	return names[1]
+}
+
+func caseTestName(idx int, c interface{}) string {
+	v := reflect.ValueOf(c)
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Struct {
+		if field := v.FieldByName("Name"); field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+			return field.String()
+		}
+	}
+
+	return fmt.Sprintf("#%v", idx)
+}
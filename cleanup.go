@@ -0,0 +1,55 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Cleanup registers fn to be called after current test case completes, like `testing.T#Cleanup`.
+// Unlike the method of the embedded *testing.T, it works with A created by NewT if the T implements `Cleanup(func())`.
+// Otherwise, e.g. for A created by NewPanic, it reports an internal error, as fn would never be called.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         db := openTestDB()
+//         a.Cleanup(func() {
+//             db.Close()
+//         })
+//     }
+func (a *A) Cleanup(fn func()) {
+	a.t.Helper()
+	assertion.Cleanup(a.lifecycleT(), fn, a.trigger("Cleanup", nil))
+}
+
+// TempDir returns a new temporary directory of current test case, which is removed after the test case completes,
+// like `testing.T#TempDir`.
+// Unlike the method of the embedded *testing.T, it works with A created by NewT if the T implements
+// `TempDir() string` or `Cleanup(func())`.
+// Otherwise, e.g. for A created by NewPanic, it reports an internal error.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         path := filepath.Join(a.TempDir(), "config.json")
+//         a.NilError(os.WriteFile(path, []byte(`{}`), 0644))
+//     }
+func (a *A) TempDir() string {
+	a.t.Helper()
+	return assertion.TempDir(a.lifecycleT(), a.trigger("TempDir", nil))
+}
+
+// lifecycleT returns the T managing the lifecycle of current test case.
+// It's a.t unless a.t is a temporary T, e.g. an attempt in `A#Flaky`,
+// in which case the embedded *testing.T is used.
+func (a *A) lifecycleT() assertion.T {
+	if _, ok := a.t.(interface{ Cleanup(func()) }); !ok && a.T != nil {
+		return a.T
+	}
+
+	return a.t
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Command assertindex generates an index of assertions in test files of a package,
+// so that assertions don't parse source files at runtime.
+//
+// Add following line to any test file of a package and run `go generate`.
+//
+//     //go:generate go run github.com/huandu/go-assert/cmd/assertindex
+//
+// It scans all test files in current directory and writes the index to `assert_index_test.go`.
+//
+// Usage:
+//
+//     assertindex [-o output] [-funcs names] [-depth n] [dir]
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+func main() {
+	output := flag.String("o", "assert_index_test.go", "name of the generated file")
+	funcs := flag.String("funcs", "", "comma separated names of customized assert functions to index")
+	depth := flag.Int("depth", 1, "max number of hops to follow when finding assignments")
+	flag.Parse()
+
+	dir := "."
+
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	names := append([]string{}, assertion.DefaultIndexFuncs...)
+
+	for _, name := range strings.Split(*funcs, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if err := generate(dir, *output, names, *depth); err != nil {
+		fmt.Fprintf(os.Stderr, "assertindex: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(dir, output string, funcs []string, depth int) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(files)
+	pkg := ""
+	entries := make([]assertion.IndexEntry, 0)
+
+	for _, file := range files {
+		if filepath.Base(file) == output {
+			continue
+		}
+
+		src, err := os.ReadFile(file)
+
+		if err != nil {
+			return err
+		}
+
+		if pkg == "" {
+			f, err := parser.ParseFile(token.NewFileSet(), file, src, parser.PackageClauseOnly)
+
+			if err != nil {
+				return err
+			}
+
+			pkg = f.Name.Name
+		}
+
+		fileEntries, err := assertion.BuildIndex(file, src, funcs, depth)
+
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, fileEntries...)
+	}
+
+	if pkg == "" {
+		return fmt.Errorf("no test file in %v", dir)
+	}
+
+	code, err := format.Source(render(pkg, entries))
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, output), code, 0644)
+}
+
+// render returns the source of the generated file.
+func render(pkg string, entries []assertion.IndexEntry) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by assertindex. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "package %v\n\n", pkg)
+	fmt.Fprintf(buf, "import \"github.com/huandu/go-assert\"\n\n")
+	fmt.Fprintf(buf, "func init() {\n")
+	fmt.Fprintf(buf, "assert.RegisterIndex(\n")
+
+	for _, entry := range entries {
+		fmt.Fprintf(buf, "assert.IndexEntry{\n")
+		fmt.Fprintf(buf, "File: %v,\n", strconv.Quote(entry.File))
+		fmt.Fprintf(buf, "FuncName: %v,\n", strconv.Quote(entry.FuncName))
+		fmt.Fprintf(buf, "Line: %v,\n", entry.Line)
+		fmt.Fprintf(buf, "EndLine: %v,\n", entry.EndLine)
+		fmt.Fprintf(buf, "Source: %v,\n", strconv.Quote(entry.Source))
+		fmt.Fprintf(buf, "Args: []assert.IndexArg{\n")
+
+		for _, arg := range entry.Args {
+			fmt.Fprintf(buf, "{\n")
+			fmt.Fprintf(buf, "Source: %v,\n", strconv.Quote(arg.Source))
+
+			if len(arg.Assignments) > 0 {
+				fmt.Fprintf(buf, "Assignments: %v,\n", renderStrings(arg.Assignments))
+			}
+
+			if len(arg.RelatedVars) > 0 {
+				fmt.Fprintf(buf, "RelatedVars: %v,\n", renderStrings(arg.RelatedVars))
+			}
+
+			fmt.Fprintf(buf, "},\n")
+		}
+
+		fmt.Fprintf(buf, "},\n")
+
+		if len(entry.LoopVars) > 0 {
+			loopVars := make([]string, 0, len(entry.LoopVars))
+
+			for _, vars := range entry.LoopVars {
+				loopVars = append(loopVars, renderStrings(vars))
+			}
+
+			fmt.Fprintf(buf, "LoopVars: [][]string{%v},\n", strings.Join(loopVars, ", "))
+		}
+
+		fmt.Fprintf(buf, "},\n")
+	}
+
+	fmt.Fprintf(buf, ")\n")
+	fmt.Fprintf(buf, "}\n")
+	return buf.Bytes()
+}
+
+func renderStrings(strs []string) string {
+	quoted := make([]string, 0, len(strs))
+
+	for _, s := range strs {
+		quoted = append(quoted, strconv.Quote(s))
+	}
+
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
@@ -0,0 +1,30 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package debugassert provides C-style debug assertions for production code.
+//
+// By default, all assertions in this package are no-ops and Enabled is false.
+// Build with tag `assertdebug` to enable them.
+// An enabled assertion panics with the same message as package assert if it fails.
+//
+// Sample code.
+//
+//     import "github.com/huandu/go-assert/debugassert"
+//
+//     func (q *Queue) Pop() *Item {
+//         debugassert.Assert(q.head <= q.tail)
+//         // ...
+//     }
+//
+// Build with `go build -tags assertdebug` and the panic message is:
+//
+//     queue.go:10: Assertion failed:
+//         q.head <= q.tail
+//
+// Arguments are still evaluated when assertions are disabled.
+// Guard expensive expressions with Enabled so that they can be eliminated by compiler.
+//
+//     if debugassert.Enabled {
+//         debugassert.Assert(q.validate())
+//     }
+package debugassert
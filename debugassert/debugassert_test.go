@@ -0,0 +1,51 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package debugassert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssert(t *testing.T) {
+	x, y := 1, 2
+
+	Assert(x < y)
+	Equal([]int{x, y}, []int{1, 2})
+
+	msg := catchPanic(func() {
+		Assert(x > y)
+	})
+
+	if !Enabled {
+		if msg != "" {
+			t.Fatalf("disabled assertion should not panic. [msg:%v]", msg)
+		}
+
+		return
+	}
+
+	if !strings.Contains(msg, "Assertion failed:\n    x > y") {
+		t.Fatalf("unexpected panic message. [msg:%v]", msg)
+	}
+
+	msg = catchPanic(func() {
+		Equal(x, y)
+	})
+
+	if !strings.Contains(msg, "Equal(x, y)") {
+		t.Fatalf("unexpected panic message. [msg:%v]", msg)
+	}
+}
+
+func catchPanic(f func()) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = r.(error).Error()
+		}
+	}()
+
+	f()
+	return
+}
@@ -0,0 +1,16 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build !assertdebug
+// +build !assertdebug
+
+package debugassert
+
+// Enabled is true if assertions are enabled by build tag `assertdebug`.
+const Enabled = false
+
+// Assert does nothing unless build tag `assertdebug` is set.
+func Assert(expr interface{}) {}
+
+// Equal does nothing unless build tag `assertdebug` is set.
+func Equal(v1, v2 interface{}) {}
@@ -0,0 +1,42 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build assertdebug
+// +build assertdebug
+
+package debugassert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Enabled is true if assertions are enabled by build tag `assertdebug`.
+const Enabled = true
+
+// Assert tests expr and panics if expr is false-equivalent value.
+// `false`, 0, nil and empty string are false-equivalent values.
+func Assert(expr interface{}) {
+	assertion.Assert(newT(), expr, newTrigger("Assert", []int{0}))
+}
+
+// Equal uses `reflect.DeepEqual` to test v1 and v2 equality and panics if they are not equal.
+func Equal(v1, v2 interface{}) {
+	assertion.AssertEqual(newT(), v1, v2, newTrigger("Equal", []int{0, 1}))
+}
+
+func newT() assertion.T {
+	return assertion.NewPanicT("debugassert")
+}
+
+func newTrigger(funcName string, args []int) *assertion.Trigger {
+	color := false
+	return &assertion.Trigger{
+		FuncName: funcName,
+		Skip:     1,
+		Args:     args,
+		Options: assertion.Options{
+			Color:     &color,
+			SkipHooks: true,
+		},
+	}
+}
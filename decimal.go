@@ -0,0 +1,65 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"reflect"
+
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// RegisterDecimal registers cmp and format for a third-party decimal type D,
+// e.g. `decimal.Decimal` in github.com/shopspring/decimal or a currency amount type.
+// Call remove to unregister them.
+//
+// After registration, wherever values of D appear, e.g. in struct fields, slices or maps,
+// Equal and NotEqual compare them by cmp instead of `reflect.DeepEqual`
+// and failure messages print them by format instead of dumping their internals.
+// The cmp returns 0 if a equals b. Either cmp or format can be nil to keep the default behavior.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         assert.RegisterDecimal(decimal.Decimal.Cmp, decimal.Decimal.String)
+//         os.Exit(m.Run())
+//     }
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.Equal(decimal.RequireFromString("1.50"), decimal.RequireFromString("1.5")) // Should pass.
+//         a.Equal(Order{ID: 1, Total: decimal.RequireFromString("1.50")}, Order{ID: 1, Total: decimal.RequireFromString("1.05")})
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.Equal(Order{ID: 1, Total: decimal.RequireFromString("1.50")}, Order{ID: 1, Total: decimal.RequireFromString("1.05")})
+//     The value of following expression should equal.
+//     [1] Order{ID: 1, Total: decimal.RequireFromString("1.50")}
+//     [2] Order{ID: 1, Total: decimal.RequireFromString("1.05")}
+//     Differences:
+//     Different values:
+//         .Total:
+//             [1] -> (decimal.Decimal)1.5
+//             [2] -> (decimal.Decimal)1.05
+//     (1 equal fields not shown)
+func RegisterDecimal[D any](cmp func(a, b D) int, format func(d D) string) (remove func()) {
+	c := assertion.Comparator{
+		Type: reflect.TypeOf((*D)(nil)).Elem(),
+	}
+
+	if cmp != nil {
+		c.Compare = func(a, b reflect.Value) int {
+			return cmp(valueAs[D](a), valueAs[D](b))
+		}
+	}
+
+	if format != nil {
+		c.Format = func(v reflect.Value) string {
+			return format(valueAs[D](v))
+		}
+	}
+
+	return assertion.AddComparator(c)
+}
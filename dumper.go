@@ -0,0 +1,56 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"time"
+
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Dumper formats values in failure messages.
+type Dumper = assertion.Dumper
+
+// DumperFunc is a func implementing Dumper.
+type DumperFunc = assertion.DumperFunc
+
+// SetDumper replaces the dumper used to format values in failure messages.
+// Set d to nil to restore the default dumper, which is backed by spew.
+// The default dumper prints a time.Time in RFC3339 format and a time.Duration like "1m30s"
+// instead of their struct internals.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         assert.SetDumper(assert.DumperFunc(func(v interface{}) string {
+//             return litter.Sdump(v)
+//         }))
+//         os.Exit(m.Run())
+//     }
+func SetDumper(d Dumper) {
+	assertion.SetDumper(d)
+}
+
+// HintTimestamp marks integer struct fields matching pattern as timestamps since Unix epoch in unit,
+// so that the default dumper prints their time in RFC3339 format after their values.
+// The pattern is matched against field names by `path.Match`, e.g. "CreatedAt" or "*At".
+// If unit is not positive, `time.Second` is used.
+// Call remove to unregister the hint.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         assert.HintTimestamp("*AtMillis", time.Millisecond)
+//         os.Exit(m.Run())
+//     }
+//
+// A field `CreatedAtMillis int64` is dumped as following.
+//
+//     CreatedAtMillis:(int64)1704164645000 (2024-01-02T03:04:05Z)
+func HintTimestamp(pattern string, unit time.Duration) (remove func()) {
+	return assertion.AddTimestampHint(assertion.TimestampHint{
+		Field: pattern,
+		Unit:  unit,
+	})
+}
@@ -0,0 +1,20 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package expect provides assertion functions which report failures and let the test case continue.
+// Package require provides the same functions which stop the test case on failure.
+// Both are thin wrappers of package assert for teams used to the require/assert split in testify.
+// Failure messages are the same as package assert.
+//
+// Sample code.
+//
+//     import "github.com/huandu/go-assert/expect"
+//
+//     func TestSomething(t *testing.T) {
+//         user := loadUser()
+//         expect.Equal(t, user.Name, "example")
+//         expect.Assert(t, user.Age > 0) // Still checked if the name is wrong.
+//     }
+package expect
+
+//go:generate go run ../internal/genrequire -pkg expect
@@ -0,0 +1,35 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// T is the subset of `testing.TB` used by assertions.
+type T = assertion.T
+
+// FakeT is a fake T recording all calls and messages.
+// Unlike *testing.T, FakeT.Fatalf doesn't stop current goroutine.
+type FakeT = assertion.FakeT
+
+// FakeCall is a call to FakeT.
+type FakeCall = assertion.FakeCall
+
+// NewFakeT creates a FakeT with name.
+//
+// Sample code.
+//
+//     func TestMyAssertion(t *testing.T) {
+//         ft := assert.NewFakeT("TestFoo")
+//         a := assert.NewT(ft)
+//         a.Equal(1, 2)
+//
+//         if !ft.Fatal() || !strings.Contains(ft.Messages()[0], "should equal") {
+//             t.Fatalf("assertion should fail")
+//         }
+//     }
+func NewFakeT(name string) *FakeT {
+	return assertion.NewFakeT(name)
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// RegisterFalsy registers isFalse to treat some values of V as false-equivalent values in Assert,
+// e.g. a `sql.NullString` which is not valid or the zero value of a domain type.
+// Call remove to unregister it.
+//
+// The isFalse is called with every value of V tested by Assert.
+// If V is an interface type, it's called with every value implementing V.
+// Values of V which are not false-equivalent according to isFalse are true-equivalent,
+// even if they are zero or empty strings.
+// If there are several funcs registered for the same value, the last registered one is used.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         assert.RegisterFalsy(func(s sql.NullString) bool {
+//             return !s.Valid
+//         })
+//         os.Exit(m.Run())
+//     }
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         name := db.QueryName(id)
+//         a.Assert(name)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         name
+//     Referenced variables are assigned in following statements:
+//         name := db.QueryName(id)
+func RegisterFalsy[V any](isFalse func(v V) bool) (remove func()) {
+	return assertion.RegisterFalsy(func(v interface{}) (assertion.FalseKind, bool) {
+		val, ok := v.(V)
+
+		if !ok {
+			return assertion.Positive, false
+		}
+
+		if isFalse(val) {
+			return assertion.Falsy, true
+		}
+
+		return assertion.Positive, true
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"testing"
+
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+var typeOfA = reflect.TypeOf((*A)(nil))
+
+// Fuzz runs fn as the fuzz target of f.
+// The fn must be a func like `func(a *assert.A, s string, n int)`.
+// Params after a are generated by the fuzzing engine, which accepts same types as `f.Fuzz`.
+// Options customize all assertions made by the A passed to fn.
+//
+// Inputs are registered by `Use` automatically.
+// If any assertion inside fn fails, all inputs triggering the failure are printed in failure message.
+//
+// Sample code.
+//
+//     func FuzzPrefix(f *testing.F) {
+//         f.Add("go", 5)
+//
+//         assert.Fuzz(f, func(a *assert.A, s string, n int) {
+//             a.Assert(len(prefix(s, n)) == n)
+//         })
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         len(prefix(s, n)) == n
+//     Fuzz inputs:
+//         s = (string)go
+//         n = (int)5
+func Fuzz(f *testing.F, fn interface{}, opts ...Option) {
+	f.Helper()
+	fv := reflect.ValueOf(fn)
+
+	if fv.Kind() != reflect.Func {
+		f.Fatalf("go-assert: Fuzz requires fn like `func(a *assert.A, args ...)` but got %T", fn)
+		return
+	}
+
+	ft := fv.Type()
+
+	if ft.NumIn() == 0 || ft.In(0) != typeOfA || ft.NumOut() != 0 || ft.IsVariadic() {
+		f.Fatalf("go-assert: Fuzz requires fn like `func(a *assert.A, args ...)` but got %v", ft)
+		return
+	}
+
+	names := parseInputNames(ft.NumIn() - 1)
+	in := make([]reflect.Type, 0, ft.NumIn())
+	in = append(in, reflect.TypeOf((*testing.T)(nil)))
+
+	for i := 1; i < ft.NumIn(); i++ {
+		in = append(in, ft.In(i))
+	}
+
+	target := reflect.MakeFunc(reflect.FuncOf(in, nil, false), func(args []reflect.Value) []reflect.Value {
+		a := New(args[0].Interface().(*testing.T), opts...)
+		vars := make(map[string]interface{}, len(names))
+
+		for i, name := range names {
+			ptr := reflect.New(ft.In(i + 1))
+			ptr.Elem().Set(args[i+1])
+			vars[name] = ptr.Interface()
+		}
+
+		a.setVars(vars)
+		a.opts.Inputs = names
+
+		args[0] = reflect.ValueOf(a)
+		fv.Call(args)
+		return nil
+	})
+
+	f.Fuzz(target.Interface())
+}
+
+// parseInputNames parses the source of the call to Fuzz and returns names of the input params
+// in the fn literal. If a name cannot be parsed, it's named after its index, e.g. "in1".
+func parseInputNames(n int) []string {
+	names := make([]string, n)
+
+	for i := range names {
+		names[i] = fmt.Sprintf("in%v", i)
+	}
+
+	fn, err := new(assertion.Parser).ParseArgs("Fuzz", 2, []int{1})
+
+	if err != nil || len(fn.Args) == 0 {
+		return names
+	}
+
+	lit, ok := fn.Args[0].(*ast.FuncLit)
+
+	if !ok || lit.Type.Params == nil {
+		return names
+	}
+
+	params := make([]string, 0, n+1)
+
+	for _, field := range lit.Type.Params.List {
+		for _, name := range field.Names {
+			params = append(params, name.Name)
+		}
+	}
+
+	if len(params) != n+1 {
+		return names
+	}
+
+	for i, name := range params[1:] {
+		if name != "_" {
+			names[i] = name
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// GinkgoFail is the signature of `ginkgo.Fail` in github.com/onsi/ginkgo.
+type GinkgoFail = assertion.GinkgoFail
+
+// GomegaMatcher is the interface of matchers in github.com/onsi/gomega, i.e. `types.GomegaMatcher`.
+type GomegaMatcher = assertion.GomegaMatcher
+
+// GinkgoT returns a T reporting failures to Ginkgo by calling fail, which is usually `ginkgo.Fail`.
+// Pass it to NewT to use assertions in Ginkgo specs.
+// Ginkgo reports failures at assertion calls in specs instead of lines in this package.
+//
+// Ginkgo has no non-fatal failure. A failure always stops the spec, even if WithFatal(false) is set.
+//
+// Sample code.
+//
+//     var _ = Describe("Parser", func() {
+//         It("parses numbers", func() {
+//             a := assert.NewT(assert.GinkgoT(Fail))
+//             n, err := Parse("42")
+//             a.NilError(err)
+//             a.Equal(n, 42)
+//         })
+//     })
+func GinkgoT(fail GinkgoFail) T {
+	return assertion.NewGinkgoT(fail)
+}
+
+// Gomega matches a value matching the Gomega matcher m.
+// It can be composed with other matchers by Not, AnyOf and AllOf.
+// The failure message of m explains the result in failure message.
+//
+// Sample code.
+//
+//     a.That(names, assert.AnyOf(
+//         assert.Gomega(gomega.BeEmpty()),
+//         assert.Gomega(gomega.ContainElement("admin")),
+//     ))
+func Gomega(m GomegaMatcher) Matcher {
+	return assertion.MatchGomega(m)
+}
@@ -1,5 +1,7 @@
 module github.com/huandu/go-assert
 
-go 1.13
+go 1.18
 
 require github.com/davecgh/go-spew v1.1.1
+
+require golang.org/x/text v0.22.0
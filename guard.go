@@ -0,0 +1,79 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Guard captures panics in goroutines spawned by a test case
+// and reports them as assertion failures in the test goroutine.
+// It's created by `A#Guard`.
+type Guard struct {
+	t     assertion.T
+	guard *assertion.Guard
+}
+
+// Guard creates a Guard to capture panics in goroutines spawned by the test case.
+// A panic in a goroutine crashes the whole test binary without running other test cases.
+// With a Guard, the panic is recovered and reported as an assertion failure with the panic stack.
+//
+// Captured panics are reported when `Guard#Check` is called
+// or after the test case and all its subtests complete.
+// Wait for all goroutines to return before the test case completes,
+// otherwise panics after that are recovered but never reported.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         done := a.Guard()
+//         var wg sync.WaitGroup
+//         wg.Add(1)
+//
+//         go func() {
+//             defer wg.Done()
+//             defer done.Recover()
+//             process(nil)
+//         }()
+//
+//         wg.Wait()
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.Guard()
+//     A goroutine guarded by following guard panicked with:
+//         (runtime.boundsError)runtime error: index out of range [0] with length 0
+//     Panic stack:
+//         goroutine 7 [running]:
+//         main.process(...)
+//             /path/to/main.go:12
+//         ...
+func (a *A) Guard() *Guard {
+	return &Guard{
+		t:     a.t,
+		guard: assertion.NewGuard(a.t, a.trigger("Guard", nil)),
+	}
+}
+
+// Recover recovers a panic and records it with the panic stack.
+// It must be deferred directly in a goroutine, e.g. `defer g.Recover()`,
+// as `recover` only works in the deferred func.
+func (g *Guard) Recover() {
+	if r := recover(); r != nil {
+		g.guard.Add(r)
+	}
+}
+
+// Check reports all panics captured since last check as assertion failures.
+// It must be called in the test goroutine.
+//
+// Check is called automatically after the test case completes.
+// Call it explicitly to report panics earlier, e.g. right after waiting for goroutines.
+func (g *Guard) Check() {
+	g.t.Helper()
+	g.guard.Check()
+}
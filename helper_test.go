@@ -0,0 +1,217 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// helperT is a FakeT recording locations of failures in the same way as *testing.T,
+// which skips functions marked by Helper.
+type helperT struct {
+	*FakeT
+
+	m         sync.Mutex
+	helpers   map[string]bool
+	locations []string
+}
+
+func newHelperT(name string) *helperT {
+	return &helperT{
+		FakeT:   NewFakeT(name),
+		helpers: map[string]bool{},
+	}
+}
+
+func (ht *helperT) Helper() {
+	var pc [1]uintptr
+	runtime.Callers(2, pc[:])
+	frame, _ := runtime.CallersFrames(pc[:]).Next()
+
+	ht.m.Lock()
+	defer ht.m.Unlock()
+	ht.helpers[frame.Function] = true
+}
+
+func (ht *helperT) Errorf(format string, args ...interface{}) {
+	ht.locate()
+	ht.FakeT.Errorf(format, args...)
+}
+
+func (ht *helperT) Fatalf(format string, args ...interface{}) {
+	ht.locate()
+	ht.FakeT.Fatalf(format, args...)
+}
+
+func (ht *helperT) Logf(format string, args ...interface{}) {
+	ht.locate()
+	ht.FakeT.Logf(format, args...)
+}
+
+func (ht *helperT) Skipf(format string, args ...interface{}) {
+	ht.locate()
+	ht.FakeT.Skipf(format, args...)
+}
+
+// locate records the first caller of ht which is not a helper.
+func (ht *helperT) locate() {
+	var pcs [64]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	ht.m.Lock()
+	defer ht.m.Unlock()
+
+	for {
+		frame, more := frames.Next()
+
+		if !ht.helpers[frame.Function] || !more {
+			ht.locations = append(ht.locations, fmt.Sprintf("%v %v", filepath.Base(frame.File), frame.Function))
+			return
+		}
+	}
+}
+
+func (ht *helperT) Locations() []string {
+	ht.m.Lock()
+	defer ht.m.Unlock()
+	return append([]string(nil), ht.locations...)
+}
+
+func TestHelper(t *testing.T) {
+	cases := map[string]func(a *A){
+		"Assert":           func(a *A) { a.Assert(1 > 2) },
+		"AssertAll":        func(a *A) { a.AssertAll(true, false) },
+		"NilError":         func(a *A) { a.NilError(1, errors.New("err")) },
+		"NonNilError":      func(a *A) { a.NonNilError(1, nil) },
+		"NoError":          func(a *A) { a.NoError(errors.New("err")) },
+		"Error":            func(a *A) { a.Error(nil) },
+		"Equal":            func(a *A) { a.Equal(1, 2) },
+		"NotEqual":         func(a *A) { a.NotEqual(1, 1) },
+		"PointerEqual":     func(a *A) { a.PointerEqual(&struct{ N int }{1}, &struct{ N int }{2}) },
+		"BigEqual":         func(a *A) { a.BigEqual(1, 2) },
+		"AllInDelta":       func(a *A) { a.AllInDelta([]float64{1}, []float64{2}, 0.1) },
+		"MeanInDelta":      func(a *A) { a.MeanInDelta([]float64{1}, 2, 0.1) },
+		"PercentileUnder":  func(a *A) { a.PercentileUnder([]float64{1}, 0.5, 0.1) },
+		"That":             func(a *A) { a.That(1, EqualTo(2)) },
+		"Satisfies":        func(a *A) { a.Satisfies(1, func(v int) bool { return v > 1 }) },
+		"Invariant":        func(a *A) { a.Invariant(1, "positive", func(v int) bool { return v < 0 }) },
+		"ContainsFunc":     func(a *A) { a.ContainsFunc([]int{1}, func(v int) bool { return v > 1 }) },
+		"All":              func(a *A) { a.All([]int{1}, func(v int) bool { return v > 1 }) },
+		"AnyMatch":         func(a *A) { a.AnyMatch([]int{1}, func(v int) bool { return v > 1 }) },
+		"Ordered":          func(a *A) { a.Ordered([]int{2, 1}, func(v int) int { return v }) },
+		"ContainsAll":      func(a *A) { a.ContainsAll("foo", "bar") },
+		"HasKey":           func(a *A) { a.HasKey(map[string]int{}, "foo") },
+		"Completes":        func(a *A) { a.Completes(make(chan struct{}), time.Millisecond) },
+		"Eventually":       func(a *A) { a.Eventually(func() bool { return false }, time.Millisecond, time.Millisecond) },
+		"EventuallyCtx":    func(a *A) { a.EventuallyCtx(canceledContext(), func() bool { return false }, time.Millisecond) },
+		"ReceivesCtx":      func(a *A) { a.ReceivesCtx(canceledContext(), make(chan int)) },
+		"DurationLess":     func(a *A) { a.DurationLess(time.Second, time.Millisecond) },
+		"TookLess":         func(a *A) { a.TookLess(-1, func() {}) },
+		"Type":             func(a *A) { Type[string](a, 1) },
+		"Grouped":          func(a *A) { a.Grouped("step", func(a *A) { a.Equal(1, 2) }) },
+		"With":             func(a *A) { a.With(IgnoreUnexported()).Equal(1, 2) },
+		"Flaky":            func(a *A) { a.Flaky(2, func(a *A) { a.Equal(1, 2) }) },
+		"CounterIncreased": func(a *A) { a.CounterIncreasedBy(nil, "TestHelper", 1, func() {}) },
+		"Note":             func(a *A) { a.Note(1) },
+		"Cleanup":          func(a *A) { a.Cleanup(func() {}) },
+		"TempDir":          func(a *A) { a.TempDir() },
+		"HTTPServer": func(a *A) {
+			srv := a.HTTPServer(http.NotFoundHandler())
+			defer srv.Close()
+			srv.Get("/").AssertStatus(http.StatusOK)
+		},
+		"Guard": func(a *A) {
+			g := a.Guard()
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				defer g.Recover()
+				panic("oops")
+			}()
+			<-done
+			g.Check()
+		},
+	}
+
+	for name, fn := range cases {
+		ht := newHelperT("TestHelper/" + name)
+		fn(NewT(ht, WithFatal(false)))
+		locations := ht.Locations()
+
+		if len(locations) == 0 {
+			t.Fatalf("%v: there should be a failure.", name)
+		}
+
+		for _, loc := range locations {
+			if !strings.HasPrefix(loc, "helper_test.go ") {
+				t.Errorf("%v: failure should be reported at the caller of assertion. [location:%v]", name, loc)
+			}
+		}
+	}
+}
+
+// TestHelperLocation runs failing assertions with a real *testing.T in a subprocess
+// and expects testing to report failures at lines in this file.
+func TestHelperLocation(t *testing.T) {
+	const env = "GO_ASSERT_TEST_HELPER_LOCATION"
+
+	if os.Getenv(env) != "" {
+		// Prints the line of next statement, which is expected in the location of the failure.
+		want := func() {
+			_, _, line, _ := runtime.Caller(1)
+			fmt.Printf("want helper_test.go:%v\n", line+1)
+		}
+		a := New(t, WithFatal(false))
+
+		want()
+		a.Equal(1, 2)
+		want()
+		a.With(IgnoreUnexported()).NilError(1, errors.New("err"))
+		want()
+		a.ContainsAll("foo", "bar")
+		a.Grouped("step", func(a *A) {
+			want()
+			a.Assert(1 > 2)
+		})
+		want()
+		Equal(t, 1, 2)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperLocation$")
+	cmd.Env = append(os.Environ(), env+"=1")
+	out, _ := cmd.CombinedOutput()
+	output := string(out)
+	wants := regexp.MustCompile(`(?m)^want (\S+)$`).FindAllStringSubmatch(output, -1)
+
+	if len(wants) != 5 {
+		t.Fatalf("all samples should run. [output:%v]", output)
+	}
+
+	for _, want := range wants {
+		// Locations reported by testing are indented by 4 spaces, while lines of messages are indented by 8 spaces.
+		if !regexp.MustCompile(`(?m)^    ` + regexp.QuoteMeta(want[1]) + `: `).MatchString(output) {
+			t.Errorf("failure should be reported at %v. [output:%v]", want[1], output)
+		}
+	}
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
@@ -0,0 +1,30 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Failure represents an assertion failure.
+// It's passed to hooks registered by OnFailure.
+type Failure = assertion.Failure
+
+// OnFailure registers a hook which is called on every assertion failure
+// before the test case is terminated.
+// Call remove to unregister the hook.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         remove := assert.OnFailure(func(f *assert.Failure) {
+//             log.Printf("%v failed at %v:%v", f.TestName, f.Filename, f.Line)
+//         })
+//         code := m.Run()
+//         remove()
+//         os.Exit(code)
+//     }
+func OnFailure(hook func(f *Failure)) (remove func()) {
+	return assertion.AddFailureHook(hook)
+}
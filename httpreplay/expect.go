@@ -0,0 +1,198 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package httpreplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// BodyMatcher matches the body of a request.
+type BodyMatcher interface {
+	Match(body string) bool
+	String() string
+}
+
+type bodyMatcher struct {
+	desc  string
+	match func(body string) bool
+}
+
+func (m *bodyMatcher) Match(body string) bool { return m.match(body) }
+func (m *bodyMatcher) String() string         { return m.desc }
+
+// BodyEqual matches a body equal to s.
+func BodyEqual(s string) BodyMatcher {
+	return &bodyMatcher{
+		desc: fmt.Sprintf("%q", s),
+		match: func(body string) bool {
+			return body == s
+		},
+	}
+}
+
+// BodyContains matches a body containing s.
+func BodyContains(s string) BodyMatcher {
+	return &bodyMatcher{
+		desc: fmt.Sprintf("containing %q", s),
+		match: func(body string) bool {
+			return strings.Contains(body, s)
+		},
+	}
+}
+
+// BodyJSON matches a body which is a JSON semantically equal to s.
+// Spaces and the order of object keys are ignored.
+func BodyJSON(s string) BodyMatcher {
+	var expected interface{}
+	err := json.Unmarshal([]byte(s), &expected)
+
+	return &bodyMatcher{
+		desc: fmt.Sprintf("JSON %v", s),
+		match: func(body string) bool {
+			if err != nil {
+				return false
+			}
+
+			var actual interface{}
+
+			if json.Unmarshal([]byte(body), &actual) != nil {
+				return false
+			}
+
+			return reflect.DeepEqual(expected, actual)
+		},
+	}
+}
+
+// Expectation is an expected request.
+type Expectation struct {
+	Method string
+	Path   string      // URL path. If it contains "?", the query is matched as well.
+	Body   BodyMatcher // If it's nil, any body matches.
+}
+
+// Expect creates an expectation of a request with method and path.
+func Expect(method, path string) Expectation {
+	return Expectation{
+		Method: method,
+		Path:   path,
+	}
+}
+
+// WithBody returns a copy of e expecting a body matching m.
+func (e Expectation) WithBody(m BodyMatcher) Expectation {
+	e.Body = m
+	return e
+}
+
+// Match returns true if req meets e.
+func (e Expectation) Match(req Request) bool {
+	if !strings.EqualFold(e.Method, req.Method) {
+		return false
+	}
+
+	u, err := url.Parse(req.URL)
+
+	if err != nil {
+		return false
+	}
+
+	path := u.Path
+
+	if strings.Contains(e.Path, "?") {
+		path = u.RequestURI()
+	}
+
+	if path != e.Path {
+		return false
+	}
+
+	return e.Body == nil || e.Body.Match(req.Body)
+}
+
+// String returns the description of e.
+func (e Expectation) String() string {
+	if e.Body == nil {
+		return strings.ToUpper(e.Method) + " " + e.Path
+	}
+
+	return fmt.Sprintf("%v %v body %v", strings.ToUpper(e.Method), e.Path, e.Body)
+}
+
+// AssertRequests expects requests issued through r match expected in order.
+// Otherwise, it will terminate the test case using `t.Fatalf` with differences
+// between expected and issued requests.
+func (r *Recorder) AssertRequests(expected ...Expectation) {
+	requests := r.Requests()
+	expectedDescs := make([]string, 0, len(expected))
+	issuedDescs := make([]string, 0, len(requests))
+
+	for _, e := range expected {
+		expectedDescs = append(expectedDescs, e.String())
+	}
+
+	for i, req := range requests {
+		issuedDescs = append(issuedDescs, describeRequest(expected, i, req))
+	}
+
+	if reflect.DeepEqual(expectedDescs, issuedDescs) {
+		return
+	}
+
+	trigger := &assertion.Trigger{
+		FuncName: "AssertRequests",
+		Args:     []int{0},
+	}
+	f, err := trigger.P().ParseArgs(trigger.FuncName, 1, trigger.Args)
+
+	if err != nil {
+		r.t.Fatalf("%v", fmt.Sprintf(assertion.CurrentMessages().InternalErrorFormat, err))
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := assertion.CurrentMessages()
+	diff := assertion.FormatDiff(expectedDescs, issuedDescs)
+
+	if diff == "" {
+		diff = fmt.Sprintf("%v\n[1] -> %#v\n[2] -> %#v", msgs.Values, expectedDescs, issuedDescs)
+	}
+
+	assertion.Fail(r.t, &assertion.Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n%v",
+			f.Filename, f.Line, msgs.AssertionFailed,
+			strings.Replace(info.Source, "\n", "\n    ", -1),
+			msgs.ShouldIssueRequests, diff,
+		),
+		Values: []string{strings.Join(expectedDescs, "\n"), strings.Join(issuedDescs, "\n")},
+	})
+}
+
+// describeRequest returns the description of the expectation matched by req.
+// The expectation at the same index is preferred.
+// If no expectation matches req, the description of req is returned.
+func describeRequest(expected []Expectation, idx int, req Request) string {
+	if idx < len(expected) && expected[idx].Match(req) {
+		return expected[idx].String()
+	}
+
+	for _, e := range expected {
+		if e.Match(req) {
+			return e.String()
+		}
+	}
+
+	return req.String()
+}
@@ -0,0 +1,294 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package httpreplay records HTTP interactions to cassettes in testdata,
+// replays them in tests and asserts requests issued by code under test.
+//
+// By default, a Recorder replays interactions in the cassette.
+// Set env GO_ASSERT_HTTPREPLAY to "record" to send requests to real servers
+// and write interactions to the cassette.
+//
+// Sample code.
+//
+//     func TestGetUser(t *testing.T) {
+//         r := httpreplay.New(t, "get-user")
+//         client := NewClient(r.Client())
+//         client.GetUser(1)
+//
+//         r.AssertRequests(
+//             httpreplay.Expect("GET", "/users/1"),
+//         )
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         r.AssertRequests(
+//             httpreplay.Expect("GET", "/users/1"),
+//         )
+//     Issued requests should match expectations. [1] is expected and [2] is issued.
+//     Differences:
+//     Different values:
+//         [0]:
+//             [1] -> (string)GET /users/1
+//             [2] -> (string)GET https://api.example.com/users/2
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// EnvMode is the env to set the mode of all recorders.
+// The value can be "record" or "replay".
+const EnvMode = "GO_ASSERT_HTTPREPLAY"
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+// All supported modes.
+const (
+	ModeReplay Mode = iota // Replay interactions in cassette.
+	ModeRecord             // Send requests to real servers and record interactions.
+)
+
+// Cassette is a list of recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+// Request headers are not recorded as they may contain credentials.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is a http.RoundTripper recording or replaying interactions.
+type Recorder struct {
+	// Transport sends requests to real servers in record mode.
+	// If it's nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	t    *testing.T
+	path string
+	mode Mode
+
+	m        sync.Mutex
+	cassette Cassette
+	used     []bool
+	requests []Request
+}
+
+// New creates a recorder with cassette file testdata/name.json.
+// The mode is set by env GO_ASSERT_HTTPREPLAY. It's ModeReplay by default.
+//
+// In replay mode, the cassette must exist. Otherwise, the test case is terminated.
+// In record mode, the cassette is written when the test case and all its subtests complete.
+func New(t *testing.T, name string) *Recorder {
+	mode := ModeReplay
+
+	if strings.EqualFold(os.Getenv(EnvMode), "record") {
+		mode = ModeRecord
+	}
+
+	return NewWithMode(t, name, mode)
+}
+
+// NewWithMode creates a recorder with cassette file testdata/name.json in mode.
+func NewWithMode(t *testing.T, name string, mode Mode) *Recorder {
+	r := &Recorder{
+		t:    t,
+		path: filepath.Join("testdata", name+".json"),
+		mode: mode,
+	}
+
+	if mode == ModeRecord {
+		t.Cleanup(r.save)
+		return r
+	}
+
+	data, err := os.ReadFile(r.path)
+
+	if err != nil {
+		t.Fatalf("httpreplay: fail to read cassette %v: %v (set env %v=record to record it)", r.path, err, EnvMode)
+		return r
+	}
+
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		t.Fatalf("httpreplay: fail to parse cassette %v: %v", r.path, err)
+		return r
+	}
+
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r
+}
+
+// Client returns a http client using r as transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{
+		Transport: r,
+	}
+}
+
+// Requests returns all requests issued through r in order.
+func (r *Recorder) Requests() []Request {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	requests := make([]Request, len(r.requests))
+	copy(requests, r.requests)
+	return requests
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request := Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Body:   body,
+	}
+
+	r.m.Lock()
+	r.requests = append(r.requests, request)
+	r.m.Unlock()
+
+	if r.mode == ModeRecord {
+		return r.record(req, request)
+	}
+
+	return r.replay(req, request)
+}
+
+func (r *Recorder) record(req *http.Request, request Request) (*http.Response, error) {
+	transport := r.Transport
+
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readBody(&resp.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: request,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       body,
+		},
+	})
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, request Request) (*http.Response, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.Request != request {
+			continue
+		}
+
+		r.used[i] = true
+		resp := interaction.Response
+		return &http.Response{
+			Status:        fmt.Sprintf("%v %v", resp.StatusCode, http.StatusText(resp.StatusCode)),
+			StatusCode:    resp.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        resp.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(resp.Body)),
+			ContentLength: int64(len(resp.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("httpreplay: no interaction in cassette %v matches request %v", r.path, request)
+}
+
+func (r *Recorder) save() {
+	r.m.Lock()
+	data, err := json.MarshalIndent(&r.cassette, "", "  ")
+	r.m.Unlock()
+
+	if err != nil {
+		r.t.Errorf("httpreplay: fail to encode cassette %v: %v", r.path, err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		r.t.Errorf("httpreplay: fail to create dir for cassette %v: %v", r.path, err)
+		return
+	}
+
+	if err := os.WriteFile(r.path, append(data, '\n'), 0644); err != nil {
+		r.t.Errorf("httpreplay: fail to write cassette %v: %v", r.path, err)
+	}
+}
+
+// String returns the method, URL and body of req.
+func (req Request) String() string {
+	if req.Body == "" {
+		return req.Method + " " + req.URL
+	}
+
+	return fmt.Sprintf("%v %v body %q", req.Method, req.URL, req.Body)
+}
+
+// readBody reads all content in body and replaces body with a reader of the content.
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil || *body == http.NoBody {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+
+	if err != nil {
+		return "", err
+	}
+
+	*body = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package httpreplay
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		w.Header().Set("X-Test", "yes")
+		io.WriteString(w, req.Method+" "+req.URL.Path+" "+string(body))
+	}))
+	defer server.Close()
+
+	const name = "record-and-replay"
+	os.Remove(filepath.Join("testdata", name+".json"))
+	defer os.Remove("testdata") // Remove the dir only if it's empty.
+	defer os.Remove(filepath.Join("testdata", name+".json"))
+
+	t.Run("record", func(t *testing.T) {
+		r := NewWithMode(t, name, ModeRecord)
+		get(t, r.Client(), server.URL+"/users/1")
+		post(t, r.Client(), server.URL+"/users", `{"name":"foo"}`)
+
+		r.AssertRequests(
+			Expect("GET", "/users/1"),
+			Expect("POST", "/users").WithBody(BodyJSON(`{ "name": "foo" }`)),
+		)
+	})
+
+	// Server is closed. All responses must come from cassette.
+	server.Close()
+
+	t.Run("replay", func(t *testing.T) {
+		r := NewWithMode(t, name, ModeReplay)
+		post(t, r.Client(), server.URL+"/users", `{"name":"foo"}`)
+
+		if body := get(t, r.Client(), server.URL+"/users/1"); body != "GET /users/1 " {
+			t.Fatalf("unexpected body. [body:%v]", body)
+		}
+
+		if _, err := r.Client().Get(server.URL + "/users/2"); err == nil {
+			t.Fatalf("request not in cassette should fail.")
+		}
+
+		r.AssertRequests(
+			Expect("POST", "/users").WithBody(BodyContains(`"foo"`)),
+			Expect("GET", "/users/1"),
+			Expect("GET", "/users/2"),
+		)
+	})
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	resp, err := client.Get(url)
+
+	if err != nil {
+		t.Fatalf("fail to get %v: %v", url, err)
+	}
+
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.Header.Get("X-Test") != "yes" {
+		t.Fatalf("response header is not recorded.")
+	}
+
+	return string(body)
+}
+
+func post(t *testing.T, client *http.Client, url, body string) string {
+	resp, err := client.Post(url, "application/json", strings.NewReader(body))
+
+	if err != nil {
+		t.Fatalf("fail to post %v: %v", url, err)
+	}
+
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return string(data)
+}
+
+func TestDescribeRequest(t *testing.T) {
+	expected := []Expectation{
+		Expect("GET", "/users/1"),
+		Expect("GET", "/users?page=2"),
+		Expect("POST", "/users").WithBody(BodyEqual("foo")),
+	}
+	cases := []struct {
+		Idx      int
+		Request  Request
+		Expected string
+	}{
+		{0, Request{Method: "GET", URL: "http://example.com/users/1"}, "GET /users/1"},
+		{0, Request{Method: "GET", URL: "http://example.com/users?page=2"}, "GET /users?page=2"},
+		{2, Request{Method: "POST", URL: "http://example.com/users", Body: "foo"}, `POST /users body "foo"`},
+		{2, Request{Method: "POST", URL: "http://example.com/users", Body: "bar"}, `POST http://example.com/users body "bar"`},
+	}
+
+	for i, c := range cases {
+		if desc := describeRequest(expected, c.Idx, c.Request); desc != c.Expected {
+			t.Fatalf("case #%v: unexpected description. [expected:%v] [actual:%v]", i, c.Expected, desc)
+		}
+	}
+}
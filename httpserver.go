@@ -0,0 +1,212 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"strings"
+
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// HTTPServer is a test server started by `A#HTTPServer`.
+// Requests sent by its methods return HTTPResponse with assertion methods.
+type HTTPServer struct {
+	*httptest.Server
+
+	a *A
+}
+
+// HTTPResponse is a response returned by a HTTPServer.
+// The body is read completely so that it can be asserted many times.
+type HTTPResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	a *A
+
+	// exchange is the dump of the request and the response.
+	exchange string
+}
+
+// HTTPServer starts a test server serving handler.
+// The server is closed automatically when the test case and all its subtests complete.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         srv := a.HTTPServer(NewHandler())
+//         resp := srv.Get("/users/1")
+//         resp.AssertStatus(http.StatusOK)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         resp.AssertStatus(http.StatusOK)
+//     The response status code should be 200 but got 404.
+//     Last request and response:
+//         GET /users/1 HTTP/1.1
+//         Host: 127.0.0.1:40215
+//
+//         HTTP/1.1 404 Not Found
+//         Content-Length: 19
+//         Content-Type: text/plain; charset=utf-8
+//
+//         404 page not found
+func (a *A) HTTPServer(handler http.Handler) *HTTPServer {
+	srv := &HTTPServer{
+		Server: httptest.NewServer(handler),
+		a:      a,
+	}
+
+	if a.T != nil {
+		a.Cleanup(srv.Close)
+	}
+
+	return srv
+}
+
+// Get sends a GET request to path.
+func (s *HTTPServer) Get(path string) *HTTPResponse {
+	s.a.t.Helper()
+	return s.send("Get", http.MethodGet, path, "", "")
+}
+
+// Post sends a POST request with body to path.
+func (s *HTTPServer) Post(path, contentType, body string) *HTTPResponse {
+	s.a.t.Helper()
+	return s.send("Post", http.MethodPost, path, contentType, body)
+}
+
+// Do sends req to s. If the URL of req has no host, it's sent to s,
+// so req can be created with a path only.
+func (s *HTTPServer) Do(req *http.Request) *HTTPResponse {
+	s.a.t.Helper()
+	return s.do("Do", 2, req)
+}
+
+// send creates a request and sends it to s.
+func (s *HTTPServer) send(funcName, method, path, contentType, body string) *HTTPResponse {
+	s.a.t.Helper()
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+
+	if err != nil {
+		s.fail(funcName, 3, err)
+		return &HTTPResponse{a: s.a}
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return s.do(funcName, 3, req)
+}
+
+// do sends req and reads the response.
+// The skip is the number of frames between the method called by test code and do, including do.
+func (s *HTTPServer) do(funcName string, skip int, req *http.Request) *HTTPResponse {
+	s.a.t.Helper()
+	resp := &HTTPResponse{a: s.a}
+
+	if req.URL.Host == "" {
+		u := *req.URL
+		u.Scheme = "http"
+		u.Host = s.Listener.Addr().String()
+		req.URL = &u
+	}
+
+	reqDump, err := httputil.DumpRequestOut(req, true)
+
+	if err != nil {
+		s.fail(funcName, skip+1, err)
+		return resp
+	}
+
+	res, err := s.Client().Do(req)
+
+	if err != nil {
+		s.fail(funcName, skip+1, err)
+		return resp
+	}
+
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+
+	if err != nil {
+		s.fail(funcName, skip+1, err)
+		return resp
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	resDump, _ := httputil.DumpResponse(res, true)
+
+	resp.StatusCode = res.StatusCode
+	resp.Header = res.Header
+	resp.Body = body
+	resp.exchange = formatDump(reqDump) + "\n\n" + formatDump(resDump)
+	return resp
+}
+
+// fail reports err as a failure of the method funcName.
+// The skip is the number of frames between the method and fail, including fail.
+func (s *HTTPServer) fail(funcName string, skip int, err error) {
+	s.a.t.Helper()
+	trigger := s.a.trigger(funcName, argsFirst)
+	trigger.Skip = skip
+	assertion.AssertNilError(s.a.t, []interface{}{err}, trigger)
+}
+
+// AssertStatus expects the status code of r is code.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the request and the response.
+func (r *HTTPResponse) AssertStatus(code int) {
+	r.a.t.Helper()
+	msgs := assertion.CurrentMessages()
+	assertion.AssertHTTP(r.a.t, r.StatusCode == code,
+		fmt.Sprintf(msgs.ShouldHaveStatusFormat, code, r.StatusCode),
+		r.exchange, r.a.trigger("AssertStatus", argsFirst))
+}
+
+// AssertHeader expects the value of header key in r is value.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the request and the response.
+func (r *HTTPResponse) AssertHeader(key, value string) {
+	r.a.t.Helper()
+	msgs := assertion.CurrentMessages()
+	actual := r.Header.Get(key)
+	assertion.AssertHTTP(r.a.t, actual == value,
+		fmt.Sprintf(msgs.ShouldHaveHeaderFormat, key, value, actual),
+		r.exchange, r.a.trigger("AssertHeader", argsFirst))
+}
+
+// AssertBody expects the body of r is body.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the request and the response.
+func (r *HTTPResponse) AssertBody(body string) {
+	r.a.t.Helper()
+	msgs := assertion.CurrentMessages()
+	assertion.AssertHTTP(r.a.t, string(r.Body) == body,
+		msgs.ShouldHaveBody,
+		r.exchange, r.a.trigger("AssertBody", argsFirst))
+}
+
+// AssertBodyContains expects the body of r contains s.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the request and the response.
+func (r *HTTPResponse) AssertBodyContains(s string) {
+	r.a.t.Helper()
+	msgs := assertion.CurrentMessages()
+	assertion.AssertHTTP(r.a.t, bytes.Contains(r.Body, []byte(s)),
+		fmt.Sprintf(msgs.ShouldContainFormat, s),
+		r.exchange, r.a.trigger("AssertBodyContains", argsFirst))
+}
+
+// formatDump normalizes line endings of a dumped request or response.
+func formatDump(dump []byte) string {
+	return strings.TrimSpace(strings.Replace(string(dump), "\r\n", "\n", -1))
+}
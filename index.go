@@ -0,0 +1,47 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"path/filepath"
+	"runtime"
+
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// IndexEntry is the precomputed parsing result of a call to an assertion method.
+type IndexEntry = assertion.IndexEntry
+
+// IndexArg is the precomputed parsing result of an argument.
+type IndexArg = assertion.IndexArg
+
+// RegisterIndex registers entries of source files in the same directory of the caller.
+// Assertions called in these files use registered entries instead of parsing source files at runtime.
+// It's useful for huge test files or test binaries running without source files.
+//
+// RegisterIndex is designed to be called by the code generated by `assertindex`.
+// Add following line to any test file and run `go generate` to generate `assert_index_test.go`.
+// Re-run `go generate` whenever test files change. Otherwise, failure messages may be wrong.
+//
+//     //go:generate go run github.com/huandu/go-assert/cmd/assertindex
+func RegisterIndex(entries ...IndexEntry) {
+	_, file, _, ok := runtime.Caller(1)
+
+	if !ok {
+		return
+	}
+
+	assertion.RegisterIndex(filepath.Dir(file), entries)
+}
+
+// PurgeFile removes the parsed source file at path from the parser cache shared by all assertions,
+// so that it's parsed again when an assertion in it fails.
+// It's safe to call PurgeFile concurrently with assertions.
+//
+// Files changed on disk are detected by mod time and size and parsed again automatically.
+// Long-lived tools embedding assertions, e.g. REPLs and hot-reload test runners,
+// can call PurgeFile whenever they rewrite a file to make sure stale ASTs are never used.
+func PurgeFile(path string) {
+	assertion.PurgeFile(path)
+}
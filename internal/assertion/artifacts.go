@@ -0,0 +1,85 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// EnvArtifactsDir is the environment variable setting the directory of per-test artifacts directories,
+// e.g. a directory uploaded by CI after tests.
+const EnvArtifactsDir = "GO_ASSERT_ARTIFACTS_DIR"
+
+var (
+	artifactsLock sync.Mutex
+	artifactsDirs = map[string]string{} // Test name to its artifacts directory.
+)
+
+// tempDirer is implemented by *testing.T and *testing.B.
+type tempDirer interface {
+	TempDir() string
+}
+
+// FailureDir returns the artifacts directory of the test case t, which is created on first call.
+// The directory is printed in every following failure message of t
+// and set in `Failure.ArtifactsDir` for failure hooks.
+//
+// If env GO_ASSERT_ARTIFACTS_DIR is set, the directory is created in it and named after the test case.
+// Otherwise, it's created by `t.TempDir()`, or in the system temporary directory if t doesn't implement `TempDir() string`.
+func FailureDir(t T, trigger *Trigger) string {
+	t.Helper()
+	name := t.Name()
+
+	artifactsLock.Lock()
+	dir, ok := artifactsDirs[name]
+	artifactsLock.Unlock()
+
+	if ok {
+		return dir
+	}
+
+	dir, err := newArtifactsDir(t)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return ""
+	}
+
+	artifactsLock.Lock()
+	artifactsDirs[name] = dir
+	artifactsLock.Unlock()
+
+	if c, ok := t.(cleaner); ok {
+		c.Cleanup(func() {
+			artifactsLock.Lock()
+			defer artifactsLock.Unlock()
+			delete(artifactsDirs, name)
+		})
+	}
+
+	return dir
+}
+
+func newArtifactsDir(t T) (string, error) {
+	if base := os.Getenv(EnvArtifactsDir); base != "" {
+		dir := filepath.Join(base, sanitizeFilename(t.Name()))
+		return dir, os.MkdirAll(dir, 0755)
+	}
+
+	if td, ok := t.(tempDirer); ok {
+		return td.TempDir(), nil
+	}
+
+	return os.MkdirTemp("", "go-assert-"+sanitizeFilename(t.Name())+"-")
+}
+
+// artifactsDir returns the artifacts directory of the test case named name.
+// It returns empty string if FailureDir has not been called in the test case.
+func artifactsDir(name string) string {
+	artifactsLock.Lock()
+	defer artifactsLock.Unlock()
+	return artifactsDirs[name]
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFailureDir(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv(EnvArtifactsDir, base)
+
+	ft := NewFakeT("TestFailureDir/sub case")
+	defer func() {
+		artifactsLock.Lock()
+		defer artifactsLock.Unlock()
+		delete(artifactsDirs, ft.Name())
+	}()
+
+	trigger := &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true},
+	}
+	AssertEqual(ft, 1, 2, trigger)
+	assertEqual(t, strings.Contains(ft.Messages()[0], "Artifacts of this test are in"), false)
+
+	dir := FailureDir(ft, trigger)
+	assertEqual(t, dir, filepath.Join(base, "TestFailureDir_sub_case"))
+	assertEqual(t, FailureDir(ft, trigger), dir)
+
+	info, err := os.Stat(dir)
+	assertEqual(t, err, nil)
+	assertEqual(t, info.IsDir(), true)
+
+	var hooked string
+	remove := AddFailureHook(func(f *Failure) {
+		hooked = f.ArtifactsDir
+	})
+	defer remove()
+
+	AssertEqual(ft, 1, 2, trigger)
+	assertEqual(t, hooked, dir)
+	assertEqual(t, strings.Contains(ft.Messages()[1], "Artifacts of this test are in "+dir), true)
+}
+
+func TestFailureDirTempDir(t *testing.T) {
+	t.Setenv(EnvArtifactsDir, "")
+	var dir string
+
+	t.Run("sub", func(t *testing.T) {
+		dir = FailureDir(t, &Trigger{
+			FuncName: "FailureDir",
+		})
+		assertEqual(t, dir != "", true)
+		assertEqual(t, artifactsDir(t.Name()), dir)
+	})
+
+	assertEqual(t, artifactsDir(t.Name()+"/sub"), "")
+
+	// The dir created by `t.TempDir()` is removed after the test case.
+	_, err := os.Stat(dir)
+	assertEqual(t, os.IsNotExist(err), true)
+}
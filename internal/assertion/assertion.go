@@ -6,9 +6,9 @@
 package assertion
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
-	"testing"
 	"unsafe"
 
 	"github.com/davecgh/go-spew/spew"
@@ -21,8 +21,69 @@ type Trigger struct {
 	Skip     int
 	Args     []int
 	Vars     map[string]interface{}
+
+	// Case is the name of a var in Vars holding current test case.
+	// If it's set, the test case is always printed in failure message.
+	Case string
+
+	// Options customizes the assertion. Zero value uses global settings.
+	Options Options
+}
+
+// Options customizes assertions.
+type Options struct {
+	Color     *bool     // Enables or disables colors. If it's nil, global setting is used.
+	NonFatal  bool      // Use `t.Errorf` instead of `t.Fatalf` to report failures.
+	Formatter Formatter // Formats failure message. If it's nil, message is formatted with global width and compact settings.
+	Dumper    Dumper    // Dumps values. If it's nil, current dumper is used.
+	SkipHooks bool      // Don't call failure hooks. It's used when a failure is not a test failure.
+
+	// SliceSorts sorts slices in values before comparing them in Equal and NotEqual.
+	SliceSorts []SliceSort
+
+	// Strings normalizes strings before comparing them in Equal and NotEqual.
+	Strings StringNormalization
+
+	// Unexported sets how Equal and NotEqual handle unexported struct fields.
+	Unexported UnexportedMode
+
+	// VisibleWhitespace renders tabs, CRs, non-breaking spaces and trailing spaces visibly in dumps.
+	VisibleWhitespace bool
+
+	// CallGetters calls methods without args on registered vars, e.g. `cfg.Timeout()`,
+	// to print their results in related variables. Getters must be free of side effects.
+	CallGetters bool
+
+	// Groups are labeled blocks enclosing the assertion, outermost first.
+	// Failures are prefixed with labels and locations of these groups.
+	Groups []Group
+
+	// SnapshotVars makes vars registered by Use copied by Snapshot.
+	// Copies are printed in failure messages instead of the latest values.
+	SnapshotVars bool
+
+	// Inputs are names of vars in Trigger.Vars holding inputs generated by fuzzing.
+	// If it's set, all inputs are always printed in failure message.
+	Inputs []string
+
+	// Tracer provides recently executed assertion sites.
+	// They are printed in failure message if reports of Tracer are enabled.
+	Tracer *Tracer
+
+	// Timer is stopped while a failure is parsed and formatted, so that failures
+	// don't distort timings of benchmarks. It's usually the running *testing.B.
+	Timer Timer
+}
+
+// Timer is the timer of a benchmark. *testing.B implements it.
+type Timer interface {
+	StartTimer()
+	StopTimer()
 }
 
+// Formatter formats the failure message passed to `t.Fatalf` or `t.Errorf`.
+type Formatter func(f *Failure) string
+
 // P returns a valid parser.
 func (t *Trigger) P() *Parser {
 	if t.Parser != nil {
@@ -32,53 +93,219 @@ func (t *Trigger) P() *Parser {
 	return &Parser{}
 }
 
+// parseArgs parses args of the assertion calling the func which calls parseArgs.
+// Parsing args is the first step of every failure, so the timer is stopped here
+// and restarted by startTimer after the failure is reported.
+func (t *Trigger) parseArgs() (*Func, error) {
+	if t.Options.Timer != nil {
+		t.Options.Timer.StopTimer()
+	}
+
+	return t.P().ParseArgs(t.FuncName, t.Skip+2, t.Args)
+}
+
+// startTimer restarts the timer stopped by parseArgs.
+func (t *Trigger) startTimer() {
+	if t != nil && t.Options.Timer != nil {
+		t.Options.Timer.StartTimer()
+	}
+}
+
+// dumper returns the dumper used by the assertion.
+// Dumps longer than max dump setting are truncated.
+func (t *Trigger) dumper() Dumper {
+	d := CurrentDumper()
+
+	if t != nil && t.Options.Dumper != nil {
+		d = t.Options.Dumper
+	}
+
+	if t != nil && t.Options.VisibleWhitespace {
+		d = visibleDumper{d}
+	}
+
+	return limitDumper(d)
+}
+
+// colorEnabled returns true if failure message of the assertion should be colorized.
+func (t *Trigger) colorEnabled() bool {
+	if t != nil && t.Options.Color != nil {
+		return *t.Options.Color
+	}
+
+	return ColorEnabled()
+}
+
+// dumpConfig is the config to dump values in failure messages.
+var dumpConfig = &spew.ConfigState{
+	DisableMethods:          true,
+	DisablePointerMethods:   true,
+	DisablePointerAddresses: true,
+	DisableCapacities:       true,
+	SortKeys:                true,
+	SpewKeys:                true,
+}
+
 // Assert tests expr and call `t.Fatalf` to terminate test case if expr is false-equivalent value.
-func Assert(t *testing.T, expr interface{}, trigger *Trigger) {
+func Assert(t T, expr interface{}, trigger *Trigger) {
+	t.Helper()
 	k := ParseFalseKind(expr)
 
 	if k == Positive {
 		return
 	}
 
-	f, err := trigger.P().ParseArgs(trigger.FuncName, trigger.Skip+1, trigger.Args)
+	f, err := trigger.parseArgs()
 
 	if err != nil {
-		t.Fatalf("Assertion failed with an internal error: %v", err)
+		failInternal(t, trigger, err)
 		return
 	}
 
 	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
 	suffix := ""
 	arg := info.Args[0]
+	names := append([]string{arg}, info.RelatedVars...)
+	notes, typedNils := formatTypedNilNotes(msgs, names, trigger.Vars)
 
-	if !strings.ContainsRune(arg, ' ') {
-		switch k {
-		case Nil:
-			suffix = " != nil"
-		case False:
-			suffix = " != true"
-		case Zero:
-			suffix = " != 0"
-		case EmptyString:
-			suffix = ` != ""`
-		}
+	_, isTypedNil := typedNils[arg]
+
+	// Suffix " != nil" is misleading if arg is an interface holding a typed nil.
+	if !isTypedNil {
+		suffix = falseSuffix(k, arg)
 	}
 
 	assignment := indentAssignments(info.Assignments[0], 4)
 
 	if assignment != "" {
-		assignment = "\nReferenced variables are assigned in following statements:" + assignment
+		assignment = "\n" + msgs.Assignments + assignment
+	}
+
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v%v%v%v%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(arg, 4), suffix,
+			assignment, notes, formatSubExprs(msgs, f.FileSet, f.Args[0], trigger.Vars),
+			formatVars(msgs, info, trigger),
+		),
+	})
+}
+
+// falseSuffix returns the suffix of arg in failure message to explain why it's false-equivalent.
+// It returns an empty string if arg is not a simple expression, e.g. `x > y`.
+func falseSuffix(k FalseKind, arg string) string {
+	if strings.ContainsRune(arg, ' ') {
+		return ""
+	}
+
+	switch k {
+	case Nil:
+		return " != nil"
+	case False:
+		return " != true"
+	case Zero:
+		return " != 0"
+	case EmptyString:
+		return ` != ""`
+	}
+
+	return ""
+}
+
+// AssertConditions tests all exprs and calls `t.Fatalf` once to terminate test case
+// if any expr is false-equivalent value.
+// Every false-equivalent expr is reported with its own source, suffix and assignments.
+//
+// The trigger.Args must contain the index of the first expr in the call.
+func AssertConditions(t T, exprs []interface{}, trigger *Trigger) {
+	t.Helper()
+	var failed []int
+	var kinds []FalseKind
+
+	for i, expr := range exprs {
+		if k := ParseFalseKind(expr); k != Positive {
+			failed = append(failed, i)
+			kinds = append(kinds, k)
+		}
+	}
+
+	if len(failed) == 0 {
+		return
+	}
+
+	first := 0
+
+	if len(trigger.Args) > 0 {
+		first = trigger.Args[0]
+	}
+
+	tr := *trigger
+	tr.Args = make([]int, 0, len(failed))
+
+	for _, i := range failed {
+		tr.Args = append(tr.Args, first+i)
+	}
+
+	f, err := tr.parseArgs()
+
+	if err != nil {
+		failInternal(t, &tr, err)
+		return
 	}
 
-	t.Fatalf("\n%v:%v: Assertion failed:\n    %v%v%v%v",
-		f.Filename, f.Line, indentCode(arg, 4), suffix,
-		assignment, formatRelatedVars(info.RelatedVars, trigger.Vars),
-	)
+	info := tr.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	conds := make([]string, 0, len(failed))
+
+	// Sources of exprs are unavailable if exprs are passed by a slice, e.g. `a.AssertAll(conds...)`.
+	spread := strings.HasSuffix(info.Source, "...)")
+
+	for i, idx := range failed {
+		arg := info.Args[i]
+		suffix := falseSuffix(kinds[i], arg)
+		assignments := info.Assignments[i]
+
+		if spread || arg == "" {
+			arg = trigger.dumper().Dump(exprs[idx])
+			suffix = ""
+			assignments = nil
+		}
+
+		conds = append(conds, fmt.Sprintf("[%v] %v%v%v",
+			idx+1, indentCode(arg, 4), suffix, indentAssignments(assignments, 4)))
+	}
+
+	fail(t, &tr, &Failure{
+		FuncName: tr.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4),
+			fmt.Sprintf(msgs.ConditionsFalseFormat, len(failed), len(exprs)),
+			strings.Join(conds, "\n"), formatVars(msgs, info, &tr),
+		),
+	})
 }
 
 // AssertEqual uses `reflect.DeepEqual` to test v1 and v2 equality.
-func AssertEqual(t *testing.T, v1, v2 interface{}, trigger *Trigger) {
-	if reflect.DeepEqual(v1, v2) {
+func AssertEqual(t T, v1, v2 interface{}, trigger *Trigger) {
+	t.Helper()
+	v1, v2 = prepareCompared(v1, v2, trigger.Options)
+	s1, s2, normalized := normalizeStrings(v1, v2, trigger.Options.Strings)
+	equal, completed := s1 == s2, true
+
+	if !normalized {
+		equal, completed = guardedEqual(v1, v2)
+	}
+
+	if equal {
 		return
 	}
 
@@ -101,36 +328,127 @@ func AssertEqual(t *testing.T, v1, v2 interface{}, trigger *Trigger) {
 		}
 	}
 
-	f, err := trigger.P().ParseArgs(trigger.FuncName, trigger.Skip+1, trigger.Args)
+	f, err := trigger.parseArgs()
 
 	if err != nil {
-		t.Fatalf("Assertion failed with an internal error: %v", err)
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+
+	if !completed {
+		fail(t, trigger, tooLargeFailure(msgs, trigger, f, v1, v2))
+		return
+	}
+
+	dumper := trigger.dumper()
+	msg := msgs.ShouldEqual
+	values := ""
+	var v1Dump, v2Dump string
+	caretShown := false
+
+	// The trigger must not be captured by the guarded func. Otherwise, it escapes to heap even if v1 equals v2.
+	unexported := trigger.Options.Unexported
+
+	// Dumps and differences of pathological values may take forever.
+	completed = runGuarded(func() {
+		// Large byte slices are never dumped. Only bytes around differences are printed.
+		if b1, b2, ok := largeBytes(v1, v2); ok {
+			v1Dump = fmt.Sprintf(msgs.BytesDumpFormat, len(b1))
+			v2Dump = fmt.Sprintf(msgs.BytesDumpFormat, len(b2))
+			values = formatBytesDiff(msgs, b1, b2)
+		} else {
+			v1Dump = dumper.Dump(v1)
+			v2Dump = dumper.Dump(v2)
+
+			if typeMismatch {
+				msg = msgs.ShouldBeSameType
+			} else if normalized {
+				values = fmt.Sprintf("%v\n%v\n[1] -> %v\n[2] -> %v", formatRuneDiff(msgs, s1, s2), msgs.Values, v1Dump, v2Dump)
+			} else if DiffEnabled() {
+				if s1, s2, ok := shortStrings(v1, v2); ok {
+					values = fmt.Sprintf("%v\n%v\n[1] -> %v\n[2] -> %v", formatStringDiff(msgs, s1, s2), msgs.Values, v1Dump, v2Dump)
+					caretShown = true
+				} else {
+					values = formatValuesDiff(msgs, dumper, v1, v2, unexported)
+				}
+			}
+		}
+
+		if values == "" {
+			values = fmt.Sprintf("%v\n[1] -> %v\n[2] -> %v", msgs.Values, v1Dump, v2Dump)
+		}
+
+		// The caret already points out the typo in short strings.
+		if note := equalNote(msgs, v1, v2); note != "" && !caretShown {
+			values += "\n" + msgs.Notes + "\n    " + note
+		}
+	})
+
+	if !completed {
+		fail(t, trigger, tooLargeFailure(msgs, trigger, f, v1, v2))
 		return
 	}
 
 	info := trigger.P().ParseInfo(f)
-	config := &spew.ConfigState{
-		DisableMethods:          true,
-		DisablePointerMethods:   true,
-		DisablePointerAddresses: true,
-		DisableCapacities:       true,
-		SortKeys:                true,
-		SpewKeys:                true,
-	}
-	v1Dump := config.Sprintf("%#v", v1)
-	v2Dump := config.Sprintf("%#v", v2)
-	msg := "The value of following expression should equal."
-
-	if typeMismatch {
-		msg = "The type of following expressions should be the same."
-	}
-
-	t.Fatalf("\n%v:%v: Assertion failed:\n    %v\n%v\n[1] %v%v\n[2] %v%v\nValues:\n[1] -> %v\n[2] -> %v%v",
-		f.Filename, f.Line, indentCode(info.Source, 4), msg,
-		indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
-		indentCode(info.Args[1], 4), indentAssignments(info.Assignments[1], 4),
-		v1Dump, v2Dump, formatRelatedVars(info.RelatedVars, trigger.Vars),
-	)
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n[1] %v%v\n[2] %v%v\n%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4), msg,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			indentCode(info.Args[1], 4), indentAssignments(info.Assignments[1], 4),
+			values, formatVars(msgs, info, trigger),
+		),
+		Values: []string{v1Dump, v2Dump},
+	})
+}
+
+// AssertType expects the dynamic type of v is expected.
+// The expected type can be an interface type. In this case, v must implement it.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+func AssertType(t T, v interface{}, expected reflect.Type, trigger *Trigger) {
+	t.Helper()
+	actual := reflect.TypeOf(v)
+
+	if actual != nil && (actual == expected || expected.Kind() == reflect.Interface && actual.Implements(expected)) {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	actualType := "nil"
+
+	if actual != nil {
+		actualType = actual.String()
+	}
+
+	vDump := trigger.dumper().Dump(v)
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed,
+			fmt.Sprintf(msgs.ShouldBeTypeFormat, expected, actualType),
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.Value, vDump, formatVars(msgs, info, trigger),
+		),
+		Values: []string{vDump},
+	})
 }
 
 func isNil(val reflect.Value) bool {
@@ -145,30 +463,55 @@ func isNil(val reflect.Value) bool {
 }
 
 // AssertNotEqual uses `reflect.DeepEqual` to test v1 and v2 equality.
-func AssertNotEqual(t *testing.T, v1, v2 interface{}, trigger *Trigger) {
-	if !reflect.DeepEqual(v1, v2) {
+func AssertNotEqual(t T, v1, v2 interface{}, trigger *Trigger) {
+	t.Helper()
+	v1, v2 = prepareCompared(v1, v2, trigger.Options)
+
+	s1, s2, normalized := normalizeStrings(v1, v2, trigger.Options.Strings)
+	equal, completed := s1 == s2, true
+
+	if !normalized {
+		equal, completed = guardedEqual(v1, v2)
+	}
+
+	if completed && !equal {
 		return
 	}
 
-	f, err := trigger.P().ParseArgs(trigger.FuncName, trigger.Skip+1, trigger.Args)
+	f, err := trigger.parseArgs()
 
 	if err != nil {
-		t.Fatalf("Assertion failed with an internal error: %v", err)
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+
+	if !completed {
+		fail(t, trigger, tooLargeFailure(msgs, trigger, f, v1, v2))
 		return
 	}
 
 	info := trigger.P().ParseInfo(f)
-	t.Fatalf("\n%v:%v: Assertion failed:\n    %v\nThe value of following expression should not equal.\n[1] %v%v\n[2] %v%v%v",
-		f.Filename, f.Line, indentCode(info.Source, 4),
-		indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
-		indentCode(info.Args[1], 4), indentAssignments(info.Assignments[1], 4),
-		formatRelatedVars(info.RelatedVars, trigger.Vars),
-	)
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n[1] %v%v\n[2] %v%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4), msgs.ShouldNotEqual,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			indentCode(info.Args[1], 4), indentAssignments(info.Assignments[1], 4),
+			formatVars(msgs, info, trigger),
+		),
+	})
 }
 
 // AssertNilError expects a function return a nil error.
 // Otherwise, it will terminate the test case using `t.Fatalf`.
-func AssertNilError(t *testing.T, result []interface{}, trigger *Trigger) {
+func AssertNilError(t T, result []interface{}, trigger *Trigger) {
+	t.Helper()
 	if len(result) == 0 {
 		return
 	}
@@ -180,24 +523,21 @@ func AssertNilError(t *testing.T, result []interface{}, trigger *Trigger) {
 		return
 	}
 
-	f, err := trigger.P().ParseArgs(trigger.FuncName, trigger.Skip+1, trigger.Args)
+	f, err := trigger.parseArgs()
 
 	if err != nil {
-		t.Fatalf("Assertion failed with an internal error: %v", err)
+		failInternal(t, trigger, err)
 		return
 	}
 
-	info := trigger.P().ParseInfo(f)
-	t.Fatalf("\n%v:%v: Assertion failed:\nFollowing expression should return a nil error.\n    %v%v\nThe error is:\n    %v%v",
-		f.Filename, f.Line,
-		indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
-		e, formatRelatedVars(info.RelatedVars, trigger.Vars),
-	)
+	msgs := CurrentMessages()
+	fail(t, trigger, nilErrorFailure(msgs, trigger, f, msgs.ShouldBeNilError, e))
 }
 
 // AssertNonNilError expects a function return a non-nil error.
 // Otherwise, it will terminate the test case using `t.Fatalf`.
-func AssertNonNilError(t *testing.T, result []interface{}, trigger *Trigger) {
+func AssertNonNilError(t T, result []interface{}, trigger *Trigger) {
+	t.Helper()
 	if len(result) == 0 {
 		return
 	}
@@ -215,19 +555,27 @@ func AssertNonNilError(t *testing.T, result []interface{}, trigger *Trigger) {
 		}
 	}
 
-	f, err := trigger.P().ParseArgs(trigger.FuncName, trigger.Skip+1, trigger.Args)
+	f, err := trigger.parseArgs()
 
 	if err != nil {
-		t.Fatalf("Assertion failed with an internal error: %v", err)
+		failInternal(t, trigger, err)
 		return
 	}
 
 	info := trigger.P().ParseInfo(f)
-	t.Fatalf("\n%v:%v: Assertion failed:\nFollowing expression should return an error.\n    %v%v%v",
-		f.Filename, f.Line,
-		indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
-		formatRelatedVars(info.RelatedVars, trigger.Vars),
-	)
+	msgs := CurrentMessages()
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, msgs.ShouldBeNonNilError,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			formatVars(msgs, info, trigger),
+		),
+	})
 }
 
 func indentCode(code string, spaces int) string {
@@ -235,7 +583,7 @@ func indentCode(code string, spaces int) string {
 		return ""
 	}
 
-	lines := strings.Split(code, "\n")
+	lines := strings.Split(highlight(code), "\n")
 	indented := make([]string, 0, len(lines))
 	space := strings.Repeat(" ", spaces)
 
@@ -259,7 +607,7 @@ func indentAssignments(assignments []string, spaces int) string {
 	output = append(output, "") // Add a newline at the front.
 
 	for _, code := range assignments {
-		lines := strings.Split(code, "\n")
+		lines := strings.Split(highlight(code), "\n")
 		indented := make([]string, 0, len(lines))
 
 		indented = append(indented, space+lines[0])
@@ -275,7 +623,133 @@ func indentAssignments(assignments []string, spaces int) string {
 	return strings.Join(output, "\n")
 }
 
-func formatRelatedVars(related []string, vars map[string]interface{}) string {
+// formatVars formats test case, loop vars and related vars in info.
+// Related vars printed in test case or iteration section are not printed again.
+func formatVars(msgs Messages, info *Info, trigger *Trigger) string {
+	vars := trigger.Vars
+	dumper := trigger.dumper()
+	testCase, printed := formatTestCase(msgs, dumper, trigger.Case, vars)
+	inputs, printedInputs := formatInputs(msgs, dumper, trigger.Options.Inputs, vars)
+	iteration, printedLoopVars := formatIteration(msgs, dumper, info.LoopVars, vars)
+	printed = append(printed, printedInputs...)
+	printed = append(printed, printedLoopVars...)
+
+	if len(printed) == 0 {
+		return formatRelatedVars(msgs, dumper, info.RelatedVars, vars, trigger.Options.CallGetters)
+	}
+
+	related := make([]string, 0, len(info.RelatedVars))
+
+	for _, name := range info.RelatedVars {
+		included := false
+
+		for _, p := range printed {
+			if IsIncluded(p, name) {
+				included = true
+				break
+			}
+		}
+
+		if !included {
+			related = append(related, name)
+		}
+	}
+
+	return testCase + inputs + iteration + formatRelatedVars(msgs, dumper, related, vars, trigger.Options.CallGetters)
+}
+
+func formatTestCase(msgs Messages, dumper Dumper, name string, vars map[string]interface{}) (testCase string, printed []string) {
+	if name == "" {
+		return
+	}
+
+	v, ok := vars[name]
+
+	if !ok {
+		return
+	}
+
+	val := reflect.ValueOf(v)
+
+	if !val.IsValid() || val.Kind() != reflect.Ptr {
+		return
+	}
+
+	testCase = "\n" + msgs.Case + "\n    " + name + " = " + dumper.Dump(getValueInterface(val.Elem()))
+	printed = []string{name}
+	return
+}
+
+func formatInputs(msgs Messages, dumper Dumper, names []string, vars map[string]interface{}) (inputs string, printed []string) {
+	if len(names) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, len(names)+1)
+	lines = append(lines, "\n"+msgs.FuzzInputs)
+
+	for _, name := range names {
+		val := reflect.ValueOf(vars[name])
+
+		if !val.IsValid() || val.Kind() != reflect.Ptr {
+			continue
+		}
+
+		lines = append(lines, "    "+name+" = "+dumper.Dump(getValueInterface(val.Elem())))
+		printed = append(printed, name)
+	}
+
+	if len(printed) == 0 {
+		return
+	}
+
+	inputs = strings.Join(lines, "\n")
+	return
+}
+
+func formatIteration(msgs Messages, dumper Dumper, loopVars [][]string, vars map[string]interface{}) (iteration string, printed []string) {
+	if len(loopVars) == 0 || len(vars) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, len(loopVars)+1)
+	lines = append(lines, "\n"+msgs.Iteration)
+
+	for _, names := range loopVars {
+		values := make([]string, 0, len(names))
+
+		for _, name := range names {
+			v, ok := vars[name]
+
+			if !ok {
+				continue
+			}
+
+			val := reflect.ValueOf(v)
+
+			if !val.IsValid() || val.Kind() != reflect.Ptr {
+				continue
+			}
+
+			values = append(values, name+" = "+dumper.Dump(getValueInterface(val.Elem())))
+			printed = append(printed, name)
+		}
+
+		if len(values) > 0 {
+			lines = append(lines, "    "+strings.Join(values, ", "))
+		}
+	}
+
+	// No loop var is registered by `Use`.
+	if len(lines) == 1 {
+		return
+	}
+
+	iteration = strings.Join(lines, "\n")
+	return
+}
+
+func formatRelatedVars(msgs Messages, dumper Dumper, related []string, vars map[string]interface{}, callGetters bool) string {
 	if len(related) == 0 || len(vars) == 0 {
 		return ""
 	}
@@ -283,12 +757,17 @@ func formatRelatedVars(related []string, vars map[string]interface{}) string {
 	values := make([]interface{}, 0, len(related))
 	names := make([]string, 0, len(related))
 	fields := make([]string, 0, len(related))
+	accesses := make([]relatedAccess, 0, len(related))
 
 	for _, name := range related {
+		// Elements, dereferenced values and getter results are derived from registered vars.
+		name, access := parseRelatedVar(name)
+
 		if v, ok := vars[name]; ok {
 			values = append(values, v)
 			names = append(names, name)
 			fields = append(fields, "")
+			accesses = append(accesses, access)
 			continue
 		}
 
@@ -302,6 +781,7 @@ func formatRelatedVars(related []string, vars map[string]interface{}) string {
 				values = append(values, v)
 				names = append(names, n)
 				fields = append(fields, name[len(n)+1:])
+				accesses = append(accesses, access)
 				break
 			}
 
@@ -313,16 +793,8 @@ func formatRelatedVars(related []string, vars map[string]interface{}) string {
 		return ""
 	}
 
-	config := &spew.ConfigState{
-		DisableMethods:          true,
-		DisablePointerMethods:   true,
-		DisablePointerAddresses: true,
-		DisableCapacities:       true,
-		SortKeys:                true,
-		SpewKeys:                true,
-	}
 	lines := make([]string, 0, len(values)+1)
-	lines = append(lines, "\nRelated variables:")
+	lines = append(lines, "\n"+msgs.RelatedVars)
 	visitedNames := map[string]struct{}{}
 
 	for i, v := range values {
@@ -345,11 +817,30 @@ func formatRelatedVars(related []string, vars map[string]interface{}) string {
 			name += "." + field
 		}
 
+		if access := accesses[i]; access != (relatedAccess{}) {
+			// The access applies to the field only if the field is fully resolved.
+			if field != fields[i] {
+				continue
+			}
+
+			var ptr interface{}
+
+			if field == "" {
+				ptr = values[i]
+			}
+
+			if v, ok = access.value(v, ptr, vars, callGetters); !ok {
+				continue
+			}
+
+			name = access.format(name)
+		}
+
 		if _, ok := visitedNames[name]; ok {
 			continue
 		}
 
-		lines = append(lines, config.Sprintf("    "+name+" = %#v", v))
+		lines = append(lines, "    "+name+" = "+dumper.Dump(v))
 		visitedNames[name] = struct{}{}
 	}
 
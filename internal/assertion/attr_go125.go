@@ -0,0 +1,83 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.25
+// +build go1.25
+
+package assertion
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+type attrT interface {
+	Attr(key, value string)
+}
+
+type outputT interface {
+	Output() io.Writer
+	failer
+}
+
+// emitFailureAttrs emits structured metadata of f through `t.Attr`
+// so that tools consuming test2json output can read failure details as attributes.
+// Nothing is emitted if t doesn't support attributes.
+func emitFailureAttrs(t T, f *Failure) {
+	at, ok := t.(attrT)
+
+	if !ok {
+		return
+	}
+
+	at.Attr("assert.func", f.FuncName)
+
+	if f.Filename != "" {
+		at.Attr("assert.file", f.Filename)
+		at.Attr("assert.line", strconv.Itoa(f.Line))
+	}
+
+	if f.Source != "" {
+		at.Attr("assert.source", attrValue(f.Source))
+	}
+
+	if f.ID != "" {
+		at.Attr("assert.id", f.ID)
+	}
+
+	if f.Quarantine != "" {
+		at.Attr("assert.quarantine", attrValue(f.Quarantine))
+	}
+
+	if len(f.Groups) > 0 {
+		at.Attr("assert.group", attrValue(strings.Join(f.Groups, " > ")))
+	}
+}
+
+// attrValue makes s a valid attribute value which must not contain newlines.
+func attrValue(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// writeFailureOutput reports the location of a failure with `t.Errorf` and writes output of the failure
+// through `t.Output`, so that tools consuming test2json output can tell the failure body from
+// error lines of the test. Output is indented as if it's written by `t.Errorf`.
+// It returns false if t doesn't support `t.Output`.
+func writeFailureOutput(t T, output string, nonFatal bool) bool {
+	t.Helper()
+	ot, ok := t.(outputT)
+
+	if !ok {
+		return false
+	}
+
+	t.Errorf("")
+	io.WriteString(ot.Output(), "    "+strings.ReplaceAll(output, "\n", "\n    ")+"\n")
+
+	if !nonFatal {
+		ot.FailNow()
+	}
+
+	return true
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build go1.25
+// +build go1.25
+
+package assertion
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// outputFakeT is a FakeT supporting `t.Output`.
+type outputFakeT struct {
+	*FakeT
+	output bytes.Buffer
+}
+
+func (ot *outputFakeT) Output() io.Writer {
+	return &ot.output
+}
+
+func TestWriteFailureOutput(t *testing.T) {
+	ot := &outputFakeT{FakeT: NewFakeT("TestWriteFailureOutput")}
+	AssertEqual(ot, 1, 2, &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true},
+	})
+	assertEqual(t, ot.Failed(), true)
+	assertEqual(t, ot.Fatal(), false)
+
+	msgs := ot.Messages()
+	output := ot.output.String()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, msgs[0], "")
+	assertEqual(t, strings.HasPrefix(output, "    attr_go125_test.go:"), true)
+	assertEqual(t, strings.Contains(output, "\n    "+DefaultMessages.ShouldEqual+"\n"), true)
+	assertEqual(t, strings.HasSuffix(output, "\n"), true)
+}
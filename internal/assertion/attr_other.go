@@ -0,0 +1,15 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build !go1.25
+// +build !go1.25
+
+package assertion
+
+// emitFailureAttrs does nothing as `t.Attr` requires Go 1.25 or later.
+func emitFailureAttrs(t T, f *Failure) {}
+
+// writeFailureOutput returns false as `t.Output` requires Go 1.25 or later.
+func writeFailureOutput(t T, output string, nonFatal bool) bool {
+	return false
+}
@@ -0,0 +1,129 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Run benchmarks with `go test -run '^$' -bench . -benchmem`.
+// A failure in a test file with 10k lines should be parsed and formatted within a few milliseconds.
+
+// benchFuncLines is the number of lines of every func in benchSource.
+const benchFuncLines = 10
+
+// benchSource returns source code of a test file with funcs test funcs
+// and the line of the assertion in the last func.
+func benchSource(funcs int) (src string, line int) {
+	buf := &strings.Builder{}
+	buf.WriteString("package bench\n\n")
+	line = 2
+
+	for i := 0; i < funcs; i++ {
+		fmt.Fprintf(buf, `func TestBench%v(t *testing.T) {
+	a := assert.New(t)
+	x := %v
+	y := x * 2
+	items := []int{x, y}
+	a.Use(&x, &y, &items)
+	a.Equal(items[0], x)
+	a.Assert(x+y > len(items))
+}
+
+`, i, i)
+		line += benchFuncLines
+	}
+
+	// The assertion in the last func.
+	return buf.String(), line - 3
+}
+
+// writeBenchSource writes source with funcs test funcs to a temp file.
+func writeBenchSource(b *testing.B, funcs int) (filename string, line int) {
+	src, line := benchSource(funcs)
+	filename = filepath.Join(b.TempDir(), "bench_test.go")
+
+	if err := os.WriteFile(filename, []byte(src), 0644); err != nil {
+		b.Fatal(err)
+	}
+
+	return
+}
+
+func benchmarkParseArgs(b *testing.B, funcs int) {
+	filename, line := writeBenchSource(b, funcs)
+	p := new(Parser)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		f, err := p.parseCall(filename, line, "", "Assert", []int{0})
+
+		if err != nil || f.Caller == nil {
+			b.Fatalf("fail to parse call: %v", err)
+		}
+	}
+}
+
+func BenchmarkParseArgsSmall(b *testing.B) { benchmarkParseArgs(b, 10) }
+func BenchmarkParseArgsHuge(b *testing.B)  { benchmarkParseArgs(b, 1000) }
+
+func benchmarkParseInfo(b *testing.B, funcs int) {
+	filename, line := writeBenchSource(b, funcs)
+	p := new(Parser)
+	f, err := p.parseCall(filename, line, "", "Assert", []int{0})
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		info := p.ParseInfo(f)
+
+		if len(info.Assignments[0]) == 0 {
+			b.Fatalf("assignments are not found")
+		}
+	}
+}
+
+func BenchmarkParseInfoSmall(b *testing.B) { benchmarkParseInfo(b, 10) }
+func BenchmarkParseInfoHuge(b *testing.B)  { benchmarkParseInfo(b, 1000) }
+
+func BenchmarkAssertEqualFailure(b *testing.B) {
+	ft := NewFakeT("BenchmarkAssertEqualFailure")
+	v1 := map[string][]int{"a": {1, 2, 3}, "b": {4, 5, 6}}
+	v2 := map[string][]int{"a": {1, 2, 3}, "b": {4, 5, 7}}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ft.Reset()
+		AssertEqual(ft, v1, v2, &Trigger{
+			FuncName: "AssertEqual",
+			Args:     []int{1, 2},
+			Options:  Options{NonFatal: true},
+		})
+	}
+}
+
+func BenchmarkFormatOutputWrapped(b *testing.B) {
+	line := strings.Repeat("[1] -> ([]string)[foo bar baz qux] ", 10)
+	msg := strings.TrimSpace(strings.Repeat(line+"\n", 20))
+	buf := &bytes.Buffer{}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		writeWrappedLines(buf, msg, 80)
+	}
+}
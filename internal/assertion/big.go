@@ -0,0 +1,311 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+var (
+	errInvalidBigNumber = errors.New("go-assert: number must be a non-nil *big.Int, *big.Float, *big.Rat or a Go number")
+	errInvalidTolerance = errors.New("go-assert: tolerance must be a finite non-negative number")
+)
+
+// bigNumber is a number converted from a big number or a Go number.
+// An infinite number has a nil rat and a non-zero inf which is its sign.
+type bigNumber struct {
+	rat *big.Rat
+	inf int
+}
+
+// parseBigNumber converts v to a bigNumber.
+// The v can be a *big.Int, a *big.Float, a *big.Rat, an integer or a float.
+func parseBigNumber(v interface{}) (n bigNumber, err error) {
+	switch x := v.(type) {
+	case *big.Int:
+		if x == nil {
+			err = errInvalidBigNumber
+			return
+		}
+
+		n.rat = new(big.Rat).SetInt(x)
+		return
+
+	case *big.Rat:
+		if x == nil {
+			err = errInvalidBigNumber
+			return
+		}
+
+		n.rat = new(big.Rat).Set(x)
+		return
+
+	case *big.Float:
+		if x == nil {
+			err = errInvalidBigNumber
+			return
+		}
+
+		if x.IsInf() {
+			n.inf = x.Sign()
+			return
+		}
+
+		n.rat, _ = x.Rat(nil)
+		return
+	}
+
+	val := reflect.ValueOf(v)
+
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n.rat = new(big.Rat).SetInt64(val.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n.rat = new(big.Rat).SetInt(new(big.Int).SetUint64(val.Uint()))
+
+	case reflect.Float32, reflect.Float64:
+		f := val.Float()
+
+		if math.IsNaN(f) {
+			err = errInvalidBigNumber
+			return
+		}
+
+		if math.IsInf(f, 0) {
+			if f > 0 {
+				n.inf = 1
+			} else {
+				n.inf = -1
+			}
+
+			return
+		}
+
+		n.rat = new(big.Rat).SetFloat64(f)
+
+	default:
+		err = errInvalidBigNumber
+	}
+
+	return
+}
+
+// cmp compares n and m like `big.Rat#Cmp`.
+func (n bigNumber) cmp(m bigNumber) int {
+	if n.inf == 0 && m.inf == 0 {
+		return n.rat.Cmp(m.rat)
+	}
+
+	switch {
+	case n.inf < m.inf:
+		return -1
+	case n.inf > m.inf:
+		return 1
+	}
+
+	return 0
+}
+
+// sub returns n - m. It returns nil if n or m is infinite.
+func (n bigNumber) sub(m bigNumber) *big.Rat {
+	if n.inf != 0 || m.inf != 0 {
+		return nil
+	}
+
+	return new(big.Rat).Sub(n.rat, m.rat)
+}
+
+// AssertBigEqual expects x and y are numerically equal.
+// Unlike AssertEqual, numbers are compared by their `Cmp` methods,
+// so that the internal representation of big numbers doesn't matter.
+// Numbers of different types, e.g. a *big.Int and a *big.Rat, are compared by value.
+func AssertBigEqual(t T, x, y interface{}, trigger *Trigger) {
+	t.Helper()
+	n1, n2, err := parseBigNumbers(x, y)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	if n1.cmp(n2) == 0 {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	fail(t, trigger, bigFailure(msgs, trigger, f, msgs.ShouldEqual, x, y, n1.sub(n2)))
+}
+
+// AssertBigInDelta expects the absolute difference of x and y is not greater than delta.
+// Infinite numbers are only within delta of the same infinity.
+// See AssertBigEqual for supported types of numbers.
+func AssertBigInDelta(t T, x, y, delta interface{}, trigger *Trigger) {
+	t.Helper()
+	diff, ok, err := checkBigInTolerance(x, y, delta, false)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	if ok {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	header := fmt.Sprintf(msgs.ShouldBeInDeltaFormat, formatBigNumber(delta))
+	fail(t, trigger, bigFailure(msgs, trigger, f, header, x, y, diff))
+}
+
+// AssertBigInEpsilon expects the relative difference of x and y is not greater than epsilon.
+// The relative difference is `|x - y| / max(|x|, |y|)`.
+// Infinite numbers are only within epsilon of the same infinity.
+// See AssertBigEqual for supported types of numbers.
+func AssertBigInEpsilon(t T, x, y, epsilon interface{}, trigger *Trigger) {
+	t.Helper()
+	diff, ok, err := checkBigInTolerance(x, y, epsilon, true)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	if ok {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	header := fmt.Sprintf(msgs.ShouldBeInEpsilonFormat, formatBigNumber(epsilon))
+	fail(t, trigger, bigFailure(msgs, trigger, f, header, x, y, diff))
+}
+
+// checkBigInTolerance reports whether x and y are within tolerance and returns their difference.
+// It must not call trigger.parseArgs, which depends on the depth of the caller's stack frame.
+func checkBigInTolerance(x, y, tolerance interface{}, relative bool) (diff *big.Rat, ok bool, err error) {
+	n1, n2, err := parseBigNumbers(x, y)
+
+	if err != nil {
+		return
+	}
+
+	tol, err := parseBigNumber(tolerance)
+
+	if err != nil || tol.inf != 0 || tol.rat.Sign() < 0 {
+		err = errInvalidTolerance
+		return
+	}
+
+	diff = n1.sub(n2)
+	ok = withinTolerance(n1, n2, diff, tol.rat, relative)
+	return
+}
+
+func parseBigNumbers(x, y interface{}) (n1, n2 bigNumber, err error) {
+	if n1, err = parseBigNumber(x); err != nil {
+		return
+	}
+
+	n2, err = parseBigNumber(y)
+	return
+}
+
+// withinTolerance reports whether diff, the difference of n1 and n2, is within tol.
+// If relative is true, tol is scaled by the max absolute value of n1 and n2.
+func withinTolerance(n1, n2 bigNumber, diff, tol *big.Rat, relative bool) bool {
+	if diff == nil {
+		return n1.cmp(n2) == 0
+	}
+
+	limit := tol
+
+	if relative {
+		abs1 := new(big.Rat).Abs(n1.rat)
+		abs2 := new(big.Rat).Abs(n2.rat)
+
+		if abs1.Cmp(abs2) < 0 {
+			abs1 = abs2
+		}
+
+		limit = new(big.Rat).Mul(tol, abs1)
+	}
+
+	return new(big.Rat).Abs(diff).Cmp(limit) <= 0
+}
+
+// bigFailure creates the failure of AssertBigEqual, AssertBigInDelta and AssertBigInEpsilon.
+// The difference section is omitted if diff is nil.
+func bigFailure(msgs Messages, trigger *Trigger, f *Func, header string, x, y interface{}, diff *big.Rat) *Failure {
+	info := trigger.P().ParseInfo(f)
+	dumper := trigger.dumper()
+	xDump := dumper.Dump(x)
+	yDump := dumper.Dump(y)
+	difference := ""
+
+	if diff != nil {
+		difference = fmt.Sprintf("%v\n    [1] - [2] = %v\n", msgs.Difference, formatBigRat(diff))
+	}
+
+	return &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n[1] %v%v\n[2] %v%v\n%v%v\n[1] -> %v\n[2] -> %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4), header,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			indentCode(info.Args[1], 4), indentAssignments(info.Assignments[1], 4),
+			difference, msgs.Values, xDump, yDump, formatVars(msgs, info, trigger),
+		),
+		Values: []string{xDump, yDump},
+	}
+}
+
+// formatBigNumber formats v in its canonical string form.
+func formatBigNumber(v interface{}) string {
+	switch x := v.(type) {
+	case *big.Rat:
+		return x.RatString()
+	case *big.Float:
+		return x.Text('g', -1)
+	}
+
+	return fmt.Sprint(v)
+}
+
+// formatBigRat formats r as an integer if possible.
+// Otherwise, r is formatted as a decimal float with enough precision to tell small differences.
+func formatBigRat(r *big.Rat) string {
+	if r.IsInt() {
+		return r.RatString()
+	}
+
+	return new(big.Float).SetPrec(128).SetRat(r).Text('g', 20)
+}
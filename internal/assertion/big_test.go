@@ -0,0 +1,96 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestAssertBigEqual(t *testing.T) {
+	ft := NewFakeT("TestAssertBigEqual")
+	trigger := &Trigger{
+		FuncName: "AssertBigEqual",
+		Args:     []int{1, 2},
+	}
+
+	// Equal numbers with different internal representations.
+	f1 := new(big.Float).SetPrec(10).SetInt64(3)
+	f2 := new(big.Float).SetPrec(100).SetInt64(3)
+	AssertBigEqual(ft, f1, f2, trigger)
+	AssertBigEqual(ft, big.NewRat(2, 4), 0.5, trigger)
+	AssertBigEqual(ft, big.NewInt(-3), int8(-3), trigger)
+	AssertBigEqual(ft, new(big.Float).SetInf(true), math.Inf(-1), trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertBigEqual(ft, big.NewRat(1, 3), big.NewRat(1, 4), trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "Difference:\n    [1] - [2] = 0.083333333333333333333\nValues:\n[1] -> (*big.Rat)1/3\n[2] -> (*big.Rat)1/4"), true)
+
+	ft.Reset()
+	AssertBigEqual(ft, new(big.Float).SetInf(false), 1, trigger)
+	msgs = ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "Difference:"), false)
+	assertEqual(t, strings.Contains(msgs[0], "[1] -> (*big.Float)+Inf"), true)
+
+	ft.Reset()
+	AssertBigEqual(ft, (*big.Int)(nil), 1, trigger)
+	assertEqual(t, strings.Contains(ft.Messages()[0], errInvalidBigNumber.Error()), true)
+
+	ft.Reset()
+	AssertBigEqual(ft, math.NaN(), 1, trigger)
+	assertEqual(t, strings.Contains(ft.Messages()[0], errInvalidBigNumber.Error()), true)
+}
+
+func TestAssertBigInTolerance(t *testing.T) {
+	ft := NewFakeT("TestAssertBigInTolerance")
+	trigger := &Trigger{
+		FuncName: "AssertBigInDelta",
+		Args:     []int{1, 2},
+	}
+
+	AssertBigInDelta(ft, big.NewInt(100), big.NewInt(103), 3, trigger)
+	AssertBigInDelta(ft, big.NewRat(1, 3), 0.333, big.NewRat(1, 1000), trigger)
+	AssertBigInEpsilon(ft, big.NewInt(1000), 1010, 0.01, trigger)
+	AssertBigInEpsilon(ft, -1010, big.NewInt(-1000), 0.01, trigger)
+	AssertBigInDelta(ft, math.Inf(1), math.Inf(1), 0, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertBigInDelta(ft, big.NewInt(100), big.NewInt(104), 3, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "The difference of following numbers should be within 3.\n"), true)
+	assertEqual(t, strings.Contains(msgs[0], "[1] - [2] = -4\n"), true)
+
+	ft.Reset()
+	AssertBigInEpsilon(ft, big.NewInt(1000), 1011, 0.01, trigger)
+	assertEqual(t, strings.Contains(ft.Messages()[0], "The relative difference of following numbers should be within 0.01.\n"), true)
+
+	ft.Reset()
+	AssertBigInDelta(ft, 1, 2, -1, trigger)
+	assertEqual(t, strings.Contains(ft.Messages()[0], errInvalidTolerance.Error()), true)
+}
+
+func TestDumpBigNumbers(t *testing.T) {
+	type account struct {
+		Balance *big.Int
+		rate    big.Float
+		Share   big.Rat
+	}
+
+	acc := account{
+		Balance: big.NewInt(42),
+		Share:   *big.NewRat(1, 3),
+	}
+	acc.rate.SetFloat64(0.25)
+	assertEqual(t, DefaultDumper.Dump(acc), "(assertion.account){Balance:(*big.Int)42 rate:(big.Float)0.25 Share:(big.Rat)1/3}")
+
+	// Rats are normalized. 2/6 equals 1/3.
+	d := diffValues(acc, account{Balance: big.NewInt(43), Share: *big.NewRat(2, 6)})
+	assertEqual(t, formatDiff(DefaultMessages, DefaultDumper, d), "Differences:\nDifferent values:\n    .Balance:\n        [1] -> (big.Int)42\n        [2] -> (big.Int)43\n    .rate:\n        [1] -> (big.Float)0.25\n        [2] -> (big.Float)0\n(1 equal fields not shown)")
+}
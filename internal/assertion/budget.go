@@ -0,0 +1,180 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	budgetLock       sync.Mutex
+	outputSpent      = map[T]int{}
+	repeatedFailures = map[T]*repeatCounter{}
+)
+
+// repeatCounter counts failures per call site in a test.
+type repeatCounter struct {
+	max    int
+	counts map[string]int
+	sites  []string // Call sites in order of first failure.
+}
+
+// failer is implemented by *testing.T and *testing.B.
+// It's used to fail a test case silently after its output budget is exhausted.
+type failer interface {
+	Fail()
+	FailNow()
+}
+
+// cleaner is implemented by *testing.T and *testing.B.
+type cleaner interface {
+	Cleanup(func())
+}
+
+// limitOutput limits output of a failure in t according to max output settings.
+// The exhausted is true if t has printed max test output bytes before this failure.
+// Output of t is not counted unless t implements `Cleanup(func())`,
+// as the count could never be removed after t completes.
+func limitOutput(t T, output string) (limited string, exhausted bool) {
+	configLock.RLock()
+	maxFailure := maxOutput
+	maxTest := maxTestOutput
+	configLock.RUnlock()
+
+	msgs := CurrentMessages()
+
+	if maxFailure > 0 {
+		output = elideMiddle(msgs, output, maxFailure)
+	}
+
+	c, ok := t.(cleaner)
+
+	if maxTest <= 0 || !ok {
+		return output, false
+	}
+
+	budgetLock.Lock()
+	spent, ok := outputSpent[t]
+
+	if !ok {
+		c.Cleanup(func() {
+			budgetLock.Lock()
+			defer budgetLock.Unlock()
+			delete(outputSpent, t)
+		})
+	}
+
+	remaining := maxTest - spent
+	outputSpent[t] = spent + len(output)
+	budgetLock.Unlock()
+
+	if remaining <= 0 {
+		return "", true
+	}
+
+	if len(output) > remaining {
+		output = elideMiddle(msgs, output, remaining) + "\n" + fmt.Sprintf(msgs.OutputBudgetFormat, maxTest)
+	}
+
+	return output, false
+}
+
+// elideMiddle replaces the middle of s with a note if s is longer than max bytes.
+// Head and tail of s are kept and cut at line boundaries if possible.
+func elideMiddle(msgs Messages, s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	headEnd := max / 2
+	tailStart := len(s) - (max - headEnd)
+
+	for headEnd > 0 && !utf8.RuneStart(s[headEnd]) {
+		headEnd--
+	}
+
+	for tailStart < len(s) && !utf8.RuneStart(s[tailStart]) {
+		tailStart++
+	}
+
+	if idx := strings.LastIndexByte(s[:headEnd], '\n'); idx > 0 {
+		headEnd = idx
+	}
+
+	if idx := strings.IndexByte(s[tailStart:], '\n'); idx >= 0 && tailStart+idx+1 < len(s) {
+		tailStart += idx + 1
+	}
+
+	note := fmt.Sprintf(msgs.OutputElidedFormat, tailStart-headEnd)
+	return s[:headEnd] + "\n" + note + "\n" + s[tailStart:]
+}
+
+// suppressRepeated counts failure at its call site in t and returns true
+// if failures at the site have exceeded max repeated failures.
+// Suppressed failures are summarized after t completes,
+// so that nothing is suppressed unless t implements `Cleanup(func())`.
+func suppressRepeated(t T, failure *Failure) bool {
+	configLock.RLock()
+	max := maxRepeatedFailures
+	configLock.RUnlock()
+
+	if max <= 0 || failure.Filename == "" {
+		return false
+	}
+
+	c, ok := t.(cleaner)
+
+	if !ok {
+		return false
+	}
+
+	site := fmt.Sprintf("%v:%v", failure.Filename, failure.Line)
+
+	budgetLock.Lock()
+	defer budgetLock.Unlock()
+	rc, ok := repeatedFailures[t]
+
+	if !ok {
+		rc = &repeatCounter{
+			max:    max,
+			counts: map[string]int{},
+		}
+		repeatedFailures[t] = rc
+		c.Cleanup(func() {
+			summarizeRepeated(t)
+		})
+	}
+
+	n := rc.counts[site]
+
+	if n == 0 {
+		rc.sites = append(rc.sites, site)
+	}
+
+	rc.counts[site] = n + 1
+	return n >= rc.max
+}
+
+// summarizeRepeated reports numbers of suppressed failures per call site in t.
+func summarizeRepeated(t T) {
+	budgetLock.Lock()
+	rc := repeatedFailures[t]
+	delete(repeatedFailures, t)
+	budgetLock.Unlock()
+
+	if rc == nil {
+		return
+	}
+
+	msgs := CurrentMessages()
+
+	for _, site := range rc.sites {
+		if n := rc.counts[site]; n > rc.max {
+			t.Errorf(msgs.RepeatedFailuresFormat, n-rc.max, site)
+		}
+	}
+}
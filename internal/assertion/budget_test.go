@@ -0,0 +1,138 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestElideMiddle(t *testing.T) {
+	msgs := DefaultMessages
+	cases := []struct {
+		Message string
+		Max     int
+		Elided  string
+	}{
+		{"short", 10, "short"},
+		{"0123456789abcdef", 8, "0123\n... (8 bytes elided) ...\ncdef"},
+		{"line1\nline2\nline3\nline4", 14, "line1\n... (13 bytes elided) ...\nline4"},
+		{"你好世界", 7, "你\n... (6 bytes elided) ...\n界"},
+	}
+
+	for i, c := range cases {
+		t.Logf("case %v: %v", i, c)
+		assertEqual(t, elideMiddle(msgs, c.Message, c.Max), c.Elided)
+	}
+}
+
+func TestLimitOutput(t *testing.T) {
+	SetMaxOutput(0, 100)
+	defer SetMaxOutput(0, 0)
+
+	ct := &cleanupT{
+		FakeT: NewFakeT("TestLimitOutput"),
+	}
+
+	for i := 0; i < 10; i++ {
+		AssertEqual(ct, i, -1, &Trigger{
+			FuncName: "AssertEqual",
+			Args:     []int{1, 2},
+			Options:  Options{NonFatal: true},
+		})
+	}
+
+	calls := nonHelperCalls(ct.FakeT)
+	errors := 0
+	total := 0
+
+	for _, c := range calls {
+		switch c.Method {
+		case "Errorf":
+			errors++
+			total += len(c.Message)
+		case "Fail":
+		default:
+			t.Fatalf("unexpected call %v", c.Method)
+		}
+	}
+
+	assertEqual(t, len(calls), 10)
+	assertEqual(t, errors, 1)
+	assertEqual(t, strings.Contains(calls[0].Message, "exceeds 100 bytes"), true)
+	assertEqual(t, total < 300, true)
+
+	ct.done()
+	budgetLock.Lock()
+	_, ok := outputSpent[ct]
+	budgetLock.Unlock()
+	assertEqual(t, ok, false)
+
+	// Output of a T without Cleanup is not counted, as the count would never be removed.
+	ft := NewFakeT("TestLimitOutput")
+
+	for i := 0; i < 10; i++ {
+		AssertEqual(ft, i, -1, &Trigger{
+			FuncName: "AssertEqual",
+			Args:     []int{1, 2},
+			Options:  Options{NonFatal: true},
+		})
+	}
+
+	budgetLock.Lock()
+	_, ok = outputSpent[ft]
+	budgetLock.Unlock()
+	assertEqual(t, ok, false)
+	assertEqual(t, len(ft.Messages()), 10)
+}
+
+// cleanupT is a FakeT running cleanup funcs when done is called.
+type cleanupT struct {
+	*FakeT
+	cleanups []func()
+}
+
+func (ct *cleanupT) Cleanup(f func()) {
+	ct.cleanups = append(ct.cleanups, f)
+}
+
+func (ct *cleanupT) done() {
+	for i := len(ct.cleanups) - 1; i >= 0; i-- {
+		ct.cleanups[i]()
+	}
+}
+
+func TestSuppressRepeated(t *testing.T) {
+	SetMaxRepeatedFailures(2)
+	defer SetMaxRepeatedFailures(0)
+
+	ct := &cleanupT{
+		FakeT: NewFakeT("TestSuppressRepeated"),
+	}
+
+	for i := 0; i < 5; i++ {
+		AssertEqual(ct, i, -1, &Trigger{
+			FuncName: "AssertEqual",
+			Args:     []int{1, 2},
+			Options:  Options{NonFatal: true},
+		})
+	}
+
+	AssertEqual(ct, 1, 2, &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true},
+	})
+	assertEqual(t, len(ct.Messages()), 3)
+
+	ct.done()
+	msgs := ct.Messages()
+
+	assertEqual(t, len(msgs), 4)
+	assertEqual(t, strings.HasPrefix(msgs[3], "...and 3 more failures at budget_test.go:"), true)
+
+	budgetLock.Lock()
+	defer budgetLock.Unlock()
+	assertEqual(t, len(repeatedFailures), 0)
+}
@@ -0,0 +1,177 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// minWindowedBytes is the min length of byte slices compared by windowed diff.
+// Shorter byte slices are dumped and compared as other values.
+const minWindowedBytes = 4 << 10
+
+// bytesDiffContext is the number of equal bytes printed around a differing range.
+const bytesDiffContext = 8
+
+// maxBytesDiffWidth is the max number of differing bytes printed in a range.
+const maxBytesDiffWidth = 32
+
+// byteRange is a range of differing bytes in [Start, End).
+type byteRange struct {
+	Start, End int
+}
+
+// largeBytes returns v1 and v2 as byte slices if both are []byte and any of them is large enough
+// to be compared by windowed diff.
+func largeBytes(v1, v2 interface{}) (b1, b2 []byte, ok bool) {
+	b1, ok1 := v1.([]byte)
+	b2, ok2 := v2.([]byte)
+
+	if !ok1 || !ok2 || len(b1) < minWindowedBytes && len(b2) < minWindowedBytes {
+		return
+	}
+
+	ok = true
+	return
+}
+
+// diffBytes returns ranges of differing bytes in the common length of b1 and b2.
+// Ranges separated by no more than 2*bytesDiffContext equal bytes are merged,
+// so that their context doesn't overlap.
+// At most max ranges are returned. The total is the number of all ranges.
+func diffBytes(b1, b2 []byte, max int) (ranges []byteRange, total int) {
+	n := len(b1)
+
+	if len(b2) < n {
+		n = len(b2)
+	}
+
+	for i := 0; i < n; i++ {
+		if b1[i] == b2[i] {
+			continue
+		}
+
+		end := i + 1
+
+		for end < n {
+			if b1[end] != b2[end] {
+				end++
+				continue
+			}
+
+			next := end
+
+			for next < n && next-end <= 2*bytesDiffContext && b1[next] == b2[next] {
+				next++
+			}
+
+			if next >= n || next-end > 2*bytesDiffContext {
+				break
+			}
+
+			end = next
+		}
+
+		total++
+
+		if len(ranges) < max {
+			ranges = append(ranges, byteRange{Start: i, End: end})
+		}
+
+		i = end
+	}
+
+	return
+}
+
+// formatBytesDiff formats differing ranges of b1 and b2 with a few context bytes.
+// Only bytes around differing ranges are formatted.
+func formatBytesDiff(msgs Messages, b1, b2 []byte) string {
+	lines := []string{msgs.Differences}
+
+	if len(b1) != len(b2) {
+		lines = append(lines, fmt.Sprintf(msgs.BytesLengthFormat, len(b1), len(b2)))
+	}
+
+	ranges, total := diffBytes(b1, b2, maxDiffEntries)
+
+	// The tail of the longer slice is a differing range as well.
+	if len(b1) != len(b2) {
+		total++
+
+		if len(ranges) < maxDiffEntries {
+			tail := byteRange{Start: len(b1), End: len(b2)}
+
+			if len(b2) < len(b1) {
+				tail = byteRange{Start: len(b2), End: len(b1)}
+			}
+
+			ranges = append(ranges, tail)
+		}
+	}
+
+	for _, r := range ranges {
+		lines = append(lines,
+			fmt.Sprintf(msgs.BytesRangeFormat, r.Start, r.End-r.Start),
+			"    [1] -> "+formatByteWindow(b1, r),
+			"    [2] -> "+formatByteWindow(b2, r),
+		)
+	}
+
+	if total > len(ranges) {
+		lines = append(lines, fmt.Sprintf(msgs.DiffMoreFormat, total-len(ranges)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatByteWindow formats bytes in r with context bytes around it in hex.
+// Differing bytes are enclosed in brackets.
+// The r can exceed the length of b, e.g. the tail of the longer slice.
+func formatByteWindow(b []byte, r byteRange) string {
+	diffStart := clampIndex(r.Start, len(b))
+	diffEnd := clampIndex(r.End, len(b))
+	start := clampIndex(r.Start-bytesDiffContext, len(b))
+	end := clampIndex(r.End+bytesDiffContext, len(b))
+	more := 0
+
+	if diffEnd-diffStart > maxBytesDiffWidth {
+		more = diffEnd - diffStart - maxBytesDiffWidth
+		diffEnd = diffStart + maxBytesDiffWidth
+	}
+
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "%#x:", start)
+	writeHex(buf, b[start:diffStart])
+	buf.WriteString(" [")
+	writeHex(buf, b[diffStart:diffEnd])
+
+	if more > 0 {
+		fmt.Fprintf(buf, " ...%v", more)
+	}
+
+	buf.WriteString(" ]")
+	writeHex(buf, b[clampIndex(r.End, len(b)):end])
+	return buf.String()
+}
+
+// clampIndex returns i clamped to [0, n].
+func clampIndex(i, n int) int {
+	if i < 0 {
+		return 0
+	}
+
+	if i > n {
+		return n
+	}
+
+	return i
+}
+
+func writeHex(buf *strings.Builder, b []byte) {
+	for _, c := range b {
+		fmt.Fprintf(buf, " %02x", c)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffBytes(t *testing.T) {
+	b1 := make([]byte, 1000)
+	b2 := make([]byte, 1000)
+
+	// Ranges close to each other are merged.
+	b2[10] = 1
+	b2[12] = 1
+	b2[100] = 1
+	b2[100+2*bytesDiffContext+2] = 1
+	b2[999] = 1
+
+	ranges, total := diffBytes(b1, b2, 10)
+	assertEqual(t, total, 4)
+	assertEqual(t, ranges, []byteRange{{10, 13}, {100, 101}, {118, 119}, {999, 1000}})
+
+	ranges, total = diffBytes(b1, b2, 2)
+	assertEqual(t, total, 4)
+	assertEqual(t, len(ranges), 2)
+}
+
+func TestFormatBytesDiff(t *testing.T) {
+	b1 := make([]byte, 256)
+	b2 := make([]byte, 260)
+
+	for i := range b1 {
+		b1[i] = byte(i)
+		b2[i] = byte(i)
+	}
+
+	b2[0x80] = 0xde
+	b2[0x81] = 0xad
+	b2[256] = 0xff
+	diff := formatBytesDiff(DefaultMessages, b1, b2)
+	assertEqual(t, diff, strings.Join([]string{
+		"Differences:",
+		"[1] has 256 bytes but [2] has 260 bytes.",
+		"At offset 0x80, 2 bytes differ:",
+		"    [1] -> 0x78: 78 79 7a 7b 7c 7d 7e 7f [ 80 81 ] 82 83 84 85 86 87 88 89",
+		"    [2] -> 0x78: 78 79 7a 7b 7c 7d 7e 7f [ de ad ] 82 83 84 85 86 87 88 89",
+		"At offset 0x100, 4 bytes differ:",
+		"    [1] -> 0xf8: f8 f9 fa fb fc fd fe ff [ ]",
+		"    [2] -> 0xf8: f8 f9 fa fb fc fd fe ff [ ff 00 00 00 ]",
+	}, "\n"))
+
+	// Long ranges are truncated.
+	b3 := make([]byte, 100)
+	diff = formatBytesDiff(DefaultMessages, b1[:100], b3)
+	assertEqual(t, strings.Contains(diff, "At offset 0x1, 99 bytes differ:"), true)
+	assertEqual(t, strings.Contains(diff, " ...67 ]"), true)
+}
+
+func TestAssertEqualLargeBytes(t *testing.T) {
+	ft := NewFakeT("TestAssertEqualLargeBytes")
+	b1 := make([]byte, 1<<20)
+	b2 := make([]byte, 1<<20)
+	b2[0x3f200] = 0xde
+
+	AssertEqual(ft, b1, b2, &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+	})
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "At offset 0x3f200, 1 bytes differ:"), true)
+	assertEqual(t, strings.Contains(msgs[0], "[2] -> 0x3f1f8: 00 00 00 00 00 00 00 00 [ de ] 00"), true)
+	assertEqual(t, len(msgs[0]) < 4096, true)
+}
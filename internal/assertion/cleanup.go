@@ -0,0 +1,55 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"os"
+)
+
+// Cleanup registers fn to be called after the test case t completes.
+// It reports an internal error if t doesn't implement `Cleanup(func())`, e.g. a PanicT,
+// as fn would never be called.
+func Cleanup(t T, fn func(), trigger *Trigger) {
+	t.Helper()
+	c, ok := t.(cleaner)
+
+	if !ok {
+		failInternal(t, trigger, fmt.Errorf("%T doesn't support Cleanup", t))
+		return
+	}
+
+	c.Cleanup(fn)
+}
+
+// TempDir returns a new temporary directory of the test case t, which is removed after t completes.
+// It's created by `t.TempDir()` if t implements `TempDir() string`.
+// Otherwise, it's created in the system temporary directory and removed by a cleanup registered in t.
+// It reports an internal error if t implements neither of them.
+func TempDir(t T, trigger *Trigger) string {
+	t.Helper()
+
+	if td, ok := t.(tempDirer); ok {
+		return td.TempDir()
+	}
+
+	c, ok := t.(cleaner)
+
+	if !ok {
+		failInternal(t, trigger, fmt.Errorf("%T doesn't support TempDir or Cleanup", t))
+		return ""
+	}
+
+	dir, err := os.MkdirTemp("", "go-assert-"+sanitizeFilename(t.Name())+"-")
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return ""
+	}
+
+	c.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+	return dir
+}
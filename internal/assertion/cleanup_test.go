@@ -0,0 +1,53 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCleanup(t *testing.T) {
+	trigger := &Trigger{
+		FuncName: "Cleanup",
+		Options:  Options{NonFatal: true},
+	}
+	ct := &cleanupT{FakeT: NewFakeT("TestCleanup")}
+	called := false
+	Cleanup(ct, func() { called = true }, trigger)
+	assertEqual(t, ct.Failed(), false)
+	ct.done()
+	assertEqual(t, called, true)
+
+	ft := NewFakeT("TestCleanup")
+	Cleanup(ft, func() {}, trigger)
+	assertEqual(t, len(ft.Messages()), 1)
+	assertEqual(t, strings.Contains(ft.Messages()[0], "*assertion.FakeT doesn't support Cleanup"), true)
+}
+
+func TestTempDir(t *testing.T) {
+	trigger := &Trigger{
+		FuncName: "TempDir",
+		Options:  Options{NonFatal: true},
+	}
+	assertEqual(t, TempDir(t, trigger) != "", true)
+
+	ct := &cleanupT{FakeT: NewFakeT("TestTempDir/sub case")}
+	dir := TempDir(ct, trigger)
+	assertEqual(t, ct.Failed(), false)
+	assertEqual(t, strings.Contains(dir, "go-assert-TestTempDir_sub_case-"), true)
+
+	info, err := os.Stat(dir)
+	assertEqual(t, err, nil)
+	assertEqual(t, info.IsDir(), true)
+
+	ct.done()
+	_, err = os.Stat(dir)
+	assertEqual(t, os.IsNotExist(err), true)
+
+	ft := NewFakeT("TestTempDir")
+	assertEqual(t, TempDir(ft, trigger), "")
+	assertEqual(t, ft.Failed(), true)
+}
@@ -0,0 +1,206 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// maxFailedElements is the max number of failed elements dumped in failure message.
+const maxFailedElements = 10
+
+var errInvalidCollection = errors.New("go-assert: collection must be a slice or an array")
+
+// AssertAll expects every element in collection satisfies pred.
+// Otherwise, it will terminate the test case using `t.Fatalf` with indices and dumps of failed elements.
+//
+// The collection must be a slice or an array.
+// The pred must be a `func(e E) bool` where elements of collection are assignable to E.
+func AssertAll(t T, collection, pred interface{}, trigger *Trigger) {
+	t.Helper()
+	failed, err := matchElements(collection, pred)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	if len(failed) == 0 {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	fail(t, trigger, matchFailure(msgs, trigger, f, msgs.ShouldAllMatch, collection, failed))
+}
+
+// AssertAnyMatch expects at least one element in collection satisfies pred.
+// Otherwise, it will terminate the test case using `t.Fatalf` with dumps of all elements.
+//
+// See AssertAll for supported types of collection and pred.
+func AssertAnyMatch(t T, collection, pred interface{}, trigger *Trigger) {
+	t.Helper()
+	failed, err := matchElements(collection, pred)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	if len(failed) < reflect.ValueOf(collection).Len() {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	fail(t, trigger, matchFailure(msgs, trigger, f, msgs.ShouldAnyMatch, collection, failed))
+}
+
+// matchElements calls pred with every element in collection
+// and returns indices of elements failing pred.
+func matchElements(collection, pred interface{}) (failed []int, err error) {
+	c := reflect.ValueOf(collection)
+
+	if c.Kind() != reflect.Slice && c.Kind() != reflect.Array {
+		err = errInvalidCollection
+		return
+	}
+
+	p, err := predicate(pred, c.Type().Elem())
+
+	if err != nil {
+		return
+	}
+
+	for i := 0; i < c.Len(); i++ {
+		if !callPredicate(p, c.Index(i)) {
+			failed = append(failed, i)
+		}
+	}
+
+	return
+}
+
+// matchFailure creates the failure of AssertAll and AssertAnyMatch.
+// Only elements at failed indices are dumped.
+func matchFailure(msgs Messages, trigger *Trigger, f *Func, header string, collection interface{}, failed []int) *Failure {
+	info := trigger.P().ParseInfo(f)
+	c := reflect.ValueOf(collection)
+	dumper := trigger.dumper()
+	indices := make([]string, 0, len(failed))
+	lines := make([]string, 0, len(failed))
+	values := make([]string, 0, len(failed))
+
+	for i, idx := range failed {
+		indices = append(indices, fmt.Sprint(idx))
+
+		if i >= maxFailedElements {
+			continue
+		}
+
+		v := dumper.Dump(c.Index(idx).Interface())
+		lines = append(lines, fmt.Sprintf("    [%v] -> %v", idx, v))
+		values = append(values, v)
+	}
+
+	if len(failed) > maxFailedElements {
+		lines = append(lines, "    "+fmt.Sprintf(msgs.DiffMoreFormat, len(failed)-maxFailedElements))
+	}
+
+	elements := ""
+
+	if len(failed) > 0 {
+		elements = fmt.Sprintf("\n%v\n    %v\n%v\n%v",
+			fmt.Sprintf(msgs.FailedIndicesFormat, len(failed), c.Len()), strings.Join(indices, ", "),
+			msgs.FailedElements, strings.Join(lines, "\n"),
+		)
+	}
+
+	return &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, header,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			elements, formatVars(msgs, info, trigger),
+		),
+		Values: values,
+	}
+}
+
+// AssertHasKey expects map m has key.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+// If key is a string, existing keys similar to key are suggested.
+func AssertHasKey(t T, m, key interface{}, trigger *Trigger) {
+	t.Helper()
+	mVal := reflect.ValueOf(m)
+
+	for mVal.Kind() == reflect.Ptr || mVal.Kind() == reflect.Interface {
+		mVal = mVal.Elem()
+	}
+
+	if mVal.Kind() != reflect.Map {
+		failInternal(t, trigger, fmt.Errorf("expect a map but got %T", m))
+		return
+	}
+
+	keyVal := reflect.ValueOf(key)
+
+	if keyVal.IsValid() {
+		if _, ok := indexElement(mVal, keyVal); ok {
+			return
+		}
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	keyDump := trigger.dumper().Dump(key)
+	hint := ""
+
+	if keyVal.IsValid() && keyVal.Kind() == reflect.String {
+		if h := formatDidYouMean(msgs, similarStrings(keyVal.String(), stringKeys(mVal))); h != "" {
+			hint = "\n" + h
+		}
+	}
+
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n[1] %v%v\n[2] %v%v\n%v\n[2] -> %v%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4), msgs.ShouldHaveKey,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			indentCode(info.Args[1], 4), indentAssignments(info.Assignments[1], 4),
+			msgs.Values, keyDump, hint, formatVars(msgs, info, trigger),
+		),
+		Values: []string{keyDump},
+	})
+}
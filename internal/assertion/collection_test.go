@@ -0,0 +1,93 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAssertAll(t *testing.T) {
+	ft := NewFakeT("TestAssertAll")
+	trigger := &Trigger{
+		FuncName: "AssertAll",
+		Args:     []int{1},
+	}
+	positive := func(v int) bool { return v > 0 }
+
+	AssertAll(ft, []int{1, 2, 3}, positive, trigger)
+	AssertAll(ft, [0]int{}, positive, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	values := []int{1, -2, 3, -4}
+	AssertAll(ft, values, positive, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldAllMatch), true)
+	assertEqual(t, strings.Contains(msgs[0], "2 of 4 elements fail the predicate at indices:\n    1, 3"), true)
+	assertEqual(t, strings.Contains(msgs[0], "[1] -> (int)-2\n    [3] -> (int)-4"), true)
+	assertEqual(t, strings.Contains(msgs[0], "(int)1"), false)
+
+	// Only first elements are dumped.
+	ft.Reset()
+	many := make([]int, maxFailedElements+5)
+	AssertAll(ft, many, positive, trigger)
+	msgs = ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], fmt.Sprintf("[%v] -> ", maxFailedElements-1)), true)
+	assertEqual(t, strings.Contains(msgs[0], fmt.Sprintf("[%v] -> ", maxFailedElements)), false)
+	assertEqual(t, strings.Contains(msgs[0], fmt.Sprintf(DefaultMessages.DiffMoreFormat, 5)), true)
+}
+
+func TestAssertAnyMatch(t *testing.T) {
+	ft := NewFakeT("TestAssertAnyMatch")
+	trigger := &Trigger{
+		FuncName: "AssertAnyMatch",
+		Args:     []int{1},
+	}
+	hasPrefix := func(s fmt.Stringer) bool { return strings.HasPrefix(s.String(), "z") }
+
+	AssertAnyMatch(ft, []fmt.Stringer{stringer("foo"), stringer("zoo")}, hasPrefix, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertAnyMatch(ft, []fmt.Stringer{stringer("foo"), stringer("bar")}, hasPrefix, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldAnyMatch), true)
+	assertEqual(t, strings.Contains(msgs[0], "2 of 2 elements fail the predicate at indices:\n    0, 1"), true)
+
+	// Empty collection has no matching element.
+	ft.Reset()
+	AssertAnyMatch(ft, []fmt.Stringer{}, hasPrefix, trigger)
+	assertEqual(t, ft.Fatal(), true)
+}
+
+func TestMatchElementsInvalidArgs(t *testing.T) {
+	cases := []struct {
+		Collection interface{}
+		Pred       interface{}
+		Err        error
+	}{
+		{nil, func(int) bool { return true }, errInvalidCollection},
+		{map[int]int{}, func(int) bool { return true }, errInvalidCollection},
+		{[]int{1}, nil, errInvalidPredicate},
+		{[]int{1}, (func(int) bool)(nil), errInvalidPredicate},
+		{[]int{1}, func(string) bool { return true }, errInvalidPredicate},
+		{[]int{1}, func(int) int { return 0 }, errInvalidPredicate},
+		{[]int{1}, func(...int) bool { return true }, errInvalidPredicate},
+		{[]int{1}, func(interface{}) bool { return true }, nil},
+	}
+
+	for _, c := range cases {
+		_, err := matchElements(c.Collection, c.Pred)
+		assertEqual(t, err, c.Err)
+	}
+}
+
+type stringer string
+
+func (s stringer) String() string {
+	return string(s)
+}
@@ -0,0 +1,154 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"go/scanner"
+	"go/token"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EnvColor is the environment variable to enable or disable colorized output.
+// Valid values are "always", "never", "auto" and any boolean value accepted by `strconv.ParseBool`.
+// In "auto" mode, output is colorized only if stdout is a terminal and `NO_COLOR` is not set.
+const EnvColor = "GO_ASSERT_COLOR"
+
+var reColor = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// stripColors removes ANSI colors in s.
+func stripColors(s string) string {
+	return reColor.ReplaceAllString(s, "")
+}
+
+// ANSI escape sequences used to colorize output.
+const (
+	colorReset   = "\x1b[0m"
+	colorKeyword = "\x1b[35m"
+	colorString  = "\x1b[32m"
+	colorNumber  = "\x1b[36m"
+	colorComment = "\x1b[90m"
+)
+
+// Color setting is initialized by SetDefault in init.
+var (
+	colorLock    sync.RWMutex
+	colorEnabled bool
+)
+
+// SetColor enables or disables colorized output.
+func SetColor(enabled bool) {
+	colorLock.Lock()
+	defer colorLock.Unlock()
+	colorEnabled = enabled
+}
+
+// ColorEnabled returns true if output should be colorized.
+func ColorEnabled() bool {
+	colorLock.RLock()
+	defer colorLock.RUnlock()
+	return colorEnabled
+}
+
+// detectColor returns true if output should be colorized in mode.
+// The mode can be "always", "never", "auto" or a boolean value.
+func detectColor(mode string) bool {
+	switch mode = strings.ToLower(mode); mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	case "", "auto":
+		if _, ok := os.LookupEnv("NO_COLOR"); ok {
+			return false
+		}
+
+		return terminalWidth() > 0
+	default:
+		enabled, _ := strconv.ParseBool(mode)
+		return enabled
+	}
+}
+
+// highlight adds ANSI colors to Go source code.
+// Whitespaces and unknown tokens in code are kept as is.
+//
+// Colors are always added. They are removed by fail if color is disabled,
+// as color can be enabled or disabled per assertion.
+func highlight(code string) string {
+	if code == "" {
+		return code
+	}
+
+	src := []byte(code)
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	s := &scanner.Scanner{}
+	s.Init(file, src, func(token.Position, string) {}, scanner.ScanComments)
+
+	buf := &strings.Builder{}
+	last := 0
+
+	for {
+		pos, tok, lit := s.Scan()
+
+		if tok == token.EOF {
+			break
+		}
+
+		// Ignore automatically inserted semicolons.
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+
+		color := tokenColor(tok, lit)
+
+		if color == "" {
+			continue
+		}
+
+		start := file.Offset(pos)
+		end := start + len(lit)
+
+		if lit == "" {
+			end = start + len(tok.String())
+		}
+
+		if start < last || end > len(src) {
+			continue
+		}
+
+		buf.Write(src[last:start])
+		buf.WriteString(color)
+		buf.Write(src[start:end])
+		buf.WriteString(colorReset)
+		last = end
+	}
+
+	buf.Write(src[last:])
+	return buf.String()
+}
+
+func tokenColor(tok token.Token, lit string) string {
+	switch {
+	case tok.IsKeyword():
+		return colorKeyword
+	case tok == token.STRING || tok == token.CHAR:
+		return colorString
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return colorNumber
+	case tok == token.COMMENT:
+		return colorComment
+	case tok == token.IDENT:
+		switch lit {
+		case "nil", "true", "false", "iota":
+			return colorNumber
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,222 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Comparator compares and formats values of a registered type,
+// e.g. a decimal type in a third-party package whose internal representation
+// is not canonical, so that `reflect.DeepEqual` and dumps of its struct internals are misleading.
+//
+// A map-like container, e.g. an ordered map, can be converted to a canonical form by Canonical,
+// so that it's compared, dumped and diffed as a plain map with deterministic key order.
+type Comparator struct {
+	Type      reflect.Type                      // The registered type.
+	Compare   func(a, b reflect.Value) int      // Returns 0 if a equals b. Values are of Type. If it's nil, values are compared by their canonical forms or `reflect.DeepEqual`.
+	Format    func(v reflect.Value) string      // Formats v in dumps. The v is of Type. If it's nil, v is dumped by its canonical form or as usual.
+	Canonical func(v reflect.Value) interface{} // Converts v to a canonical form, e.g. a map or a slice, whose type must not be Type. The v is of Type and addressable.
+}
+
+var (
+	comparatorLock sync.RWMutex
+	comparators    []*Comparator
+
+	// builtinComparators is the number of comparators registered by this package in init.
+	builtinComparators int
+)
+
+// AddComparator registers c.
+// Call remove to unregister c.
+// If there are several comparators of the same type, the last registered one is used.
+func AddComparator(c Comparator) (remove func()) {
+	entry := &c
+	comparatorLock.Lock()
+	comparators = append(comparators, entry)
+	comparatorLock.Unlock()
+
+	return func() {
+		comparatorLock.Lock()
+		defer comparatorLock.Unlock()
+
+		cs := make([]*Comparator, 0, len(comparators))
+
+		for _, c := range comparators {
+			if c != entry {
+				cs = append(cs, c)
+			}
+		}
+
+		comparators = cs
+	}
+}
+
+// hasUserComparators returns true if any comparator is registered by users.
+func hasUserComparators() bool {
+	comparatorLock.RLock()
+	defer comparatorLock.RUnlock()
+	return len(comparators) > builtinComparators
+}
+
+// findComparator returns the last registered comparator of t.
+func findComparator(t reflect.Type) *Comparator {
+	comparatorLock.RLock()
+	defer comparatorLock.RUnlock()
+
+	for i := len(comparators) - 1; i >= 0; i-- {
+		if comparators[i].Type == t {
+			return comparators[i]
+		}
+	}
+
+	return nil
+}
+
+// canonical returns the addressable canonical form of v.
+// It returns an invalid value if c.Canonical is nil or returns nil or a value of the registered type,
+// which cannot be converted any further.
+func (c *Comparator) canonical(v reflect.Value) reflect.Value {
+	if c.Canonical == nil {
+		return reflect.Value{}
+	}
+
+	canonical := addressable(reflect.ValueOf(c.Canonical(exportedAddressable(v))))
+
+	if canonical.IsValid() && canonical.Type() == c.Type {
+		return reflect.Value{}
+	}
+
+	return canonical
+}
+
+// comparatorEqual compares values like `reflect.DeepEqual`
+// except that values of registered types are compared by their comparators.
+type comparatorEqual struct {
+	visited map[visit]struct{}
+
+	// stopped stops the comparison if it's set to non-zero. Values are not equal after stopped.
+	// It can be nil.
+	stopped *int32
+}
+
+func (e *comparatorEqual) equal(v1, v2 reflect.Value) bool {
+	if e.stopped != nil && atomic.LoadInt32(e.stopped) != 0 {
+		return false
+	}
+
+	if !v1.IsValid() || !v2.IsValid() {
+		return v1.IsValid() == v2.IsValid()
+	}
+
+	if v1.Type() != v2.Type() {
+		return false
+	}
+
+	if c := findComparator(v1.Type()); c != nil {
+		if c.Compare != nil {
+			return c.Compare(reflect.ValueOf(exportedValue(v1)), reflect.ValueOf(exportedValue(v2))) == 0
+		}
+
+		if c1, c2 := c.canonical(v1), c.canonical(v2); c1.IsValid() && c2.IsValid() {
+			return e.equal(c1, c2)
+		}
+	}
+
+	switch v1.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v1.IsNil() || v2.IsNil() {
+			return v1.IsNil() == v2.IsNil()
+		}
+
+		if v1.Kind() == reflect.Slice && v1.Len() != v2.Len() || v1.Kind() == reflect.Map && v1.Len() != v2.Len() {
+			return false
+		}
+
+		if v1.Pointer() == v2.Pointer() {
+			return true
+		}
+
+		// Values referencing ancestors are being compared.
+		v := visit{v1.Pointer(), v2.Pointer(), v1.Type()}
+
+		if _, ok := e.visited[v]; ok {
+			return true
+		}
+
+		e.visited[v] = struct{}{}
+
+		switch v1.Kind() {
+		case reflect.Ptr:
+			return e.equal(v1.Elem(), v2.Elem())
+
+		case reflect.Map:
+			iter := v1.MapRange()
+
+			for iter.Next() {
+				if !e.equal(iter.Value(), v2.MapIndex(iter.Key())) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		return e.equalElements(v1, v2)
+
+	case reflect.Array:
+		return e.equalElements(v1, v2)
+
+	case reflect.Interface:
+		if v1.IsNil() || v2.IsNil() {
+			return v1.IsNil() == v2.IsNil()
+		}
+
+		return e.equal(v1.Elem(), v2.Elem())
+
+	case reflect.Struct:
+		for i := 0; i < v1.NumField(); i++ {
+			if !e.equal(v1.Field(i), v2.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+
+	case reflect.Func:
+		return v1.IsNil() && v2.IsNil()
+	}
+
+	return reflect.DeepEqual(exportedValue(v1), exportedValue(v2))
+}
+
+func (e *comparatorEqual) equalElements(v1, v2 reflect.Value) bool {
+	// Elements of basic types are compared at once, e.g. large byte slices.
+	if elem := v1.Type().Elem(); isBasicKind(elem.Kind()) && findComparator(elem) == nil {
+		return reflect.DeepEqual(exportedValue(v1), exportedValue(v2))
+	}
+
+	for i := 0; i < v1.Len(); i++ {
+		if !e.equal(v1.Index(i), v2.Index(i)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isBasicKind returns true if values of kind k contain no reference to other values.
+func isBasicKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,144 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// cents is an amount of money which compares and formats by its value in cents.
+type cents struct {
+	units, cents int64
+}
+
+func (c cents) value() int64 {
+	return c.units*100 + c.cents
+}
+
+func addCentsComparator() (remove func()) {
+	return AddComparator(Comparator{
+		Type: reflect.TypeOf(cents{}),
+		Compare: func(a, b reflect.Value) int {
+			v1 := a.Interface().(cents).value()
+			v2 := b.Interface().(cents).value()
+
+			switch {
+			case v1 < v2:
+				return -1
+			case v1 > v2:
+				return 1
+			}
+
+			return 0
+		},
+		Format: func(v reflect.Value) string {
+			value := v.Interface().(cents).value()
+			return fmt.Sprintf("$%v.%02d", value/100, value%100)
+		},
+	})
+}
+
+func TestComparatorEqual(t *testing.T) {
+	type item struct {
+		Name  string
+		price cents
+		Tags  map[string]*cents
+	}
+
+	v1 := []item{{"foo", cents{1, 50}, map[string]*cents{"tax": {0, 120}}}}
+	v2 := []item{{"foo", cents{0, 150}, map[string]*cents{"tax": {1, 20}}}}
+	assertEqual(t, deepEqual(v1, v2), false)
+	assertEqual(t, deepEqual(cents{1, 0}, cents{0, 100}), false)
+
+	remove := addCentsComparator()
+	assertEqual(t, deepEqual(v1, v2), true)
+	assertEqual(t, deepEqual(cents{1, 0}, cents{0, 100}), true)
+	assertEqual(t, deepEqual(cents{1, 0}, cents{0, 101}), false)
+	assertEqual(t, deepEqual(v1, []item{{"bar", cents{1, 50}, nil}}), false)
+	assertEqual(t, deepEqual([]interface{}{nil, 1.5}, []interface{}{nil, 1.5}), true)
+	assertEqual(t, DefaultDumper.Dump(v1[0].price), "(assertion.cents)$1.50")
+
+	remove()
+	assertEqual(t, deepEqual(v1, v2), false)
+	assertEqual(t, DefaultDumper.Dump(v1[0].price), "(assertion.cents){units:(int64)1 cents:(int64)50}")
+}
+
+func TestAssertEqualComparator(t *testing.T) {
+	type order struct {
+		ID    int
+		Total cents
+	}
+
+	defer addCentsComparator()()
+
+	ft := NewFakeT("TestAssertEqualComparator")
+	trigger := &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+	}
+
+	AssertEqual(ft, order{1, cents{2, 0}}, order{1, cents{0, 200}}, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertNotEqual(ft, order{1, cents{2, 0}}, order{1, cents{0, 200}}, trigger)
+	assertEqual(t, ft.Fatal(), true)
+
+	ft.Reset()
+	AssertEqual(ft, order{1, cents{2, 0}}, order{1, cents{0, 201}}, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "    .Total:\n        [1] -> (assertion.cents)$2.00\n        [2] -> (assertion.cents)$2.01\n"), true)
+}
+
+// orderedMap keeps keys in insertion order.
+// Maps with the same entries inserted in different orders are not DeepEqual.
+type orderedMap struct {
+	keys   []string
+	values map[string]int
+}
+
+func newOrderedMap(kvs ...interface{}) *orderedMap {
+	m := &orderedMap{
+		values: map[string]int{},
+	}
+
+	for i := 0; i < len(kvs); i += 2 {
+		m.keys = append(m.keys, kvs[i].(string))
+		m.values[kvs[i].(string)] = kvs[i+1].(int)
+	}
+
+	return m
+}
+
+func addOrderedMapCanonical() (remove func()) {
+	return AddComparator(Comparator{
+		Type: reflect.TypeOf(&orderedMap{}),
+		Canonical: func(v reflect.Value) interface{} {
+			return v.Interface().(*orderedMap).values
+		},
+	})
+}
+
+func TestComparatorCanonical(t *testing.T) {
+	type config struct {
+		Name string
+		Opts *orderedMap
+	}
+
+	v1 := config{"foo", newOrderedMap("b", 2, "a", 1)}
+	v2 := config{"foo", newOrderedMap("a", 1, "b", 2)}
+	assertEqual(t, deepEqual(v1, v2), false)
+
+	defer addOrderedMapCanonical()()
+	assertEqual(t, deepEqual(v1, v2), true)
+	assertEqual(t, deepEqual(v1, config{"foo", newOrderedMap("a", 1, "b", 3)}), false)
+	assertEqual(t, DefaultDumper.Dump(v1), "(assertion.config){Name:(string)foo Opts:(*assertion.orderedMap)map[a:1 b:2]}")
+	assertEqual(t, DefaultDumper.Dump(v2), DefaultDumper.Dump(v1))
+
+	d := diffValues(v1, config{"foo", newOrderedMap("c", 3, "b", 3, "a", 1)})
+	assertEqual(t, formatDiff(DefaultMessages, DefaultDumper, d), "Differences:\nOnly in [2]:\n    .Opts[\"c\"] = (int)3\nDifferent values:\n    .Opts[\"b\"]:\n        [1] -> (int)2\n        [2] -> (int)3\n(1 equal fields not shown)")
+}
@@ -0,0 +1,262 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// maxStatNodes is the max number of nodes visited when collecting size stats of a value.
+const maxStatNodes = 1 << 20
+
+// maxInlineCompareNodes is the max number of nodes of a value compared in current goroutine without the guard.
+const maxInlineCompareNodes = 1 << 12
+
+// guardedEqual returns deepEqual(v1, v2) if the comparison completes in compare timeout.
+// Otherwise, ok is false.
+//
+// Values with no more than maxInlineCompareNodes nodes are compared in current goroutine,
+// which always completes quickly unless comparators registered by users are called.
+// Other values are compared in a guarded goroutine.
+// A timed out comparison is stopped as soon as it visits next value.
+func guardedEqual(v1, v2 interface{}) (equal, ok bool) {
+	if equal, ok := fastEqual(v1, v2); ok {
+		return equal, true
+	}
+
+	small := countNodes(reflect.ValueOf(v1), maxInlineCompareNodes) >= 0 && countNodes(reflect.ValueOf(v2), maxInlineCompareNodes) >= 0
+
+	if small {
+		if reflect.DeepEqual(v1, v2) {
+			return true, true
+		}
+
+		if !hasUserComparators() {
+			return comparatorDeepEqual(v1, v2, nil), true
+		}
+	}
+
+	var result bool
+	var stopped int32
+
+	if !runGuarded(func() { result = comparatorDeepEqual(v1, v2, &stopped) }) {
+		atomic.StoreInt32(&stopped, 1)
+		return false, false
+	}
+
+	return result, true
+}
+
+// countNodes returns budget minus the number of nodes in v.
+// It stops counting and returns a negative number once budget is used up.
+// Shared references are counted every time, so that a cyclic value always uses up budget.
+func countNodes(v reflect.Value, budget int) int {
+	if budget < 0 || !v.IsValid() {
+		return budget
+	}
+
+	budget--
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			budget = countNodes(v.Elem(), budget)
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len() && budget >= 0; i++ {
+			budget = countNodes(v.Index(i), budget)
+		}
+
+	case reflect.Map:
+		if v.Len() > budget {
+			return -1
+		}
+
+		iter := v.MapRange()
+
+		for budget >= 0 && iter.Next() {
+			budget = countNodes(iter.Key(), budget)
+			budget = countNodes(iter.Value(), budget)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField() && budget >= 0; i++ {
+			budget = countNodes(v.Field(i), budget)
+		}
+	}
+
+	return budget
+}
+
+// runGuarded calls fn in a new goroutine and returns false if fn doesn't return in compare timeout.
+// A goroutine cannot be stopped. A timed out fn keeps running in background until it returns by itself.
+// If fn panics, runGuarded panics with the same value.
+func runGuarded(fn func()) bool {
+	timeout := currentCompareTimeout()
+
+	if timeout < 0 {
+		fn()
+		return true
+	}
+
+	done := make(chan struct{})
+	var recovered interface{}
+
+	go func() {
+		defer func() {
+			recovered = recover()
+			close(done)
+		}()
+
+		fn()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		if recovered != nil {
+			panic(recovered)
+		}
+
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// tooLargeFailure creates the failure of an assertion comparing v1 and v2 with size stats of them
+// when the comparison or dumps of v1 and v2 don't complete in compare timeout.
+func tooLargeFailure(msgs Messages, trigger *Trigger, f *Func, v1, v2 interface{}) *Failure {
+	info := trigger.P().ParseInfo(f)
+	stats1 := collectSizeStats(v1).format(msgs)
+	stats2 := collectSizeStats(v2).format(msgs)
+	return &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n[1] %v%v\n[2] %v%v\n%v\n[1] -> %v\n[2] -> %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4),
+			fmt.Sprintf(msgs.TooLargeToCompareFormat, formatDuration(currentCompareTimeout())),
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			indentCode(info.Args[1], 4), indentAssignments(info.Assignments[1], 4),
+			msgs.SizeStats, stats1, stats2, formatVars(msgs, info, trigger),
+		),
+		Values: []string{stats1, stats2},
+	}
+}
+
+// sizeStats describes the size of a value graph.
+type sizeStats struct {
+	Nodes     int  // Number of visited values.
+	Pointers  int  // Number of distinct pointers, slices and maps.
+	Revisits  int  // Number of references to visited pointers, slices and maps, i.e. shared or cyclic references.
+	MaxDepth  int  // Max depth of visited values.
+	Truncated bool // True if there are more than maxStatNodes nodes.
+}
+
+type statNode struct {
+	v     reflect.Value
+	depth int
+}
+
+type statPointer struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// collectSizeStats walks v to collect its size stats.
+// It stops after visiting maxStatNodes nodes, so that it completes quickly for any value.
+func collectSizeStats(v interface{}) (stats sizeStats) {
+	visited := map[statPointer]struct{}{}
+	stack := []statNode{{reflect.ValueOf(v), 0}}
+
+	for len(stack) > 0 {
+		if stats.Nodes >= maxStatNodes {
+			stats.Truncated = true
+			return
+		}
+
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !node.v.IsValid() {
+			continue
+		}
+
+		stats.Nodes++
+
+		if node.depth > stats.MaxDepth {
+			stats.MaxDepth = node.depth
+		}
+
+		val := node.v
+		depth := node.depth + 1
+
+		switch val.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			if val.IsNil() {
+				continue
+			}
+
+			key := statPointer{val.Pointer(), val.Type()}
+
+			if _, ok := visited[key]; ok {
+				stats.Revisits++
+				continue
+			}
+
+			visited[key] = struct{}{}
+			stats.Pointers++
+
+			switch val.Kind() {
+			case reflect.Ptr:
+				stack = append(stack, statNode{val.Elem(), depth})
+			case reflect.Slice:
+				for i := 0; i < val.Len(); i++ {
+					stack = append(stack, statNode{val.Index(i), depth})
+				}
+			case reflect.Map:
+				iter := val.MapRange()
+
+				for iter.Next() {
+					stack = append(stack, statNode{iter.Key(), depth}, statNode{iter.Value(), depth})
+				}
+			}
+
+		case reflect.Interface:
+			stack = append(stack, statNode{val.Elem(), depth})
+
+		case reflect.Struct:
+			for i := 0; i < val.NumField(); i++ {
+				stack = append(stack, statNode{val.Field(i), depth})
+			}
+
+		case reflect.Array:
+			for i := 0; i < val.Len(); i++ {
+				stack = append(stack, statNode{val.Index(i), depth})
+			}
+		}
+	}
+
+	return
+}
+
+func (stats sizeStats) format(msgs Messages) string {
+	nodes := strconv.Itoa(stats.Nodes)
+
+	if stats.Truncated {
+		nodes += "+"
+	}
+
+	return fmt.Sprintf(msgs.SizeStatsFormat, nodes, stats.Pointers, stats.Revisits, stats.MaxDepth)
+}
@@ -0,0 +1,125 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type guardNode struct {
+	Value    int
+	Next     *guardNode
+	Children []*guardNode
+}
+
+func TestCollectSizeStats(t *testing.T) {
+	n1 := &guardNode{Value: 1}
+	n2 := &guardNode{Value: 2, Next: n1}
+	n1.Next = n2
+	n1.Children = []*guardNode{n2, n2}
+
+	stats := collectSizeStats(n1)
+	assertEqual(t, stats.Pointers, 3)
+	assertEqual(t, stats.Revisits, 3)
+	assertEqual(t, stats.Truncated, false)
+	assertEqual(t, stats.format(DefaultMessages), "11 nodes, 3 distinct pointers, 3 shared or cyclic references, max depth 5")
+
+	stats = collectSizeStats(make([]int, maxStatNodes))
+	assertEqual(t, stats.Truncated, true)
+	assertEqual(t, strings.HasPrefix(stats.format(DefaultMessages), "1048576+ nodes"), true)
+
+	assertEqual(t, collectSizeStats(nil), sizeStats{})
+}
+
+// slowCompared and slowFormatted are compared and formatted by blocking funcs registered in tests.
+type slowCompared struct {
+	N int
+}
+
+type slowFormatted struct {
+	N int
+}
+
+func TestAssertEqualCompareTimeout(t *testing.T) {
+	defer SetCompareTimeout(0)
+	SetCompareTimeout(20 * time.Millisecond)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	defer AddComparator(Comparator{
+		Type: reflect.TypeOf(slowCompared{}),
+		Compare: func(a, b reflect.Value) int {
+			<-release
+			return 0
+		},
+	})()
+	defer AddComparator(Comparator{
+		Type: reflect.TypeOf(slowFormatted{}),
+		Format: func(v reflect.Value) string {
+			<-release
+			return ""
+		},
+	})()
+
+	ft := NewFakeT("TestAssertEqualCompareTimeout")
+	trigger := &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true},
+	}
+
+	// Comparison times out.
+	AssertEqual(ft, []slowCompared{{1}}, []slowCompared{{2}}, trigger)
+	AssertNotEqual(ft, []slowCompared{{1}}, []slowCompared{{2}}, trigger)
+
+	// Dumps time out.
+	AssertEqual(ft, []slowFormatted{{1}}, []slowFormatted{{2}}, trigger)
+
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 3)
+
+	for _, msg := range msgs {
+		assertEqual(t, strings.Contains(msg, "Values of following expressions are too large or cyclic to compare in 20ms."), true)
+		assertEqual(t, strings.Contains(msg, "Size stats:\n[1] -> 3 nodes, 1 distinct pointers, 0 shared or cyclic references, max depth 2"), true)
+	}
+}
+
+func TestRunGuardedPanic(t *testing.T) {
+	defer func() {
+		assertEqual(t, recover(), "boom")
+	}()
+
+	runGuarded(func() {
+		panic("boom")
+	})
+	t.Fatal("runGuarded should panic")
+}
+
+func TestCountNodes(t *testing.T) {
+	n1 := &guardNode{Value: 1}
+	n2 := &guardNode{Value: 2, Next: n1}
+
+	assertEqual(t, countNodes(reflect.ValueOf([]int{1, 2, 3}), 10), 6)
+	assertEqual(t, countNodes(reflect.ValueOf(map[string]int{"a": 1}), 10), 7)
+	assertEqual(t, countNodes(reflect.ValueOf(n2), 100), 91)
+	assertEqual(t, countNodes(reflect.ValueOf(nil), 10), 10)
+	assertEqual(t, countNodes(reflect.ValueOf(make(map[int]int, 20)), 10), 9)
+	assertEqual(t, countNodes(reflect.ValueOf(map[int]int{1: 1, 2: 2}), 1), -1)
+
+	// Cyclic values always use up budget.
+	n1.Next = n2
+	assertEqual(t, countNodes(reflect.ValueOf(n2), 100) < 0, true)
+}
+
+func TestComparatorDeepEqualStopped(t *testing.T) {
+	stopped := int32(0)
+	assertEqual(t, comparatorDeepEqual([]*guardNode{{Value: 1}}, []*guardNode{{Value: 1}}, &stopped), true)
+
+	stopped = 1
+	assertEqual(t, comparatorDeepEqual([]*guardNode{{Value: 1}}, []*guardNode{{Value: 1}}, &stopped), false)
+}
@@ -0,0 +1,364 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AssertDoesNotBlock expects fn returns within grace.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the stack of the goroutine running fn.
+//
+// Note that fn is not stopped when assertion fails.
+// The goroutine running fn leaks until fn returns.
+func AssertDoesNotBlock(t T, fn func(), grace time.Duration, trigger *Trigger) {
+	t.Helper()
+	started := make(chan uint64, 1)
+	done := make(chan struct{})
+
+	go func() {
+		started <- goroutineID()
+		fn()
+		close(done)
+	}()
+
+	id := <-started
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return
+	case <-timer.C:
+	}
+
+	stack := goroutineStack(id)
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed,
+			fmt.Sprintf(msgs.ShouldNotBlockFormat, formatDuration(grace)),
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.GoroutineStack, indentCode(stack, 4),
+			formatVars(msgs, info, trigger),
+		),
+		Values: []string{stack},
+	})
+}
+
+// AssertCompletes expects wgOrDoneChan completes within timeout.
+// The wgOrDoneChan can be a *sync.WaitGroup or a channel.
+// A channel completes when it's closed or receives a value.
+// Otherwise, it will terminate the test case using `t.Fatalf` with stacks of all goroutines.
+func AssertCompletes(t T, wgOrDoneChan interface{}, timeout time.Duration, trigger *Trigger) {
+	t.Helper()
+	done, err := waitChan(wgOrDoneChan)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	chosen, _, _ := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: done},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C)},
+	})
+
+	if chosen == 0 {
+		return
+	}
+
+	stacks := otherGoroutineStacks()
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed,
+			fmt.Sprintf(msgs.ShouldCompleteFormat, formatDuration(timeout)),
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.GoroutineStacks, indentCode(stacks, 4),
+			formatVars(msgs, info, trigger),
+		),
+		Values: []string{stacks},
+	})
+}
+
+// AssertReceivesCtx expects ch receives a value before ctx is done and returns the value.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the error of ctx and stacks of all goroutines.
+// It also fails if ch is closed.
+func AssertReceivesCtx(t T, ctx context.Context, ch interface{}, trigger *Trigger) interface{} {
+	t.Helper()
+	c := reflect.ValueOf(ch)
+
+	if c.Kind() != reflect.Chan || c.Type().ChanDir()&reflect.RecvDir == 0 || c.IsNil() {
+		failInternal(t, trigger, fmt.Errorf("expect a non-nil receivable channel but got %T", ch))
+		return nil
+	}
+
+	chosen, v, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: c},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+	})
+
+	if chosen == 0 && ok {
+		return v.Interface()
+	}
+
+	msgs := CurrentMessages()
+	reason := "\n" + msgs.ChannelClosed
+	values := []string(nil)
+
+	if chosen == 1 {
+		stacks := otherGoroutineStacks()
+		reason = fmt.Sprintf("\n%v\n    %v\n%v\n    %v", msgs.ContextError, ctx.Err(), msgs.GoroutineStacks, indentCode(stacks, 4))
+		values = []string{stacks}
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return nil
+	}
+
+	info := trigger.P().ParseInfo(f)
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, msgs.ShouldReceive,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			reason, formatVars(msgs, info, trigger),
+		),
+		Values: values,
+	})
+	return nil
+}
+
+// waitChan returns a channel which can be received when v completes.
+func waitChan(v interface{}) (done reflect.Value, err error) {
+	if wg, ok := v.(*sync.WaitGroup); ok {
+		ch := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(ch)
+		}()
+
+		done = reflect.ValueOf(ch)
+		return
+	}
+
+	done = reflect.ValueOf(v)
+
+	if done.Kind() != reflect.Chan || done.Type().ChanDir()&reflect.RecvDir == 0 {
+		err = fmt.Errorf("expect a *sync.WaitGroup or a receivable channel but got %T", v)
+		return
+	}
+
+	if done.IsNil() {
+		err = fmt.Errorf("channel is nil")
+		return
+	}
+
+	return
+}
+
+// goroutineID returns the id of current goroutine.
+func goroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	// The stack starts with "goroutine 123 [running]:".
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+
+	if idx := bytes.IndexByte(buf, ' '); idx >= 0 {
+		buf = buf[:idx]
+	}
+
+	id, _ := strconv.ParseUint(string(buf), 10, 64)
+	return id
+}
+
+// allGoroutineStacks returns stacks of all goroutines.
+func allGoroutineStacks() string {
+	buf := make([]byte, 64*1024)
+
+	for {
+		n := runtime.Stack(buf, true)
+
+		if n < len(buf) {
+			return strings.TrimSpace(string(buf[:n]))
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// otherGoroutineStacks returns stacks of all goroutines except current one and goroutines with excluded ids.
+func otherGoroutineStacks(excluded ...uint64) string {
+	prefixes := []string{"goroutine " + strconv.FormatUint(goroutineID(), 10) + " "}
+
+	for _, id := range excluded {
+		prefixes = append(prefixes, "goroutine "+strconv.FormatUint(id, 10)+" ")
+	}
+
+	stacks := strings.Split(allGoroutineStacks(), "\n\n")
+	others := make([]string, 0, len(stacks))
+
+	for _, stack := range stacks {
+		if !hasAnyPrefix(stack, prefixes) {
+			others = append(others, stack)
+		}
+	}
+
+	return strings.Join(others, "\n\n")
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// goroutineStack returns the stack of the goroutine with id.
+// It returns empty string if the goroutine doesn't exist.
+func goroutineStack(id uint64) string {
+	prefix := "goroutine " + strconv.FormatUint(id, 10) + " "
+
+	for _, stack := range strings.Split(allGoroutineStacks(), "\n\n") {
+		if strings.HasPrefix(stack, prefix) {
+			return stack
+		}
+	}
+
+	return ""
+}
+
+// RunWithinTimeout calls block in a new goroutine and expects it returns within timeout.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the source of block
+// and stacks of all goroutines. The stack of the goroutine running block is printed first.
+//
+// Assertions in block must report failures to the T and use the Options passed to block.
+// Their failures are reported to t after block returns.
+// If block panics, the panic is re-raised in the caller's goroutine.
+//
+// Note that block is not stopped when it times out.
+// The goroutine running block leaks until block returns and its failures are dropped.
+func RunWithinTimeout(t T, timeout time.Duration, opts Options, block func(t T, opts Options), trigger *Trigger) {
+	t.Helper()
+	at := &attemptT{
+		name: t.Name(),
+	}
+	started := make(chan uint64, 1)
+	done := make(chan struct{})
+	var panicked interface{}
+
+	go func() {
+		defer close(done)
+		defer func() {
+			panicked = recover()
+		}()
+
+		started <- goroutineID()
+		block(at, opts)
+	}()
+
+	id := <-started
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		if panicked != nil {
+			panic(panicked)
+		}
+
+		at.replay(t)
+		return
+	case <-timer.C:
+	}
+
+	stacks := goroutineStack(id)
+
+	if others := otherGoroutineStacks(id); others != "" {
+		stacks += "\n\n" + others
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	assignments := info.Assignments[0]
+
+	// Vars in a func literal are its own params and locals, not assigned outside.
+	if _, ok := f.Args[0].(*ast.FuncLit); ok {
+		assignments = nil
+	}
+
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed,
+			fmt.Sprintf(msgs.ShouldFinishFormat, formatDuration(timeout)),
+			indentCode(info.Args[0], 4), indentAssignments(assignments, 4),
+			msgs.GoroutineStacks, indentCode(stacks, 4),
+			formatVars(msgs, info, trigger),
+		),
+		Values: []string{stacks},
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoroutineStack(t *testing.T) {
+	started := make(chan uint64)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		started <- goroutineID()
+		<-done
+	}()
+
+	id := <-started
+	stack := goroutineStack(id)
+	Assert(t, strings.Contains(stack, "TestGoroutineStack"), &Trigger{
+		FuncName: "Assert",
+		Skip:     1,
+		Args:     []int{1},
+	})
+	assertEqual(t, goroutineStack(0), "")
+}
+
+func TestRunWithinTimeout(t *testing.T) {
+	ft := NewFakeT("TestRunWithinTimeout")
+	trigger := &Trigger{
+		FuncName: "RunWithinTimeout",
+		Args:     []int{3},
+	}
+
+	RunWithinTimeout(ft, time.Second, Options{}, func(t T, opts Options) {
+		Assert(t, true, &Trigger{
+			FuncName: "Assert",
+			Args:     []int{1},
+			Options:  opts,
+		})
+	}, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	// Failures in block are reported after block returns.
+	RunWithinTimeout(ft, time.Second, Options{}, func(t T, opts Options) {
+		Assert(t, 1 > 2, &Trigger{
+			FuncName: "Assert",
+			Args:     []int{1},
+			Options:  opts,
+		})
+	}, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "1 > 2"), true)
+
+	ft.Reset()
+	done := make(chan struct{})
+	defer close(done)
+	RunWithinTimeout(ft, 10*time.Millisecond, Options{}, func(t T, opts Options) {
+		<-done
+	}, trigger)
+	msgs = ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "Following block should finish within 10ms.\n    func(t T, opts Options) {\n        <-done\n    }\nGoroutine stacks:\n    goroutine "), true)
+	assertEqual(t, strings.Contains(msgs[0], "TestRunWithinTimeout.func"), true)
+}
+
+func TestRunWithinTimeoutPanic(t *testing.T) {
+	defer func() {
+		assertEqual(t, recover(), "boom")
+	}()
+
+	RunWithinTimeout(NewFakeT("TestRunWithinTimeoutPanic"), time.Second, Options{}, func(t T, opts Options) {
+		panic("boom")
+	}, &Trigger{})
+	t.Fatal("panic is not re-raised")
+}
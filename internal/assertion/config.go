@@ -0,0 +1,276 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Environment variables controlling dumps and differences.
+const (
+	EnvMaxDump       = "GO_ASSERT_MAX_DUMP"        // Max bytes of a dumped value. Set 0 to disable truncation.
+	EnvMaxOutput     = "GO_ASSERT_MAX_OUTPUT"      // Max bytes of a failure message. Set 0 to disable eliding.
+	EnvMaxTestOutput = "GO_ASSERT_MAX_TEST_OUTPUT" // Max bytes of all failure messages of a test. Set 0 to disable the budget.
+	EnvDiff          = "GO_ASSERT_DIFF"            // Set to a false value like "0" or "false" to print full dumps instead of differences.
+
+	EnvMaxRepeatedFailures = "GO_ASSERT_MAX_REPEATED_FAILURES" // Max failures printed per assertion call site in a test. Set 0 to print all failures.
+	EnvCompareTimeout      = "GO_ASSERT_COMPARE_TIMEOUT"       // Max duration to compare or dump values in Equal and NotEqual, e.g. "30s". Set a negative duration to disable the guard.
+	EnvGitHubAnnotations   = "GO_ASSERT_GITHUB_ANNOTATIONS"    // Set to a true value like "1" or "true" to print failures as GitHub Actions annotations.
+)
+
+// DefaultCompareTimeout is the max duration to compare or dump values in Equal and NotEqual by default.
+const DefaultCompareTimeout = 10 * time.Second
+
+// Config is the global default configuration of all assertions.
+type Config struct {
+	Color           string // Color mode, which can be "always", "never" or "auto". Empty means "auto".
+	Width           int    // Max width of output lines. If it's 0, width of terminal is used. If it's negative, lines are not wrapped.
+	Compact         bool   // Print every failure message in one line.
+	MaxDump         int    // Max bytes of a dumped value. Longer dumps are truncated. If it's 0, dumps are not truncated.
+	DisableDiff     bool   // Print full dumps instead of differences when Equal fails.
+	AssignmentDepth int    // Max number of hops to follow when finding assignments. If it's 0, 1 is used.
+	InlineHelpers   bool   // Print return statements of helpers called in assignments.
+	MaxOutput       int    // Max bytes of a failure message. The middle of a longer message is elided. If it's 0, messages are not elided.
+	MaxTestOutput   int    // Max bytes of all failure messages of a test. Following failures fail the test silently. If it's 0, there is no limit.
+	StableOutput    bool   // Replace run-specific details like pointers, temp dirs and durations in failure messages with placeholders.
+
+	// MaxRepeatedFailures is the max number of failures printed per assertion call site in a test
+	// when failures don't terminate the test, e.g. an assertion failing in every iteration of a loop.
+	// Following failures at the site are counted and summarized after the test completes.
+	// If it's 0, all failures are printed.
+	MaxRepeatedFailures int
+
+	// CompareTimeout is the max duration to compare or dump values in Equal and NotEqual.
+	// Pathological values, e.g. huge or cyclic graphs, fail with their size stats after the timeout
+	// instead of hanging the test.
+	// If it's 0, DefaultCompareTimeout is used. If it's negative, there is no limit.
+	CompareTimeout time.Duration
+
+	// GitHubAnnotations prints every failure as an `::error` workflow command in addition to the failure message,
+	// so that GitHub Actions shows failures inline on PR diffs.
+	// If env GITHUB_STEP_SUMMARY is set, failures are also appended to the job summary.
+	GitHubAnnotations bool
+}
+
+func init() {
+	SetDefault(Config{})
+}
+
+// SetDefault replaces global default configuration with c.
+// Environment variables take precedence over fields in c,
+// so that output can be tuned without code changes.
+func SetDefault(c Config) {
+	c = overrideConfig(c)
+	width := c.Width
+
+	if width == 0 {
+		width = detectWidth()
+	} else if width < 0 {
+		width = 0
+	}
+
+	SetColor(detectColor(c.Color))
+	SetWidth(width)
+	SetCompact(c.Compact)
+	SetMaxDump(c.MaxDump)
+	SetMaxOutput(c.MaxOutput, c.MaxTestOutput)
+	SetMaxRepeatedFailures(c.MaxRepeatedFailures)
+	SetCompareTimeout(c.CompareTimeout)
+	SetDiff(!c.DisableDiff)
+	SetAssignmentDepth(c.AssignmentDepth)
+	SetInlineHelpers(c.InlineHelpers)
+	SetStableOutput(c.StableOutput)
+	SetGitHubAnnotations(c.GitHubAnnotations)
+}
+
+// overrideConfig overrides fields in c with environment variables.
+func overrideConfig(c Config) Config {
+	if s := os.Getenv(EnvColor); s != "" {
+		c.Color = s
+	}
+
+	if width, err := strconv.Atoi(os.Getenv(EnvWidth)); err == nil && width >= 0 {
+		// Width 0 in env means no wrapping.
+		if width == 0 {
+			width = -1
+		}
+
+		c.Width = width
+	}
+
+	if compact, err := strconv.ParseBool(os.Getenv(EnvCompact)); err == nil {
+		c.Compact = compact
+	}
+
+	if maxDump, err := strconv.Atoi(os.Getenv(EnvMaxDump)); err == nil && maxDump >= 0 {
+		c.MaxDump = maxDump
+	}
+
+	if max, err := strconv.Atoi(os.Getenv(EnvMaxOutput)); err == nil && max >= 0 {
+		c.MaxOutput = max
+	}
+
+	if max, err := strconv.Atoi(os.Getenv(EnvMaxTestOutput)); err == nil && max >= 0 {
+		c.MaxTestOutput = max
+	}
+
+	if max, err := strconv.Atoi(os.Getenv(EnvMaxRepeatedFailures)); err == nil && max >= 0 {
+		c.MaxRepeatedFailures = max
+	}
+
+	if timeout, err := time.ParseDuration(os.Getenv(EnvCompareTimeout)); err == nil {
+		c.CompareTimeout = timeout
+	}
+
+	if diff, err := strconv.ParseBool(os.Getenv(EnvDiff)); err == nil {
+		c.DisableDiff = !diff
+	}
+
+	if stable, err := strconv.ParseBool(os.Getenv(EnvStable)); err == nil {
+		c.StableOutput = stable
+	}
+
+	if annotations, err := strconv.ParseBool(os.Getenv(EnvGitHubAnnotations)); err == nil {
+		c.GitHubAnnotations = annotations
+	}
+
+	return c
+}
+
+var (
+	configLock    sync.RWMutex
+	maxDump       int
+	maxOutput     int
+	maxTestOutput int
+	diffEnabled   bool
+
+	maxRepeatedFailures int
+	compareTimeout      time.Duration
+	githubAnnotations   bool
+)
+
+// SetMaxDump sets max bytes of a dumped value.
+// Longer dumps are truncated. Set it to 0 to disable truncation.
+func SetMaxDump(max int) {
+	configLock.Lock()
+	defer configLock.Unlock()
+	maxDump = max
+}
+
+// SetMaxOutput sets max bytes of a failure message and all failure messages of a test.
+// The middle of a failure message longer than maxFailure bytes is elided.
+// After a test prints maxTest bytes of failure messages, following failures fail the test silently.
+// The maxTest only applies to tests implementing `Cleanup(func())`, e.g. *testing.T.
+// Set either to 0 to disable the limit. Failure hooks always receive full messages.
+func SetMaxOutput(maxFailure, maxTest int) {
+	configLock.Lock()
+	defer configLock.Unlock()
+	maxOutput = maxFailure
+	maxTestOutput = maxTest
+}
+
+// SetMaxRepeatedFailures sets max number of failures printed per assertion call site in a test
+// when failures don't terminate the test.
+// Following failures at the site are summarized after the test completes, e.g. "...and 47 more failures at cases_test.go:88".
+// Set it to 0 to print all failures. Failure hooks always receive all failures.
+func SetMaxRepeatedFailures(max int) {
+	configLock.Lock()
+	defer configLock.Unlock()
+	maxRepeatedFailures = max
+}
+
+// SetCompareTimeout sets max duration to compare or dump values in Equal and NotEqual.
+// If it's 0, DefaultCompareTimeout is used. Set it to a negative duration to disable the limit.
+func SetCompareTimeout(timeout time.Duration) {
+	if timeout == 0 {
+		timeout = DefaultCompareTimeout
+	}
+
+	configLock.Lock()
+	defer configLock.Unlock()
+	compareTimeout = timeout
+}
+
+func currentCompareTimeout() time.Duration {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return compareTimeout
+}
+
+// SetGitHubAnnotations enables or disables printing failures as GitHub Actions annotations.
+func SetGitHubAnnotations(enabled bool) {
+	configLock.Lock()
+	defer configLock.Unlock()
+	githubAnnotations = enabled
+}
+
+// GitHubAnnotationsEnabled returns true if failures are printed as GitHub Actions annotations.
+func GitHubAnnotationsEnabled() bool {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return githubAnnotations
+}
+
+// SetDiff enables or disables differences in failure messages of Equal.
+// If it's disabled, full dumps of values are printed.
+func SetDiff(enabled bool) {
+	configLock.Lock()
+	defer configLock.Unlock()
+	diffEnabled = enabled
+}
+
+// DiffEnabled returns true if differences are printed when Equal fails.
+func DiffEnabled() bool {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return diffEnabled
+}
+
+func currentMaxDump() int {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return maxDump
+}
+
+// limitedDumper truncates dumps longer than max bytes.
+type limitedDumper struct {
+	Dumper
+	max int
+}
+
+// limitDumper returns a dumper truncating dumps according to max dump setting.
+func limitDumper(d Dumper) Dumper {
+	max := currentMaxDump()
+
+	if max <= 0 {
+		return d
+	}
+
+	return limitedDumper{
+		Dumper: d,
+		max:    max,
+	}
+}
+
+func (d limitedDumper) Dump(v interface{}) string {
+	s := d.Dumper.Dump(v)
+
+	if len(s) <= d.max {
+		return s
+	}
+
+	// Don't break a rune.
+	end := d.max
+
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+
+	return s[:end] + fmt.Sprintf(CurrentMessages().DumpTruncatedFormat, len(s)-end)
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOverrideConfig(t *testing.T) {
+	t.Setenv(EnvColor, "always")
+	t.Setenv(EnvWidth, "0")
+	t.Setenv(EnvCompact, "")
+	t.Setenv(EnvMaxDump, "100")
+	t.Setenv(EnvDiff, "false")
+	t.Setenv(EnvCompareTimeout, "1m")
+	t.Setenv(EnvGitHubAnnotations, "1")
+
+	c := overrideConfig(Config{
+		Color:   "never",
+		Width:   80,
+		Compact: true,
+		MaxDump: 10,
+	})
+	assertEqual(t, c, Config{
+		Color:             "always",
+		Width:             -1,
+		Compact:           true,
+		MaxDump:           100,
+		DisableDiff:       true,
+		CompareTimeout:    time.Minute,
+		GitHubAnnotations: true,
+	})
+}
+
+func TestLimitDumper(t *testing.T) {
+	defer SetMaxDump(0)
+
+	SetMaxDump(0)
+	assertEqual(t, limitDumper(DefaultDumper).Dump("foobar"), "(string)foobar")
+
+	SetMaxDump(10)
+	assertEqual(t, limitDumper(DefaultDumper).Dump("foobar"), "(string)fo... (4 more bytes)")
+	assertEqual(t, limitDumper(DefaultDumper).Dump("你好"), "(string)... (6 more bytes)")
+}
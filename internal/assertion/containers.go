@@ -0,0 +1,47 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+)
+
+// Comparators of containers in standard library.
+// DeepEqual compares their internal mutexes and pointers, which are meaningless in tests.
+// They are compared, dumped and diffed by contents instead.
+func init() {
+	AddComparator(Comparator{
+		Type:      reflect.TypeOf(sync.Map{}),
+		Canonical: syncMapEntries,
+	})
+	AddComparator(Comparator{
+		Type:      reflect.TypeOf(list.List{}),
+		Canonical: listElements,
+	})
+	builtinComparators = len(comparators)
+}
+
+// syncMapEntries returns all entries in a sync.Map as a map.
+func syncMapEntries(v reflect.Value) interface{} {
+	entries := map[interface{}]interface{}{}
+	v.Addr().Interface().(*sync.Map).Range(func(key, value interface{}) bool {
+		entries[key] = value
+		return true
+	})
+	return entries
+}
+
+// listElements returns values of all elements in a list.List as a slice.
+func listElements(v reflect.Value) interface{} {
+	l := v.Addr().Interface().(*list.List)
+	elements := make([]interface{}, 0, l.Len())
+
+	for e := l.Front(); e != nil; e = e.Next() {
+		elements = append(elements, e.Value)
+	}
+
+	return elements
+}
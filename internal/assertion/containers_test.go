@@ -0,0 +1,58 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"container/list"
+	"sync"
+	"testing"
+)
+
+func TestSyncMap(t *testing.T) {
+	type cache struct {
+		Name    string
+		entries *sync.Map
+	}
+
+	m1 := &sync.Map{}
+	m1.Store("a", 1)
+	m1.Store("b", 2)
+	m1.Load("a")
+
+	m2 := &sync.Map{}
+	m2.Store("b", 2)
+	m2.Store("a", 0)
+	m2.Store("a", 1)
+
+	assertEqual(t, deepEqual(m1, m2), true)
+	assertEqual(t, deepEqual(cache{"foo", m1}, cache{"foo", m2}), true)
+	assertEqual(t, DefaultDumper.Dump(m1), "(*sync.Map)map[(string)a:(int)1 (string)b:(int)2]")
+
+	m2.Delete("b")
+	m2.Store("c", 3)
+	assertEqual(t, deepEqual(m1, m2), false)
+
+	d := diffValues(cache{"foo", m1}, cache{"foo", m2})
+	assertEqual(t, formatDiff(DefaultMessages, DefaultDumper, d), "Differences:\nOnly in [1]:\n    .entries[\"b\"] = (int)2\nOnly in [2]:\n    .entries[\"c\"] = (int)3\n(1 equal fields not shown)")
+}
+
+func TestList(t *testing.T) {
+	l1 := list.New()
+	l1.PushBack(1)
+	l1.PushBack(2)
+
+	l2 := list.New()
+	l2.PushFront(2)
+	l2.PushFront(1)
+
+	assertEqual(t, deepEqual(l1, l2), true)
+	assertEqual(t, deepEqual([]*list.List{l1}, []*list.List{l2}), true)
+	assertEqual(t, DefaultDumper.Dump(l1), "(*list.List)[(int)1 (int)2]")
+
+	l2.PushBack(3)
+	assertEqual(t, deepEqual(l1, l2), false)
+
+	d := diffValues(l1, l2)
+	assertEqual(t, formatDiff(DefaultMessages, DefaultDumper, d), "Differences:\nOnly in [2]:\n    [2] = (int)3")
+}
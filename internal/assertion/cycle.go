@@ -0,0 +1,212 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"reflect"
+	"strings"
+)
+
+// maxDumpDepth is the max depth of a value dumped by cycleDumper.
+const maxDumpDepth = 32
+
+// hasCycle reports whether v references itself through pointers or maps.
+func hasCycle(v reflect.Value) bool {
+	c := &cycleFinder{
+		ancestors: map[uintptr]struct{}{},
+	}
+	return c.find(v, 0)
+}
+
+type cycleFinder struct {
+	ancestors map[uintptr]struct{}
+}
+
+func (c *cycleFinder) find(v reflect.Value, depth int) bool {
+	if depth > maxDumpDepth {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map:
+		if v.IsNil() {
+			return false
+		}
+
+		addr := v.Pointer()
+
+		if _, ok := c.ancestors[addr]; ok {
+			return true
+		}
+
+		c.ancestors[addr] = struct{}{}
+		defer delete(c.ancestors, addr)
+
+		if v.Kind() == reflect.Ptr {
+			return c.find(v.Elem(), depth+1)
+		}
+
+		for _, key := range v.MapKeys() {
+			if c.find(v.MapIndex(key), depth+1) {
+				return true
+			}
+		}
+
+	case reflect.Interface:
+		return c.find(v.Elem(), depth+1)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if c.find(v.Field(i), depth+1) {
+				return true
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if c.find(v.Index(i), depth+1) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// cycleDumper dumps a value in the same style as spew
+// and annotates pointers referencing their ancestors with paths of the ancestors.
+// Time values and timestamp fields are dumped in human form.
+type cycleDumper struct {
+	buf       strings.Builder
+	ancestors map[uintptr]string
+}
+
+// The field is the name of the struct field holding v. It's empty if v is not a field.
+func (d *cycleDumper) dump(path, field string, v reflect.Value, typed bool, depth int) {
+	if !v.IsValid() {
+		d.buf.WriteString("<nil>")
+		return
+	}
+
+	if depth > maxDumpDepth {
+		d.buf.WriteString("<max depth reached>")
+		return
+	}
+
+	if s, ok := humanize(v, field); ok {
+		d.writeType(v, typed)
+		d.buf.WriteString(s)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			d.writeType(v, typed)
+			d.buf.WriteString("<nil>")
+			return
+		}
+
+		d.dump(path, "", v.Elem(), true, depth+1)
+		return
+
+	case reflect.Ptr, reflect.Map:
+		if v.IsNil() {
+			d.writeType(v, typed)
+			d.buf.WriteString("<nil>")
+			return
+		}
+
+		addr := v.Pointer()
+
+		if target, ok := d.ancestors[addr]; ok {
+			d.writeType(v, typed)
+
+			if target == "" {
+				target = "root"
+			}
+
+			d.buf.WriteString("<cycle to " + target + ">")
+			return
+		}
+
+		d.ancestors[addr] = path
+		defer delete(d.ancestors, addr)
+
+		if v.Kind() == reflect.Ptr {
+			d.writeType(v, typed)
+			d.dump(path, field, v.Elem(), false, depth+1)
+			return
+		}
+
+		d.writeType(v, typed)
+		d.buf.WriteString("map[")
+		keys := v.MapKeys()
+		sortValues(keys)
+
+		for i, key := range keys {
+			if i > 0 {
+				d.buf.WriteString(" ")
+			}
+
+			d.dump(path, "", key, false, depth+1)
+			d.buf.WriteString(":")
+			d.dump(path+"["+formatKey(key)+"]", "", v.MapIndex(key), false, depth+1)
+		}
+
+		d.buf.WriteString("]")
+		return
+
+	case reflect.Struct:
+		t := v.Type()
+		d.writeType(v, typed)
+		d.buf.WriteString("{")
+
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				d.buf.WriteString(" ")
+			}
+
+			name := t.Field(i).Name
+			d.buf.WriteString(name + ":")
+			d.dump(path+"."+name, name, v.Field(i), true, depth+1)
+		}
+
+		d.buf.WriteString("}")
+		return
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			d.writeType(v, typed)
+			d.buf.WriteString("<nil>")
+			return
+		}
+
+		d.writeType(v, typed)
+		d.buf.WriteString("[")
+
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				d.buf.WriteString(" ")
+			}
+
+			d.dump(path+"["+formatKey(reflect.ValueOf(i))+"]", "", v.Index(i), false, depth+1)
+		}
+
+		d.buf.WriteString("]")
+		return
+	}
+
+	if typed {
+		d.buf.WriteString(dumpConfig.Sprintf("%#v", getValueInterface(v)))
+	} else {
+		d.buf.WriteString(dumpConfig.Sprintf("%v", getValueInterface(v)))
+	}
+}
+
+func (d *cycleDumper) writeType(v reflect.Value, typed bool) {
+	if typed {
+		d.buf.WriteString("(" + v.Type().String() + ")")
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+type cycleNode struct {
+	Name     string
+	Parent   *cycleNode
+	Children []*cycleNode
+}
+
+func newCycleTree(child string) *cycleNode {
+	root := &cycleNode{Name: "root"}
+	root.Children = []*cycleNode{
+		{Name: child, Parent: root},
+	}
+	return root
+}
+
+func TestDumpCycle(t *testing.T) {
+	root := newCycleTree("child")
+	assertEqual(t, DefaultDumper.Dump(root), "(*assertion.cycleNode){Name:(string)root Parent:(*assertion.cycleNode)<nil> Children:([]*assertion.cycleNode)[{Name:(string)child Parent:(*assertion.cycleNode)<cycle to root> Children:([]*assertion.cycleNode)<nil>}]}")
+
+	m := map[string]interface{}{}
+	m["self"] = m
+	assertEqual(t, DefaultDumper.Dump(m), `(map[string]interface {})map[self:(map[string]interface {})<cycle to root>]`)
+
+	// Values without cycle are dumped by spew.
+	assertEqual(t, DefaultDumper.Dump([]int{1, 2}), "([]int)[1 2]")
+}
+
+func TestDiffCycle(t *testing.T) {
+	v1 := newCycleTree("foo")
+	v2 := newCycleTree("bar")
+
+	diff := formatDiff(DefaultMessages, DefaultDumper, diffValues(v1, v2))
+	assertEqual(t, diff, strings.Join([]string{
+		`Differences:`,
+		`Different values:`,
+		`    .Children[0].Name:`,
+		`        [1] -> (string)foo`,
+		`        [2] -> (string)bar`,
+		`(3 equal fields not shown)`,
+	}, "\n"))
+}
@@ -0,0 +1,546 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxDiffEntries is the max number of entries printed in a diff section.
+const maxDiffEntries = 20
+
+// maxDiffDepth is the max depth of values compared by differ.
+// Values deeper than it are compared as a whole.
+const maxDiffDepth = 32
+
+// maxLCSCells is the max size of the table to compute LCS of two slices.
+// If the table is larger than it, slices are compared index by index.
+const maxLCSCells = 1 << 20
+
+type diffKind int
+
+const (
+	diffChanged diffKind = iota // Value exists in both sides but differs.
+	diffOnlyIn1                 // Value exists only in [1].
+	diffOnlyIn2                 // Value exists only in [2].
+)
+
+// diffEntry is a difference found by differ.
+type diffEntry struct {
+	Kind diffKind
+	Path string
+	V1   reflect.Value
+	V2   reflect.Value
+	Key  reflect.Value // Key of the map element. It's invalid if the entry is not a map element.
+
+	// Unexported is true if the path contains any unexported struct field.
+	Unexported bool
+}
+
+// differ walks two values and collects differences.
+type differ struct {
+	Entries []diffEntry
+	Equal   int // Number of equal struct fields which are not in Entries.
+
+	// LabelUnexported labels entries of unexported fields in formatted differences.
+	LabelUnexported bool
+
+	depth      int
+	unexported int // Number of unexported fields in current path.
+	visited    map[visit]struct{}
+}
+
+// visit is a pair of pointers being compared by differ.
+// It's used to detect cycles in values.
+type visit struct {
+	p1, p2 uintptr
+	typ    reflect.Type
+}
+
+// diffValues compares v1 and v2 and returns all differences.
+// It returns nil if v1 and v2 cannot be compared piece by piece.
+// In this case, caller should dump both values instead.
+func diffValues(v1, v2 interface{}) *differ {
+	val1 := addressable(reflect.ValueOf(v1))
+	val2 := addressable(reflect.ValueOf(v2))
+
+	if !val1.IsValid() || !val2.IsValid() || val1.Type() != val2.Type() {
+		return nil
+	}
+
+	switch val1.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Ptr, reflect.Interface:
+	default:
+		return nil
+	}
+
+	d := &differ{
+		visited: map[visit]struct{}{},
+	}
+	d.diff("", val1, val2)
+
+	// The values are totally different. Dumps are more readable.
+	for _, entry := range d.Entries {
+		if entry.Path == "" {
+			return nil
+		}
+	}
+
+	return d
+}
+
+func (d *differ) add(kind diffKind, path string, v1, v2 reflect.Value) {
+	d.Entries = append(d.Entries, diffEntry{
+		Kind:       kind,
+		Path:       path,
+		V1:         v1,
+		V2:         v2,
+		Unexported: d.unexported > 0,
+	})
+}
+
+func (d *differ) diff(path string, v1, v2 reflect.Value) {
+	if d.depth >= maxDiffDepth {
+		if !deepEqual(exportedValue(v1), exportedValue(v2)) {
+			d.add(diffChanged, path, v1, v2)
+		}
+
+		return
+	}
+
+	// Values of registered types are compared as a whole unless they have canonical forms.
+	if c := findComparator(v1.Type()); c != nil {
+		if c.Compare == nil {
+			c1 := c.canonical(v1)
+			c2 := c.canonical(v2)
+
+			if c1.IsValid() && c2.IsValid() && c1.Type() == c2.Type() {
+				d.diff(path, c1, c2)
+				return
+			}
+		}
+
+		if !deepEqual(exportedValue(v1), exportedValue(v2)) {
+			d.add(diffChanged, path, v1, v2)
+		}
+
+		return
+	}
+
+	d.depth++
+	defer func() {
+		d.depth--
+	}()
+
+	switch v1.Kind() {
+	case reflect.Map, reflect.Ptr:
+		if v1.IsNil() || v2.IsNil() {
+			break
+		}
+
+		// Pointers referencing ancestors have been compared.
+		v := visit{v1.Pointer(), v2.Pointer(), v1.Type()}
+
+		if _, ok := d.visited[v]; ok {
+			return
+		}
+
+		d.visited[v] = struct{}{}
+		defer delete(d.visited, v)
+
+		if v1.Kind() == reflect.Ptr {
+			d.diff(path, v1.Elem(), v2.Elem())
+		} else {
+			d.diffMap(path, v1, v2)
+		}
+
+		return
+	case reflect.Interface:
+		if v1.IsNil() || v2.IsNil() || v1.Elem().Type() != v2.Elem().Type() {
+			break
+		}
+
+		d.diff(path, v1.Elem(), v2.Elem())
+		return
+	case reflect.Slice:
+		if !v1.IsNil() && !v2.IsNil() {
+			d.diffSlice(path, v1, v2)
+			return
+		}
+	case reflect.Array:
+		d.diffSlice(path, v1, v2)
+		return
+	case reflect.Struct:
+		// Values dumped in human form, e.g. time.Time, are compared as a whole.
+		if !isHumanizedStruct(v1.Type()) {
+			d.diffStruct(path, v1, v2)
+			return
+		}
+	}
+
+	if !deepEqual(exportedValue(v1), exportedValue(v2)) {
+		d.add(diffChanged, path, v1, v2)
+	}
+}
+
+func (d *differ) diffMap(path string, v1, v2 reflect.Value) {
+	keys := v1.MapKeys()
+
+	for _, key := range v2.MapKeys() {
+		if !v1.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+
+	sortValues(keys)
+
+	for _, key := range keys {
+		elem1 := v1.MapIndex(key)
+		elem2 := v2.MapIndex(key)
+		p := path + "[" + formatKey(key) + "]"
+
+		switch {
+		case !elem2.IsValid():
+			d.add(diffOnlyIn1, p, elem1, elem2)
+			d.Entries[len(d.Entries)-1].Key = key
+		case !elem1.IsValid():
+			d.add(diffOnlyIn2, p, elem1, elem2)
+			d.Entries[len(d.Entries)-1].Key = key
+		default:
+			d.diff(p, elem1, elem2)
+		}
+	}
+}
+
+// diffStruct compares struct fields one by one.
+// Only different fields are added to entries. Equal fields are counted.
+func (d *differ) diffStruct(path string, v1, v2 reflect.Value) {
+	t := v1.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field1 := v1.Field(i)
+		field2 := v2.Field(i)
+
+		if deepEqual(exportedValue(field1), exportedValue(field2)) {
+			d.Equal++
+			continue
+		}
+
+		if field := t.Field(i); field.PkgPath != "" {
+			d.unexported++
+			d.diff(path+"."+field.Name, field1, field2)
+			d.unexported--
+		} else {
+			d.diff(path+"."+field.Name, field1, field2)
+		}
+	}
+}
+
+// diffSlice compares elements in slices or arrays by finding the longest common subsequence.
+// Removed elements are reported with their indexes in v1 and inserted elements are
+// reported with their indexes in v2.
+// A removed element followed by an inserted element is reported as a changed element.
+func (d *differ) diffSlice(path string, v1, v2 reflect.Value) {
+	n1, n2 := v1.Len(), v2.Len()
+	equal := func(i, j int) bool {
+		return deepEqual(exportedValue(v1.Index(i)), exportedValue(v2.Index(j)))
+	}
+
+	// Skip common prefix and suffix to reduce the size of LCS table.
+	start := 0
+
+	for start < n1 && start < n2 && equal(start, start) {
+		start++
+	}
+
+	end1, end2 := n1, n2
+
+	for end1 > start && end2 > start && equal(end1-1, end2-1) {
+		end1--
+		end2--
+	}
+
+	m1, m2 := end1-start, end2-start
+	ops := make([]sliceOp, 0, m1+m2)
+
+	if m1*m2 > maxLCSCells {
+		// Too large to compute LCS. Compare elements index by index.
+		for i := 0; i < m1 || i < m2; i++ {
+			switch {
+			case i >= m2:
+				ops = append(ops, sliceOp{Kind: diffOnlyIn1, I: start + i})
+			case i >= m1:
+				ops = append(ops, sliceOp{Kind: diffOnlyIn2, J: start + i})
+			default:
+				ops = append(ops, sliceOp{Kind: diffChanged, I: start + i, J: start + i})
+			}
+		}
+	} else {
+		// lcs[i][j] is the length of LCS of v1[start+i:end1] and v2[start+j:end2].
+		lcs := make([][]int, m1+1)
+
+		for i := range lcs {
+			lcs[i] = make([]int, m2+1)
+		}
+
+		for i := m1 - 1; i >= 0; i-- {
+			for j := m2 - 1; j >= 0; j-- {
+				if equal(start+i, start+j) {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+
+		i, j := 0, 0
+
+		for i < m1 || j < m2 {
+			switch {
+			case i < m1 && j < m2 && equal(start+i, start+j):
+				// Keep equal elements to separate removed and inserted elements around them.
+				ops = append(ops, sliceOp{Kind: diffChanged, I: start + i, J: start + j, Equal: true})
+				i++
+				j++
+			case j == m2 || i < m1 && lcs[i+1][j] >= lcs[i][j+1]:
+				ops = append(ops, sliceOp{Kind: diffOnlyIn1, I: start + i})
+				i++
+			default:
+				ops = append(ops, sliceOp{Kind: diffOnlyIn2, J: start + j})
+				j++
+			}
+		}
+
+		ops = pairSliceOps(ops)
+	}
+
+	for _, op := range ops {
+		if op.Equal {
+			continue
+		}
+
+		switch op.Kind {
+		case diffOnlyIn1:
+			d.add(diffOnlyIn1, path+fmt.Sprintf("[%v]", op.I), v1.Index(op.I), reflect.Value{})
+		case diffOnlyIn2:
+			d.add(diffOnlyIn2, path+fmt.Sprintf("[%v]", op.J), reflect.Value{}, v2.Index(op.J))
+		default:
+			d.diff(path+fmt.Sprintf("[%v]", op.I), v1.Index(op.I), v2.Index(op.J))
+		}
+	}
+}
+
+type sliceOp struct {
+	Kind  diffKind
+	I     int  // Index in v1.
+	J     int  // Index in v2.
+	Equal bool // Elements are equal.
+}
+
+// pairSliceOps merges adjacent removed and inserted elements to changed elements.
+func pairSliceOps(ops []sliceOp) []sliceOp {
+	paired := make([]sliceOp, 0, len(ops))
+
+	for len(ops) > 0 {
+		removed := 0
+
+		for removed < len(ops) && ops[removed].Kind == diffOnlyIn1 {
+			removed++
+		}
+
+		inserted := 0
+
+		for removed+inserted < len(ops) && ops[removed+inserted].Kind == diffOnlyIn2 {
+			inserted++
+		}
+
+		if removed == 0 && inserted == 0 {
+			paired = append(paired, ops[0])
+			ops = ops[1:]
+			continue
+		}
+
+		changed := removed
+
+		if inserted < changed {
+			changed = inserted
+		}
+
+		for k := 0; k < changed; k++ {
+			paired = append(paired, sliceOp{Kind: diffChanged, I: ops[k].I, J: ops[removed+k].J})
+		}
+
+		paired = append(paired, ops[changed:removed]...)
+		paired = append(paired, ops[removed+changed:removed+inserted]...)
+		ops = ops[removed+inserted:]
+	}
+
+	return paired
+}
+
+// formatKey formats a map key in Go syntax.
+func formatKey(key reflect.Value) string {
+	return fmt.Sprintf("%#v", exportedValue(key))
+}
+
+// sortValues sorts values in a stable and human-friendly order.
+// Numbers are sorted by value, strings are sorted lexically and
+// other values are sorted by their Go syntax representation.
+func sortValues(values []reflect.Value) {
+	sort.SliceStable(values, func(i, j int) bool {
+		return lessValue(values[i], values[j])
+	})
+}
+
+func lessValue(v1, v2 reflect.Value) bool {
+	if v1.Kind() == reflect.Interface {
+		v1 = v1.Elem()
+	}
+
+	if v2.Kind() == reflect.Interface {
+		v2 = v2.Elem()
+	}
+
+	if v1.Kind() == v2.Kind() {
+		switch v1.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return v1.Int() < v2.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return v1.Uint() < v2.Uint()
+		case reflect.Float32, reflect.Float64:
+			return v1.Float() < v2.Float()
+		case reflect.String:
+			return v1.String() < v2.String()
+		case reflect.Bool:
+			return !v1.Bool() && v2.Bool()
+		}
+	}
+
+	return fmt.Sprintf("%#v", exportedValue(v1)) < fmt.Sprintf("%#v", exportedValue(v2))
+}
+
+// FormatDiff formats differences between v1 and v2 in the same way as Equal.
+// It returns an empty string if v1 and v2 cannot be compared piece by piece or there is no difference.
+func FormatDiff(v1, v2 interface{}) string {
+	return formatValuesDiff(CurrentMessages(), limitDumper(CurrentDumper()), v1, v2, UnexportedCompare)
+}
+
+func formatValuesDiff(msgs Messages, dumper Dumper, v1, v2 interface{}, mode UnexportedMode) string {
+	d := diffValues(v1, v2)
+
+	if d == nil || len(d.Entries) == 0 {
+		return ""
+	}
+
+	d.LabelUnexported = mode == UnexportedLabel
+
+	return formatDiff(msgs, dumper, d) + formatIncomparableNotes(msgs, d) + formatKeyTypoNotes(msgs, d)
+}
+
+// formatDiff formats differences in sections.
+func formatDiff(msgs Messages, dumper Dumper, d *differ) string {
+	entries := d.Entries
+	sections := []struct {
+		Kind  diffKind
+		Title string
+	}{
+		{diffOnlyIn1, msgs.DiffOnlyIn1},
+		{diffOnlyIn2, msgs.DiffOnlyIn2},
+		{diffChanged, msgs.DiffChanged},
+	}
+	lines := make([]string, 0, 2*len(entries)+4)
+	lines = append(lines, msgs.Differences)
+
+	for _, section := range sections {
+		count := 0
+
+		for _, entry := range entries {
+			if entry.Kind != section.Kind {
+				continue
+			}
+
+			if count == 0 {
+				lines = append(lines, section.Title)
+			}
+
+			count++
+
+			if count > maxDiffEntries {
+				continue
+			}
+
+			path := entry.Path
+
+			if d.LabelUnexported && entry.Unexported {
+				path += " " + msgs.DiffUnexported
+			}
+
+			switch entry.Kind {
+			case diffOnlyIn1:
+				lines = append(lines, "    "+path+" = "+dumpValue(dumper, entry.V1))
+			case diffOnlyIn2:
+				lines = append(lines, "    "+path+" = "+dumpValue(dumper, entry.V2))
+			default:
+				lines = append(lines,
+					"    "+path+":",
+					"        [1] -> "+dumpValue(dumper, entry.V1),
+					"        [2] -> "+dumpValue(dumper, entry.V2),
+				)
+			}
+		}
+
+		if count > maxDiffEntries {
+			lines = append(lines, "    "+fmt.Sprintf(msgs.DiffMoreFormat, count-maxDiffEntries))
+		}
+	}
+
+	if d.Equal > 0 {
+		lines = append(lines, fmt.Sprintf(msgs.DiffEqualFormat, d.Equal))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatIncomparableNotes returns notes if the only differences are func or chan members,
+// which cannot be DeepEqual in most cases.
+func formatIncomparableNotes(msgs Messages, d *differ) string {
+	if len(d.Entries) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(d.Entries)+1)
+	lines = append(lines, "\n"+msgs.Notes)
+
+	for _, entry := range d.Entries {
+		if entry.Kind != diffChanged {
+			return ""
+		}
+
+		name := strings.TrimPrefix(entry.Path, ".")
+
+		switch entry.V1.Kind() {
+		case reflect.Func:
+			lines = append(lines, "    "+fmt.Sprintf(msgs.FuncMemberFormat, name))
+		case reflect.Chan:
+			lines = append(lines, "    "+fmt.Sprintf(msgs.ChanMemberFormat, name))
+		default:
+			return ""
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func dumpValue(dumper Dumper, v reflect.Value) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+
+	return dumper.Dump(exportedValue(v))
+}
@@ -0,0 +1,172 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiffMap(t *testing.T) {
+	v1 := map[string]int{
+		"foo": 1,
+		"bar": 2,
+		"baz": 3,
+	}
+	v2 := map[string]int{
+		"foo": 1,
+		"bar": 20,
+		"qux": 4,
+	}
+	diff := formatDiff(DefaultMessages, DefaultDumper, diffValues(v1, v2))
+	assertEqual(t, diff, strings.Join([]string{
+		`Differences:`,
+		`Only in [1]:`,
+		`    ["baz"] = (int)3`,
+		`Only in [2]:`,
+		`    ["qux"] = (int)4`,
+		`Different values:`,
+		`    ["bar"]:`,
+		`        [1] -> (int)2`,
+		`        [2] -> (int)20`,
+	}, "\n"))
+}
+
+func TestDiffTruncated(t *testing.T) {
+	v1 := map[int]string{}
+	v2 := map[int]string{}
+
+	for i := 0; i < maxDiffEntries+5; i++ {
+		v1[i] = fmt.Sprint(i)
+	}
+
+	diff := formatDiff(DefaultMessages, DefaultDumper, diffValues(v1, v2))
+	lines := strings.Split(diff, "\n")
+	assertEqual(t, len(lines), maxDiffEntries+3)
+	assertEqual(t, lines[2], "    [0] = (string)0")
+	assertEqual(t, lines[len(lines)-1], "    ... and 5 more")
+}
+
+func TestDiffSlice(t *testing.T) {
+	v1 := make([]int, 500)
+	v2 := make([]int, 500)
+
+	for i := range v1 {
+		v1[i] = i
+		v2[i] = i
+	}
+
+	v2[123] = -1
+	diff := formatDiff(DefaultMessages, DefaultDumper, diffValues(v1, v2))
+	assertEqual(t, diff, strings.Join([]string{
+		`Differences:`,
+		`Different values:`,
+		`    [123]:`,
+		`        [1] -> (int)123`,
+		`        [2] -> (int)-1`,
+	}, "\n"))
+
+	diff = formatDiff(DefaultMessages, DefaultDumper, diffValues(
+		[]string{"a", "b", "c", "d", "e"},
+		[]string{"a", "x", "c", "e", "f"},
+	))
+	assertEqual(t, diff, strings.Join([]string{
+		`Differences:`,
+		`Only in [1]:`,
+		`    [3] = (string)d`,
+		`Only in [2]:`,
+		`    [4] = (string)f`,
+		`Different values:`,
+		`    [1]:`,
+		`        [1] -> (string)b`,
+		`        [2] -> (string)x`,
+	}, "\n"))
+
+	diff = formatDiff(DefaultMessages, DefaultDumper, diffValues([2][]int{{1, 2}, {3}}, [2][]int{{1, 2}, {3, 4}}))
+	assertEqual(t, diff, strings.Join([]string{
+		`Differences:`,
+		`Only in [2]:`,
+		`    [1][1] = (int)4`,
+	}, "\n"))
+
+	assertEqual(t, diffValues([]int(nil), []int{}) == nil, true)
+}
+
+type diffUser struct {
+	Name    string
+	Age     int
+	Email   string
+	Address diffAddress
+	tags    []string
+}
+
+type diffAddress struct {
+	City   string
+	Street string
+	Zip    string
+}
+
+func TestDiffStruct(t *testing.T) {
+	v1 := diffUser{
+		Name:  "Alice",
+		Age:   20,
+		Email: "alice@example.com",
+		Address: diffAddress{
+			City:   "Beijing",
+			Street: "Main St.",
+			Zip:    "100000",
+		},
+		tags: []string{"foo"},
+	}
+	v2 := v1
+	v2.Age = 21
+	v2.Address.Zip = "100001"
+	v2.tags = []string{"foo", "bar"}
+
+	diff := formatDiff(DefaultMessages, DefaultDumper, diffValues(v1, v2))
+	assertEqual(t, diff, strings.Join([]string{
+		`Differences:`,
+		`Only in [2]:`,
+		`    .tags[1] = (string)bar`,
+		`Different values:`,
+		`    .Age:`,
+		`        [1] -> (int)20`,
+		`        [2] -> (int)21`,
+		`    .Address.Zip:`,
+		`        [1] -> (string)100000`,
+		`        [2] -> (string)100001`,
+		`(4 equal fields not shown)`,
+	}, "\n"))
+}
+
+type diffServer struct {
+	Addr    string
+	Handler func()
+	Done    chan struct{}
+}
+
+func TestFormatIncomparableNotes(t *testing.T) {
+	v1 := diffServer{
+		Addr:    ":8080",
+		Handler: func() {},
+		Done:    make(chan struct{}),
+	}
+	v2 := diffServer{
+		Addr:    ":8080",
+		Handler: func() {},
+		Done:    make(chan struct{}),
+	}
+	notes := formatIncomparableNotes(DefaultMessages, diffValues(v1, v2))
+	assertEqual(t, notes, strings.Join([]string{
+		``,
+		`Notes:`,
+		`    Handler is a func; funcs are never DeepEqual unless both are nil. Compare other members instead.`,
+		`    Done is a chan; chans are DeepEqual only if they are the same chan. Compare other members instead.`,
+	}, "\n"))
+
+	v2.Addr = ":8081"
+	notes = formatIncomparableNotes(DefaultMessages, diffValues(v1, v2))
+	assertEqual(t, notes, "")
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Dumper formats values in failure messages.
+type Dumper interface {
+	Dump(v interface{}) string
+}
+
+// DumperFunc is a func implementing Dumper.
+type DumperFunc func(v interface{}) string
+
+// Dump calls f(v).
+func (f DumperFunc) Dump(v interface{}) string {
+	return f(v)
+}
+
+// DefaultDumper is the default dumper backed by spew.
+// A value with pointer cycles is dumped with annotations like `<cycle to .Parent>`.
+// A time.Time is dumped in RFC3339 format and a time.Duration is dumped like "1m30s".
+// Big numbers in math/big are dumped in their canonical string forms.
+// Integer fields matching any timestamp hint are dumped with their time.
+var DefaultDumper Dumper = spewDumper{}
+
+type spewDumper struct{}
+
+func (spewDumper) Dump(v interface{}) string {
+	if val := reflect.ValueOf(v); !hasCycle(val) && !hasHumanized(val) {
+		return dumpConfig.Sprintf("%#v", v)
+	}
+
+	d := &cycleDumper{
+		ancestors: map[uintptr]string{},
+	}
+	d.dump("", "", addressable(reflect.ValueOf(v)), true, 0)
+	return d.buf.String()
+}
+
+var (
+	dumperLock sync.RWMutex
+	dumper     = DefaultDumper
+)
+
+// SetDumper replaces the dumper used by all assertions.
+// If d is nil, DefaultDumper is used.
+func SetDumper(d Dumper) {
+	if d == nil {
+		d = DefaultDumper
+	}
+
+	dumperLock.Lock()
+	defer dumperLock.Unlock()
+	dumper = d
+}
+
+// CurrentDumper returns current dumper.
+func CurrentDumper() Dumper {
+	dumperLock.RLock()
+	defer dumperLock.RUnlock()
+	return dumper
+}
@@ -0,0 +1,26 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetDumper(t *testing.T) {
+	defer SetDumper(nil)
+
+	assertEqual(t, CurrentDumper().Dump([]int{1, 2}), "([]int)[1 2]")
+
+	SetDumper(DumperFunc(func(v interface{}) string {
+		return fmt.Sprintf("%v", v)
+	}))
+	assertEqual(t, CurrentDumper().Dump([]int{1, 2}), "[1 2]")
+
+	d := diffValues(map[string]int{"a": 1}, map[string]int{"a": 2})
+	assertEqual(t, formatDiff(DefaultMessages, CurrentDumper(), d), "Differences:\nDifferent values:\n    [\"a\"]:\n        [1] -> 1\n        [2] -> 2")
+
+	SetDumper(nil)
+	assertEqual(t, CurrentDumper().Dump([]int{1, 2}), "([]int)[1 2]")
+}
@@ -0,0 +1,136 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var errInvalidDurationRange = errors.New("go-assert: lower bound of duration must not be greater than upper bound")
+
+// AssertDurationLess expects d is less than limit.
+// Otherwise, it will terminate the test case using `t.Fatalf` with d and how much d exceeds limit.
+func AssertDurationLess(t T, d, limit time.Duration, trigger *Trigger) {
+	t.Helper()
+	if d < limit {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	fail(t, trigger, durationFailure(msgs, trigger, f, fmt.Sprintf(msgs.ShouldBeShorterFormat, formatDuration(limit)),
+		fmt.Sprintf(msgs.DurationOverFormat, formatDuration(d), formatDuration(d-limit))))
+}
+
+// AssertDurationBetween expects d is in range [lo, hi].
+// Otherwise, it will terminate the test case using `t.Fatalf` with d and how much d is out of range.
+func AssertDurationBetween(t T, d, lo, hi time.Duration, trigger *Trigger) {
+	t.Helper()
+	if lo > hi {
+		failInternal(t, trigger, errInvalidDurationRange)
+		return
+	}
+
+	if d >= lo && d <= hi {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	duration := fmt.Sprintf(msgs.DurationOverFormat, formatDuration(d), formatDuration(d-hi))
+
+	if d < lo {
+		duration = fmt.Sprintf(msgs.DurationUnderFormat, formatDuration(d), formatDuration(lo-d))
+	}
+
+	fail(t, trigger, durationFailure(msgs, trigger, f, fmt.Sprintf(msgs.ShouldBeBetweenFormat, formatDuration(lo), formatDuration(hi)), duration))
+}
+
+// AssertTookLess expects fn returns in less than limit.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the time taken by fn.
+//
+// Unlike AssertDoesNotBlock, fn is called in current goroutine
+// and the assertion fails after fn returns.
+func AssertTookLess(t T, limit time.Duration, fn func(), trigger *Trigger) {
+	t.Helper()
+	start := time.Now()
+	fn()
+	elapsed := time.Since(start)
+
+	if elapsed < limit {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	fail(t, trigger, durationFailure(msgs, trigger, f, fmt.Sprintf(msgs.ShouldTakeLessFormat, formatDuration(limit)),
+		fmt.Sprintf(msgs.DurationOverFormat, formatDuration(elapsed), formatDuration(elapsed-limit))))
+}
+
+// durationFailure creates the failure of AssertDurationLess, AssertDurationBetween and AssertTookLess.
+func durationFailure(msgs Messages, trigger *Trigger, f *Func, header, duration string) *Failure {
+	info := trigger.P().ParseInfo(f)
+	return &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, header,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.Duration, duration,
+			formatVars(msgs, info, trigger),
+		),
+		Values: []string{duration},
+	}
+}
+
+// humanizeDuration formats d rounded to 4 significant digits,
+// e.g. "1.235s" instead of "1.234567891s".
+func humanizeDuration(d time.Duration) string {
+	abs := d
+
+	if abs < 0 {
+		abs = -abs
+	}
+
+	unit := time.Duration(1)
+
+	for abs/unit >= 10000 {
+		unit *= 10
+	}
+
+	return d.Round(unit).String()
+}
+
+// formatDuration formats d measured or configured by an assertion with humanizeDuration.
+// In stable output mode, StableDuration is returned instead.
+func formatDuration(d time.Duration) string {
+	if StableOutputEnabled() {
+		return StableDuration
+	}
+
+	return humanizeDuration(d)
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHumanizeDuration(t *testing.T) {
+	cases := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "0s"},
+		{999, "999ns"},
+		{12345, "12.35µs"},
+		{1234567891, "1.235s"},
+		{-1234567891, "-1.235s"},
+		{150123456789, "2m30.1s"},
+		{time.Hour + time.Second, "1h0m1s"},
+	}
+
+	for _, c := range cases {
+		assertEqual(t, humanizeDuration(c.d), c.expected)
+	}
+}
+
+func TestAssertDurations(t *testing.T) {
+	ft := NewFakeT("TestAssertDurations")
+	trigger := &Trigger{
+		FuncName: "AssertDurationLess",
+		Args:     []int{1},
+		Options:  Options{NonFatal: true},
+	}
+	betweenTrigger := &Trigger{
+		FuncName: "AssertDurationBetween",
+		Args:     []int{1},
+		Options:  Options{NonFatal: true},
+	}
+
+	AssertDurationLess(ft, time.Millisecond, time.Second, trigger)
+	AssertDurationBetween(ft, time.Second, time.Second, time.Second, betweenTrigger)
+	assertEqual(t, len(ft.Messages()), 0)
+
+	AssertDurationLess(ft, time.Second, time.Second, trigger)
+	AssertDurationBetween(ft, 1500*time.Millisecond, 2*time.Second, 3*time.Second, betweenTrigger)
+	AssertDurationBetween(ft, 4*time.Second, 2*time.Second, 3*time.Second, betweenTrigger)
+	AssertDurationBetween(ft, time.Second, 3*time.Second, 2*time.Second, betweenTrigger)
+	msgs := ft.Messages()
+
+	assertEqual(t, len(msgs), 4)
+	assertEqual(t, strings.Contains(msgs[0], "Following duration should be less than 1s."), true)
+	assertEqual(t, strings.Contains(msgs[0], "1s, which is 0s over the limit."), true)
+	assertEqual(t, strings.Contains(msgs[1], "1.5s, which is 500ms under the lower bound."), true)
+	assertEqual(t, strings.Contains(msgs[2], "4s, which is 1s over the limit."), true)
+	assertEqual(t, strings.Contains(msgs[3], errInvalidDurationRange.Error()), true)
+}
+
+func TestAssertTookLess(t *testing.T) {
+	ft := NewFakeT("TestAssertTookLess")
+	trigger := &Trigger{
+		FuncName: "AssertTookLess",
+		Args:     []int{2},
+		Options:  Options{NonFatal: true},
+	}
+	called := 0
+
+	AssertTookLess(ft, time.Minute, func() { called++ }, trigger)
+	assertEqual(t, len(ft.Messages()), 0)
+
+	AssertTookLess(ft, time.Millisecond, func() {
+		called++
+		time.Sleep(5 * time.Millisecond)
+	}, trigger)
+	msgs := ft.Messages()
+
+	assertEqual(t, called, 2)
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "Following function should take less than 1ms."), true)
+}
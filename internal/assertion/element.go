@@ -0,0 +1,301 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// isIndexedVar returns true if expr is a var indexed by literals or idents,
+// e.g. `items[0]`, `m["key"]`, `s.rows[i][1]`.
+func isIndexedVar(expr *ast.IndexExpr) bool {
+	switch idx := expr.Index.(type) {
+	case *ast.BasicLit:
+		if idx.Kind != token.INT && idx.Kind != token.STRING && idx.Kind != token.CHAR {
+			return false
+		}
+	case *ast.Ident:
+	default:
+		return false
+	}
+
+	if x, ok := expr.X.(*ast.IndexExpr); ok {
+		return isIndexedVar(x)
+	}
+
+	return IsVar(expr.X)
+}
+
+// isDerefVar returns true if expr dereferences a var, e.g. `*p` or `**s.pp`.
+func isDerefVar(expr *ast.StarExpr) bool {
+	if x, ok := expr.X.(*ast.StarExpr); ok {
+		return isDerefVar(x)
+	}
+
+	return IsVar(expr.X)
+}
+
+// isGetterCall returns true if expr calls a method of a var without args, e.g. `cfg.Timeout()`.
+func isGetterCall(expr *ast.CallExpr) bool {
+	if len(expr.Args) != 0 || expr.Ellipsis.IsValid() {
+		return false
+	}
+
+	sel, ok := expr.Fun.(*ast.SelectorExpr)
+	return ok && IsVar(sel.X)
+}
+
+// relatedAccess is the way to get the value of a related var from a registered var or its field,
+// e.g. `*p` dereferences p, `items[0]` indexes items and `cfg.Timeout()` calls a getter of cfg.
+type relatedAccess struct {
+	derefs int    // Number of leading "*".
+	getter string // Name of the getter method. Empty if no getter is called.
+	suffix string // Index suffix like `[0]["key"]`.
+}
+
+// parseRelatedVar splits name of a related var into the var and the way to access its value.
+func parseRelatedVar(name string) (v string, access relatedAccess) {
+	v, access.suffix = splitIndexedVar(name)
+
+	for strings.HasPrefix(v, "*") {
+		v = v[1:]
+		access.derefs++
+	}
+
+	if strings.HasSuffix(v, "()") {
+		if idx := strings.LastIndexByte(v, '.'); idx > 0 {
+			access.getter = v[idx+1 : len(v)-2]
+			v = v[:idx]
+		}
+	}
+
+	return
+}
+
+// format returns the name of the related var accessing v.
+func (access relatedAccess) format(v string) string {
+	if access.getter != "" {
+		v += "." + access.getter + "()"
+	}
+
+	return strings.Repeat("*", access.derefs) + v + access.suffix
+}
+
+// value returns the value of the related var accessing v.
+// The ptr points to v if it's available. It's used to call getters with pointer receivers.
+// Getters are called only if callGetters is true.
+func (access relatedAccess) value(v, ptr interface{}, vars map[string]interface{}, callGetters bool) (value interface{}, ok bool) {
+	value = v
+
+	if access.getter != "" {
+		if !callGetters {
+			return
+		}
+
+		if value, ok = callGetter(v, ptr, access.getter); !ok {
+			return
+		}
+	}
+
+	if access.derefs > 0 {
+		val := reflect.ValueOf(value)
+
+		for i := 0; i < access.derefs; i++ {
+			if val.Kind() != reflect.Ptr || val.IsNil() {
+				return nil, false
+			}
+
+			val = val.Elem()
+		}
+
+		value = getValueInterface(val)
+	}
+
+	if access.suffix != "" {
+		return elementValue(value, access.suffix, vars)
+	}
+
+	return value, true
+}
+
+// callGetter calls method name of v or ptr.
+// The method must have no arg and return exactly one value.
+// A panicking getter is treated as missing.
+func callGetter(v, ptr interface{}, name string) (value interface{}, ok bool) {
+	method := reflect.ValueOf(v).MethodByName(name)
+
+	if !method.IsValid() && ptr != nil {
+		method = reflect.ValueOf(ptr).MethodByName(name)
+	}
+
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			value, ok = nil, false
+		}
+	}()
+
+	value = method.Call(nil)[0].Interface()
+	ok = true
+	return
+}
+
+// splitIndexedVar splits name of an indexed var into the container var and the index suffix.
+// For instance, `s.rows[i][1]` is split into `s.rows` and `[i][1]`.
+// If name is not indexed, the suffix is empty.
+func splitIndexedVar(name string) (container, suffix string) {
+	idx := strings.IndexByte(name, '[')
+
+	if idx <= 0 {
+		return name, ""
+	}
+
+	return name[:idx], name[idx:]
+}
+
+// elementValue returns the element of container selected by suffix like `[0]["key"]`.
+// Idents in suffix are resolved by vars registered by `Use`.
+// It returns false if any index is unknown or out of range or any key is missing.
+func elementValue(container interface{}, suffix string, vars map[string]interface{}) (elem interface{}, ok bool) {
+	expr, err := parser.ParseExpr("_" + suffix)
+
+	if err != nil {
+		return
+	}
+
+	var indices []ast.Expr
+
+	for {
+		ie, isIndex := expr.(*ast.IndexExpr)
+
+		if !isIndex {
+			break
+		}
+
+		indices = append(indices, ie.Index)
+		expr = ie.X
+	}
+
+	v := reflect.ValueOf(container)
+
+	for i := len(indices) - 1; i >= 0; i-- {
+		key, known := indexValue(indices[i], vars)
+
+		if !known {
+			return
+		}
+
+		if v, ok = indexElement(v, key); !ok {
+			return
+		}
+	}
+
+	return getValueInterface(v), true
+}
+
+// indexValue evaluates an index, which is either a literal or an ident registered by `Use`.
+func indexValue(expr ast.Expr, vars map[string]interface{}) (v reflect.Value, ok bool) {
+	switch idx := expr.(type) {
+	case *ast.BasicLit:
+		switch idx.Kind {
+		case token.INT:
+			n, err := strconv.ParseInt(idx.Value, 0, 64)
+
+			if err != nil {
+				return
+			}
+
+			return reflect.ValueOf(n), true
+
+		case token.STRING:
+			s, err := strconv.Unquote(idx.Value)
+
+			if err != nil {
+				return
+			}
+
+			return reflect.ValueOf(s), true
+
+		case token.CHAR:
+			s, err := strconv.Unquote(idx.Value)
+
+			if err != nil {
+				return
+			}
+
+			r, _ := utf8.DecodeRuneInString(s)
+			return reflect.ValueOf(r), true
+		}
+
+	case *ast.Ident:
+		ptr := reflect.ValueOf(vars[idx.Name])
+
+		if !ptr.IsValid() || ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+			return
+		}
+
+		return ptr.Elem(), true
+	}
+
+	return
+}
+
+// indexElement returns the element of v at key.
+// Pointers and interfaces are followed implicitly.
+func indexElement(v reflect.Value, key reflect.Value) (elem reflect.Value, ok bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.String:
+		if !isInteger(key.Kind()) {
+			return
+		}
+
+		i := int(key.Convert(reflect.TypeOf(0)).Int())
+
+		if i < 0 || i >= v.Len() {
+			return
+		}
+
+		return v.Index(i), true
+
+	case reflect.Map:
+		typ := v.Type().Key()
+
+		if !key.Type().AssignableTo(typ) {
+			if isInteger(key.Kind()) != isInteger(typ.Kind()) || !key.Type().ConvertibleTo(typ) {
+				return
+			}
+
+			key = key.Convert(typ)
+		}
+
+		elem = v.MapIndex(key)
+		ok = elem.IsValid()
+		return
+	}
+
+	return
+}
+
+func isInteger(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return false
+}
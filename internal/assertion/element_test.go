@@ -0,0 +1,127 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"go/ast"
+	"go/parser"
+	"testing"
+)
+
+func TestIsIndexedVar(t *testing.T) {
+	cases := []struct {
+		Code    string
+		Indexed bool
+	}{
+		{`items[0]`, true},
+		{`m["key"]`, true},
+		{`m['k']`, true},
+		{`s.rows[i][1]`, true},
+		{`items[i+1]`, false},
+		{`f()[0]`, false},
+		{`m[f()]`, false},
+	}
+
+	for i, c := range cases {
+		t.Logf("case %v: %v", i, c)
+		expr, err := parser.ParseExpr(c.Code)
+		assertEqual(t, err, nil)
+		assertEqual(t, isIndexedVar(expr.(*ast.IndexExpr)), c.Indexed)
+	}
+}
+
+type idString string
+
+func TestElementValue(t *testing.T) {
+	i := 1
+	key := "b"
+	vars := map[string]interface{}{
+		"i":   &i,
+		"key": &key,
+	}
+	rows := [][]int{{1, 2}, {3, 4}}
+	m := map[string][]string{"a": {"x", "y"}, "b": {"z"}}
+	ids := map[idString]int{"a": 1}
+	codes := map[int8]string{1: "one"}
+	cases := []struct {
+		Container interface{}
+		Suffix    string
+		Elem      interface{}
+		OK        bool
+	}{
+		{rows, `[1][0]`, 3, true},
+		{&rows, `[i][i]`, 4, true},
+		{rows, `[2]`, nil, false},
+		{rows, `[j]`, nil, false},
+		{m, `["a"][1]`, "y", true},
+		{m, `[key]`, []string{"z"}, true},
+		{m, `["c"]`, nil, false},
+		{m, `[0]`, nil, false},
+		{ids, `["a"]`, 1, true},
+		{codes, `[1]`, "one", true},
+		{"abc", `[0x1]`, uint8('b'), true},
+	}
+
+	for i, c := range cases {
+		t.Logf("case %v: %v", i, c)
+		elem, ok := elementValue(c.Container, c.Suffix, vars)
+		assertEqual(t, ok, c.OK)
+		assertEqual(t, elem, c.Elem)
+	}
+}
+
+type getterConfig struct {
+	timeout int
+}
+
+func (c getterConfig) Timeout() int {
+	return c.timeout
+}
+
+func (c *getterConfig) Retries() int {
+	return c.timeout * 2
+}
+
+func (c getterConfig) Panic() int {
+	panic("getter panics")
+}
+
+func TestRelatedAccess(t *testing.T) {
+	n := 3
+	p := &n
+	cfg := getterConfig{timeout: 5}
+	cases := []struct {
+		Name        string
+		Var         string
+		Value       interface{}
+		Ptr         interface{}
+		CallGetters bool
+		Result      interface{}
+		OK          bool
+	}{
+		{`p`, `p`, p, &p, false, p, true},
+		{`*p`, `p`, p, &p, false, 3, true},
+		{`**p`, `p`, p, &p, false, nil, false},
+		{`cfg.Timeout()`, `cfg`, cfg, &cfg, false, nil, false},
+		{`cfg.Timeout()`, `cfg`, cfg, &cfg, true, 5, true},
+		{`cfg.Retries()`, `cfg`, cfg, &cfg, true, 10, true},
+		{`cfg.Retries()`, `cfg`, cfg, nil, true, nil, false},
+		{`cfg.Panic()`, `cfg`, cfg, &cfg, true, nil, false},
+		{`cfg.Missing()`, `cfg`, cfg, &cfg, true, nil, false},
+	}
+
+	for i, c := range cases {
+		t.Logf("case %v: %v", i, c.Name)
+		v, access := parseRelatedVar(c.Name)
+		assertEqual(t, v, c.Var)
+		assertEqual(t, access.format(v), c.Name)
+
+		result, ok := access.value(c.Value, c.Ptr, nil, c.CallGetters)
+		assertEqual(t, ok, c.OK)
+
+		if ok {
+			assertEqual(t, result, c.Result)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// fastEqual compares v1 and v2 without reflection if both are the same basic type.
+// If ok is false, v1 and v2 must be compared by `reflect.DeepEqual`.
+//
+// NaN is never equal to itself in `reflect.DeepEqual`, so are floats here.
+func fastEqual(v1, v2 interface{}) (equal, ok bool) {
+	switch x := v1.(type) {
+	case int:
+		y, ok := v2.(int)
+		return ok && x == y, ok
+	case int8:
+		y, ok := v2.(int8)
+		return ok && x == y, ok
+	case int16:
+		y, ok := v2.(int16)
+		return ok && x == y, ok
+	case int32:
+		y, ok := v2.(int32)
+		return ok && x == y, ok
+	case int64:
+		y, ok := v2.(int64)
+		return ok && x == y, ok
+	case uint:
+		y, ok := v2.(uint)
+		return ok && x == y, ok
+	case uint8:
+		y, ok := v2.(uint8)
+		return ok && x == y, ok
+	case uint16:
+		y, ok := v2.(uint16)
+		return ok && x == y, ok
+	case uint32:
+		y, ok := v2.(uint32)
+		return ok && x == y, ok
+	case uint64:
+		y, ok := v2.(uint64)
+		return ok && x == y, ok
+	case uintptr:
+		y, ok := v2.(uintptr)
+		return ok && x == y, ok
+	case float32:
+		y, ok := v2.(float32)
+		return ok && x == y, ok
+	case float64:
+		y, ok := v2.(float64)
+		return ok && x == y, ok
+	case string:
+		y, ok := v2.(string)
+		return ok && x == y, ok
+	case bool:
+		y, ok := v2.(bool)
+		return ok && x == y, ok
+	}
+
+	return false, false
+}
+
+// deepEqual is the same as `reflect.DeepEqual` with a fast path for basic types,
+// except that values of types registered by AddComparator are compared by comparators.
+//
+// Deeply equal values are always equal, so comparators are only used
+// when `reflect.DeepEqual` reports a difference.
+func deepEqual(v1, v2 interface{}) bool {
+	if equal, ok := fastEqual(v1, v2); ok {
+		return equal
+	}
+
+	if reflect.DeepEqual(v1, v2) {
+		return true
+	}
+
+	return comparatorDeepEqual(v1, v2, nil)
+}
+
+// comparatorDeepEqual compares v1 and v2 like `reflect.DeepEqual`
+// except that values of registered types are compared by their comparators.
+// It returns false as soon as stopped is set to non-zero. The stopped can be nil.
+func comparatorDeepEqual(v1, v2 interface{}, stopped *int32) bool {
+	e := &comparatorEqual{
+		visited: map[visit]struct{}{},
+		stopped: stopped,
+	}
+	return e.equal(addressable(reflect.ValueOf(v1)), addressable(reflect.ValueOf(v2)))
+}
+
+// equalNote explains why v1 and v2 are not equal if they look the same,
+// e.g. errors with the same message, a pointer and the value it points to or strings with typos.
+// It returns an empty string if there is nothing to explain.
+func equalNote(msgs Messages, v1, v2 interface{}) (note string) {
+	if v1 == nil || v2 == nil || isNil(reflect.ValueOf(v1)) || isNil(reflect.ValueOf(v2)) {
+		return
+	}
+
+	// Methods of errors are user code which may panic.
+	defer func() {
+		if r := recover(); r != nil {
+			note = ""
+		}
+	}()
+
+	e1, ok1 := v1.(error)
+	e2, ok2 := v2.(error)
+
+	if ok1 && ok2 {
+		if errors.Is(e1, e2) || errors.Is(e2, e1) {
+			return msgs.ErrorChainNote
+		}
+
+		if e1.Error() == e2.Error() {
+			return msgs.ErrorTextNote
+		}
+	}
+
+	t1 := reflect.TypeOf(v1)
+	t2 := reflect.TypeOf(v2)
+
+	if t1 == t2 && t1.Kind() == reflect.String {
+		if d := typoDistance(reflect.ValueOf(v1).String(), reflect.ValueOf(v2).String()); d > 0 {
+			return fmt.Sprintf(msgs.StringEditsFormat, d)
+		}
+
+		return
+	}
+
+	if t1.Kind() == reflect.Ptr && t1.Elem() == t2 {
+		return fmt.Sprintf(msgs.PointerTypeFormat, 1, 2)
+	}
+
+	if t2.Kind() == reflect.Ptr && t2.Elem() == t1 {
+		return fmt.Sprintf(msgs.PointerTypeFormat, 2, 1)
+	}
+
+	return
+}
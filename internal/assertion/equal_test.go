@@ -0,0 +1,96 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDeepEqual(t *testing.T) {
+	type myInt int
+
+	cases := []struct {
+		V1, V2 interface{}
+	}{
+		{1, 1},
+		{1, 2},
+		{1, int64(1)},
+		{1, myInt(1)},
+		{uint8(3), uint8(3)},
+		{"foo", "foo"},
+		{"foo", "bar"},
+		{true, false},
+		{1.5, 1.5},
+		{math.NaN(), math.NaN()},
+		{nil, nil},
+		{nil, 0},
+		{[]int{1}, []int{1}},
+	}
+
+	for _, c := range cases {
+		assertEqual(t, deepEqual(c.V1, c.V2), reflect.DeepEqual(c.V1, c.V2))
+	}
+}
+
+type noteError struct {
+	msg string
+}
+
+func (e *noteError) Error() string {
+	return e.msg
+}
+
+func TestEqualNote(t *testing.T) {
+	msgs := DefaultMessages
+	errNotFound := errors.New("not found")
+	v := noteError{"foo"}
+	var nilErr *noteError
+	cases := []struct {
+		V1, V2 interface{}
+		Note   string
+	}{
+		{fmt.Errorf("load: %w", errNotFound), errNotFound, msgs.ErrorChainNote},
+		{errNotFound, fmt.Errorf("load: %w", errNotFound), msgs.ErrorChainNote},
+		{errors.New("foo"), &noteError{"foo"}, msgs.ErrorTextNote},
+		{&v, v, fmt.Sprintf(msgs.PointerTypeFormat, 1, 2)},
+		{v, &v, fmt.Sprintf(msgs.PointerTypeFormat, 2, 1)},
+		{errors.New("foo"), errors.New("bar"), ""},
+		{nilErr, errNotFound, ""},
+		{1, "1", ""},
+	}
+
+	for i, c := range cases {
+		t.Logf("case %v: %v", i, c)
+		assertEqual(t, equalNote(msgs, c.V1, c.V2), c.Note)
+	}
+}
+
+func BenchmarkAssertEqualInt(b *testing.B) {
+	trigger := &Trigger{FuncName: "AssertEqual"}
+
+	for i := 0; i < b.N; i++ {
+		AssertEqual(b, i, i, trigger)
+	}
+}
+
+func BenchmarkAssertEqualString(b *testing.B) {
+	trigger := &Trigger{FuncName: "AssertEqual"}
+	s1, s2 := "go-assert", "go-assert"
+
+	for i := 0; i < b.N; i++ {
+		AssertEqual(b, s1, s2, trigger)
+	}
+}
+
+func BenchmarkReflectDeepEqualInt(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if !reflect.DeepEqual(i, i) {
+			b.Fatal("should equal")
+		}
+	}
+}
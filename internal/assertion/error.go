@@ -0,0 +1,91 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AssertNoError expects e is nil.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the error.
+// An interface holding a typed nil is not nil.
+func AssertNoError(t T, e error, trigger *Trigger) {
+	t.Helper()
+	if e == nil {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	fail(t, trigger, nilErrorFailure(msgs, trigger, f, msgs.ShouldBeNoError, e))
+}
+
+// AssertError expects e is a non-nil error.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+// An interface holding a typed nil is treated as nil.
+func AssertError(t T, e error, trigger *Trigger) {
+	t.Helper()
+	val := reflect.ValueOf(e)
+
+	if e != nil && !isNil(val) {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, msgs.ShouldBeError,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			typedNilErrorNotes(msgs, val), formatVars(msgs, info, trigger),
+		),
+	})
+}
+
+// nilErrorFailure creates the failure of AssertNilError and AssertNoError.
+// The e is the unexpected non-nil error.
+func nilErrorFailure(msgs Messages, trigger *Trigger, f *Func, header string, e interface{}) *Failure {
+	info := trigger.P().ParseInfo(f)
+	return &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n    %v%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, header,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.ErrorIs, e, typedNilErrorNotes(msgs, reflect.ValueOf(e)), formatVars(msgs, info, trigger),
+		),
+		Values: []string{fmt.Sprint(e)},
+	}
+}
+
+// typedNilErrorNotes returns notes if val is an error holding a typed nil.
+func typedNilErrorNotes(msgs Messages, val reflect.Value) string {
+	if !val.IsValid() || !isNil(val) {
+		return ""
+	}
+
+	return "\n" + msgs.Notes + "\n    " + fmt.Sprintf(msgs.TypedNilErrorFormat, val.Type())
+}
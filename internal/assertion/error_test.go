@@ -0,0 +1,64 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type typedNilError struct{}
+
+func (*typedNilError) Error() string {
+	return "typed nil"
+}
+
+func TestAssertNoError(t *testing.T) {
+	ft := NewFakeT("TestAssertNoError")
+	trigger := &Trigger{
+		FuncName: "AssertNoError",
+		Args:     []int{1},
+	}
+
+	AssertNoError(ft, nil, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	err := errors.New("something wrong")
+	AssertNoError(ft, err, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldBeNoError+"\n    err\n    err := errors.New(\"something wrong\")\nThe error is:\n    something wrong"), true)
+
+	ft.Reset()
+	var e *typedNilError
+	AssertNoError(ft, e, trigger)
+	msgs = ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "Notes:"), true)
+}
+
+func TestAssertError(t *testing.T) {
+	ft := NewFakeT("TestAssertError")
+	trigger := &Trigger{
+		FuncName: "AssertError",
+		Args:     []int{1},
+	}
+
+	AssertError(ft, errors.New("expected"), trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	var err error
+	AssertError(ft, err, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldBeError+"\n    err"), true)
+
+	ft.Reset()
+	var e *typedNilError
+	AssertError(ft, e, trigger)
+	msgs = ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "Notes:"), true)
+}
@@ -0,0 +1,335 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// subExpr is a sub-expression and its value evaluated with vars registered by `Use`.
+type subExpr struct {
+	Expr  string
+	Value string
+}
+
+// evalSubExprs evaluates expr with vars and returns values of all compound sub-expressions
+// referencing any var in evaluation order, e.g. `x+y` and `z*2` in `x+y > z*2`.
+//
+// Only vars, literals, `len` and pure operators are supported.
+// If any part of expr cannot be evaluated, nil is returned.
+func evalSubExprs(fset *token.FileSet, expr ast.Expr, vars map[string]interface{}) []subExpr {
+	if expr == nil || len(vars) == 0 {
+		return nil
+	}
+
+	for {
+		paren, ok := expr.(*ast.ParenExpr)
+
+		if !ok {
+			break
+		}
+
+		expr = paren.X
+	}
+
+	e := &evaluator{
+		fset: fset,
+		root: expr,
+		vars: vars,
+	}
+
+	if _, ok := e.eval(expr); !ok {
+		return nil
+	}
+
+	return e.subExprs
+}
+
+type evaluator struct {
+	fset     *token.FileSet
+	root     ast.Expr
+	vars     map[string]interface{}
+	refs     int // Number of vars referenced so far.
+	subExprs []subExpr
+}
+
+func (e *evaluator) eval(expr ast.Expr) (v constant.Value, ok bool) {
+	defer func() {
+		// go/constant panics on invalid operations.
+		if r := recover(); r != nil {
+			v, ok = nil, false
+		}
+	}()
+
+	refs := e.refs
+
+	switch n := expr.(type) {
+	case *ast.ParenExpr:
+		return e.eval(n.X)
+
+	case *ast.BasicLit:
+		v = constant.MakeFromLiteral(n.Value, n.Kind, 0)
+		return v, v.Kind() != constant.Unknown && v.Kind() != constant.Complex
+
+	case *ast.Ident, *ast.SelectorExpr:
+		if !IsVar(n) {
+			return
+		}
+
+		val, found := lookupVar(formatNode(e.fset, n), e.vars)
+
+		if !found {
+			if ident, isIdent := n.(*ast.Ident); isIdent && (ident.Name == "true" || ident.Name == "false") {
+				return constant.MakeBool(ident.Name == "true"), true
+			}
+
+			return
+		}
+
+		e.refs++
+		return makeConstant(val)
+
+	case *ast.CallExpr:
+		v, ok = e.evalLen(n)
+
+	case *ast.UnaryExpr:
+		x, xok := e.eval(n.X)
+
+		if !xok {
+			return
+		}
+
+		switch n.Op {
+		case token.NOT:
+			if x.Kind() != constant.Bool {
+				return
+			}
+		case token.ADD, token.SUB:
+			if !isNumeric(x) {
+				return
+			}
+		case token.XOR:
+			if x.Kind() != constant.Int {
+				return
+			}
+		default:
+			return
+		}
+
+		v, ok = constant.UnaryOp(n.Op, x, 0), true
+
+	case *ast.BinaryExpr:
+		x, xok := e.eval(n.X)
+		y, yok := e.eval(n.Y)
+
+		if !xok || !yok {
+			return
+		}
+
+		v, ok = binaryOp(x, n.Op, y)
+
+	default:
+		return
+	}
+
+	if ok && expr != e.root && e.refs > refs {
+		e.subExprs = append(e.subExprs, subExpr{
+			Expr:  formatNode(e.fset, expr),
+			Value: v.String(),
+		})
+	}
+
+	return
+}
+
+// evalLen evaluates builtin `len`.
+func (e *evaluator) evalLen(call *ast.CallExpr) (v constant.Value, ok bool) {
+	if fn, isIdent := call.Fun.(*ast.Ident); !isIdent || fn.Name != "len" || len(call.Args) != 1 {
+		return
+	}
+
+	if _, found := e.vars["len"]; found {
+		return
+	}
+
+	arg := call.Args[0]
+
+	if !IsVar(arg) {
+		x, xok := e.eval(arg)
+
+		if !xok || x.Kind() != constant.String {
+			return
+		}
+
+		return constant.MakeInt64(int64(len(constant.StringVal(x)))), true
+	}
+
+	val, found := lookupVar(formatNode(e.fset, arg), e.vars)
+
+	if !found {
+		return
+	}
+
+	e.refs++
+
+	switch val.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return constant.MakeInt64(int64(val.Len())), true
+	}
+
+	return
+}
+
+func binaryOp(x constant.Value, op token.Token, y constant.Value) (v constant.Value, ok bool) {
+	switch op {
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		if !isComparable(x, y) {
+			return
+		}
+
+		if x.Kind() == constant.Bool && op != token.EQL && op != token.NEQ {
+			return
+		}
+
+		return constant.MakeBool(constant.Compare(x, op, y)), true
+
+	case token.LAND, token.LOR:
+		if x.Kind() != constant.Bool || y.Kind() != constant.Bool {
+			return
+		}
+
+	case token.ADD:
+		if !isComparable(x, y) || x.Kind() == constant.Bool {
+			return
+		}
+
+	case token.SUB, token.MUL:
+		if !isNumeric(x) || !isNumeric(y) {
+			return
+		}
+
+	case token.QUO:
+		if !isNumeric(x) || !isNumeric(y) || constant.Sign(y) == 0 {
+			return
+		}
+
+		// Integer division truncates.
+		if x.Kind() == constant.Int && y.Kind() == constant.Int {
+			op = token.QUO_ASSIGN
+		}
+
+	case token.REM, token.AND, token.OR, token.XOR, token.AND_NOT:
+		if x.Kind() != constant.Int || y.Kind() != constant.Int {
+			return
+		}
+
+		if op == token.REM && constant.Sign(y) == 0 {
+			return
+		}
+
+	case token.SHL, token.SHR:
+		s, exact := constant.Uint64Val(y)
+
+		if x.Kind() != constant.Int || y.Kind() != constant.Int || !exact || s > 64 {
+			return
+		}
+
+		return constant.Shift(x, op, uint(s)), true
+
+	default:
+		return
+	}
+
+	return constant.BinaryOp(x, op, y), true
+}
+
+func isNumeric(v constant.Value) bool {
+	return v.Kind() == constant.Int || v.Kind() == constant.Float
+}
+
+func isComparable(x, y constant.Value) bool {
+	return x.Kind() == y.Kind() || isNumeric(x) && isNumeric(y)
+}
+
+// lookupVar finds the value of a var or a field of a var registered by `Use`.
+func lookupVar(name string, vars map[string]interface{}) (val reflect.Value, ok bool) {
+	if v, found := vars[name]; found {
+		return derefVar(v)
+	}
+
+	for i := strings.LastIndexByte(name, '.'); i > 0; i = strings.LastIndexByte(name[:i], '.') {
+		v, found := vars[name[:i]]
+
+		if !found {
+			continue
+		}
+
+		if val, ok = derefVar(v); !ok {
+			return
+		}
+
+		field := name[i+1:]
+		actual, value, found := getValue(field, val)
+
+		if !found || actual != field {
+			return reflect.Value{}, false
+		}
+
+		return reflect.ValueOf(value), true
+	}
+
+	return
+}
+
+// derefVar returns the value referenced by a var registered by `Use`.
+func derefVar(v interface{}) (val reflect.Value, ok bool) {
+	val = reflect.ValueOf(v)
+
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return
+	}
+
+	return reflect.ValueOf(getValueInterface(val.Elem())), true
+}
+
+// makeConstant converts a value of basic type to a constant.
+func makeConstant(val reflect.Value) (v constant.Value, ok bool) {
+	switch val.Kind() {
+	case reflect.Bool:
+		return constant.MakeBool(val.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return constant.MakeInt64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return constant.MakeUint64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		v = constant.MakeFloat64(val.Float())
+		return v, v.Kind() == constant.Float
+	case reflect.String:
+		return constant.MakeString(val.String()), true
+	}
+
+	return
+}
+
+// formatSubExprs returns the section of compound sub-expressions in expr.
+func formatSubExprs(msgs Messages, fset *token.FileSet, expr ast.Expr, vars map[string]interface{}) string {
+	subExprs := evalSubExprs(fset, expr, vars)
+
+	if len(subExprs) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(subExprs)+1)
+	lines = append(lines, "\n"+msgs.SubExprs)
+
+	for _, sub := range subExprs {
+		lines = append(lines, "    "+sub.Expr+" = "+sub.Value)
+	}
+
+	return strings.Join(lines, "\n")
+}
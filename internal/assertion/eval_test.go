@@ -0,0 +1,62 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestEvalSubExprs(t *testing.T) {
+	type config struct {
+		Name  string
+		Items []int
+		Ratio float64
+	}
+
+	x, y := 3, uint(4)
+	s := "foo"
+	cfg := &config{Name: "bar", Items: []int{1, 2}, Ratio: 0.5}
+	vars := map[string]interface{}{
+		"x":   &x,
+		"y":   &y,
+		"s":   &s,
+		"cfg": &cfg,
+	}
+	cases := []struct {
+		Expr     string
+		SubExprs []subExpr
+	}{
+		{"x > 1", []subExpr{}},
+		{"(x+1)*2 > int(y)", nil},
+		{"x+int(y) == 0", nil},
+		{"x+1 == 0", []subExpr{{"x + 1", "4"}}},
+		{"(x*2)/4 == int64(y)", nil},
+		{"(x*2)/4 == 0", []subExpr{{"x * 2", "6"}, {"(x * 2) / 4", "1"}}},
+		{"-x >= 0 && s+\"bar\" == cfg.Name", []subExpr{{"-x", "-3"}, {"-x >= 0", "false"}, {`s + "bar"`, `"foobar"`}, {`s+"bar" == cfg.Name`, "false"}}},
+		{"len(cfg.Items) > len(s)", []subExpr{{"len(cfg.Items)", "2"}, {"len(s)", "3"}}},
+		{"cfg.Ratio*2 < 1 || !true", []subExpr{{"cfg.Ratio * 2", "1"}, {"cfg.Ratio*2 < 1", "false"}}},
+		{"x/0 == 1", nil},
+		{"s > x", nil},
+		{"unknown + 1", nil},
+		{"cfg.Missing + 1", nil},
+	}
+
+	for _, c := range cases {
+		expr, err := parser.ParseExpr(c.Expr)
+
+		if err != nil {
+			t.Fatalf("fail to parse expr. [expr:%v] [err:%v]", c.Expr, err)
+		}
+
+		subExprs := evalSubExprs(token.NewFileSet(), expr, vars)
+
+		if len(c.SubExprs) == 0 && len(subExprs) == 0 {
+			continue
+		}
+
+		assertEqual(t, subExprs, c.SubExprs)
+	}
+}
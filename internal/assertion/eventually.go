@@ -0,0 +1,297 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInvalidCondition = errors.New("go-assert: condition must be a func() bool, func() (bool, interface{}) or func() error")
+
+// observation is the result of calling a condition once.
+type observation struct {
+	OK       bool
+	Value    interface{}
+	HasValue bool
+	Err      error
+}
+
+// observer returns a func calling condition and recording what it observes.
+func observer(condition interface{}) (func() observation, error) {
+	switch c := condition.(type) {
+	case func() bool:
+		return func() observation {
+			return observation{OK: c()}
+		}, nil
+	case func() (bool, interface{}):
+		return func() observation {
+			ok, v := c()
+			return observation{OK: ok, Value: v, HasValue: true}
+		}, nil
+	case func() error:
+		return func() observation {
+			err := c()
+			return observation{OK: err == nil, Err: err}
+		}, nil
+	}
+
+	return nil, errInvalidCondition
+}
+
+// AssertEventually calls condition every interval until it's satisfied.
+// If condition is not satisfied within timeout, it will terminate the test case using `t.Fatalf`
+// with the value or error observed in the last call.
+//
+// The condition can be one of following types.
+//
+//   - `func() bool`: Satisfied if it returns true.
+//   - `func() (bool, interface{})`: Satisfied if it returns true. The value is recorded as last observed value.
+//   - `func() error`: Satisfied if it returns nil. The error is recorded as last observed error.
+//
+// The condition is called in current goroutine. It's called at least once even if it takes longer than timeout.
+func AssertEventually(t T, condition interface{}, timeout, interval time.Duration, trigger *Trigger) {
+	t.Helper()
+	observe, err := observer(condition)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	last, attempts := poll(ctx, observe, interval)
+
+	if last.OK {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	fail(t, trigger, eventuallyFailure(msgs, trigger, f, fmt.Sprintf(msgs.ShouldBeSatisfiedFormat, formatDuration(timeout)), attempts, last, ""))
+}
+
+// AssertEventuallyCtx calls condition every interval until it's satisfied.
+// If ctx is done before condition is satisfied, it will terminate the test case using `t.Fatalf`
+// with the error of ctx and the value or error observed in the last call.
+//
+// See AssertEventually for supported types of condition.
+func AssertEventuallyCtx(t T, ctx context.Context, condition interface{}, interval time.Duration, trigger *Trigger) {
+	t.Helper()
+	observe, err := observer(condition)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	last, attempts := poll(ctx, observe, interval)
+
+	if last.OK {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	ctxErr := "\n" + msgs.ContextError + "\n    " + ctx.Err().Error()
+	fail(t, trigger, eventuallyFailure(msgs, trigger, f, msgs.ShouldBeSatisfiedBeforeDone, attempts, last, ctxErr))
+}
+
+// AssertEventuallyNoLeak is the same as AssertEventually
+// except that it also expects condition doesn't leak goroutines.
+// A goroutine leaks if it's started while polling condition and it's still running
+// after condition is satisfied or timeout, with a grace period for the goroutine to return.
+// Otherwise, it will terminate the test case using `t.Fatalf` with stacks of leaked goroutines.
+//
+// Goroutines are compared by ids before and after polling,
+// so goroutines started by other tests running in parallel are reported as leaks as well.
+func AssertEventuallyNoLeak(t T, condition interface{}, timeout, interval time.Duration, trigger *Trigger) {
+	t.Helper()
+	observe, err := observer(condition)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	before := goroutineIDs()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	last, attempts := poll(ctx, observe, interval)
+	cancel()
+	leaked := leakedGoroutines(before, leakGracePeriod)
+
+	if last.OK && len(leaked) == 0 {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	header := fmt.Sprintf(msgs.ShouldBeSatisfiedFormat, formatDuration(timeout))
+
+	if last.OK {
+		header = msgs.ShouldNotLeak
+	}
+
+	fail(t, trigger, eventuallyFailure(msgs, trigger, f, header, attempts, last, formatLeakedGoroutines(msgs, leaked)))
+}
+
+// leakGracePeriod is the max duration to wait for goroutines started by a condition to return.
+const leakGracePeriod = 100 * time.Millisecond
+
+// maxLeakedStacks is the max number of stacks of leaked goroutines printed in a failure message.
+const maxLeakedStacks = 5
+
+// goroutineIDs returns ids of all goroutines.
+func goroutineIDs() map[uint64]struct{} {
+	ids := map[uint64]struct{}{}
+
+	for _, stack := range strings.Split(allGoroutineStacks(), "\n\n") {
+		if id, ok := stackGoroutineID(stack); ok {
+			ids[id] = struct{}{}
+		}
+	}
+
+	return ids
+}
+
+// stackGoroutineID parses the goroutine id in the header of stack, e.g. "goroutine 123 [running]:".
+func stackGoroutineID(stack string) (id uint64, ok bool) {
+	s := strings.TrimPrefix(stack, "goroutine ")
+
+	if len(s) == len(stack) {
+		return
+	}
+
+	if idx := strings.IndexByte(s, ' '); idx >= 0 {
+		s = s[:idx]
+	}
+
+	id, err := strconv.ParseUint(s, 10, 64)
+	return id, err == nil
+}
+
+// leakedGoroutines returns stacks of goroutines which are not in before.
+// Goroutines are checked again until all of them return or grace period elapses.
+func leakedGoroutines(before map[uint64]struct{}, grace time.Duration) (leaked []string) {
+	deadline := time.Now().Add(grace)
+
+	for {
+		leaked = leaked[:0]
+
+		for _, stack := range strings.Split(allGoroutineStacks(), "\n\n") {
+			if id, ok := stackGoroutineID(stack); ok {
+				if _, existed := before[id]; !existed {
+					leaked = append(leaked, stack)
+				}
+			}
+		}
+
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			return
+		}
+
+		time.Sleep(grace / 10)
+	}
+}
+
+// formatLeakedGoroutines returns the section of leaked goroutines.
+// It returns an empty string if there is no leaked goroutine.
+func formatLeakedGoroutines(msgs Messages, leaked []string) string {
+	if len(leaked) == 0 {
+		return ""
+	}
+
+	stacks := leaked
+
+	if len(stacks) > maxLeakedStacks {
+		stacks = stacks[:maxLeakedStacks]
+	}
+
+	section := "\n" + fmt.Sprintf(msgs.LeakedGoroutinesFormat, len(leaked)) + "\n    " + indentCode(strings.Join(stacks, "\n\n"), 4)
+
+	if len(leaked) > maxLeakedStacks {
+		section += "\n    " + fmt.Sprintf(msgs.DiffMoreFormat, len(leaked)-maxLeakedStacks)
+	}
+
+	return section
+}
+
+// poll calls observe every interval until it's satisfied or ctx is done.
+// The observe is called at least once even if ctx is done.
+func poll(ctx context.Context, observe func() observation, interval time.Duration) (last observation, attempts int) {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		attempts++
+		last = observe()
+
+		if last.OK || ctx.Err() != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			timer.Reset(interval)
+		}
+	}
+}
+
+// eventuallyFailure creates the failure of AssertEventually, AssertEventuallyCtx and AssertEventuallyNoLeak.
+// The extra is a section printed after the number of attempts, e.g. the context error.
+func eventuallyFailure(msgs Messages, trigger *Trigger, f *Func, header string, attempts int, last observation, extra string) *Failure {
+	info := trigger.P().ParseInfo(f)
+	observed := ""
+	values := []string(nil)
+
+	if last.HasValue {
+		v := trigger.dumper().Dump(last.Value)
+		observed = "\n" + msgs.LastValue + "\n    " + v
+		values = append(values, v)
+	} else if last.Err != nil {
+		e := last.Err.Error()
+		observed = "\n" + msgs.LastError + "\n    " + e
+		values = append(values, e)
+	}
+
+	return &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v%v%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, header,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			fmt.Sprintf(msgs.AttemptsFormat, attempts), extra, observed,
+			formatVars(msgs, info, trigger),
+		),
+		Values: values,
+	}
+}
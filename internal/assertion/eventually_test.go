@@ -0,0 +1,117 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestObserver(t *testing.T) {
+	observe, err := observer(func() (bool, interface{}) { return false, 123 })
+	assertEqual(t, err, nil)
+	assertEqual(t, observe(), observation{Value: 123, HasValue: true})
+
+	errFoo := errors.New("foo")
+	observe, err = observer(func() error { return errFoo })
+	assertEqual(t, err, nil)
+	assertEqual(t, observe(), observation{Err: errFoo})
+
+	observe, err = observer(func() bool { return true })
+	assertEqual(t, err, nil)
+	assertEqual(t, observe(), observation{OK: true})
+
+	_, err = observer(func() int { return 0 })
+	assertEqual(t, err, errInvalidCondition)
+}
+
+func TestAssertEventuallyCtx(t *testing.T) {
+	ft := NewFakeT("TestAssertEventuallyCtx")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	errFoo := errors.New("foo")
+	AssertEventuallyCtx(ft, ctx, func() error { return errFoo }, time.Millisecond, &Trigger{
+		FuncName: "AssertEventuallyCtx",
+		Args:     []int{2},
+	})
+
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldBeSatisfiedBeforeDone), true)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ContextError+"\n    context deadline exceeded"), true)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.LastError+"\n    foo"), true)
+}
+
+func TestAssertEventuallyNoLeak(t *testing.T) {
+	ft := NewFakeT("TestAssertEventuallyNoLeak")
+	trigger := &Trigger{
+		FuncName: "AssertEventuallyNoLeak",
+		Args:     []int{1},
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+
+	AssertEventuallyNoLeak(ft, func() bool {
+		done := make(chan struct{})
+		go close(done)
+		<-done
+		return true
+	}, time.Second, time.Millisecond, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertEventuallyNoLeak(ft, func() bool {
+		go func() { <-stop }()
+		return true
+	}, time.Second, time.Millisecond, trigger)
+	AssertEventuallyNoLeak(ft, func() bool {
+		go func() { <-stop }()
+		return false
+	}, 10*time.Millisecond, time.Millisecond, trigger)
+
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 2)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldNotLeak), true)
+	assertEqual(t, strings.Contains(msgs[0], "1 goroutines started by the condition are still running:\n    goroutine "), true)
+	assertEqual(t, strings.Contains(msgs[0], "TestAssertEventuallyNoLeak.func"), true)
+	assertEqual(t, strings.Contains(msgs[1], "Following condition should be satisfied within 10ms."), true)
+	assertEqual(t, strings.Contains(msgs[1], "goroutines started by the condition are still running:"), true)
+}
+
+func TestStackGoroutineID(t *testing.T) {
+	id, ok := stackGoroutineID("goroutine 123 [running]:\nmain.main()")
+	assertEqual(t, id, uint64(123))
+	assertEqual(t, ok, true)
+
+	_, ok = stackGoroutineID("main.main()")
+	assertEqual(t, ok, false)
+
+	_, ok = goroutineIDs()[goroutineID()]
+	assertEqual(t, ok, true)
+}
+
+func TestAssertReceivesCtx(t *testing.T) {
+	ft := NewFakeT("TestAssertReceivesCtx")
+	ch := make(chan int, 1)
+	ch <- 123
+	trigger := &Trigger{
+		FuncName: "AssertReceivesCtx",
+		Args:     []int{2},
+	}
+	assertEqual(t, AssertReceivesCtx(ft, context.Background(), ch, trigger), 123)
+	assertEqual(t, ft.Failed(), false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assertEqual(t, AssertReceivesCtx(ft, ctx, ch, trigger), nil)
+	close(ch)
+	assertEqual(t, AssertReceivesCtx(ft, context.Background(), ch, trigger), nil)
+
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 2)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ContextError+"\n    context canceled"), true)
+	assertEqual(t, strings.Contains(msgs[1], DefaultMessages.ChannelClosed), true)
+}
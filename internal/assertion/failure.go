@@ -0,0 +1,235 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Failure represents an assertion failure.
+type Failure struct {
+	TestName string // Name of the test case in which assertion fails.
+	FuncName string // Name of the assertion function.
+	Filename string // Base name of the file calling assertion function.
+	Path     string // Full path of the file calling assertion function. It may be empty if unknown.
+	Line     int    // Line number of the assertion function call.
+	Source   string // Source code of the assertion function call.
+	ID       string // Stable ID of the assertion site. See AssertionID for details.
+	Message  string // Formatted failure message. It may contain ANSI colors if color is enabled.
+
+	// Values contains dumps of values checked by assertion function.
+	// It may be empty if assertion function doesn't check any value.
+	Values []string
+
+	// Flaky is true if the failure is recovered by retrying in `A#Flaky`.
+	// A flaky failure is reported to hooks only and doesn't fail the test case.
+	Flaky bool
+
+	// Attempts is the number of attempts made by `A#Flaky` when the failure is reported.
+	// It's 0 if the failure is not reported by `A#Flaky`.
+	Attempts int
+
+	// Quarantine is the tracking tag of the quarantine rule matching the failure.
+	// It's empty if the failure is not quarantined.
+	Quarantine string
+
+	// Groups contains labels of groups enclosing the assertion, outermost first.
+	// It's empty if the assertion is not made in `A#Grouped`.
+	Groups []string
+
+	// Trace contains recently executed assertion sites, the most recent last.
+	// It's empty unless tracing is enabled.
+	Trace []string
+
+	// ArtifactsDir is the artifacts directory of the test case created by `A#FailureDir`.
+	// Hooks can write dumps in it. It's empty if the directory has not been created.
+	ArtifactsDir string
+}
+
+// Error returns the failure message so that f can be used as an error.
+func (f *Failure) Error() string {
+	return f.Message
+}
+
+// FailureHook is called with the failure before test case is terminated.
+// The hook can append extra information to f.Message, which will be printed in failure message.
+type FailureHook func(f *Failure)
+
+type failureHookEntry struct {
+	hook FailureHook
+}
+
+var (
+	failureHooksLock sync.RWMutex
+	failureHooks     []*failureHookEntry
+)
+
+// AddFailureHook registers a hook called on every assertion failure.
+// Hooks are called in registration order.
+// Call remove to unregister the hook.
+func AddFailureHook(hook FailureHook) (remove func()) {
+	entry := &failureHookEntry{
+		hook: hook,
+	}
+
+	failureHooksLock.Lock()
+	failureHooks = append(failureHooks, entry)
+	failureHooksLock.Unlock()
+
+	return func() {
+		failureHooksLock.Lock()
+		defer failureHooksLock.Unlock()
+
+		for i, e := range failureHooks {
+			if e == entry {
+				hooks := make([]*failureHookEntry, 0, len(failureHooks)-1)
+				hooks = append(hooks, failureHooks[:i]...)
+				failureHooks = append(hooks, failureHooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func runFailureHooks(f *Failure) {
+	failureHooksLock.RLock()
+	hooks := failureHooks
+	failureHooksLock.RUnlock()
+
+	for _, entry := range hooks {
+		entry.hook(f)
+	}
+
+	annotateFailure(f)
+}
+
+// Fail reports failure to hooks and terminates the test case.
+// It's the way for a customized assert function to report a failure.
+func Fail(t T, failure *Failure) {
+	t.Helper()
+	fail(t, nil, failure)
+}
+
+// fail reports failure to hooks and terminates the test case.
+// The trigger can be nil.
+func fail(t T, trigger *Trigger, failure *Failure) {
+	t.Helper()
+	defer trigger.startTimer()
+	failure.TestName = t.Name()
+
+	if failure.Path == "" {
+		failure.Path = callerPath(failure.Filename, failure.Line)
+	}
+
+	if trigger != nil && len(trigger.Options.Groups) > 0 {
+		failure.Groups = groupLabels(trigger.Options.Groups)
+		failure.Message = formatGroups(CurrentMessages(), trigger.Options.Groups) + "\n" + failure.Message
+	}
+
+	if trigger != nil && trigger.Options.Tracer != nil && trigger.Options.Tracer.reportsEnabled() {
+		failure.Trace = trigger.Options.Tracer.report()
+		failure.Message += "\n" + formatTrace(CurrentMessages(), failure.Trace)
+	}
+
+	if dir := artifactsDir(failure.TestName); dir != "" {
+		failure.ArtifactsDir = dir
+		failure.Message += "\n" + fmt.Sprintf(CurrentMessages().ArtifactsDirFormat, dir)
+	}
+
+	if failure.ID != "" {
+		failure.Message += "\n" + fmt.Sprintf(CurrentMessages().AssertionIDFormat, failure.ID)
+	}
+
+	if StableOutputEnabled() {
+		failure.Message = stabilize(failure.Message)
+	} else if !trigger.colorEnabled() {
+		failure.Message = stripColors(failure.Message)
+	}
+
+	var opts Options
+
+	if trigger != nil {
+		opts = trigger.Options
+	}
+
+	rule := findQuarantine(failure)
+
+	if rule != nil {
+		failure.Quarantine = rule.Tag
+	}
+
+	if !opts.SkipHooks {
+		runFailureHooks(failure)
+	}
+
+	emitFailureAttrs(t, failure)
+
+	// Repeated failures at a call site are summarized after the test in soft mode.
+	if opts.NonFatal && rule == nil && suppressRepeated(t, failure) {
+		return
+	}
+
+	// Output is written to a pooled buffer, which is reused by following failures in soft mode.
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if opts.Formatter != nil {
+		buf.WriteString(opts.Formatter(failure))
+	} else {
+		writeOutput(buf, failure.Message)
+	}
+
+	output, exhausted := limitOutput(t, buf.String())
+
+	if rule != nil && quarantine(t, rule, output) {
+		return
+	}
+
+	// Fail silently if possible to avoid flooding logs with empty messages.
+	if f, ok := t.(failer); ok && exhausted {
+		if opts.NonFatal {
+			f.Fail()
+		} else {
+			f.FailNow()
+		}
+
+		return
+	}
+
+	if writeFailureOutput(t, output, opts.NonFatal) {
+		return
+	}
+
+	if opts.NonFatal {
+		t.Errorf("\n%v", output)
+	} else {
+		t.Fatalf("\n%v", output)
+	}
+}
+
+// failInternal reports an internal error and terminates the test case.
+func failInternal(t T, trigger *Trigger, err error) {
+	t.Helper()
+	defer trigger.startTimer()
+	failure := &Failure{
+		TestName: t.Name(),
+		FuncName: trigger.FuncName,
+		Message:  fmt.Sprintf(CurrentMessages().InternalErrorFormat, err),
+	}
+
+	if StableOutputEnabled() {
+		failure.Message = stabilize(failure.Message)
+	}
+
+	if !trigger.Options.SkipHooks {
+		runFailureHooks(failure)
+	}
+
+	if trigger.Options.NonFatal {
+		t.Errorf("%v", formatOutput(failure.Message))
+	} else {
+		t.Fatalf("%v", formatOutput(failure.Message))
+	}
+}
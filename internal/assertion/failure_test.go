@@ -0,0 +1,40 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"testing"
+)
+
+type fakeTimer struct {
+	calls []string
+}
+
+func (ft *fakeTimer) StartTimer() {
+	ft.calls = append(ft.calls, "start")
+}
+
+func (ft *fakeTimer) StopTimer() {
+	ft.calls = append(ft.calls, "stop")
+}
+
+func TestFailTimer(t *testing.T) {
+	timer := &fakeTimer{}
+	ft := NewFakeT("TestFailTimer")
+	trigger := &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options: Options{
+			NonFatal: true,
+			Timer:    timer,
+		},
+	}
+
+	AssertEqual(ft, 1, 1, trigger)
+	assertEqual(t, len(timer.calls), 0)
+
+	AssertEqual(ft, 1, 2, trigger)
+	assertEqual(t, timer.calls, []string{"stop", "start"})
+	assertEqual(t, len(ft.Messages()), 1)
+}
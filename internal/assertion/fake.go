@@ -0,0 +1,174 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FakeCall is a call to FakeT.
+type FakeCall struct {
+	Method  string // Name of the method, e.g. "Fatalf".
+	Message string // Formatted message. It's empty for Helper.
+}
+
+// FakeT is a fake T recording all calls and messages.
+// It's designed to test customized assert functions without failing the real test case.
+//
+// Unlike *testing.T, FakeT.Fatalf doesn't stop current goroutine.
+// Code after a failed assertion keeps running.
+type FakeT struct {
+	name string
+
+	m      sync.Mutex
+	calls  []FakeCall
+	failed bool
+}
+
+// NewFakeT creates a FakeT with name.
+func NewFakeT(name string) *FakeT {
+	return &FakeT{
+		name: name,
+	}
+}
+
+// Name returns the name of the fake test case.
+func (ft *FakeT) Name() string {
+	return ft.name
+}
+
+// Helper records a call to Helper.
+func (ft *FakeT) Helper() {
+	ft.record("Helper", "")
+}
+
+// Errorf records a call to Errorf with formatted message and marks ft as failed.
+func (ft *FakeT) Errorf(format string, args ...interface{}) {
+	ft.recordFailure("Errorf", fmt.Sprintf(format, args...))
+}
+
+// Fatalf records a call to Fatalf with formatted message and marks ft as failed.
+func (ft *FakeT) Fatalf(format string, args ...interface{}) {
+	ft.recordFailure("Fatalf", fmt.Sprintf(format, args...))
+}
+
+// Fail records a call to Fail and marks ft as failed.
+func (ft *FakeT) Fail() {
+	ft.recordFailure("Fail", "")
+}
+
+// FailNow records a call to FailNow and marks ft as failed.
+// Unlike *testing.T, it doesn't stop current goroutine.
+func (ft *FakeT) FailNow() {
+	ft.recordFailure("FailNow", "")
+}
+
+// Logf records a call to Logf with formatted message.
+func (ft *FakeT) Logf(format string, args ...interface{}) {
+	ft.record("Logf", fmt.Sprintf(format, args...))
+}
+
+// Skipf records a call to Skipf with formatted message.
+// Unlike *testing.T, it doesn't stop current goroutine.
+func (ft *FakeT) Skipf(format string, args ...interface{}) {
+	ft.record("Skipf", fmt.Sprintf(format, args...))
+}
+
+func (ft *FakeT) record(method, msg string) {
+	ft.m.Lock()
+	defer ft.m.Unlock()
+	ft.calls = append(ft.calls, FakeCall{
+		Method:  method,
+		Message: msg,
+	})
+}
+
+func (ft *FakeT) recordFailure(method, msg string) {
+	ft.m.Lock()
+	defer ft.m.Unlock()
+	ft.failed = true
+	ft.calls = append(ft.calls, FakeCall{
+		Method:  method,
+		Message: msg,
+	})
+}
+
+// Calls returns all recorded calls in order.
+func (ft *FakeT) Calls() []FakeCall {
+	ft.m.Lock()
+	defer ft.m.Unlock()
+
+	calls := make([]FakeCall, len(ft.calls))
+	copy(calls, ft.calls)
+	return calls
+}
+
+// Failed returns true if Errorf, Fatalf, Fail or FailNow is called.
+func (ft *FakeT) Failed() bool {
+	ft.m.Lock()
+	defer ft.m.Unlock()
+	return ft.failed
+}
+
+// Fatal returns true if Fatalf is called.
+func (ft *FakeT) Fatal() bool {
+	for _, call := range ft.Calls() {
+		if call.Method == "Fatalf" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Messages returns messages passed to Errorf and Fatalf in order.
+// Leading and trailing spaces are trimmed.
+func (ft *FakeT) Messages() []string {
+	calls := ft.Calls()
+	msgs := make([]string, 0, len(calls))
+
+	for _, call := range calls {
+		if call.Method != "Errorf" && call.Method != "Fatalf" {
+			continue
+		}
+
+		msgs = append(msgs, strings.TrimSpace(call.Message))
+	}
+
+	return msgs
+}
+
+// Skipped returns true if Skipf is called.
+func (ft *FakeT) Skipped() bool {
+	for _, call := range ft.Calls() {
+		if call.Method == "Skipf" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HelperCalls returns the number of calls to Helper.
+func (ft *FakeT) HelperCalls() int {
+	count := 0
+
+	for _, call := range ft.Calls() {
+		if call.Method == "Helper" {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Reset removes all recorded calls.
+func (ft *FakeT) Reset() {
+	ft.m.Lock()
+	defer ft.m.Unlock()
+	ft.calls = nil
+	ft.failed = false
+}
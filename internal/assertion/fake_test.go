@@ -0,0 +1,132 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// assertMessage compares the failure message msg with expected except the location prefix, e.g. "foo_test.go:12: ".
+// A leading newline in expected is ignored, so that expected can start on its own line in a raw string.
+func assertMessage(t *testing.T, msg, expected string) {
+	t.Helper()
+	_, actual, _ := strings.Cut(stripColors(msg), ": ")
+	assertEqual(t, actual, strings.TrimPrefix(expected, "\n"))
+}
+
+func TestFakeT(t *testing.T) {
+	ft := NewFakeT("TestFake")
+	AssertEqual(ft, 1, 1, &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+	})
+	assertEqual(t, ft.Failed(), false)
+
+	AssertEqual(ft, 1, 2, &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+	})
+	assertEqual(t, ft.Fatal(), true)
+
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.HasPrefix(msgs[0], "fake_test.go:"), true)
+	assertEqual(t, strings.Contains(msgs[0], "AssertEqual(ft, 1, 2, &Trigger{"), true)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldEqual), true)
+
+	helpers := ft.HelperCalls()
+	assertEqual(t, helpers > 0, true)
+
+	ft.Errorf("foo %v", 123)
+	ft.Helper()
+	assertEqual(t, ft.Messages()[1], "foo 123")
+	assertEqual(t, ft.HelperCalls(), helpers+1)
+
+	ft.Reset()
+	assertEqual(t, ft.Failed(), false)
+	assertEqual(t, len(ft.Calls()), 0)
+}
+
+func TestFakeTFailed(t *testing.T) {
+	ft := NewFakeT("TestFakeTFailed")
+	ft.Logf("log")
+	ft.Skipf("skip")
+	ft.Helper()
+	assertEqual(t, ft.Failed(), false)
+
+	for _, fail := range []func(){
+		ft.Fail,
+		ft.FailNow,
+		func() { ft.Errorf("error") },
+		func() { ft.Fatalf("fatal") },
+	} {
+		ft.Reset()
+		fail()
+		assertEqual(t, ft.Failed(), true)
+	}
+}
+
+func TestTriggerOptions(t *testing.T) {
+	ft := NewFakeT("TestOptions")
+	color := true
+	AssertEqual(ft, []int{1}, []int{2}, &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options: Options{
+			Color:    &color,
+			NonFatal: true,
+			Formatter: func(f *Failure) string {
+				return f.FuncName + ": " + f.Message
+			},
+			Dumper: DumperFunc(func(v interface{}) string {
+				return "dumped"
+			}),
+		},
+	})
+	calls := nonHelperCalls(ft)
+	assertEqual(t, len(calls), 1)
+	assertEqual(t, calls[0].Method, "Errorf")
+
+	msg := strings.TrimSpace(calls[0].Message)
+	assertEqual(t, strings.HasPrefix(msg, "AssertEqual: fake_test.go:"), true)
+	assertEqual(t, strings.Contains(msg, colorReset), true)
+	assertEqual(t, strings.Contains(msg, "[1] -> dumped"), true)
+
+	ft.Reset()
+	color = false
+	provided := 0
+	AssertEqual(ft, 1, 2, &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Parser: &Parser{
+			Source: func(filename string) ([]byte, error) {
+				provided++
+				return os.ReadFile(filename)
+			},
+		},
+		Options: Options{
+			Color: &color,
+		},
+	})
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, provided, 1)
+	assertEqual(t, strings.Contains(msgs[0], "AssertEqual(ft, 1, 2, &Trigger{"), true)
+	assertEqual(t, strings.Contains(msgs[0], colorReset), false)
+}
+
+// nonHelperCalls returns calls to ft except calls to Helper.
+func nonHelperCalls(ft *FakeT) []FakeCall {
+	var calls []FakeCall
+
+	for _, call := range ft.Calls() {
+		if call.Method != "Helper" {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
@@ -2,6 +2,7 @@ package assertion
 
 import (
 	"reflect"
+	"sync"
 )
 
 // FalseKind is the kind of a false-equivalent value.
@@ -14,16 +15,76 @@ const (
 	False
 	Zero
 	EmptyString
+	Falsy // A false-equivalent value detected by a func registered by RegisterFalsy.
 )
 
+var (
+	falsyLock  sync.RWMutex
+	falsyFuncs []*func(v interface{}) (FalseKind, bool)
+)
+
+// RegisterFalsy registers falsy to detect custom false-equivalent values,
+// e.g. a `sql.NullString` which is not valid or the zero value of a domain type.
+// Call remove to unregister falsy.
+//
+// The falsy is called with every non-nil value tested by ParseFalseKind.
+// It returns the kind of v and true if it handles v, e.g. `(Falsy, true)` for a false-equivalent value
+// or `(Positive, true)` for a true-equivalent value.
+// It returns false to let other falsy funcs and built-in rules handle v.
+// Funcs registered later are called first.
+func RegisterFalsy(falsy func(v interface{}) (FalseKind, bool)) (remove func()) {
+	entry := &falsy
+	falsyLock.Lock()
+	falsyFuncs = append(falsyFuncs, entry)
+	falsyLock.Unlock()
+
+	return func() {
+		falsyLock.Lock()
+		defer falsyLock.Unlock()
+
+		fns := make([]*func(v interface{}) (FalseKind, bool), 0, len(falsyFuncs))
+
+		for _, fn := range falsyFuncs {
+			if fn != entry {
+				fns = append(fns, fn)
+			}
+		}
+
+		falsyFuncs = fns
+	}
+}
+
+// parseCustomFalseKind returns the kind of expr detected by registered falsy funcs.
+func parseCustomFalseKind(expr interface{}) (FalseKind, bool) {
+	falsyLock.RLock()
+	defer falsyLock.RUnlock()
+
+	for i := len(falsyFuncs) - 1; i >= 0; i-- {
+		if k, ok := (*falsyFuncs[i])(expr); ok {
+			return k, true
+		}
+	}
+
+	return Positive, false
+}
+
 // ParseFalseKind checks expr value and return false when expr is `false`, 0, `nil` and empty string.
 // Otherwise, return true.
+// Values detected by funcs registered by RegisterFalsy are checked first.
 func ParseFalseKind(expr interface{}) FalseKind {
 	if expr == nil {
 		return Nil
 	}
 
-	if v, ok := expr.(bool); ok && !v {
+	if k, ok := parseCustomFalseKind(expr); ok {
+		return k
+	}
+
+	if v, ok := expr.(bool); ok {
+		if v {
+			return Positive
+		}
+
 		return False
 	}
 
@@ -0,0 +1,125 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// RunFlaky calls attempt until it passes or it has been called maxAttempts times.
+// Assertions in attempt must report failures to the T and use the Options passed to attempt.
+//
+// If an attempt passes after failed attempts, the last failure is reported to failure hooks
+// with Flaky set to true and the test case passes.
+// If all attempts fail, failures of the last attempt are reported to t.
+func RunFlaky(t T, maxAttempts int, opts Options, attempt func(t T, opts Options)) {
+	t.Helper()
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var at *attemptT
+	var last *Failure
+
+	for i := 1; i <= maxAttempts; i++ {
+		at = &attemptT{
+			name: t.Name(),
+		}
+		attemptOpts := opts
+		attemptOpts.SkipHooks = true
+		attemptOpts.Formatter = func(f *Failure) string {
+			last = f
+
+			if opts.Formatter != nil {
+				return opts.Formatter(f)
+			}
+
+			return formatOutput(f.Message)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			attempt(at, attemptOpts)
+		}()
+		<-done
+
+		if !at.failed() {
+			if last != nil && !opts.SkipHooks {
+				flaky := *last
+				flaky.Flaky = true
+				flaky.Attempts = i
+				runFailureHooks(&flaky)
+			}
+
+			return
+		}
+	}
+
+	if last != nil && !opts.SkipHooks {
+		failure := *last
+		failure.Attempts = maxAttempts
+		runFailureHooks(&failure)
+	}
+
+	at.replay(t)
+}
+
+// attemptT is the T of an attempt in RunFlaky.
+// Fatalf stops the attempt by calling `runtime.Goexit`.
+type attemptT struct {
+	name string
+
+	m     sync.Mutex
+	calls []FakeCall
+}
+
+func (at *attemptT) Name() string {
+	return at.name
+}
+
+func (at *attemptT) Helper() {}
+
+func (at *attemptT) Errorf(format string, args ...interface{}) {
+	at.record("Errorf", fmt.Sprintf(format, args...))
+}
+
+func (at *attemptT) Fatalf(format string, args ...interface{}) {
+	at.record("Fatalf", fmt.Sprintf(format, args...))
+	runtime.Goexit()
+}
+
+func (at *attemptT) record(method, msg string) {
+	at.m.Lock()
+	defer at.m.Unlock()
+	at.calls = append(at.calls, FakeCall{
+		Method:  method,
+		Message: msg,
+	})
+}
+
+// replay reports recorded failures to t in order.
+func (at *attemptT) replay(t T) {
+	t.Helper()
+	at.m.Lock()
+	calls := at.calls
+	at.m.Unlock()
+
+	for _, call := range calls {
+		if call.Method == "Fatalf" {
+			t.Fatalf("%v", call.Message)
+			return
+		}
+
+		t.Errorf("%v", call.Message)
+	}
+}
+
+func (at *attemptT) failed() bool {
+	at.m.Lock()
+	defer at.m.Unlock()
+	return len(at.calls) > 0
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"testing"
+)
+
+func TestRunFlaky(t *testing.T) {
+	var failures []Failure
+	remove := AddFailureHook(func(f *Failure) {
+		failures = append(failures, *f)
+	})
+	defer remove()
+
+	ft := NewFakeT("TestRunFlaky")
+	count := 0
+	RunFlaky(ft, 3, Options{}, func(t T, opts Options) {
+		count++
+		Assert(t, count == 2, &Trigger{
+			FuncName: "Assert",
+			Args:     []int{1},
+			Options:  opts,
+		})
+	})
+
+	assertEqual(t, count, 2)
+	assertEqual(t, ft.Failed(), false)
+	assertEqual(t, len(failures), 1)
+	assertEqual(t, failures[0].Flaky, true)
+	assertEqual(t, failures[0].Attempts, 2)
+	assertEqual(t, failures[0].TestName, "TestRunFlaky")
+
+	failures = nil
+	count = 0
+	RunFlaky(ft, 3, Options{}, func(t T, opts Options) {
+		count++
+		Assert(t, count > 3, &Trigger{
+			FuncName: "Assert",
+			Args:     []int{1},
+			Options:  opts,
+		})
+		count += 100
+	})
+
+	assertEqual(t, count, 3)
+	assertEqual(t, ft.Fatal(), true)
+	assertEqual(t, len(ft.Messages()), 1)
+	assertEqual(t, len(failures), 1)
+	assertEqual(t, failures[0].Flaky, false)
+	assertEqual(t, failures[0].Attempts, 3)
+}
@@ -0,0 +1,82 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// modulePath is the import path of this module.
+// Frames in packages under it are skipped when reporting failures to Ginkgo.
+const modulePath = "github.com/huandu/go-assert"
+
+// GinkgoFail is the signature of `ginkgo.Fail` in github.com/onsi/ginkgo.
+type GinkgoFail func(message string, callerSkip ...int)
+
+// GinkgoT is a T reporting failures to Ginkgo by calling `ginkgo.Fail`.
+// The callerSkip passed to `ginkgo.Fail` skips all frames in this module,
+// so that Ginkgo reports a failure at the assertion call in the spec.
+//
+// Ginkgo has no non-fatal failure. Both Errorf and Fatalf fail the spec and stop it.
+type GinkgoT struct {
+	fail GinkgoFail
+}
+
+var _ T = (*GinkgoT)(nil)
+
+// NewGinkgoT creates a GinkgoT calling fail to report failures.
+func NewGinkgoT(fail GinkgoFail) *GinkgoT {
+	return &GinkgoT{
+		fail: fail,
+	}
+}
+
+// Name returns an empty string, as the spec text is unavailable without importing Ginkgo.
+func (gt *GinkgoT) Name() string {
+	return ""
+}
+
+// Helper does nothing.
+func (gt *GinkgoT) Helper() {}
+
+// Errorf fails the spec with formatted message.
+func (gt *GinkgoT) Errorf(format string, args ...interface{}) {
+	gt.report(fmt.Sprintf(format, args...))
+}
+
+// Fatalf fails the spec with formatted message.
+func (gt *GinkgoT) Fatalf(format string, args ...interface{}) {
+	gt.report(fmt.Sprintf(format, args...))
+}
+
+func (gt *GinkgoT) report(msg string) {
+	gt.fail(strings.TrimPrefix(msg, "\n"), callerSkip())
+}
+
+// callerSkip returns the number of frames between the caller of callerSkip
+// and the first frame out of this module, which is the callerSkip of `ginkgo.Fail`.
+// Test files in this module are treated as out of this module.
+func callerSkip() int {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for skip := 0; ; skip++ {
+		frame, more := frames.Next()
+
+		if !inModule(frame) || !more {
+			return skip
+		}
+	}
+}
+
+func inModule(frame runtime.Frame) bool {
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+
+	return strings.HasPrefix(frame.Function, modulePath+".") || strings.HasPrefix(frame.Function, modulePath+"/")
+}
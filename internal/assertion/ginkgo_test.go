@@ -0,0 +1,89 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type ginkgoFailure struct {
+	Message string
+	File    string
+	Line    int
+}
+
+// fakeGinkgoFail reads the location from callerSkip like `ginkgo.Fail`.
+func fakeGinkgoFail(failures *[]ginkgoFailure) GinkgoFail {
+	return func(message string, callerSkip ...int) {
+		skip := 0
+
+		if len(callerSkip) > 0 {
+			skip = callerSkip[0]
+		}
+
+		_, file, line, _ := runtime.Caller(skip + 1)
+		*failures = append(*failures, ginkgoFailure{
+			Message: message,
+			File:    filepath.Base(file),
+			Line:    line,
+		})
+	}
+}
+
+func TestGinkgoT(t *testing.T) {
+	var failures []ginkgoFailure
+	gt := NewGinkgoT(fakeGinkgoFail(&failures))
+	_, _, line, _ := runtime.Caller(0)
+	AssertEqual(gt, 1, 2, &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+	})
+
+	assertEqual(t, len(failures), 1)
+	assertEqual(t, failures[0].File, "ginkgo_test.go")
+	assertEqual(t, failures[0].Line, line+1)
+	assertEqual(t, strings.HasPrefix(failures[0].Message, "ginkgo_test.go:"), true)
+}
+
+type fakeGomegaMatcher struct {
+	expected interface{}
+	err      error
+}
+
+func (m fakeGomegaMatcher) Match(actual interface{}) (bool, error) {
+	return actual == m.expected, m.err
+}
+
+func (m fakeGomegaMatcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n    %v\nto equal\n    %v", actual, m.expected)
+}
+
+func (m fakeGomegaMatcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("Expected\n    %v\nnot to equal\n    %v", actual, m.expected)
+}
+
+func TestMatchGomega(t *testing.T) {
+	d := CurrentDumper()
+	cases := []struct {
+		m    GomegaMatcher
+		ok   bool
+		desc string
+	}{
+		{fakeGomegaMatcher{expected: 1}, true, "Expected\n    1\nnot to equal\n    1"},
+		{fakeGomegaMatcher{expected: 2}, false, "Expected\n    1\nto equal\n    2"},
+		{fakeGomegaMatcher{expected: 1, err: errors.New("boom")}, false, "Gomega matcher failed with error: boom"},
+		{nil, false, errNilMatcher.Error()},
+	}
+
+	for _, c := range cases {
+		result := MatchGomega(c.m).Match(1, d)
+		assertEqual(t, result.OK, c.ok)
+		assertEqual(t, result.Description, c.desc)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Environment variables set by GitHub Actions.
+const (
+	EnvGitHubWorkspace   = "GITHUB_WORKSPACE"
+	EnvGitHubStepSummary = "GITHUB_STEP_SUMMARY"
+)
+
+var (
+	// annotationOutput receives workflow commands of failures.
+	// GitHub Actions parses them from stdout of the step.
+	annotationOutput io.Writer = os.Stdout
+	annotationLock   sync.Mutex
+)
+
+// annotateFailure prints f as an `::error` workflow command, so that GitHub Actions shows it
+// inline on the file and line of the assertion in PR diffs.
+// If env GITHUB_STEP_SUMMARY is set, f is also appended to the job summary in markdown.
+//
+// Flaky and quarantined failures don't fail the test case and are not annotated.
+func annotateFailure(f *Failure) {
+	if !GitHubAnnotationsEnabled() || f.Flaky || f.Quarantine != "" {
+		return
+	}
+
+	msg := stripColors(f.Message)
+	file := annotationFile(f)
+	props := make([]string, 0, 3)
+
+	if file != "" {
+		props = append(props, "file="+escapeProperty(file))
+
+		if f.Line > 0 {
+			props = append(props, fmt.Sprintf("line=%v", f.Line))
+		}
+	}
+
+	if f.TestName != "" {
+		props = append(props, "title="+escapeProperty(f.TestName))
+	}
+
+	cmd := "::error"
+
+	if len(props) > 0 {
+		cmd += " " + strings.Join(props, ",")
+	}
+
+	cmd += "::" + escapeData(msg) + "\n"
+
+	annotationLock.Lock()
+	defer annotationLock.Unlock()
+	io.WriteString(annotationOutput, cmd)
+
+	if summary := os.Getenv(EnvGitHubStepSummary); summary != "" {
+		writeStepSummary(summary, f, file, msg)
+	}
+}
+
+// annotationFile returns the file of f relative to GITHUB_WORKSPACE,
+// which is the form GitHub Actions expects to match files in PR diffs.
+// It falls back to the full path or the base name of the file.
+func annotationFile(f *Failure) string {
+	if f.Path == "" {
+		return f.Filename
+	}
+
+	if ws := os.Getenv(EnvGitHubWorkspace); ws != "" {
+		if rel, err := filepath.Rel(ws, f.Path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	return filepath.ToSlash(f.Path)
+}
+
+// writeStepSummary appends f to the job summary file in markdown.
+// Errors are ignored, as the summary is a best-effort addition to the test output.
+func writeStepSummary(filename string, f *Failure, file, msg string) {
+	out, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+
+	if err != nil {
+		return
+	}
+
+	defer out.Close()
+
+	title := f.TestName
+
+	if title == "" {
+		title = f.FuncName
+	}
+
+	fmt.Fprintf(out, "#### %v\n\n", title)
+
+	if file != "" {
+		fmt.Fprintf(out, "`%v:%v`\n\n", file, f.Line)
+	}
+
+	fence := "```"
+
+	for strings.Contains(msg, fence) {
+		fence += "`"
+	}
+
+	fmt.Fprintf(out, "%v\n%v\n%v\n\n", fence, msg, fence)
+}
+
+// escapeData escapes s as the data of a workflow command.
+func escapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeProperty escapes s as a property value of a workflow command.
+func escapeProperty(s string) string {
+	s = escapeData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
+
+// callerPath returns the full path of the file named filename in call stack
+// which has a frame at line.
+// It returns empty string if there is no such frame.
+func callerPath(filename string, line int) string {
+	if filename == "" {
+		return ""
+	}
+
+	var pcs [64]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+
+		if frame.Line == line && filepath.Base(frame.File) == filename {
+			return frame.File
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnnotateFailure(t *testing.T) {
+	buf := &bytes.Buffer{}
+	annotationOutput = buf
+	defer func() {
+		annotationOutput = os.Stdout
+	}()
+
+	summary := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv(EnvGitHubStepSummary, summary)
+	wd, _ := os.Getwd()
+	t.Setenv(EnvGitHubWorkspace, wd)
+
+	SetGitHubAnnotations(true)
+	defer SetGitHubAnnotations(false)
+
+	ft := NewFakeT("TestAnnotateFailure/a,b")
+	trigger := &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true},
+	}
+	AssertEqual(ft, "50%", "a:b", trigger)
+	assertEqual(t, len(ft.Messages()), 1)
+
+	out := buf.String()
+	assertEqual(t, strings.HasPrefix(out, "::error file=github_test.go,line="), true)
+	assertEqual(t, strings.Contains(out, ",title=TestAnnotateFailure/a%2Cb::"), true)
+	assertEqual(t, strings.Contains(out, "50%25"), true)
+	assertEqual(t, strings.Contains(out, "%0A"), true)
+	assertEqual(t, strings.Count(out, "\n"), 1)
+
+	data, err := os.ReadFile(summary)
+	assertEqual(t, err, nil)
+	assertEqual(t, strings.HasPrefix(string(data), "#### TestAnnotateFailure/a,b\n\n`github_test.go:"), true)
+	assertEqual(t, strings.Contains(string(data), "```\n"), true)
+
+	// Flaky failures don't fail the test case and are not annotated.
+	buf.Reset()
+	annotateFailure(&Failure{
+		TestName: "TestFlaky",
+		Message:  "failed",
+		Flaky:    true,
+	})
+	assertEqual(t, buf.String(), "")
+
+	SetGitHubAnnotations(false)
+	AssertEqual(ft, 1, 2, trigger)
+	assertEqual(t, buf.String(), "")
+}
+
+func TestAnnotationFile(t *testing.T) {
+	ws := filepath.Join(string(filepath.Separator)+"work", "repo")
+	t.Setenv(EnvGitHubWorkspace, ws)
+
+	cases := []struct {
+		failure  *Failure
+		expected string
+	}{
+		{&Failure{Filename: "a_test.go"}, "a_test.go"},
+		{&Failure{Filename: "a_test.go", Path: filepath.Join(ws, "pkg", "a_test.go")}, "pkg/a_test.go"},
+		{&Failure{Filename: "a_test.go", Path: filepath.Join(ws+"2", "a_test.go")}, filepath.ToSlash(filepath.Join(ws+"2", "a_test.go"))},
+	}
+
+	for _, c := range cases {
+		assertEqual(t, annotationFile(c.failure), c.expected)
+	}
+}
+
+func TestEscapeProperty(t *testing.T) {
+	assertEqual(t, escapeData("100%\r\nok: a,b"), "100%25%0D%0Aok: a,b")
+	assertEqual(t, escapeProperty("100%\r\nok: a,b"), "100%25%0D%0Aok%3A a%2Cb")
+}
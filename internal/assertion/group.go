@@ -0,0 +1,61 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// Group is a labeled block of assertions.
+// Failures in the block are prefixed with the label and the location of the block.
+type Group struct {
+	Label    string // Label of the group, e.g. "creating user".
+	Filename string // Base name of the file starting the group.
+	Line     int    // Line number of the call starting the group.
+}
+
+// RunGrouped calls block with opts in which a group with label is appended to opts.Groups.
+// The location of the group is the caller skipping skip frames, e.g. 0 is the caller of RunGrouped.
+// Groups can be nested. Failures list all enclosing groups, outermost first.
+func RunGrouped(label string, skip int, opts Options, block func(opts Options)) {
+	g := Group{
+		Label: label,
+	}
+
+	if _, file, line, ok := runtime.Caller(skip + 1); ok {
+		g.Filename = path.Base(file)
+		g.Line = line
+	}
+
+	// Copy groups so that sibling groups never share the backing array.
+	groups := make([]Group, 0, len(opts.Groups)+1)
+	groups = append(groups, opts.Groups...)
+	opts.Groups = append(groups, g)
+	block(opts)
+}
+
+// formatGroups formats groups as lines prefixed to the failure message.
+func formatGroups(msgs Messages, groups []Group) string {
+	lines := make([]string, 0, len(groups))
+
+	for _, g := range groups {
+		lines = append(lines, fmt.Sprintf(msgs.GroupFormat, g.Label, g.Filename, g.Line))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// groupLabels returns labels of groups, outermost first.
+func groupLabels(groups []Group) []string {
+	labels := make([]string, 0, len(groups))
+
+	for _, g := range groups {
+		labels = append(labels, g.Label)
+	}
+
+	return labels
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunGrouped(t *testing.T) {
+	var failures []Failure
+	remove := AddFailureHook(func(f *Failure) {
+		failures = append(failures, *f)
+	})
+	defer remove()
+
+	ft := NewFakeT("TestRunGrouped")
+	var innerLine int
+	_, _, outerLine, _ := runtime.Caller(0)
+	RunGrouped("creating user", 0, Options{NonFatal: true}, func(opts Options) {
+		Assert(ft, 1 > 2, &Trigger{
+			FuncName: "Assert",
+			Args:     []int{1},
+			Options:  opts,
+		})
+
+		_, _, innerLine, _ = runtime.Caller(0)
+		RunGrouped("setting password", 0, opts, func(opts Options) {
+			Assert(ft, 2 > 3, &Trigger{
+				FuncName: "Assert",
+				Args:     []int{1},
+				Options:  opts,
+			})
+		})
+	})
+
+	outer := fmt.Sprintf("[creating user] group_test.go:%v:\n", outerLine+1)
+	inner := fmt.Sprintf("[setting password] group_test.go:%v:\n", innerLine+1)
+	msgs := ft.Messages()
+
+	assertEqual(t, len(msgs), 2)
+	assertEqual(t, len(failures), 2)
+	assertEqual(t, strings.HasPrefix(failures[0].Message, outer+"group_test.go:"), true)
+	assertEqual(t, strings.HasPrefix(failures[1].Message, outer+inner+"group_test.go:"), true)
+	assertEqual(t, strings.Contains(msgs[0], "creating user"), true)
+	assertEqual(t, strings.Contains(msgs[0], "setting password"), false)
+	assertEqual(t, strings.Contains(msgs[1], "setting password"), true)
+	assertEqual(t, failures[0].Groups, []string{"creating user"})
+	assertEqual(t, failures[1].Groups, []string{"creating user", "setting password"})
+
+	// Groups end with their blocks.
+	failures = nil
+	Assert(ft, 3 > 4, &Trigger{
+		FuncName: "Assert",
+		Args:     []int{1},
+		Options:  Options{NonFatal: true},
+	})
+
+	assertEqual(t, len(failures), 1)
+	assertEqual(t, len(failures[0].Groups), 0)
+	assertEqual(t, strings.HasPrefix(failures[0].Message, "group_test.go:"), true)
+}
@@ -0,0 +1,159 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// Guard collects panics recovered in goroutines and reports them as failures in the test goroutine.
+// Panics are reported when Check is called or, if t implements `Cleanup(func())`,
+// after the test case completes.
+type Guard struct {
+	t       T
+	trigger *Trigger
+
+	// Location of the call creating the guard.
+	// A failure is reported at this location as a goroutine has no assertion call site.
+	filename string
+	line     int
+	function string
+
+	lock   sync.Mutex
+	panics []recoveredPanic
+}
+
+type recoveredPanic struct {
+	Value interface{}
+	Stack string
+}
+
+// NewGuard creates a new Guard reporting panics to t.
+// The trigger.Skip must be the stack frame calling the function creating the guard.
+func NewGuard(t T, trigger *Trigger) *Guard {
+	g := &Guard{
+		t:       t,
+		trigger: trigger,
+	}
+
+	// The location is read eagerly, as the stack is unavailable when the failure is reported.
+	// If it fails, the error is reported with panics.
+	g.filename, g.line, g.function, _ = findCaller(trigger.Skip + 1)
+
+	if c, ok := t.(cleaner); ok {
+		c.Cleanup(g.Check)
+	}
+
+	return g
+}
+
+// Add records a panic value recovered by a deferred func in a goroutine.
+// The stack of the panicking goroutine is recorded as well,
+// so that Add must be called by the deferred func before it returns.
+func (g *Guard) Add(recovered interface{}) {
+	stack := trimPanicStack(string(debug.Stack()))
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.panics = append(g.panics, recoveredPanic{
+		Value: recovered,
+		Stack: stack,
+	})
+}
+
+// Check reports all panics recovered since last check.
+// It must be called in the test goroutine.
+func (g *Guard) Check() {
+	g.t.Helper()
+	g.lock.Lock()
+	panics := g.panics
+	g.panics = nil
+	g.lock.Unlock()
+
+	for _, p := range panics {
+		g.report(p)
+	}
+}
+
+func (g *Guard) report(p recoveredPanic) {
+	g.t.Helper()
+	trigger := g.trigger
+
+	if trigger.Options.Timer != nil {
+		trigger.Options.Timer.StopTimer()
+	}
+
+	if g.filename == "" {
+		failInternal(g.t, trigger, fmt.Errorf("fail to read source code information of guard; panic: %v", p.Value))
+		return
+	}
+
+	f, err := trigger.P().parseCall(g.filename, g.line, g.function, trigger.FuncName, nil)
+
+	if err != nil {
+		failInternal(g.t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	value := formatPanicValue(trigger, p.Value)
+	fail(g.t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n    %v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4),
+			msgs.GoroutinePanicked, indentCode(value, 4),
+			msgs.PanicStack, indentCode(p.Stack, 4),
+			formatVars(msgs, info, trigger),
+		),
+		Values: []string{value, p.Stack},
+	})
+}
+
+// formatPanicValue formats an error panic value, e.g. a runtime error, by its message
+// instead of dumping its internal fields.
+func formatPanicValue(trigger *Trigger, v interface{}) string {
+	if err, ok := v.(error); ok {
+		return fmt.Sprintf("(%T)%v", err, err)
+	}
+
+	return trigger.dumper().Dump(v)
+}
+
+// trimPanicStack removes frames before the panic from a stack returned by `debug.Stack()`
+// in a deferred func, i.e. frames of `debug.Stack`, the deferred func and the runtime panic handling.
+// The goroutine header is kept.
+func trimPanicStack(stack string) string {
+	const panicFrame = "\npanic("
+
+	idx := strings.Index(stack, panicFrame)
+
+	if idx < 0 {
+		return strings.TrimSpace(stack)
+	}
+
+	header := stack
+
+	if nl := strings.IndexByte(stack, '\n'); nl >= 0 {
+		header = stack[:nl]
+	}
+
+	rest := stack[idx+len(panicFrame):]
+
+	// Skip the panic frame, which is followed by its file and line.
+	for i := 0; i < 2; i++ {
+		if nl := strings.IndexByte(rest, '\n'); nl >= 0 {
+			rest = rest[nl+1:]
+		}
+	}
+
+	return header + "\n" + strings.TrimSpace(rest)
+}
@@ -0,0 +1,76 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGuard(t *testing.T) {
+	ft := NewFakeT("TestGuard")
+	g := NewGuard(ft, &Trigger{
+		FuncName: "NewGuard",
+		Options:  Options{NonFatal: true},
+	})
+	var wg sync.WaitGroup
+	panics := []interface{}{"foo", errors.New("bar"), nil}
+	wg.Add(len(panics))
+
+	for _, p := range panics {
+		go func(p interface{}) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					g.Add(r)
+				}
+			}()
+
+			if p != nil {
+				panic(p)
+			}
+		}(p)
+	}
+
+	wg.Wait()
+	assertEqual(t, len(ft.Messages()), 0)
+
+	g.Check()
+	g.Check()
+	msgs := ft.Messages()
+
+	assertEqual(t, len(msgs), 2)
+
+	for _, msg := range msgs {
+		assertEqual(t, strings.Contains(msg, "guard_test.go:"), true)
+		assertEqual(t, strings.Contains(msg, "NewGuard(ft, &Trigger{"), true)
+		assertEqual(t, strings.Contains(msg, "A goroutine guarded by following guard panicked with:"), true)
+		assertEqual(t, strings.Contains(msg, "TestGuard.func1"), true)
+		assertEqual(t, strings.Contains(msg, "panic("), false)
+	}
+
+	all := strings.Join(msgs, "\n")
+	assertEqual(t, strings.Contains(all, "(string)foo"), true)
+	assertEqual(t, strings.Contains(all, "(*errors.errorString)bar"), true)
+}
+
+func TestTrimPanicStack(t *testing.T) {
+	stack := `goroutine 9 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+main.main.func1()
+	/path/to/main.go:8 +0x25
+panic({0x4a5f20?, 0x4d8a70?})
+	/usr/local/go/src/runtime/panic.go:770 +0x132
+main.process(...)
+	/path/to/main.go:12
+`
+
+	assertEqual(t, trimPanicStack(stack), `goroutine 9 [running]:
+main.process(...)
+	/path/to/main.go:12`)
+	assertEqual(t, trimPanicStack("goroutine 1 [running]:\nmain.main()\n"), "goroutine 1 [running]:\nmain.main()")
+}
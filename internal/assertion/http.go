@@ -0,0 +1,43 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AssertHTTP expects ok is true.
+// Otherwise, it will terminate the test case using `t.Fatalf` with explanation
+// and the last request and response exchanged with a test server.
+func AssertHTTP(t T, ok bool, explanation, exchange string, trigger *Trigger) {
+	t.Helper()
+	if ok {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4),
+			explanation, msgs.LastExchange,
+			strings.Replace(strings.TrimSpace(exchange), "\n", "\n    ", -1),
+			formatVars(msgs, info, trigger),
+		),
+		Values: []string{exchange},
+	})
+}
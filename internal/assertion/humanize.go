@@ -0,0 +1,240 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"math/big"
+	"path"
+	"reflect"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+)
+
+// TimestampHint marks integer struct fields as timestamps,
+// so that they are dumped with their time in RFC3339 format.
+type TimestampHint struct {
+	Field string        // Pattern of field names matched by `path.Match`, e.g. "CreatedAt" or "*At".
+	Unit  time.Duration // Unit of timestamps since Unix epoch, e.g. `time.Second` or `time.Millisecond`.
+}
+
+var (
+	timestampHintLock sync.RWMutex
+	timestampHints    []*TimestampHint
+)
+
+// AddTimestampHint registers hint.
+// Call remove to unregister the hint.
+// If hint.Unit is not positive, `time.Second` is used.
+func AddTimestampHint(hint TimestampHint) (remove func()) {
+	if hint.Unit <= 0 {
+		hint.Unit = time.Second
+	}
+
+	entry := &hint
+	timestampHintLock.Lock()
+	timestampHints = append(timestampHints, entry)
+	timestampHintLock.Unlock()
+
+	return func() {
+		timestampHintLock.Lock()
+		defer timestampHintLock.Unlock()
+
+		hints := make([]*TimestampHint, 0, len(timestampHints))
+
+		for _, h := range timestampHints {
+			if h != entry {
+				hints = append(hints, h)
+			}
+		}
+
+		timestampHints = hints
+	}
+}
+
+// findTimestampHint returns the unit of the first hint matching field.
+func findTimestampHint(field string) (unit time.Duration, ok bool) {
+	timestampHintLock.RLock()
+	defer timestampHintLock.RUnlock()
+
+	for _, h := range timestampHints {
+		if matched, _ := path.Match(h.Field, field); matched {
+			return h.Unit, true
+		}
+	}
+
+	return
+}
+
+// humanize returns v in human form if v is a time.Time, a time.Duration, a big number,
+// a value of a type registered with a formatter or an integer field matching a timestamp hint.
+// The field is the name of the struct field holding v. It's empty if v is not a field.
+func humanize(v reflect.Value, field string) (s string, ok bool) {
+	if c := findComparator(v.Type()); c != nil {
+		if c.Format != nil {
+			return c.Format(reflect.ValueOf(exportedValue(v))), true
+		}
+
+		// Maps in the canonical form are dumped with sorted keys.
+		if canonical := c.canonical(v); canonical.IsValid() {
+			d := &cycleDumper{
+				ancestors: map[uintptr]string{},
+			}
+			d.dump("", "", canonical, false, 0)
+			return d.buf.String(), true
+		}
+	}
+
+	switch {
+	case v.Type() == timeType:
+		return exportedValue(v).(time.Time).Format(time.RFC3339Nano), true
+
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String(), true
+
+	case v.Type() == bigIntType:
+		x := exportedValue(v).(big.Int)
+		return x.String(), true
+
+	case v.Type() == bigFloatType:
+		x := exportedValue(v).(big.Float)
+		return x.Text('g', -1), true
+
+	case v.Type() == bigRatType:
+		x := exportedValue(v).(big.Rat)
+		return x.RatString(), true
+
+	case field != "" && isTimestampKind(v.Kind()):
+		unit, found := findTimestampHint(field)
+
+		if !found {
+			return
+		}
+
+		ts := time.Unix(0, v.Int()*int64(unit)).UTC()
+		return fmt.Sprintf("%v (%v)", v.Int(), ts.Format(time.RFC3339Nano)), true
+	}
+
+	return
+}
+
+// exportedValue returns the value of v even if v is an unexported field.
+// Unlike getValueInterface, unexported fields of an addressable struct are kept,
+// so that values like time.Time held by an unexported field can be formatted.
+func exportedValue(v reflect.Value) interface{} {
+	if !v.CanInterface() && v.CanAddr() {
+		return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem().Interface()
+	}
+
+	return getValueInterface(v)
+}
+
+// exportedAddressable returns an addressable value of v which can be used as if it's exported.
+// If v is addressable, the returned value shares memory with v. Otherwise, it's a copy of v.
+func exportedAddressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+	}
+
+	return addressable(reflect.ValueOf(exportedValue(v)))
+}
+
+// addressable returns an addressable copy of v,
+// so that unexported struct fields in v can be read by exportedValue.
+func addressable(v reflect.Value) reflect.Value {
+	if !v.IsValid() || v.CanAddr() {
+		return v
+	}
+
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
+// isHumanizedStruct reports whether values of struct type t are dumped in human form
+// and should be compared as a whole.
+func isHumanizedStruct(t reflect.Type) bool {
+	return t == timeType || t == bigIntType || t == bigFloatType || t == bigRatType
+}
+
+func isTimestampKind(k reflect.Kind) bool {
+	return k == reflect.Int64 || k == reflect.Int
+}
+
+// hasHumanized reports whether v contains any value rendered by humanize.
+func hasHumanized(v reflect.Value) bool {
+	h := &humanizedFinder{
+		visited: map[uintptr]struct{}{},
+	}
+	return h.find(v, "", 0)
+}
+
+type humanizedFinder struct {
+	visited map[uintptr]struct{}
+}
+
+func (h *humanizedFinder) find(v reflect.Value, field string, depth int) bool {
+	if !v.IsValid() || depth > maxDumpDepth {
+		return false
+	}
+
+	if _, ok := humanize(v, field); ok {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map:
+		if v.IsNil() {
+			return false
+		}
+
+		addr := v.Pointer()
+
+		if _, ok := h.visited[addr]; ok {
+			return false
+		}
+
+		h.visited[addr] = struct{}{}
+
+		if v.Kind() == reflect.Ptr {
+			return h.find(v.Elem(), field, depth+1)
+		}
+
+		iter := v.MapRange()
+
+		for iter.Next() {
+			if h.find(iter.Key(), "", depth+1) || h.find(iter.Value(), "", depth+1) {
+				return true
+			}
+		}
+
+	case reflect.Interface:
+		return h.find(v.Elem(), "", depth+1)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if h.find(v.Field(i), v.Type().Field(i).Name, depth+1) {
+				return true
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if h.find(v.Index(i), "", depth+1) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDumpHumanized(t *testing.T) {
+	type event struct {
+		At       time.Time
+		Duration time.Duration
+		Started  *time.Time
+		SentAtMs int64
+		seq      int64
+	}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	e := event{
+		At:       at,
+		Duration: 90 * time.Second,
+		Started:  &at,
+		SentAtMs: at.UnixMilli(),
+		seq:      1,
+	}
+
+	assertEqual(t, DefaultDumper.Dump(at), "(time.Time)2024-01-02T03:04:05.0000006Z")
+	assertEqual(t, DefaultDumper.Dump(struct{ at time.Time }{at}), "(struct { at time.Time }){at:(time.Time)2024-01-02T03:04:05.0000006Z}")
+	assertEqual(t, DefaultDumper.Dump(map[string]time.Duration{"timeout": time.Second}), "(map[string]time.Duration)map[timeout:1s]")
+	assertEqual(t, DefaultDumper.Dump(e), "(assertion.event){At:(time.Time)2024-01-02T03:04:05.0000006Z Duration:(time.Duration)1m30s Started:(*time.Time)2024-01-02T03:04:05.0000006Z SentAtMs:(int64)1704164645000 seq:(int64)1}")
+
+	remove := AddTimestampHint(TimestampHint{Field: "*AtMs", Unit: time.Millisecond})
+	assertEqual(t, DefaultDumper.Dump(e), "(assertion.event){At:(time.Time)2024-01-02T03:04:05.0000006Z Duration:(time.Duration)1m30s Started:(*time.Time)2024-01-02T03:04:05.0000006Z SentAtMs:(int64)1704164645000 (2024-01-02T03:04:05Z) seq:(int64)1}")
+
+	// Hints only apply to fields.
+	assertEqual(t, DefaultDumper.Dump(e.SentAtMs), "(int64)1704164645000")
+
+	remove()
+	assertEqual(t, DefaultDumper.Dump(e.seq), "(int64)1")
+	assertEqual(t, DefaultDumper.Dump(e), "(assertion.event){At:(time.Time)2024-01-02T03:04:05.0000006Z Duration:(time.Duration)1m30s Started:(*time.Time)2024-01-02T03:04:05.0000006Z SentAtMs:(int64)1704164645000 seq:(int64)1}")
+}
+
+func TestDiffTime(t *testing.T) {
+	type record struct {
+		Name    string
+		updated time.Time
+	}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	d := diffValues(record{"foo", at}, record{"foo", at.Add(time.Hour)})
+	assertEqual(t, formatDiff(DefaultMessages, DefaultDumper, d), "Differences:\nDifferent values:\n    .updated:\n        [1] -> (time.Time)2024-01-02T03:04:05Z\n        [2] -> (time.Time)2024-01-02T04:04:05Z\n(1 equal fields not shown)")
+}
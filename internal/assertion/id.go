@@ -0,0 +1,68 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// AssertionID returns a stable ID of an assertion site.
+// The function is the full name of the function calling assertion function
+// and the source is the source code of the call.
+//
+// The ID is a hash of the package, the enclosing top-level function and the source,
+// so that it survives line number changes and reformatting.
+// Closures share the ID space of their enclosing function.
+// Identical assertions in the same function share the same ID.
+func AssertionID(function, source string) string {
+	if source == "" {
+		return ""
+	}
+
+	h := sha256.New()
+	h.Write([]byte(stableFuncName(function)))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.Join(strings.Fields(source), " ")))
+	return hex.EncodeToString(h.Sum(nil)[:6])
+}
+
+// stableFuncName trims closure suffixes like ".func1" or ".func1.2" from the function name,
+// as these suffixes change when closures are added or removed before the function.
+func stableFuncName(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	parts := strings.Split(function[slash+1:], ".")
+
+	for i, part := range parts {
+		if i > 1 && isClosureName(part) {
+			parts = parts[:i]
+			break
+		}
+	}
+
+	return function[:slash+1] + strings.Join(parts, ".")
+}
+
+// isClosureName returns true if name is a compiler-generated closure name like "func1", "gowrap1" or "2".
+func isClosureName(name string) bool {
+	for _, prefix := range []string{"func", "gowrap", "deferwrap"} {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			name = name[len(prefix):]
+			break
+		}
+	}
+
+	if name == "" {
+		return false
+	}
+
+	for _, c := range name {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,69 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStableFuncName(t *testing.T) {
+	cases := []struct {
+		Function string
+		Stable   string
+	}{
+		{"github.com/huandu/go-assert.TestFoo", "github.com/huandu/go-assert.TestFoo"},
+		{"github.com/huandu/go-assert.TestFoo.func1", "github.com/huandu/go-assert.TestFoo"},
+		{"github.com/huandu/go-assert.TestFoo.func1.2", "github.com/huandu/go-assert.TestFoo"},
+		{"github.com/huandu/go-assert.(*A).Run.func1", "github.com/huandu/go-assert.(*A).Run"},
+		{"gopkg.in/yaml.v3.TestFoo.gowrap1", "gopkg.in/yaml.v3.TestFoo"},
+		{"main.func1", "main.func1"},
+	}
+
+	for _, c := range cases {
+		assertEqual(t, stableFuncName(c.Function), c.Stable)
+	}
+}
+
+func TestAssertionID(t *testing.T) {
+	id := AssertionID("pkg.TestFoo", "a.Equal(x, y)")
+	assertEqual(t, len(id), 12)
+	assertEqual(t, AssertionID("pkg.TestFoo.func2", "a.Equal(x,\n\ty)"), id)
+	assertEqual(t, AssertionID("pkg.TestBar", "a.Equal(x, y)") != id, true)
+	assertEqual(t, AssertionID("pkg.TestFoo", "a.Equal(y, x)") != id, true)
+	assertEqual(t, AssertionID("pkg.TestFoo", ""), "")
+
+	var failures []Failure
+	remove := AddFailureHook(func(f *Failure) {
+		failures = append(failures, *f)
+	})
+	ft := NewFakeT("TestAssertionID")
+	assertFalse := func() {
+		Assert(ft, 1 > 2, &Trigger{
+			FuncName: "Assert",
+			Args:     []int{1},
+		})
+	}
+	assertFalse()
+	assertFalse()
+	remove()
+
+	assertEqual(t, len(failures), 2)
+	assertEqual(t, failures[0].ID, failures[1].ID)
+	assertEqual(t, failures[0].ID, AssertionID("github.com/huandu/go-assert/internal/assertion.TestAssertionID", failures[0].Source))
+	assertEqual(t, strings.HasSuffix(ft.Messages()[0], "Assertion ID: "+failures[0].ID), true)
+
+	// Quarantine by ID.
+	ft.Reset()
+	remove = AddQuarantine(QuarantineRule{
+		ID:   failures[0].ID,
+		Tag:  "ISSUE-1",
+		Mode: QuarantineLog,
+	})
+	assertFalse()
+	remove()
+
+	assertEqual(t, ft.Failed(), false)
+	assertEqual(t, len(nonHelperCalls(ft)), 1)
+}
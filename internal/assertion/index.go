@@ -0,0 +1,270 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// IndexEntry is the precomputed parsing result of a call to an assertion function.
+// With registered entries, assertions don't parse source files at runtime.
+type IndexEntry struct {
+	File     string     // Base name of the source file.
+	FuncName string     // Name of the called function without package name or receiver.
+	Line     int        // First line of the call.
+	EndLine  int        // Last line of the call.
+	Source   string     // Source code of the call.
+	Args     []IndexArg // All arguments of the call.
+	LoopVars [][]string // Key and value vars of range loops enclosing the call.
+}
+
+// IndexArg is the precomputed parsing result of an argument.
+type IndexArg struct {
+	Source      string   // Source code of the argument.
+	Assignments []string // Source code of assignments of vars referenced by the argument.
+	RelatedVars []string // Vars referenced by the argument and its assignments.
+}
+
+// DefaultIndexFuncs is the list of assertion functions indexed by default.
+var DefaultIndexFuncs = []string{
+	"Assert", "AssertAll", "Equal", "NotEqual", "AssertEqual", "AssertNotEqual",
+	"NilError", "NonNilError", "DoesNotBlock", "Completes", "Eventually",
+	"EventuallyCtx", "EventuallyNoLeak", "ReceivesCtx", "HeapGrowthUnder", "Type", "Use", "UseStruct", "Cases",
+	"Verify", "VerifyEqual", "AssertRequests",
+	"AssertStatus", "AssertHeader", "AssertBody", "AssertBodyContains",
+	"PortOpen", "DialSucceeds", "ContainsFunc", "All", "AnyMatch", "Satisfies", "That",
+	"ContainsAll", "ContainsAny", "BigEqual", "BigInDelta", "BigInEpsilon",
+	"NoError", "Error", "WithinTimeout", "HasKey", "AllInDelta", "AllInDelta2D",
+	"MeanInDelta", "PercentileUnder", "DurationLess", "DurationBetween", "TookLess", "PointerEqual", "Ordered", "Invariant", "CounterIncreasedBy",
+}
+
+// excludedIndexFuncs are functions whose `&v` args are not treated as assignments.
+// It's the same as the exprs excluded by `A#Use` at runtime.
+var excludedIndexFuncs = map[string]struct{}{
+	"Use":       {},
+	"UseStruct": {},
+}
+
+var (
+	indexLock sync.RWMutex
+	index     = map[string][]IndexEntry{}
+)
+
+// RegisterIndex registers entries of source files in dir.
+// Entries of the same file replace previously registered ones.
+func RegisterIndex(dir string, entries []IndexEntry) {
+	files := map[string][]IndexEntry{}
+
+	for _, entry := range entries {
+		filename := filepath.Join(dir, entry.File)
+		files[filename] = append(files[filename], entry)
+	}
+
+	indexLock.Lock()
+	defer indexLock.Unlock()
+
+	for filename, entries := range files {
+		index[filename] = entries
+	}
+}
+
+// lookupIndex returns the first registered entry of a call to funcName
+// covering line in filename.
+func lookupIndex(filename string, line int, funcName string) *IndexEntry {
+	indexLock.RLock()
+	entries := index[filepath.Clean(filename)]
+	indexLock.RUnlock()
+
+	for i := range entries {
+		entry := &entries[i]
+
+		if entry.FuncName == funcName && entry.Line <= line && line <= entry.EndLine {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// indexedFunc creates a Func from entry.
+// Args are parsed from sources in entry so that they can be inspected as usual.
+func indexedFunc(entry *IndexEntry, filename string, line int, argIndex []int) *Func {
+	f := &Func{
+		FileSet:  token.NewFileSet(),
+		Args:     make([]ast.Expr, 0, len(argIndex)),
+		Filename: filepath.Base(filename),
+		Line:     line,
+		entry:    entry,
+		argIndex: argIndex,
+	}
+
+	for _, idx := range argIndex {
+		arg := entry.arg(idx)
+
+		if arg == nil {
+			f.Args = append(f.Args, nil)
+			continue
+		}
+
+		expr, err := parser.ParseExprFrom(f.FileSet, "", arg.Source, 0)
+
+		if err != nil {
+			expr = nil
+		}
+
+		f.Args = append(f.Args, expr)
+	}
+
+	return f
+}
+
+// arg returns the arg at idx. Negative idx counts from the last arg.
+func (entry *IndexEntry) arg(idx int) *IndexArg {
+	if idx < 0 {
+		idx += len(entry.Args)
+	}
+
+	if idx < 0 || idx >= len(entry.Args) {
+		return nil
+	}
+
+	return &entry.Args[idx]
+}
+
+// indexedInfo creates an Info from the entry of f.
+func indexedInfo(f *Func) *Info {
+	info := &Info{
+		Source:      f.entry.Source,
+		Args:        make([]string, 0, len(f.argIndex)),
+		Assignments: make([][]string, 0, len(f.argIndex)),
+		LoopVars:    f.entry.LoopVars,
+	}
+	relatedVars := map[string]struct{}{}
+
+	for _, idx := range f.argIndex {
+		arg := f.entry.arg(idx)
+
+		if arg == nil {
+			info.Args = append(info.Args, "")
+			info.Assignments = append(info.Assignments, nil)
+			continue
+		}
+
+		info.Args = append(info.Args, arg.Source)
+		info.Assignments = append(info.Assignments, arg.Assignments)
+
+		for _, v := range arg.RelatedVars {
+			relatedVars[v] = struct{}{}
+		}
+	}
+
+	info.RelatedVars = make([]string, 0, len(relatedVars))
+
+	for v := range relatedVars {
+		info.RelatedVars = append(info.RelatedVars, v)
+	}
+
+	sort.Strings(info.RelatedVars)
+	info.ID = AssertionID(f.Function, info.Source)
+	return info
+}
+
+// BuildIndex parses src of filename and returns entries of all calls to funcs.
+// The depth is the max number of hops to follow when finding assignments.
+func BuildIndex(filename string, src []byte, funcs []string, depth int) ([]IndexEntry, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(funcs))
+
+	for _, fn := range funcs {
+		names[fn] = struct{}{}
+	}
+
+	excluded := make([]*ast.CallExpr, 0)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if _, ok := excludedIndexFuncs[callName(call)]; ok {
+				excluded = append(excluded, call)
+			}
+		}
+
+		return true
+	})
+
+	var decl *ast.FuncDecl
+	entries := make([]IndexEntry, 0)
+	base := filepath.Base(filename)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if fd, ok := n.(*ast.FuncDecl); ok {
+			decl = fd
+			return true
+		}
+
+		call, ok := n.(*ast.CallExpr)
+
+		if !ok {
+			return true
+		}
+
+		name := callName(call)
+
+		if _, ok := names[name]; !ok {
+			return true
+		}
+
+		line := fset.Position(call.Pos()).Line
+		entry := IndexEntry{
+			File:     base,
+			FuncName: name,
+			Line:     line,
+			EndLine:  fset.Position(call.End()).Line,
+			Source:   formatNode(fset, call),
+			Args:     make([]IndexArg, 0, len(call.Args)),
+			LoopVars: findLoopVars(decl, call),
+		}
+
+		for _, arg := range call.Args {
+			assignments, related := findAssignments(fset, decl, line, arg, excluded, depth, nil)
+			vars := make([]string, 0, len(related))
+
+			for v := range related {
+				vars = append(vars, v)
+			}
+
+			sort.Strings(vars)
+			entry.Args = append(entry.Args, IndexArg{
+				Source:      formatNode(fset, arg),
+				Assignments: assignments,
+				RelatedVars: vars,
+			})
+		}
+
+		entries = append(entries, entry)
+		return true
+	})
+
+	return entries, nil
+}
+
+// callName returns the name of the function called by call without package name or receiver.
+func callName(call *ast.CallExpr) string {
+	switch expr := funcExpr(call.Fun).(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	}
+
+	return ""
+}
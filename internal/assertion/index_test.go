@@ -0,0 +1,57 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	_, filename, _, _ := runtime.Caller(0)
+	src, err := os.ReadFile(filename)
+
+	if err != nil {
+		t.Fatalf("fail to read source. [err:%v]", err)
+	}
+
+	entries, err := BuildIndex(filename, src, []string{"indexedAssert"}, 1)
+
+	if err != nil {
+		t.Fatalf("fail to build index. [err:%v]", err)
+	}
+
+	assertEqual(t, len(entries), 2)
+
+	x := 1
+	y := x + 1
+	f, info := indexedAssert(x, y)
+	assertEqual(t, f.entry == nil, true)
+	assertEqual(t, info.Assignments, [][]string{{"y := x + 1"}, {"x := 1"}})
+
+	// Replace source to make sure the index is used.
+	for i := range entries {
+		entries[i].Source = "indexed"
+	}
+
+	RegisterIndex(filepath.Dir(filename), entries)
+	defer RegisterIndex(filepath.Dir(filename), []IndexEntry{{File: entries[0].File}})
+
+	f, info = indexedAssert(x, y)
+	assertEqual(t, f.entry != nil, true)
+	assertEqual(t, f.Filename, filepath.Base(filename))
+	assertEqual(t, info.Source, "indexed")
+	assertEqual(t, info.Args, []string{"y", "x"})
+	assertEqual(t, info.Assignments, [][]string{{"y := x + 1"}, {"x := 1"}})
+	assertEqual(t, info.RelatedVars, []string{"x"})
+	assertEqual(t, len(f.Args), 2)
+}
+
+func indexedAssert(v1, v2 interface{}) (*Func, *Info) {
+	p := &Parser{}
+	f, _ := p.ParseArgs("indexedAssert", 1, []int{1, 0})
+	return f, p.ParseInfo(f)
+}
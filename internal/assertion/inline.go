@@ -0,0 +1,225 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/token"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxInlinedLines is the max number of lines of return statements inlined from a helper.
+// Larger helpers are not inlined.
+const maxInlinedLines = 10
+
+var inlineHelpers int32
+
+// SetInlineHelpers enables or disables inlining return statements of helpers in assignments.
+// If it's enabled, for an assignment like `v := makeFixture()`,
+// return statements of `makeFixture` are printed under the assignment
+// if makeFixture is a func defined in the same package.
+// Only the helper called in the assignment is inlined. Helpers called by the helper are not.
+func SetInlineHelpers(enabled bool) {
+	var v int32
+
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&inlineHelpers, v)
+}
+
+// InlineHelpersEnabled returns true if return statements of helpers are inlined in assignments.
+func InlineHelpersEnabled() bool {
+	return atomic.LoadInt32(&inlineHelpers) != 0
+}
+
+// helperFinder finds helpers defined in the same package as the file calling assertions.
+type helperFinder struct {
+	p    *Parser
+	fset *token.FileSet
+	file *ast.File
+	path string
+}
+
+// helpers returns a helperFinder for f.
+// It returns nil if inlining is disabled or the source of f is not parsed.
+func (p *Parser) helpers(f *Func) *helperFinder {
+	if f.file == nil || !InlineHelpersEnabled() {
+		return nil
+	}
+
+	return &helperFinder{
+		p:    p,
+		fset: f.FileSet,
+		file: f.file,
+		path: f.path,
+	}
+}
+
+// inline returns return statements of the helper called in stmt as indented lines.
+// It returns an empty string if stmt doesn't assign the result of a small helper.
+func (h *helperFinder) inline(stmt ast.Stmt) string {
+	if h == nil {
+		return ""
+	}
+
+	assign, ok := stmt.(*ast.AssignStmt)
+
+	if !ok || len(assign.Rhs) != 1 {
+		return ""
+	}
+
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+
+	if !ok {
+		return ""
+	}
+
+	ident, ok := funcExpr(call.Fun).(*ast.Ident)
+
+	if !ok {
+		return ""
+	}
+
+	fset, decl := h.find(ident.Name)
+
+	if decl == nil || decl.Body == nil {
+		return ""
+	}
+
+	lines := []string{"// " + fmt.Sprintf(CurrentMessages().HelperReturnsFormat, ident.Name)}
+
+	for _, ret := range findReturns(decl) {
+		lines = append(lines, strings.Split(formatNode(fset, ret), "\n")...)
+	}
+
+	if len(lines) == 1 || len(lines) > maxInlinedLines+1 {
+		return ""
+	}
+
+	return "\n    " + strings.Join(lines, "\n    ")
+}
+
+// find returns the func decl of name in the same package.
+// The file calling assertions is searched first.
+// Other files in the same directory are searched only if source is read from file system.
+func (h *helperFinder) find(name string) (fset *token.FileSet, decl *ast.FuncDecl) {
+	if decl = findFuncDecl(h.file, name); decl != nil {
+		return h.fset, decl
+	}
+
+	if h.p.Source != nil || h.path == "" {
+		return
+	}
+
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(h.path), "*.go"))
+
+	if err != nil {
+		return
+	}
+
+	for _, filename := range files {
+		if filename == h.path {
+			continue
+		}
+
+		// Files excluded by build constraints may define helpers with the same name,
+		// e.g. in foo_linux_test.go and foo_windows_test.go.
+		if !matchBuildContext(filename) {
+			continue
+		}
+
+		fs, file, err := parseFile(filename)
+
+		if err != nil || file.Name.Name != h.file.Name.Name {
+			continue
+		}
+
+		if decl = findFuncDecl(file, name); decl != nil {
+			return fs, decl
+		}
+	}
+
+	return
+}
+
+// findFuncDecl returns the func named name in file. Methods are ignored.
+func findFuncDecl(file *ast.File, name string) *ast.FuncDecl {
+	for _, d := range file.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == name {
+			return fd
+		}
+	}
+
+	return nil
+}
+
+// findReturns returns return statements with results in decl.
+// Return statements in func literals are ignored.
+func findReturns(decl *ast.FuncDecl) (returns []*ast.ReturnStmt) {
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) > 0 {
+				returns = append(returns, node)
+			}
+
+			return false
+		}
+
+		return true
+	})
+	return
+}
+
+var (
+	buildContextOnce sync.Once
+	buildContext     build.Context
+)
+
+// currentBuildContext returns the build context of current binary.
+// Build tags, GOOS and GOARCH are read from build info if available.
+func currentBuildContext() *build.Context {
+	buildContextOnce.Do(func() {
+		buildContext = build.Default
+
+		info, ok := debug.ReadBuildInfo()
+
+		if !ok {
+			return
+		}
+
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "-tags":
+				if setting.Value != "" {
+					buildContext.BuildTags = strings.Split(setting.Value, ",")
+				}
+			case "GOOS":
+				buildContext.GOOS = setting.Value
+			case "GOARCH":
+				buildContext.GOARCH = setting.Value
+			}
+		}
+	})
+
+	return &buildContext
+}
+
+// matchBuildContext returns true if file at filename is built in current build context.
+// It checks file name suffixes like "_linux_test.go" and build constraints like `//go:build linux`.
+// Files with unreadable constraints are treated as matched.
+func matchBuildContext(filename string) bool {
+	ok, err := currentBuildContext().MatchFile(filepath.Dir(filename), filepath.Base(filename))
+	return ok || err != nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func makeInlineFixture() []string {
+	return []string{"foo", "bar"}
+}
+
+func TestInlineHelpers(t *testing.T) {
+	p := new(Parser)
+	parse := func(args ...interface{}) (*Func, error) {
+		return p.ParseArgs("parse", 1, []int{0})
+	}
+
+	fixture := makeInlineFixture()
+	f, err := parse(fixture)
+	assertEqual(t, err, nil)
+	assertEqual(t, p.ParseInfo(f).Assignments[0], []string{`fixture := makeInlineFixture()`})
+
+	SetInlineHelpers(true)
+	defer SetInlineHelpers(false)
+
+	f, err = parse(fixture)
+	assertEqual(t, err, nil)
+	assertEqual(t, p.ParseInfo(f).Assignments[0], []string{
+		"fixture := makeInlineFixture()\n    // makeInlineFixture returns:\n    return []string{\"foo\", \"bar\"}",
+	})
+
+	// Helpers in other files of the same package are inlined.
+	integer := isInteger(reflect.Int)
+	f, err = parse(integer)
+	assertEqual(t, err, nil)
+	assertEqual(t, p.ParseInfo(f).Assignments[0], []string{
+		"integer := isInteger(reflect.Int)\n    // isInteger returns:\n    return true\n    return false",
+	})
+
+	// Methods and large helpers are not inlined.
+	ft := NewFakeT("TestInlineHelpers")
+	name := ft.Name()
+	f, err = parse(name)
+	assertEqual(t, err, nil)
+	assertEqual(t, p.ParseInfo(f).Assignments[0], []string{`name := ft.Name()`})
+
+	dump := getValueInterface(reflect.ValueOf(fixture))
+	f, err = parse(dump)
+	assertEqual(t, err, nil)
+	assertEqual(t, p.ParseInfo(f).Assignments[0], []string{`dump := getValueInterface(reflect.ValueOf(fixture))`})
+}
+
+func TestInlineHelpersBuildConstraints(t *testing.T) {
+	otherOS := "aix"
+
+	if runtime.GOOS == otherOS {
+		otherOS = "linux"
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"fixture_test.go":                 "package fixture\n\nfunc TestFixture() { v := makeFixture() }\n",
+		"fixture_a.go":                    "//go:build ignore\n\npackage fixture\n\nfunc makeFixture() string { return \"ignored\" }\n",
+		"fixture_" + otherOS + ".go":      "package fixture\n\nfunc makeFixture() string { return \"" + otherOS + "\" }\n",
+		"fixture_" + runtime.GOOS + ".go": "package fixture\n\nfunc makeFixture() string { return \"" + runtime.GOOS + "\" }\n",
+	}
+
+	for name, src := range files {
+		assertEqual(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0644), nil)
+	}
+
+	path := filepath.Join(dir, "fixture_test.go")
+	fset, file, err := parseFile(path)
+	assertEqual(t, err, nil)
+
+	h := &helperFinder{
+		p:    new(Parser),
+		fset: fset,
+		file: file,
+		path: path,
+	}
+	body := file.Decls[0].(*ast.FuncDecl).Body
+	assertEqual(t, h.inline(body.List[0]), "\n    // makeFixture returns:\n    return \""+runtime.GOOS+"\"")
+}
@@ -0,0 +1,172 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strings"
+)
+
+// AssertInvariant expects the invariant named name holds for v, i.e. pred returns true for v.
+// The pred must be a `func(v V) bool` where v is assignable to V.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+//
+// If pred is a func literal, only fields of v referenced by pred are dumped,
+// e.g. `x.Start` and `x.End` in `func(x Span) bool { return x.Start.Before(x.End) }`.
+// Otherwise, v is dumped as a whole.
+func AssertInvariant(t T, v interface{}, name string, pred interface{}, trigger *Trigger) {
+	t.Helper()
+	val := reflect.ValueOf(v)
+	p, err := predicate(pred, reflect.TypeOf(v))
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	// A nil v is passed to pred as a nil V.
+	if !val.IsValid() {
+		val = reflect.Zero(p.Type().In(0))
+	}
+
+	if callPredicate(p, val) {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	dumper := trigger.dumper()
+	predAssignments := info.Assignments[1]
+	var fields []string
+	var values []string
+
+	if lit, ok := f.Args[1].(*ast.FuncLit); ok {
+		// Vars in a func literal are its own params and locals, not assigned outside.
+		predAssignments = nil
+		param, paths := referencedFields(lit)
+		seen := map[string]struct{}{}
+
+		for _, path := range paths {
+			field, selected := selectField(val, path)
+			expr := strings.Join(append([]string{param}, selected...), ".")
+
+			if _, ok := seen[expr]; ok {
+				continue
+			}
+
+			seen[expr] = struct{}{}
+			fieldDump := dumpValue(dumper, field)
+			fields = append(fields, fmt.Sprintf("    %v = %v", expr, fieldDump))
+			values = append(values, fieldDump)
+		}
+	}
+
+	var section string
+
+	if len(fields) != 0 {
+		section = msgs.ReferencedFields + "\n" + strings.Join(fields, "\n")
+	} else {
+		vDump := dumper.Dump(v)
+		section = msgs.Value + "\n    " + vDump
+		values = []string{vDump}
+	}
+
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n    %v%v\n%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, fmt.Sprintf(msgs.ShouldHoldFormat, name),
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.Predicate,
+			indentCode(info.Args[1], 4), indentAssignments(predAssignments, 4),
+			section, formatVars(msgs, info, trigger),
+		),
+		Values: values,
+	})
+}
+
+// referencedFields returns the name of the only param of lit
+// and selector paths on the param in lit in order of appearance, e.g. ["Start", "Before"] for `x.Start.Before(now)`.
+// Paths may contain method names, which are trimmed by selectField.
+func referencedFields(lit *ast.FuncLit) (param string, paths [][]string) {
+	params := lit.Type.Params.List
+
+	if len(params) != 1 || len(params[0].Names) != 1 || params[0].Names[0].Name == "_" {
+		return
+	}
+
+	param = params[0].Names[0].Name
+	ast.Inspect(lit.Body, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+
+		if !ok {
+			return true
+		}
+
+		var path []string
+		var expr ast.Expr = sel
+
+		for {
+			if s, ok := expr.(*ast.SelectorExpr); ok {
+				path = append([]string{s.Sel.Name}, path...)
+				expr = s.X
+				continue
+			}
+
+			break
+		}
+
+		// Only selectors on the param are fields. Others may contain selectors on the param in args.
+		if ident, ok := expr.(*ast.Ident); !ok || ident.Name != param {
+			return true
+		}
+
+		paths = append(paths, path)
+		return false
+	})
+	return
+}
+
+// selectField selects the field at path in v.
+// Pointers and interfaces are followed implicitly.
+// It stops at the first name which is not a field, e.g. a method, or at a nil pointer,
+// and returns the selected field and names in path leading to it.
+func selectField(v reflect.Value, path []string) (field reflect.Value, selected []string) {
+	field = v
+
+	for _, name := range path {
+		st := field
+
+		for (st.Kind() == reflect.Ptr || st.Kind() == reflect.Interface) && !st.IsNil() {
+			st = st.Elem()
+		}
+
+		if st.Kind() != reflect.Struct {
+			return
+		}
+
+		f := st.FieldByName(name)
+
+		if !f.IsValid() {
+			return
+		}
+
+		field = f
+		selected = append(selected, name)
+	}
+
+	return
+}
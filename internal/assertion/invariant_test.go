@@ -0,0 +1,58 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type invariantSpan struct {
+	Name   string
+	Start  time.Time
+	End    time.Time
+	Parent *invariantSpan
+	tags   []string
+}
+
+func (s invariantSpan) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+func TestAssertInvariant(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	span := invariantSpan{Name: "query", Start: at, End: at.Add(-time.Second), tags: []string{"db"}}
+	ft := NewFakeT("TestAssertInvariant")
+	trigger := &Trigger{
+		FuncName: "AssertInvariant",
+		Args:     []int{1, 3},
+		Options:  Options{NonFatal: true},
+	}
+
+	AssertInvariant(ft, span, "named", func(x invariantSpan) bool { return x.Name != "" }, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertInvariant(ft, span, "Start before End", func(x invariantSpan) bool { return x.Start.Before(x.End) && x.Duration() > 0 }, trigger)
+	AssertInvariant(ft, &span, "has parent", func(s *invariantSpan) bool { return s.Parent != nil && s.Parent.Name != "" && len(s.tags) > 1 }, trigger)
+
+	notEmpty := func(s invariantSpan) bool { return s.Name == "" }
+	AssertInvariant(ft, span, "unnamed", notEmpty, trigger)
+
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 3)
+
+	msg := stripColors(msgs[0])
+	assertEqual(t, strings.Contains(msg, `Invariant "Start before End" should hold for following expression.`), true)
+	assertEqual(t, strings.Contains(msg, "Referenced fields:\n    x.Start = (time.Time)2024-01-02T03:04:05Z\n    x.End = (time.Time)2024-01-02T03:04:04Z\n    x = "), true)
+	assertEqual(t, strings.Contains(msg, "x.Name ="), false)
+
+	msg = stripColors(msgs[1])
+	assertEqual(t, strings.Contains(msg, "    s.Parent = (*assertion.invariantSpan)<nil>\n    s.tags = ([]string)"), true)
+	assertEqual(t, strings.Contains(msg, "s.Parent.Name ="), false)
+
+	msg = stripColors(msgs[2])
+	assertEqual(t, strings.Contains(msg, "Referenced fields:"), false)
+	assertEqual(t, strings.Contains(msg, "Value:\n    (assertion.invariantSpan)"), true)
+}
@@ -0,0 +1,244 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var errNilMatcher = errors.New("go-assert: matcher must not be nil")
+
+// Matcher matches a value and explains the result.
+type Matcher interface {
+	// Match tests v. The d formats values in the description of the result.
+	Match(v interface{}, d Dumper) MatchResult
+}
+
+// MatchResult is the result of a Matcher.
+// Results of combinators contain results of nested matchers,
+// which are printed as an indented tree in failure message.
+type MatchResult struct {
+	OK          bool          // True if the value matches.
+	Description string        // Description of the expectation, e.g. "equal to (int)1".
+	Children    []MatchResult // Results of nested matchers.
+}
+
+// MatcherFunc is a func implementing Matcher.
+type MatcherFunc func(v interface{}, d Dumper) MatchResult
+
+// Match calls f(v, d).
+func (f MatcherFunc) Match(v interface{}, d Dumper) MatchResult {
+	return f(v, d)
+}
+
+// MatchNot matches a value which doesn't match m.
+func MatchNot(m Matcher) Matcher {
+	return MatcherFunc(func(v interface{}, d Dumper) MatchResult {
+		result := matchValue(m, v, d)
+		return MatchResult{
+			OK:          !result.OK,
+			Description: CurrentMessages().MatchNot,
+			Children:    []MatchResult{result},
+		}
+	})
+}
+
+// MatchAnyOf matches a value matching at least one of matchers.
+// All matchers are called so that all results are explained.
+func MatchAnyOf(matchers ...Matcher) Matcher {
+	return MatcherFunc(func(v interface{}, d Dumper) MatchResult {
+		return matchGroup(CurrentMessages().MatchAnyOf, matchers, v, d, false)
+	})
+}
+
+// MatchAllOf matches a value matching all of matchers.
+// All matchers are called so that all results are explained.
+func MatchAllOf(matchers ...Matcher) Matcher {
+	return MatcherFunc(func(v interface{}, d Dumper) MatchResult {
+		return matchGroup(CurrentMessages().MatchAllOf, matchers, v, d, true)
+	})
+}
+
+func matchGroup(desc string, matchers []Matcher, v interface{}, d Dumper, all bool) MatchResult {
+	result := MatchResult{
+		OK:          all,
+		Description: desc,
+		Children:    make([]MatchResult, 0, len(matchers)),
+	}
+
+	for _, m := range matchers {
+		r := matchValue(m, v, d)
+		result.Children = append(result.Children, r)
+
+		if all {
+			result.OK = result.OK && r.OK
+		} else {
+			result.OK = result.OK || r.OK
+		}
+	}
+
+	return result
+}
+
+// MatchEqualTo matches a value deeply equal to expected.
+func MatchEqualTo(expected interface{}) Matcher {
+	return MatcherFunc(func(v interface{}, d Dumper) MatchResult {
+		return MatchResult{
+			OK:          deepEqual(v, expected),
+			Description: fmt.Sprintf(CurrentMessages().MatchEqualToFormat, d.Dump(expected)),
+		}
+	})
+}
+
+// MatchInSlice matches a value deeply equal to any element of slice.
+// The slice can be a slice or an array.
+// If slice is neither a slice nor an array, nothing matches.
+func MatchInSlice(slice interface{}) Matcher {
+	return MatcherFunc(func(v interface{}, d Dumper) MatchResult {
+		result := MatchResult{
+			Description: fmt.Sprintf(CurrentMessages().MatchInSliceFormat, d.Dump(slice)),
+		}
+		s := reflect.ValueOf(slice)
+
+		if s.Kind() != reflect.Slice && s.Kind() != reflect.Array {
+			return result
+		}
+
+		for i := 0; i < s.Len(); i++ {
+			if deepEqual(v, s.Index(i).Interface()) {
+				result.OK = true
+				break
+			}
+		}
+
+		return result
+	})
+}
+
+// MatchPredicate matches a value for which pred returns true.
+// The desc describes the expectation in failure message.
+func MatchPredicate(desc string, pred func(v interface{}) bool) Matcher {
+	return MatcherFunc(func(v interface{}, d Dumper) MatchResult {
+		return MatchResult{
+			OK:          pred(v),
+			Description: desc,
+		}
+	})
+}
+
+// GomegaMatcher is the interface of matchers in github.com/onsi/gomega, i.e. `types.GomegaMatcher`.
+type GomegaMatcher interface {
+	Match(actual interface{}) (success bool, err error)
+	FailureMessage(actual interface{}) (message string)
+	NegatedFailureMessage(actual interface{}) (message string)
+}
+
+// MatchGomega matches a value matching the Gomega matcher m.
+// The description is the failure message of m if the value doesn't match,
+// or the negated failure message if it matches, which explains why a Not matcher fails.
+// If m returns an error, the value doesn't match and the error is the description.
+func MatchGomega(m GomegaMatcher) Matcher {
+	return MatcherFunc(func(v interface{}, d Dumper) MatchResult {
+		if m == nil {
+			return MatchResult{
+				Description: errNilMatcher.Error(),
+			}
+		}
+
+		ok, err := m.Match(v)
+
+		if err != nil {
+			return MatchResult{
+				Description: fmt.Sprintf(CurrentMessages().MatchGomegaErrorFormat, err),
+			}
+		}
+
+		if ok {
+			return MatchResult{
+				OK:          true,
+				Description: m.NegatedFailureMessage(v),
+			}
+		}
+
+		return MatchResult{
+			Description: m.FailureMessage(v),
+		}
+	})
+}
+
+// matchValue calls m.Match. A nil m never matches.
+func matchValue(m Matcher, v interface{}, d Dumper) MatchResult {
+	if m == nil {
+		return MatchResult{
+			Description: errNilMatcher.Error(),
+		}
+	}
+
+	return m.Match(v, d)
+}
+
+// AssertThat expects v matches m.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the explanation of m.
+func AssertThat(t T, v interface{}, m Matcher, trigger *Trigger) {
+	t.Helper()
+	if m == nil {
+		failInternal(t, trigger, errNilMatcher)
+		return
+	}
+
+	dumper := trigger.dumper()
+	result := m.Match(v, dumper)
+
+	if result.OK {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	vDump := dumper.Dump(v)
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, msgs.ShouldMatch,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.Explanation, formatMatchResult(result, 4),
+			msgs.Value, vDump, formatVars(msgs, info, trigger),
+		),
+		Values: []string{vDump},
+	})
+}
+
+// formatMatchResult formats result and its children as an indented tree.
+func formatMatchResult(result MatchResult, indent int) string {
+	lines := make([]string, 0, 1+len(result.Children))
+	mark := "✗"
+
+	if result.OK {
+		mark = "✓"
+	}
+
+	space := strings.Repeat(" ", indent)
+	desc := strings.Replace(result.Description, "\n", "\n"+space+"  ", -1)
+	lines = append(lines, space+mark+" "+desc)
+
+	for _, child := range result.Children {
+		lines = append(lines, formatMatchResult(child, indent+4))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,70 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchers(t *testing.T) {
+	blocked := []string{"root", "admin"}
+	cases := []struct {
+		Matcher Matcher
+		Value   interface{}
+		OK      bool
+	}{
+		{MatchEqualTo(1), 1, true},
+		{MatchEqualTo([]int{1}), []int{1}, true},
+		{MatchEqualTo(1), int64(1), false},
+		{MatchInSlice(blocked), "root", true},
+		{MatchInSlice(blocked), "guest", false},
+		{MatchInSlice(123), 123, false},
+		{MatchNot(MatchInSlice(blocked)), "guest", true},
+		{MatchNot(nil), "guest", true},
+		{MatchAnyOf(), 1, false},
+		{MatchAnyOf(MatchEqualTo(1), MatchEqualTo(2)), 2, true},
+		{MatchAllOf(), 1, true},
+		{MatchAllOf(MatchEqualTo(1), MatchNot(MatchEqualTo(2))), 1, true},
+		{MatchAllOf(MatchEqualTo(1), MatchEqualTo(2)), 1, false},
+		{MatchPredicate("positive", func(v interface{}) bool { return v.(int) > 0 }), 1, true},
+	}
+
+	for i, c := range cases {
+		result := c.Matcher.Match(c.Value, DefaultDumper)
+
+		if result.OK != c.OK {
+			t.Fatalf("case %v: result should be %v.", i, c.OK)
+		}
+	}
+}
+
+func TestAssertThat(t *testing.T) {
+	ft := NewFakeT("TestAssertThat")
+	trigger := &Trigger{
+		FuncName: "AssertThat",
+		Args:     []int{1},
+	}
+	blocked := []string{"root", "admin"}
+	name := "admin"
+
+	AssertThat(ft, "guest", MatchNot(MatchInSlice(blocked)), trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertThat(ft, name, MatchAnyOf(MatchEqualTo("guest"), MatchNot(MatchInSlice(blocked))), trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldMatch+"\n    name\n    name := \"admin\""), true)
+	assertEqual(t, strings.Contains(msgs[0], `Explanation:
+    ✗ any of
+        ✗ equal to (string)guest
+        ✗ not
+            ✓ in ([]string)[root admin]
+Value:
+    (string)admin`), true)
+
+	ft.Reset()
+	AssertThat(ft, name, nil, trigger)
+	assertEqual(t, strings.Contains(ft.Messages()[0], errNilMatcher.Error()), true)
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// AssertHeapGrowthUnder expects heap grows no more than maxBytes after calling fn.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+//
+// The heap growth is the difference of live heap bytes measured after a forced GC
+// before and after calling fn.
+// Memory allocated by fn and released before it returns doesn't count.
+func AssertHeapGrowthUnder(t T, maxBytes uint64, fn func(), trigger *Trigger) {
+	t.Helper()
+	before := liveHeapBytes()
+	fn()
+	after := liveHeapBytes()
+
+	if after <= before || after-before <= maxBytes {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	heap := fmt.Sprintf(msgs.HeapStatsFormat, before, after, after-before)
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed,
+			fmt.Sprintf(msgs.ShouldGrowHeapUnderFormat, maxBytes),
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.Heap, indentCode(heap, 4),
+			formatVars(msgs, info, trigger),
+		),
+		Values: []string{heap},
+	})
+}
+
+// liveHeapBytes runs a GC and returns bytes of allocated heap objects.
+func liveHeapBytes() uint64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
@@ -0,0 +1,390 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Messages is a table of templates used to format assertion failures.
+// Every field is a piece of text printed in failure messages.
+// Fields ending with "Format" are formatted by `fmt.Sprintf` with documented arguments.
+//
+// Any empty field in Messages falls back to the default template.
+type Messages struct {
+	AssertionFailed     string // Header of every failure message.
+	AssertionIDFormat   string // Printed at the end of a failure message. Args: the stable ID of the assertion site.
+	GroupFormat         string // Printed before a failure message in a group. Args: label, file name, line number.
+	InternalErrorFormat string // Printed when assertion source cannot be parsed. Args: the error.
+	Assignments         string // Title of the assignment statements section.
+	HelperReturnsFormat string // Printed before return statements of a helper inlined in an assignment. Args: the helper name.
+	RelatedVars         string // Title of the related variables section.
+	Iteration           string // Title of the loop iteration section.
+	Case                string // Title of the test case section.
+	FuzzInputs          string // Title of the section of inputs generated by fuzzing.
+	Values              string // Title of the value dumps section.
+	SubExprs            string // Title of the section of sub-expression values in Assert.
+	DumpTruncatedFormat string // Appended to a truncated dump. Args: number of truncated bytes.
+	ShouldEqual         string // Printed when Equal fails.
+	ShouldBeSameType    string // Printed when Equal fails due to type mismatch.
+	ErrorChainNote      string // Printed when Equal fails on errors but one error wraps the other.
+	ErrorTextNote       string // Printed when Equal fails on different errors with the same message.
+	PointerTypeFormat   string // Printed when Equal fails on a pointer and a value of the pointed type. Args: index of the pointer and the value.
+	ShouldNotEqual      string // Printed when NotEqual fails.
+	Differences         string // Title of the differences section replacing value dumps.
+	DiffOnlyIn1         string // Title of the entries only in [1] in differences section.
+	DiffOnlyIn2         string // Title of the entries only in [2] in differences section.
+	DiffChanged         string // Title of the entries with different values in differences section.
+	BytesLengthFormat   string // Printed when lengths of large byte slices differ. Args: length of [1] and [2].
+	BytesRangeFormat    string // Title of a range of differing bytes. Args: offset and number of bytes.
+	BytesDumpFormat     string // Summary of a large byte slice replacing its dump. Args: length.
+	RuneDiffFormat      string // Printed when normalized strings differ. Args: index of the first differing rune.
+	EndOfString         string // Printed when a normalized string ends before the differing rune.
+	DiffMoreFormat      string // Printed when there are too many differences. Args: number of elided entries.
+	DiffEqualFormat     string // Printed when equal struct fields are not shown. Args: number of equal fields.
+	ShouldBeNilError    string // Printed when NilError fails.
+	ShouldBeNonNilError string // Printed when NonNilError fails.
+	ShouldBeNoError     string // Printed when NoError fails.
+	ShouldBeError       string // Printed when Error fails.
+	ErrorIs             string // Title of the error section in NilError and NoError.
+	ShouldBeTypeFormat  string // Printed when Type fails. Args: expected type and actual type.
+	Value               string // Title of the value dump section.
+	Notes               string // Title of the notes section.
+	TypedNilFormat      string // Printed when a var is an interface holding a typed nil. Args: var name and type.
+	TypedNilErrorFormat string // Printed when the error is an interface holding a typed nil. Args: type.
+	FuncMemberFormat    string // Printed when only func members differ. Args: member path.
+	ChanMemberFormat    string // Printed when only chan members differ. Args: member path.
+
+	ConditionsFalseFormat string // Printed when AssertAll fails. Args: number of false conditions and all conditions.
+
+	ShouldNotBlockFormat string // Printed when DoesNotBlock fails. Args: the grace duration.
+	GoroutineStack       string // Title of the goroutine stack section.
+	ShouldCompleteFormat string // Printed when Completes fails. Args: the timeout.
+	GoroutineStacks      string // Title of the section of all goroutine stacks.
+	ShouldFinishFormat   string // Printed when WithinTimeout fails. Args: the timeout.
+	ShouldReceive        string // Printed when ReceivesCtx fails.
+	ChannelClosed        string // Printed when ReceivesCtx fails as the channel is closed.
+	ContextError         string // Title of the section of the context error.
+
+	ShouldBeSatisfiedFormat     string // Printed when Eventually fails. Args: the timeout.
+	ShouldBeSatisfiedBeforeDone string // Printed when EventuallyCtx fails.
+	AttemptsFormat              string // Printed when Eventually fails. Args: number of attempts.
+	LastValue                   string // Title of the section of the last value observed by Eventually.
+	LastError                   string // Title of the section of the last error observed by Eventually.
+
+	ShouldNotLeak          string // Printed when EventuallyNoLeak fails as goroutines leak.
+	LeakedGoroutinesFormat string // Title of the section of leaked goroutines. Args: number of leaked goroutines.
+
+	ShouldGrowHeapUnderFormat string // Printed when HeapGrowthUnder fails. Args: max bytes.
+	Heap                      string // Title of the heap stats section.
+	HeapStatsFormat           string // Heap stats. Args: bytes before, bytes after and growth.
+
+	ShouldBeShorterFormat string // Printed when DurationLess fails. Args: the limit.
+	ShouldBeBetweenFormat string // Printed when DurationBetween fails. Args: the lower and upper bound.
+	ShouldTakeLessFormat  string // Printed when TookLess fails. Args: the limit.
+	Duration              string // Title of the duration section.
+	DurationOverFormat    string // Printed when a duration is too long. Args: the duration and how much it exceeds the limit.
+	DurationUnderFormat   string // Printed when a duration is too short. Args: the duration and how much it's below the lower bound.
+
+	GoroutinePanicked string // Printed when a goroutine guarded by Guard panics.
+	PanicStack        string // Title of the section of the stack of a panicking goroutine.
+
+	WatchdogFormat     string // Printed when a test case doesn't complete before its watchdog fires. Args: the test name and the timeout.
+	RecentAssertions   string // Title of the section of recently executed assertion sites.
+	NoRecentAssertions string // Printed when no assertion has been executed.
+
+	ShouldSatisfy          string // Printed when Satisfies fails.
+	Predicate              string // Title of the predicate section.
+	ShouldMatch            string // Printed when That fails.
+	Explanation            string // Title of the section explaining results of matchers.
+	MatchNot               string // Description of the Not matcher.
+	MatchAnyOf             string // Description of the AnyOf matcher.
+	MatchAllOf             string // Description of the AllOf matcher.
+	MatchEqualToFormat     string // Description of the EqualTo matcher. Args: the expected value.
+	MatchInSliceFormat     string // Description of the InSlice matcher. Args: the slice.
+	MatchGomegaErrorFormat string // Description of a Gomega matcher returning an error. Args: the error.
+	ShouldContainAll       string // Printed when ContainsAll fails.
+	ShouldContainAny       string // Printed when ContainsAny fails.
+	Substrings             string // Title of the substrings section.
+	SubstringFoundFormat   string // Printed for a found substring. Args: the substring and its index.
+	SubstringMissingFormat string // Printed for a missing substring. Args: the substring.
+	ShouldAllMatch         string // Printed when All fails.
+	ShouldAnyMatch         string // Printed when AnyMatch or ContainsFunc fails.
+	FailedIndicesFormat    string // Title of the section of indices failing the predicate. Args: number of failed elements and length.
+	FailedElements         string // Title of the section of elements failing the predicate.
+
+	ShouldBeInDeltaFormat   string // Printed when BigInDelta fails. Args: the delta.
+	ShouldBeInEpsilonFormat string // Printed when BigInEpsilon fails. Args: the epsilon.
+	Difference              string // Title of the section of the difference of numbers.
+
+	ShouldAllBeInDeltaFormat string // Printed when AllInDelta or AllInDelta2D fails. Args: the delta.
+	ElementsLengthFormat     string // Printed when lengths of numbers differ. Args: index of the row or empty, length of [1] and [2].
+	DeviationsFormat         string // Title of the section of elements not within the delta. Args: number of such elements and compared elements.
+	DeviationFormat          string // Printed for an element not within the delta. Args: index, element of [1] and [2], and the difference.
+
+	ShouldHaveMeanFormat       string // Printed when MeanInDelta fails. Args: the delta and the expected mean.
+	ShouldHavePercentileFormat string // Printed when PercentileUnder fails. Args: the percentile in percent and the limit.
+	PercentileFormat           string // Printed after ShouldHavePercentileFormat. Args: the actual percentile.
+	NoSamples                  string // Printed when there is no sample.
+	Summary                    string // Title of the section of the summary of samples.
+	SamplesSummaryFormat       string // Summary of samples. Args: count, min, max, mean, p50 and p99.
+
+	ShouldIssueRequests string // Printed when httpreplay.Recorder.AssertRequests fails.
+	ShouldDialFormat    string // Printed when PortOpen or DialSucceeds fails. Args: the network.
+
+	ShouldHaveStatusFormat string // Printed when HTTPResponse.AssertStatus fails. Args: expected and actual status code.
+	ShouldHaveHeaderFormat string // Printed when HTTPResponse.AssertHeader fails. Args: header key, expected and actual value.
+	ShouldContainFormat    string // Printed when HTTPResponse.AssertBodyContains fails. Args: expected substring.
+	ShouldHaveBody         string // Printed when HTTPResponse.AssertBody fails.
+	LastExchange           string // Title of the section of the last request and response.
+
+	Profiles           string // Title of the section of profiles written on failure.
+	ProfileErrorFormat string // Printed when a profile cannot be written. Args: the error.
+
+	QuarantinedFormat string // Printed when a failure is quarantined. Args: the tracking tag.
+
+	OutputElidedFormat string // Replaces the middle of a failure message longer than max output. Args: number of elided bytes.
+	OutputBudgetFormat string // Printed when failure output of a test exceeds max test output. Args: max test output.
+
+	RepeatedFailuresFormat string // Printed after a test if failures at a call site exceed max repeated failures. Args: number of suppressed failures and the call site.
+
+	ArtifactsDirFormat string // Printed in failure messages of a test with an artifacts directory. Args: the directory.
+
+	DiffUnexported string // Label of a difference in unexported struct fields.
+
+	StringDiffFormat string // Printed when short strings differ. Args: index of the first differing rune.
+
+	ShouldPointerEqual string // Printed when PointerEqual fails.
+
+	ShouldBeOrdered  string // Printed when Ordered fails.
+	OrderingKey      string // Title of the ordering key section.
+	OutOfOrderFormat string // Title of the first out-of-order pair of elements. Args: indices of the elements.
+	KeyArrow         string // Prefix of the key of an element.
+
+	ShouldHoldFormat string // Printed when Invariant fails. Args: name of the invariant.
+	ReferencedFields string // Title of the section of fields referenced by the predicate of an invariant.
+
+	ShouldIncreaseByFormat string // Printed when CounterIncreasedBy fails. Args: name of the metric, expected and actual increase.
+	MetricBefore           string // Title of samples of a metric before calling the func.
+	MetricAfter            string // Title of samples of a metric after calling the func.
+	NoMetricSamples        string // Printed when a metric has no sample.
+
+	TooLargeToCompareFormat string // Printed when values cannot be compared in compare timeout. Args: the timeout.
+	SizeStats               string // Title of the section of size stats of values.
+	SizeStatsFormat         string // Size stats of a value. Args: number of nodes, distinct pointers, shared or cyclic references and max depth.
+
+	Note string // Header of a note logged by Note.
+
+	ShouldHaveKey     string // Printed when HasKey fails.
+	DidYouMeanFormat  string // Printed when a missing key is similar to existing keys. Args: quoted similar keys.
+	StringEditsFormat string // Printed when Equal fails on similar strings. Args: edit distance.
+	KeyTypoFormat     string // Printed when a map key only in [1] is similar to a key only in [2]. Args: paths of both keys.
+}
+
+// DefaultMessages is the default message table.
+var DefaultMessages = Messages{
+	AssertionFailed:     "Assertion failed:",
+	AssertionIDFormat:   "Assertion ID: %v",
+	GroupFormat:         "[%v] %v:%v:",
+	InternalErrorFormat: "Assertion failed with an internal error: %v",
+	Assignments:         "Referenced variables are assigned in following statements:",
+	HelperReturnsFormat: "%v returns:",
+	RelatedVars:         "Related variables:",
+	Iteration:           "Iteration:",
+	Case:                "Test case:",
+	FuzzInputs:          "Fuzz inputs:",
+	Values:              "Values:",
+	SubExprs:            "Values of sub-expressions:",
+	DumpTruncatedFormat: "... (%v more bytes)",
+	ShouldEqual:         "The value of following expression should equal.",
+	ShouldBeSameType:    "The type of following expressions should be the same.",
+	ErrorChainNote:      "One error wraps the other. Equal compares error values. Use errors.Is to test error chains.",
+	ErrorTextNote:       "Errors have the same message but they are different values. Use errors.Is to test sentinel errors or compare messages by err.Error().",
+	PointerTypeFormat:   "[%v] is a pointer to the type of [%v]. Equal never dereferences pointers. Compare values of the same type.",
+	ShouldNotEqual:      "The value of following expression should not equal.",
+	Differences:         "Differences:",
+	DiffOnlyIn1:         "Only in [1]:",
+	DiffOnlyIn2:         "Only in [2]:",
+	DiffChanged:         "Different values:",
+	BytesLengthFormat:   "[1] has %v bytes but [2] has %v bytes.",
+	BytesRangeFormat:    "At offset %#x, %v bytes differ:",
+	BytesDumpFormat:     "([]uint8) %v bytes",
+	RuneDiffFormat:      "Normalized strings differ at rune %v:",
+	EndOfString:         "(end of string)",
+	DiffMoreFormat:      "... and %v more",
+	DiffEqualFormat:     "(%v equal fields not shown)",
+	ShouldBeNilError:    "Following expression should return a nil error.",
+	ShouldBeNonNilError: "Following expression should return an error.",
+	ShouldBeNoError:     "Following error should be nil.",
+	ShouldBeError:       "Following error should not be nil.",
+	ErrorIs:             "The error is:",
+	ShouldBeTypeFormat:  "The type of following expression should be %v but got %v.",
+	Value:               "Value:",
+	Notes:               "Notes:",
+	TypedNilFormat:      "%v is a non-nil interface holding a nil %v.",
+	TypedNilErrorFormat: "The error is a non-nil interface holding a nil %v.",
+	FuncMemberFormat:    "%v is a func; funcs are never DeepEqual unless both are nil. Compare other members instead.",
+	ChanMemberFormat:    "%v is a chan; chans are DeepEqual only if they are the same chan. Compare other members instead.",
+
+	ConditionsFalseFormat: "%v of %v conditions are false:",
+
+	ShouldNotBlockFormat: "Following function should return within %v.",
+	GoroutineStack:       "Goroutine stack:",
+	ShouldCompleteFormat: "Following expression should complete within %v.",
+	GoroutineStacks:      "Goroutine stacks:",
+	ShouldFinishFormat:   "Following block should finish within %v.",
+	ShouldReceive:        "Following channel should receive a value before the context is done.",
+	ChannelClosed:        "The channel is closed.",
+	ContextError:         "The context is done with error:",
+
+	ShouldBeSatisfiedFormat:     "Following condition should be satisfied within %v.",
+	ShouldBeSatisfiedBeforeDone: "Following condition should be satisfied before the context is done.",
+	AttemptsFormat:              "The condition was checked %v times.",
+	LastValue:                   "Last observed value:",
+	LastError:                   "Last observed error:",
+
+	ShouldNotLeak:          "Following condition should not leak goroutines.",
+	LeakedGoroutinesFormat: "%v goroutines started by the condition are still running:",
+
+	ShouldGrowHeapUnderFormat: "Heap should grow no more than %v bytes after calling following function.",
+	Heap:                      "Heap:",
+	HeapStatsFormat:           "before = %v bytes\nafter = %v bytes\ngrowth = %v bytes",
+
+	ShouldBeShorterFormat: "Following duration should be less than %v.",
+	ShouldBeBetweenFormat: "Following duration should be between %v and %v.",
+	ShouldTakeLessFormat:  "Following function should take less than %v.",
+	Duration:              "Duration:",
+	DurationOverFormat:    "%v, which is %v over the limit.",
+	DurationUnderFormat:   "%v, which is %v under the lower bound.",
+
+	GoroutinePanicked: "A goroutine guarded by following guard panicked with:",
+	PanicStack:        "Panic stack:",
+
+	WatchdogFormat:     "Test case %v has not completed in %v.",
+	RecentAssertions:   "Recently executed assertions, the most recent last:",
+	NoRecentAssertions: "(no assertion has been executed)",
+
+	ShouldSatisfy:          "Following value should satisfy the predicate.",
+	Predicate:              "Predicate:",
+	ShouldMatch:            "Following value should match the matcher.",
+	Explanation:            "Explanation:",
+	MatchNot:               "not",
+	MatchAnyOf:             "any of",
+	MatchAllOf:             "all of",
+	MatchEqualToFormat:     "equal to %v",
+	MatchInSliceFormat:     "in %v",
+	MatchGomegaErrorFormat: "Gomega matcher failed with error: %v",
+	ShouldContainAll:       "Following string should contain all substrings.",
+	ShouldContainAny:       "Following string should contain at least one of substrings.",
+	Substrings:             "Substrings:",
+	SubstringFoundFormat:   "%q is found at index %v.",
+	SubstringMissingFormat: "%q is missing.",
+	ShouldAllMatch:         "All elements of following collection should satisfy the predicate.",
+	ShouldAnyMatch:         "At least one element of following collection should satisfy the predicate.",
+	FailedIndicesFormat:    "%v of %v elements fail the predicate at indices:",
+	FailedElements:         "Failed elements:",
+
+	ShouldBeInDeltaFormat:   "The difference of following numbers should be within %v.",
+	ShouldBeInEpsilonFormat: "The relative difference of following numbers should be within %v.",
+	Difference:              "Difference:",
+
+	ShouldAllBeInDeltaFormat: "Elements of following numbers should be pairwise within %v.",
+	ElementsLengthFormat:     "[1]%[1]v has %[2]v elements but [2]%[1]v has %[3]v elements.",
+	DeviationsFormat:         "%v of %v elements are not within the delta:",
+	DeviationFormat:          "%v: [1] -> %v, [2] -> %v, difference = %v",
+
+	ShouldHaveMeanFormat:       "Mean of following samples should be within %v of %v.",
+	ShouldHavePercentileFormat: "The p%v of following samples should not be greater than %v.",
+	PercentileFormat:           "The actual value is %v.",
+	NoSamples:                  "There is no sample.",
+	Summary:                    "Summary:",
+	SamplesSummaryFormat:       "count = %v, min = %v, max = %v, mean = %v, p50 = %v, p99 = %v",
+
+	ShouldIssueRequests: "Issued requests should match expectations. [1] is expected and [2] is issued.",
+	ShouldDialFormat:    "Following address should accept %v connections.",
+
+	ShouldHaveStatusFormat: "The response status code should be %v but got %v.",
+	ShouldHaveHeaderFormat: "The response header %v should be %q but got %q.",
+	ShouldContainFormat:    "The response body should contain %q.",
+	ShouldHaveBody:         "The response body should equal the expected body.",
+	LastExchange:           "Last request and response:",
+
+	Profiles:           "Profiles are written to following files:",
+	ProfileErrorFormat: "fail to write profile: %v",
+
+	QuarantinedFormat: "Quarantined failure [%v]. The test case is not failed.",
+
+	OutputElidedFormat: "... (%v bytes elided) ...",
+	OutputBudgetFormat: "Failure output of this test exceeds %v bytes. Following failure messages are suppressed.",
+
+	RepeatedFailuresFormat: "...and %v more failures at %v",
+
+	ArtifactsDirFormat: "Artifacts of this test are in %v",
+
+	DiffUnexported: "(unexported)",
+
+	StringDiffFormat: "Strings differ at rune %v:",
+
+	ShouldPointerEqual: "The value of following expression should equal except parts which are nil pointers in [2].",
+
+	ShouldBeOrdered:  "Elements of following expression should be in non-decreasing order of key.",
+	OrderingKey:      "Key:",
+	OutOfOrderFormat: "Elements [%v] and [%v] are out of order:",
+	KeyArrow:         "key ->",
+
+	ShouldHoldFormat: "Invariant %q should hold for following expression.",
+	ReferencedFields: "Referenced fields:",
+
+	ShouldIncreaseByFormat: "Metric %q should increase by %v but increased by %v.",
+	MetricBefore:           "Samples before:",
+	MetricAfter:            "Samples after:",
+	NoMetricSamples:        "(no samples)",
+
+	TooLargeToCompareFormat: "Values of following expressions are too large or cyclic to compare in %v.",
+	SizeStats:               "Size stats:",
+	SizeStatsFormat:         "%v nodes, %v distinct pointers, %v shared or cyclic references, max depth %v",
+
+	Note: "Note:",
+
+	ShouldHaveKey:     "The map should have following key.",
+	DidYouMeanFormat:  "Did you mean %v?",
+	StringEditsFormat: "Edit distance between strings is %v. Check for typos.",
+	KeyTypoFormat:     "%v only in [1] looks like a typo of %v only in [2].",
+}
+
+var (
+	messagesLock sync.RWMutex
+	messages     = DefaultMessages
+)
+
+// SetMessages replaces the message table used by all assertions.
+// Empty fields in m are filled with DefaultMessages.
+func SetMessages(m Messages) {
+	merged := mergeMessages(m, DefaultMessages)
+
+	messagesLock.Lock()
+	defer messagesLock.Unlock()
+	messages = merged
+}
+
+// CurrentMessages returns current message table.
+func CurrentMessages() Messages {
+	messagesLock.RLock()
+	defer messagesLock.RUnlock()
+	return messages
+}
+
+func mergeMessages(m, defaults Messages) Messages {
+	v := reflect.ValueOf(&m).Elem()
+	def := reflect.ValueOf(defaults)
+
+	for i := 0; i < v.NumField(); i++ {
+		if field := v.Field(i); field.String() == "" {
+			field.Set(def.Field(i))
+		}
+	}
+
+	return m
+}
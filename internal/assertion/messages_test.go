@@ -0,0 +1,22 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"testing"
+)
+
+func TestSetMessages(t *testing.T) {
+	defer SetMessages(DefaultMessages)
+
+	SetMessages(Messages{
+		AssertionFailed: "Check failed:",
+	})
+	msgs := CurrentMessages()
+	assertEqual(t, msgs.AssertionFailed, "Check failed:")
+	assertEqual(t, msgs.ShouldEqual, DefaultMessages.ShouldEqual)
+
+	SetMessages(Messages{})
+	assertEqual(t, CurrentMessages(), DefaultMessages)
+}
@@ -0,0 +1,246 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var errInvalidMetricsSource = errors.New("go-assert: metrics source must be nil for expvar, an *expvar.Map or a prometheus.Gatherer")
+
+// metricSample is a sample of a metric family.
+type metricSample struct {
+	Labels string // Labels formatted like `{code="200"}`. It's empty if there is no label.
+	Value  float64
+}
+
+// AssertCounterIncreasedBy expects the sum of all samples of the metric named name increases by delta after calling fn.
+// Otherwise, it will terminate the test case using `t.Fatalf` with samples of the metric before and after calling fn.
+//
+// The source can be one of following values.
+//   - nil: Metrics published by package expvar. Vars of type *expvar.Map are families with label "key".
+//   - *expvar.Map: Vars in the map in the same way as above.
+//   - prometheus.Gatherer: Any value with method `Gather() ([]*dto.MetricFamily, error)`.
+//     Counters, gauges and untyped metrics are read.
+func AssertCounterIncreasedBy(t T, source interface{}, name string, delta float64, fn func(), trigger *Trigger) {
+	t.Helper()
+	before, err := gatherMetric(source, name)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	fn()
+	after, err := gatherMetric(source, name)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	increased := sumSamples(after) - sumSamples(before)
+
+	if increased == delta {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	beforeDump := formatSamples(msgs, name, before)
+	afterDump := formatSamples(msgs, name, after)
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n%v\n%v\n%v\n%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4),
+			fmt.Sprintf(msgs.ShouldIncreaseByFormat, name, formatFloat(delta), formatFloat(increased)),
+			msgs.MetricBefore, beforeDump, msgs.MetricAfter, afterDump,
+			formatVars(msgs, info, trigger),
+		),
+		Values: []string{beforeDump, afterDump},
+	})
+}
+
+// gatherMetric returns samples of the metric named name in source sorted by labels.
+func gatherMetric(source interface{}, name string) ([]metricSample, error) {
+	var samples []metricSample
+	var err error
+
+	switch s := source.(type) {
+	case nil:
+		samples = expvarSamples(expvar.Get(name))
+	case *expvar.Map:
+		samples = expvarSamples(s.Get(name))
+	default:
+		samples, err = gathererSamples(source, name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Labels < samples[j].Labels
+	})
+	return samples, nil
+}
+
+// expvarSamples returns samples of v.
+// A *expvar.Map is a family of samples with label "key".
+func expvarSamples(v expvar.Var) []metricSample {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case *expvar.Int:
+		return []metricSample{{Value: float64(v.Value())}}
+	case *expvar.Float:
+		return []metricSample{{Value: v.Value()}}
+	case *expvar.Map:
+		var samples []metricSample
+		v.Do(func(kv expvar.KeyValue) {
+			for _, s := range expvarSamples(kv.Value) {
+				samples = append(samples, metricSample{
+					Labels: fmt.Sprintf("{key=%q}", kv.Key),
+					Value:  s.Value,
+				})
+			}
+		})
+		return samples
+	}
+
+	// Values of other vars, e.g. expvar.Func, are read from their JSON strings.
+	if value, err := strconv.ParseFloat(v.String(), 64); err == nil {
+		return []metricSample{{Value: value}}
+	}
+
+	return nil
+}
+
+// gathererSamples calls `Gather()` of a prometheus.Gatherer and returns samples of the metric named name.
+// The prometheus packages are not imported. Metric families are read by their getters.
+func gathererSamples(gatherer interface{}, name string) ([]metricSample, error) {
+	g := reflect.ValueOf(gatherer).MethodByName("Gather")
+
+	if !g.IsValid() || g.Type().NumIn() != 0 || g.Type().NumOut() != 2 || g.Type().Out(0).Kind() != reflect.Slice {
+		return nil, errInvalidMetricsSource
+	}
+
+	out := g.Call(nil)
+
+	if err, _ := out[1].Interface().(error); err != nil {
+		return nil, fmt.Errorf("go-assert: fail to gather metrics: %w", err)
+	}
+
+	families := out[0]
+	var samples []metricSample
+
+	for i := 0; i < families.Len(); i++ {
+		family := families.Index(i)
+
+		if getter(family, "GetName").String() != name {
+			continue
+		}
+
+		metrics := getter(family, "GetMetric")
+
+		for j := 0; j < metrics.Len(); j++ {
+			metric := metrics.Index(j)
+			value, ok := metricValue(metric)
+
+			if !ok {
+				continue
+			}
+
+			samples = append(samples, metricSample{
+				Labels: metricLabels(metric),
+				Value:  value,
+			})
+		}
+	}
+
+	return samples, nil
+}
+
+// metricValue returns the value of a counter, a gauge or an untyped metric.
+func metricValue(metric reflect.Value) (float64, bool) {
+	for _, name := range []string{"GetCounter", "GetGauge", "GetUntyped"} {
+		if v := getter(metric, name); v.IsValid() && !v.IsNil() {
+			return getter(v, "GetValue").Float(), true
+		}
+	}
+
+	return 0, false
+}
+
+// metricLabels formats labels of metric like `{code="200",method="GET"}`.
+func metricLabels(metric reflect.Value) string {
+	pairs := getter(metric, "GetLabel")
+
+	if !pairs.IsValid() || pairs.Len() == 0 {
+		return ""
+	}
+
+	labels := make([]string, 0, pairs.Len())
+
+	for i := 0; i < pairs.Len(); i++ {
+		pair := pairs.Index(i)
+		labels = append(labels, fmt.Sprintf("%v=%q", getter(pair, "GetName").String(), getter(pair, "GetValue").String()))
+	}
+
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+// getter calls the method named name without args on v and returns the result.
+// It returns an invalid value if there is no such method.
+func getter(v reflect.Value, name string) reflect.Value {
+	m := v.MethodByName(name)
+
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return reflect.Value{}
+	}
+
+	return m.Call(nil)[0]
+}
+
+func sumSamples(samples []metricSample) float64 {
+	sum := 0.0
+
+	for _, s := range samples {
+		sum += s.Value
+	}
+
+	return sum
+}
+
+// formatSamples formats samples of the metric named name in prometheus text format.
+func formatSamples(msgs Messages, name string, samples []metricSample) string {
+	if len(samples) == 0 {
+		return "    " + msgs.NoMetricSamples
+	}
+
+	lines := make([]string, 0, len(samples))
+
+	for _, s := range samples {
+		lines = append(lines, "    "+name+s.Labels+" "+formatFloat(s.Value))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,128 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"expvar"
+	"strings"
+	"testing"
+)
+
+// Fake types with the same getters as types in github.com/prometheus/client_model/go.
+type fakeMetricFamily struct {
+	name    string
+	metrics []*fakeMetric
+}
+
+func (f *fakeMetricFamily) GetName() string          { return f.name }
+func (f *fakeMetricFamily) GetMetric() []*fakeMetric { return f.metrics }
+
+type fakeMetric struct {
+	labels  []*fakeLabelPair
+	counter *fakeValue
+	gauge   *fakeValue
+}
+
+func (m *fakeMetric) GetLabel() []*fakeLabelPair { return m.labels }
+func (m *fakeMetric) GetCounter() *fakeValue     { return m.counter }
+func (m *fakeMetric) GetGauge() *fakeValue       { return m.gauge }
+
+type fakeLabelPair struct {
+	name, value string
+}
+
+func (p *fakeLabelPair) GetName() string  { return p.name }
+func (p *fakeLabelPair) GetValue() string { return p.value }
+
+type fakeValue struct {
+	value float64
+}
+
+func (v *fakeValue) GetValue() float64 { return v.value }
+
+type fakeGatherer struct {
+	families []*fakeMetricFamily
+	err      error
+}
+
+func (g *fakeGatherer) Gather() ([]*fakeMetricFamily, error) {
+	return g.families, g.err
+}
+
+func TestAssertCounterIncreasedByGatherer(t *testing.T) {
+	ok := &fakeMetric{labels: []*fakeLabelPair{{"code", "200"}}, counter: &fakeValue{5}}
+	failed := &fakeMetric{labels: []*fakeLabelPair{{"code", "500"}}, counter: &fakeValue{1}}
+	g := &fakeGatherer{
+		families: []*fakeMetricFamily{
+			{name: "requests_total", metrics: []*fakeMetric{failed, ok}},
+			{name: "in_flight", metrics: []*fakeMetric{{gauge: &fakeValue{2}}}},
+		},
+	}
+	ft := NewFakeT("TestAssertCounterIncreasedByGatherer")
+	trigger := &Trigger{
+		FuncName: "AssertCounterIncreasedBy",
+		Args:     []int{2},
+		Options:  Options{NonFatal: true},
+	}
+
+	AssertCounterIncreasedBy(ft, g, "requests_total", 3, func() {
+		ok.counter.value += 2
+		failed.counter.value++
+	}, trigger)
+	AssertCounterIncreasedBy(ft, g, "in_flight", -1, func() {
+		g.families[1].metrics[0].gauge.value--
+	}, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertCounterIncreasedBy(ft, g, "requests_total", 3, func() {
+		ok.counter.value += 2
+	}, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], `Metric "requests_total" should increase by 3 but increased by 2.`), true)
+	assertEqual(t, strings.Contains(msgs[0], "Samples before:\n    requests_total{code=\"200\"} 7\n    requests_total{code=\"500\"} 2\n"), true)
+	assertEqual(t, strings.Contains(msgs[0], "Samples after:\n    requests_total{code=\"200\"} 9\n    requests_total{code=\"500\"} 2"), true)
+
+	ft.Reset()
+	g.err = errors.New("broken")
+	AssertCounterIncreasedBy(ft, g, "requests_total", 0, func() {}, trigger)
+	AssertCounterIncreasedBy(ft, 42, "requests_total", 0, func() {}, trigger)
+	msgs = ft.Messages()
+	assertEqual(t, len(msgs), 2)
+	assertEqual(t, strings.Contains(msgs[0], "broken"), true)
+	assertEqual(t, strings.Contains(msgs[1], errInvalidMetricsSource.Error()), true)
+}
+
+func TestAssertCounterIncreasedByExpvar(t *testing.T) {
+	published := expvar.NewInt("TestAssertCounterIncreasedByExpvar")
+	m := new(expvar.Map).Init()
+	m.Add("hits", 1)
+	m.Add("misses", 1)
+	ft := NewFakeT("TestAssertCounterIncreasedByExpvar")
+	trigger := &Trigger{
+		FuncName: "AssertCounterIncreasedBy",
+		Args:     []int{2},
+		Options:  Options{NonFatal: true},
+	}
+
+	AssertCounterIncreasedBy(ft, nil, "TestAssertCounterIncreasedByExpvar", 2, func() {
+		published.Add(2)
+	}, trigger)
+	AssertCounterIncreasedBy(ft, m, "hits", 1, func() {
+		m.Add("hits", 1)
+	}, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	nested := new(expvar.Map).Init()
+	m.Set("cache", nested)
+	AssertCounterIncreasedBy(ft, m, "cache", 1, func() {
+		nested.Add("users", 2)
+	}, trigger)
+	AssertCounterIncreasedBy(ft, nil, "TestAssertCounterIncreasedByExpvar/missing", 1, func() {}, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 2)
+	assertEqual(t, strings.Contains(msgs[0], "Samples before:\n    (no samples)\nSamples after:\n    cache{key=\"users\"} 2"), true)
+	assertEqual(t, strings.Contains(msgs[1], `should increase by 1 but increased by 0.`), true)
+}
@@ -0,0 +1,49 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// AssertDial expects a connection to addr in network can be established within timeout.
+// If timeout is 0, there is no timeout other than the one set by operating system.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the dial error.
+//
+// The connection is closed immediately after it's established.
+func AssertDial(t T, network, addr string, timeout time.Duration, trigger *Trigger) {
+	t.Helper()
+	conn, dialErr := net.DialTimeout(network, addr, timeout)
+
+	if dialErr == nil {
+		conn.Close()
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n    %v%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4),
+			fmt.Sprintf(msgs.ShouldDialFormat, network),
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.ErrorIs, dialErr, formatVars(msgs, info, trigger),
+		),
+		Values: []string{dialErr.Error()},
+	})
+}
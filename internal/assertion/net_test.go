@@ -0,0 +1,32 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssertDial(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assertEqual(t, err, nil)
+	addr := l.Addr().String()
+	ft := NewFakeT("TestAssertDial")
+	trigger := &Trigger{
+		FuncName: "AssertDial",
+		Args:     []int{2},
+	}
+
+	AssertDial(ft, "tcp", addr, time.Second, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	l.Close()
+	AssertDial(ft, "tcp", addr, time.Second, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "Following address should accept tcp connections.\n    addr\n"), true)
+	assertEqual(t, strings.Contains(msgs[0], "connection refused"), true)
+}
@@ -0,0 +1,93 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// logger is the subset of `testing.TB` used to log notes.
+type logger interface {
+	Logf(format string, args ...interface{})
+}
+
+// Note logs values with their expressions, assignments and related variables
+// in the same format as failure messages.
+// The trigger.Args[0] is the index of the first value in the call to Note.
+//
+// Nothing is logged if there is no value or t doesn't implement `Logf(string, ...interface{})`.
+// Dumps of values are still logged if the source of the call cannot be parsed,
+// as a note must never fail the test case.
+func Note(t T, values []interface{}, trigger *Trigger) {
+	t.Helper()
+	l, ok := t.(logger)
+
+	if !ok || len(values) == 0 {
+		return
+	}
+
+	defer trigger.startTimer()
+
+	first := 0
+
+	if len(trigger.Args) > 0 {
+		first = trigger.Args[0]
+	}
+
+	tr := *trigger
+	tr.Args = make([]int, 0, len(values))
+
+	for i := range values {
+		tr.Args = append(tr.Args, first+i)
+	}
+
+	msgs := CurrentMessages()
+	dumper := tr.dumper()
+	dumps := make([]string, 0, len(values))
+	lines := make([]string, 0, len(values)*2+2)
+
+	for _, v := range values {
+		dumps = append(dumps, dumper.Dump(v))
+	}
+
+	f, err := tr.parseArgs()
+	var info *Info
+
+	if err == nil {
+		info = tr.P().ParseInfo(f)
+		lines = append(lines, fmt.Sprintf("%v:%v: %v\n    %v", f.Filename, f.Line, msgs.Note, indentCode(info.Source, 4)))
+
+		// Sources of values are unavailable if values are passed by a slice, e.g. `a.Note(values...)`.
+		if !strings.HasSuffix(info.Source, "...)") {
+			for i := range values {
+				if i < len(info.Args) && info.Args[i] != "" {
+					lines = append(lines, fmt.Sprintf("[%v] %v%v", i+1, indentCode(info.Args[i], 4), indentAssignments(info.Assignments[i], 4)))
+				}
+			}
+		}
+	} else {
+		lines = append(lines, msgs.Note)
+	}
+
+	lines = append(lines, msgs.Values)
+
+	for i, dump := range dumps {
+		lines = append(lines, fmt.Sprintf("[%v] -> %v", i+1, dump))
+	}
+
+	msg := strings.Join(lines, "\n")
+
+	if info != nil {
+		msg += formatVars(msgs, info, &tr)
+	}
+
+	if StableOutputEnabled() {
+		msg = stabilize(msg)
+	} else if !tr.colorEnabled() {
+		msg = stripColors(msg)
+	}
+
+	l.Logf("\n%v", formatOutput(msg))
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNote(t *testing.T) {
+	ft := NewFakeT("TestNote")
+	note := func(values ...interface{}) {
+		Note(ft, values, &Trigger{
+			FuncName: "note",
+			Skip:     1,
+			Args:     []int{0},
+		})
+	}
+
+	name := "alice"
+	items := []int{1, 2, 3}
+	note(name, len(items))
+	assertEqual(t, ft.Failed(), false)
+
+	calls := nonHelperCalls(ft)
+	assertEqual(t, len(calls), 1)
+	assertEqual(t, calls[0].Method, "Logf")
+
+	msg := stripColors(calls[0].Message)
+	assertEqual(t, strings.Contains(msg, "Note:\n    note(name, len(items))"), true)
+	assertEqual(t, strings.Contains(msg, "[1] name\n    name := \"alice\"\n[2] len(items)\n    items := []int{1, 2, 3}"), true)
+	assertEqual(t, strings.Contains(msg, "Values:\n[1] -> (string)alice\n[2] -> (int)3"), true)
+
+	// Values passed by a slice are dumped only.
+	ft.Reset()
+	values := []interface{}{name}
+	note(values...)
+	msg = stripColors(nonHelperCalls(ft)[0].Message)
+	assertEqual(t, strings.Contains(msg, "[1] values"), false)
+	assertEqual(t, strings.Contains(msg, "Values:\n[1] -> (string)alice"), true)
+
+	ft.Reset()
+	note()
+	assertEqual(t, len(nonHelperCalls(ft)), 0)
+}
@@ -0,0 +1,168 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// deviation is a pair of elements whose difference exceeds the tolerance.
+type deviation struct {
+	Index string
+	X, Y  float64
+}
+
+// AssertAllInDelta expects x and y have the same length and
+// the absolute difference of every pair of elements is not greater than delta.
+// NaNs are only within delta of NaNs. Infinite numbers are only within delta of the same infinity.
+// Otherwise, it will terminate the test case using `t.Fatalf` with all deviating elements.
+func AssertAllInDelta(t T, x, y []float64, delta float64, trigger *Trigger) {
+	t.Helper()
+	if !validDelta(delta) {
+		failInternal(t, trigger, errInvalidTolerance)
+		return
+	}
+
+	var lengths []string
+	deviations, total := appendDeviations(nil, "", x, y, delta)
+
+	if len(x) != len(y) {
+		lengths = append(lengths, fmt.Sprintf(CurrentMessages().ElementsLengthFormat, "", len(x), len(y)))
+	}
+
+	if len(deviations) == 0 && len(lengths) == 0 {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	fail(t, trigger, deviationsFailure(CurrentMessages(), trigger, f, delta, lengths, deviations, total))
+}
+
+// AssertAllInDelta2D is the same as AssertAllInDelta except that x and y are matrices.
+// Rows are compared one by one.
+func AssertAllInDelta2D(t T, x, y [][]float64, delta float64, trigger *Trigger) {
+	t.Helper()
+	if !validDelta(delta) {
+		failInternal(t, trigger, errInvalidTolerance)
+		return
+	}
+
+	msgs := CurrentMessages()
+	var lengths []string
+	var deviations []deviation
+	total := 0
+
+	if len(x) != len(y) {
+		lengths = append(lengths, fmt.Sprintf(msgs.ElementsLengthFormat, "", len(x), len(y)))
+	}
+
+	for i := 0; i < len(x) && i < len(y); i++ {
+		var n int
+		index := "[" + strconv.Itoa(i) + "]"
+		deviations, n = appendDeviations(deviations, index, x[i], y[i], delta)
+		total += n
+
+		if len(x[i]) != len(y[i]) {
+			lengths = append(lengths, fmt.Sprintf(msgs.ElementsLengthFormat, index, len(x[i]), len(y[i])))
+		}
+	}
+
+	if len(deviations) == 0 && len(lengths) == 0 {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	fail(t, trigger, deviationsFailure(msgs, trigger, f, delta, lengths, deviations, total))
+}
+
+func validDelta(delta float64) bool {
+	return delta >= 0 && !math.IsInf(delta, 1)
+}
+
+// appendDeviations appends deviating elements in x and y to deviations.
+// Elements are compared up to the shorter length. The total is the number of compared elements.
+func appendDeviations(deviations []deviation, prefix string, x, y []float64, delta float64) ([]deviation, int) {
+	total := len(x)
+
+	if len(y) < total {
+		total = len(y)
+	}
+
+	for i := 0; i < total; i++ {
+		if !inDelta(x[i], y[i], delta) {
+			deviations = append(deviations, deviation{
+				Index: prefix + "[" + strconv.Itoa(i) + "]",
+				X:     x[i],
+				Y:     y[i],
+			})
+		}
+	}
+
+	return deviations, total
+}
+
+func inDelta(x, y, delta float64) bool {
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return math.IsNaN(x) && math.IsNaN(y)
+	}
+
+	if math.IsInf(x, 0) || math.IsInf(y, 0) {
+		return x == y
+	}
+
+	return math.Abs(x-y) <= delta
+}
+
+// deviationsFailure creates the failure of AssertAllInDelta and AssertAllInDelta2D.
+func deviationsFailure(msgs Messages, trigger *Trigger, f *Func, delta float64, lengths []string, deviations []deviation, total int) *Failure {
+	info := trigger.P().ParseInfo(f)
+	lines := make([]string, 0, len(lengths)+len(deviations)+2)
+	lines = append(lines, lengths...)
+
+	if len(deviations) > 0 {
+		lines = append(lines, fmt.Sprintf(msgs.DeviationsFormat, len(deviations), total))
+
+		for i, d := range deviations {
+			if i == maxDiffEntries {
+				lines = append(lines, "    "+fmt.Sprintf(msgs.DiffMoreFormat, len(deviations)-maxDiffEntries))
+				break
+			}
+
+			lines = append(lines, "    "+fmt.Sprintf(msgs.DeviationFormat, d.Index, formatFloat(d.X), formatFloat(d.Y), formatFloat(d.X-d.Y)))
+		}
+	}
+
+	return &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n[1] %v%v\n[2] %v%v\n%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4), fmt.Sprintf(msgs.ShouldAllBeInDeltaFormat, formatFloat(delta)),
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			indentCode(info.Args[1], 4), indentAssignments(info.Assignments[1], 4),
+			strings.Join(lines, "\n"), formatVars(msgs, info, trigger),
+		),
+	}
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
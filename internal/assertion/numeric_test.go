@@ -0,0 +1,53 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestAssertAllInDelta(t *testing.T) {
+	ft := NewFakeT("TestAssertAllInDelta")
+	trigger := &Trigger{
+		FuncName: "AssertAllInDelta",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true},
+	}
+	nan := math.NaN()
+	inf := math.Inf(1)
+
+	AssertAllInDelta(ft, []float64{1, 2, nan, inf}, []float64{1.05, 1.95, nan, inf}, 0.1, trigger)
+	AssertAllInDelta(ft, nil, []float64{}, 0, trigger)
+	assertEqual(t, len(ft.Messages()), 0)
+
+	AssertAllInDelta(ft, []float64{1, 2, 3, nan}, []float64{1, 2.5, 3}, 0.1, trigger)
+	AssertAllInDelta(ft, []float64{1, inf}, []float64{1, 1}, math.NaN(), trigger)
+	msgs := ft.Messages()
+
+	assertEqual(t, len(msgs), 2)
+	assertEqual(t, strings.Contains(msgs[0], "[1] has 4 elements but [2] has 3 elements."), true)
+	assertEqual(t, strings.Contains(msgs[0], "1 of 3 elements are not within the delta:\n    [1]: [1] -> 2, [2] -> 2.5, difference = -0.5"), true)
+	assertEqual(t, strings.Contains(msgs[1], errInvalidTolerance.Error()), true)
+}
+
+func TestAssertAllInDelta2D(t *testing.T) {
+	ft := NewFakeT("TestAssertAllInDelta2D")
+	trigger := &Trigger{
+		FuncName: "AssertAllInDelta2D",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true},
+	}
+
+	AssertAllInDelta2D(ft, [][]float64{{1, 2}, {3, 4}}, [][]float64{{1, 2}, {3, 4.01}}, 0.1, trigger)
+	assertEqual(t, len(ft.Messages()), 0)
+
+	AssertAllInDelta2D(ft, [][]float64{{1, 2}, {3, 4}}, [][]float64{{1, 2}, {3.5, 4}, {5}}, 0.1, trigger)
+	msgs := ft.Messages()
+
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "[1] has 2 elements but [2] has 3 elements."), true)
+	assertEqual(t, strings.Contains(msgs[0], "1 of 4 elements are not within the delta:\n    [1][0]: [1] -> 3, [2] -> 3.5, difference = -0.5"), true)
+}
@@ -0,0 +1,129 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"reflect"
+)
+
+var errInvalidOrderingKey = errors.New("go-assert: key must be a func(e E) K accepting elements and returning a number, a string or a K with method `Before(K) bool`")
+
+// AssertOrdered expects keys of elements in collection are non-decreasing.
+// Otherwise, it will terminate the test case using `t.Fatalf` with the first out-of-order pair of elements.
+//
+// The collection must be a slice or an array.
+// The key must be a `func(e E) K` where elements of collection are assignable to E.
+// The K must be a number, a string or a type with method `Before(K) bool` like `time.Time`.
+func AssertOrdered(t T, collection, key interface{}, trigger *Trigger) {
+	t.Helper()
+	c := reflect.ValueOf(collection)
+
+	if c.Kind() != reflect.Slice && c.Kind() != reflect.Array {
+		failInternal(t, trigger, errInvalidCollection)
+		return
+	}
+
+	k, less, err := orderingKey(key, c.Type().Elem())
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	idx := -1
+	var prev, curr reflect.Value
+
+	for i := 0; i < c.Len(); i++ {
+		prev = curr
+		curr = k.Call([]reflect.Value{c.Index(i)})[0]
+
+		if i > 0 && less(curr, prev) {
+			idx = i
+			break
+		}
+	}
+
+	if idx < 0 {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	dumper := trigger.dumper()
+	keyAssignments := info.Assignments[1]
+
+	// Vars in a func literal are its own params and locals, not assigned outside.
+	if _, ok := f.Args[1].(*ast.FuncLit); ok {
+		keyAssignments = nil
+	}
+
+	prevDump := dumper.Dump(c.Index(idx - 1).Interface())
+	currDump := dumper.Dump(c.Index(idx).Interface())
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n    %v%v\n%v\n    [%v] -> %v\n        %v %v\n    [%v] -> %v\n        %v %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, msgs.ShouldBeOrdered,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.OrderingKey,
+			indentCode(info.Args[1], 4), indentAssignments(keyAssignments, 4),
+			fmt.Sprintf(msgs.OutOfOrderFormat, idx-1, idx),
+			idx-1, prevDump, msgs.KeyArrow, dumper.Dump(prev.Interface()),
+			idx, currDump, msgs.KeyArrow, dumper.Dump(curr.Interface()),
+			formatVars(msgs, info, trigger),
+		),
+		Values: []string{prevDump, currDump},
+	})
+}
+
+// orderingKey validates key is a `func(e E) K` accepting values of typ and returns a less func of K.
+func orderingKey(key interface{}, typ reflect.Type) (k reflect.Value, less func(k1, k2 reflect.Value) bool, err error) {
+	k = reflect.ValueOf(key)
+
+	if k.Kind() != reflect.Func || k.IsNil() {
+		err = errInvalidOrderingKey
+		return
+	}
+
+	kt := k.Type()
+
+	if kt.NumIn() != 1 || kt.IsVariadic() || kt.NumOut() != 1 || !typ.AssignableTo(kt.In(0)) {
+		err = errInvalidOrderingKey
+		return
+	}
+
+	out := kt.Out(0)
+
+	if m, ok := out.MethodByName("Before"); ok && m.Type.NumIn() == 2 && m.Type.In(1) == out &&
+		m.Type.NumOut() == 1 && m.Type.Out(0).Kind() == reflect.Bool {
+		less = func(k1, k2 reflect.Value) bool {
+			return k1.MethodByName("Before").Call([]reflect.Value{k2})[0].Bool()
+		}
+		return
+	}
+
+	switch out.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		less = lessValue
+	default:
+		err = errInvalidOrderingKey
+	}
+
+	return
+}
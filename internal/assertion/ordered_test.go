@@ -0,0 +1,70 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAssertOrdered(t *testing.T) {
+	type Event struct {
+		Seq int
+		At  time.Time
+	}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	events := []Event{
+		{1, at},
+		{2, at},
+		{3, at.Add(time.Second)},
+		{4, at.Add(-time.Second)},
+		{5, at.Add(-time.Minute)},
+	}
+	ft := NewFakeT("TestAssertOrdered")
+	trigger := &Trigger{
+		FuncName: "AssertOrdered",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true},
+	}
+
+	AssertOrdered(ft, events, func(e Event) int { return e.Seq }, trigger)
+	AssertOrdered(ft, events[:3], func(e Event) time.Time { return e.At }, trigger)
+	AssertOrdered(ft, []string{}, func(s string) string { return s }, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertOrdered(ft, events, func(e Event) time.Time { return e.At }, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertMessage(t, msgs[0], `
+Assertion failed:
+Elements of following expression should be in non-decreasing order of key.
+    events
+    events := []Event{
+        {1, at},
+        {2, at},
+        {3, at.Add(time.Second)},
+        {4, at.Add(-time.Second)},
+        {5, at.Add(-time.Minute)},
+    }
+Key:
+    func(e Event) time.Time { return e.At }
+Elements [2] and [3] are out of order:
+    [2] -> (assertion.Event){Seq:(int)3 At:(time.Time)2024-01-02T03:04:06Z}
+        key -> (time.Time)2024-01-02T03:04:06Z
+    [3] -> (assertion.Event){Seq:(int)4 At:(time.Time)2024-01-02T03:04:04Z}
+        key -> (time.Time)2024-01-02T03:04:04Z
+Assertion ID: 9a6f562280a0`)
+
+	ft.Reset()
+	AssertOrdered(ft, events, func(e Event) Event { return e }, trigger)
+	AssertOrdered(ft, events, func(e Event) {}, trigger)
+	AssertOrdered(ft, 1, func(e Event) int { return e.Seq }, trigger)
+	msgs = ft.Messages()
+	assertEqual(t, len(msgs), 3)
+	assertEqual(t, msgs[0], fmt.Sprintf(DefaultMessages.InternalErrorFormat, errInvalidOrderingKey))
+	assertEqual(t, msgs[1], fmt.Sprintf(DefaultMessages.InternalErrorFormat, errInvalidOrderingKey))
+	assertEqual(t, msgs[2], fmt.Sprintf(DefaultMessages.InternalErrorFormat, errInvalidCollection))
+}
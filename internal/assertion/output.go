@@ -0,0 +1,275 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Environment variables controlling output format.
+const (
+	EnvWidth   = "GO_ASSERT_WIDTH"   // Max width of output lines. Set 0 to disable wrapping.
+	EnvCompact = "GO_ASSERT_COMPACT" // Set to a true value like "1" or "true" to enable compact mode.
+)
+
+const minWrapWidth = 20
+
+// Output settings are initialized by SetDefault in init.
+var (
+	outputLock  sync.RWMutex
+	outputWidth int
+	compactMode bool
+)
+
+// SetWidth sets max width of output lines.
+// Lines longer than width are wrapped and aligned to its leading indentation.
+// Set width to 0 to disable wrapping.
+func SetWidth(width int) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	outputWidth = width
+}
+
+// SetCompact enables or disables compact mode.
+// In compact mode, a failure message is printed in one line,
+// which is friendly to environments like GitHub Actions annotations.
+func SetCompact(compact bool) {
+	outputLock.Lock()
+	defer outputLock.Unlock()
+	compactMode = compact
+}
+
+// detectWidth returns the width of terminal or `COLUMNS`.
+func detectWidth() int {
+	if width := terminalWidth(); width > 0 {
+		return width
+	}
+
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+
+	return 0
+}
+
+// maxPooledBufferSize is the max capacity of a buffer put back to bufferPool.
+// Larger buffers are dropped to avoid holding memory after a huge failure message.
+const maxPooledBufferSize = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return &bytes.Buffer{}
+	},
+}
+
+// getBuffer returns an empty buffer from bufferPool.
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// putBuffer resets buf and puts it back to bufferPool.
+// The buf must not be used after calling putBuffer.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// formatOutput formats msg according to width and compact mode.
+func formatOutput(msg string) string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	writeOutput(buf, msg)
+	return buf.String()
+}
+
+// writeOutput writes msg to w according to width and compact mode.
+func writeOutput(w io.Writer, msg string) {
+	outputLock.RLock()
+	width := outputWidth
+	compact := compactMode
+	outputLock.RUnlock()
+
+	if compact {
+		writeCompactLines(w, msg)
+		return
+	}
+
+	if width >= minWrapWidth {
+		writeWrappedLines(w, msg, width)
+		return
+	}
+
+	io.WriteString(w, msg)
+}
+
+// cutLine cuts the first line of s.
+// The more is false if line is the last line in s.
+func cutLine(s string) (line, rest string, more bool) {
+	idx := strings.IndexByte(s, '\n')
+
+	if idx < 0 {
+		return s, "", false
+	}
+
+	return s[:idx], s[idx+1:], true
+}
+
+func compactLines(msg string) string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	writeCompactLines(buf, msg)
+	return buf.String()
+}
+
+func writeCompactLines(w io.Writer, msg string) {
+	sep := ""
+
+	for more := true; more; {
+		var line string
+		line, msg, more = cutLine(msg)
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		io.WriteString(w, sep)
+		io.WriteString(w, line)
+		sep = " | "
+	}
+}
+
+// reLinePrefix matches prefixes like `[1] ` or `[2] -> ` in value lines.
+var reLinePrefix = regexp.MustCompile(`^\[\d+\] (-> )?`)
+
+func wrapLines(msg string, width int) string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	writeWrappedLines(buf, msg, width)
+	return buf.String()
+}
+
+func writeWrappedLines(w io.Writer, msg string, width int) {
+	for more := true; more; {
+		var line string
+		line, msg, more = cutLine(msg)
+
+		if visibleLen(line) > width {
+			content := strings.TrimLeft(line, " ")
+			indent := len(line) - len(content)
+			indent += len(reLinePrefix.FindString(content))
+
+			// Don't align to a position too close to the end of line.
+			if indent > width/2 {
+				indent = width / 2
+			}
+
+			space := strings.Repeat(" ", indent)
+			head, tail := splitLine(line, width, indent)
+
+			// Following parts are split without the leading space to avoid concatenating strings.
+			for {
+				io.WriteString(w, head)
+				io.WriteString(w, "\n")
+				io.WriteString(w, space)
+
+				if visibleLen(tail) <= width-indent {
+					break
+				}
+
+				head, tail = splitLine(tail, width-indent, 0)
+			}
+
+			line = tail
+		}
+
+		io.WriteString(w, line)
+
+		if more {
+			io.WriteString(w, "\n")
+		}
+	}
+}
+
+// splitLine splits line at the last space before width.
+// If there is no proper space, line is split at width.
+func splitLine(line string, width, indent int) (head, tail string) {
+	end := 0
+	runes := 0
+
+	for i := 0; i < len(line); {
+		if n := escapeLen(line[i:]); n > 0 {
+			i += n
+			continue
+		}
+
+		if runes == width {
+			end = i
+			break
+		}
+
+		_, size := utf8.DecodeRuneInString(line[i:])
+		i += size
+		runes++
+	}
+
+	if idx := strings.LastIndexByte(line[:end], ' '); idx > indent {
+		return strings.TrimRight(line[:idx], " "), strings.TrimLeft(line[idx+1:], " ")
+	}
+
+	return line[:end], line[end:]
+}
+
+// visibleLen returns the number of runes in s excluding ANSI escape sequences.
+func visibleLen(s string) int {
+	if !strings.Contains(s, "\x1b[") {
+		return utf8.RuneCountInString(s)
+	}
+
+	n := 0
+
+	for i := 0; i < len(s); {
+		if l := escapeLen(s[i:]); l > 0 {
+			i += l
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		n++
+	}
+
+	return n
+}
+
+// escapeLen returns the length of the ANSI escape sequence at the beginning of s.
+// It returns 0 if s doesn't start with an escape sequence.
+func escapeLen(s string) int {
+	if !strings.HasPrefix(s, "\x1b[") {
+		return 0
+	}
+
+	for i := 2; i < len(s); i++ {
+		if c := s[i]; c != ';' && (c < '0' || c > '9') {
+			if c == 'm' {
+				return i + 1
+			}
+
+			return 0
+		}
+	}
+
+	return 0
+}
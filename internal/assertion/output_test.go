@@ -0,0 +1,83 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrapLines(t *testing.T) {
+	cases := []struct {
+		Message string
+		Width   int
+		Wrapped string
+	}{
+		{
+			"short line",
+			20,
+			"short line",
+		},
+		{
+			"[1] -> ([]string)[foo bar baz qux]",
+			24,
+			"[1] -> ([]string)[foo\n       bar baz qux]",
+		},
+		{
+			"    abcdefghijklmnopqrstuvwxyz",
+			20,
+			"    abcdefghijklmnop\n    qrstuvwxyz",
+		},
+	}
+
+	for i, c := range cases {
+		t.Logf("case %v: %v", i, c)
+		assertEqual(t, wrapLines(c.Message, c.Width), c.Wrapped)
+	}
+}
+
+func TestCompactLines(t *testing.T) {
+	msg := "foo_test.go:12: Assertion failed:\n    a > b\n\nReferenced variables are assigned in following statements:\n    a, b := 1, 2"
+	assertEqual(t, compactLines(msg), "foo_test.go:12: Assertion failed: | a > b | Referenced variables are assigned in following statements: | a, b := 1, 2")
+}
+
+func TestWriteOutput(t *testing.T) {
+	SetCompact(true)
+	defer SetCompact(false)
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("prefix:")
+	writeOutput(buf, "\n  a > b\n\n")
+	assertEqual(t, buf.String(), "prefix:a > b")
+
+	SetCompact(false)
+	SetWidth(20)
+	defer SetWidth(0)
+
+	buf.Reset()
+	writeOutput(buf, "    abcdefghijklmnopqrstuvwxyz\n")
+	assertEqual(t, buf.String(), "    abcdefghijklmnop\n    qrstuvwxyz\n")
+
+	// Large buffers are not reused.
+	large := getBuffer()
+	large.Grow(maxPooledBufferSize * 2)
+	putBuffer(large)
+	buf = getBuffer()
+	assertEqual(t, buf.Len(), 0)
+	assertEqual(t, buf.Cap() <= maxPooledBufferSize, true)
+	putBuffer(buf)
+}
+
+func TestHighlight(t *testing.T) {
+	SetColor(true)
+	defer SetColor(false)
+
+	code := `for i, c := range cases { f(c, "foo", 12, nil) } // done`
+	expected := colorKeyword + "for" + colorReset + " i, c := " + colorKeyword + "range" + colorReset + " cases { f(c, " +
+		colorString + `"foo"` + colorReset + ", " + colorNumber + "12" + colorReset + ", " + colorNumber + "nil" + colorReset + ") } " +
+		colorComment + "// done" + colorReset
+	colorized := highlight(code)
+	assertEqual(t, colorized, expected)
+	assertEqual(t, visibleLen(colorized), len(code))
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PanicT is a T panicking with failure messages.
+// It's designed for assertions running outside of a test case.
+//
+// The panic value is an error with the formatted failure message.
+type PanicT struct {
+	name string
+}
+
+// NewPanicT creates a PanicT with name.
+func NewPanicT(name string) *PanicT {
+	return &PanicT{
+		name: name,
+	}
+}
+
+// Name returns the name of pt.
+func (pt *PanicT) Name() string {
+	return pt.name
+}
+
+// Helper does nothing.
+func (pt *PanicT) Helper() {}
+
+// Errorf panics with formatted message.
+func (pt *PanicT) Errorf(format string, args ...interface{}) {
+	pt.Fatalf(format, args...)
+}
+
+// Fatalf panics with formatted message.
+func (pt *PanicT) Fatalf(format string, args ...interface{}) {
+	panic(errors.New(strings.TrimPrefix(fmt.Sprintf(format, args...), "\n")))
+}
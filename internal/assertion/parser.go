@@ -9,10 +9,13 @@ import (
 	"go/token"
 	"os"
 	"path"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Parser represents a source file parser.
@@ -21,6 +24,44 @@ type Parser struct {
 
 	// Excluded call exprs should be excluded when finding assignments.
 	excluded []*ast.CallExpr
+
+	// Calls of excluded funcs should be excluded when finding assignments.
+	// It's copy-on-write so that it can be read without lock.
+	excludedFuncs map[string]struct{}
+
+	// AssignmentDepth is the max number of hops to follow when finding assignments.
+	// For instance, with depth 2, both `v3 := v2[0]` and `v2 := loadFixtures()` are
+	// reported for expr `v3`.
+	// If it's 0, the value set by SetAssignmentDepth is used.
+	AssignmentDepth int
+
+	// Source provides source code of files calling assertions.
+	// If it's nil, source code is read from file system.
+	Source SourceProvider
+}
+
+// SourceProvider returns source code of the file.
+// It's useful when source files are not available at the paths recorded in test binary.
+type SourceProvider func(filename string) ([]byte, error)
+
+var defaultAssignmentDepth int32 = 1
+
+// SetAssignmentDepth sets default max number of hops to follow when finding assignments.
+// The depth must be at least 1, which means only the last assignments are reported.
+func SetAssignmentDepth(depth int) {
+	if depth < 1 {
+		depth = 1
+	}
+
+	atomic.StoreInt32(&defaultAssignmentDepth, int32(depth))
+}
+
+func (p *Parser) depth() int {
+	if p.AssignmentDepth > 0 {
+		return p.AssignmentDepth
+	}
+
+	return int(atomic.LoadInt32(&defaultAssignmentDepth))
 }
 
 // Info represents code analysis information of an assertion function.
@@ -45,6 +86,23 @@ type Info struct {
 	// Note that, `i` is listed in related vars because of the value of `i` is assigned in
 	// `i, c := range cases` in which `c` is also assigned.
 	RelatedVars []string
+
+	// LoopVars is the list of key and value vars of range loops enclosing the caller.
+	// Loops are listed from the outermost to the innermost one.
+	// For instance, consider following code.
+	//
+	//     for i, c := range cases {
+	//         for _, v := range c.Values {
+	//             Assert(t, v > 0)
+	//         }
+	//     }
+	//
+	// After parsing `Assert`, LoopVars is `[][]string{{"i", "c"}, {"v"}}`.
+	LoopVars [][]string
+
+	// ID is the stable ID of the assertion site. See AssertionID for details.
+	// It's empty if the source of the caller is not found.
+	ID string
 }
 
 // Func represents AST information of an assertion function.
@@ -56,6 +114,18 @@ type Func struct {
 
 	Filename string
 	Line     int
+
+	// Function is the full name of the function calling assertion function,
+	// e.g. "github.com/huandu/go-assert.TestAssert.func1".
+	Function string
+
+	// entry is set if f is created from a registered index entry.
+	entry    *IndexEntry
+	argIndex []int
+
+	// file is the parsed file calling assertion function at path.
+	file *ast.File
+	path string
 }
 
 // ParseArgs parses caller's source code, finds out the right call expression by name
@@ -70,7 +140,7 @@ func (p *Parser) ParseArgs(name string, skip int, argIndex []int) (f *Func, err
 		return
 	}
 
-	filename, line, err := findCaller(skip + 1)
+	filename, line, function, err := findCaller(skip + 1)
 
 	if err != nil {
 		return
@@ -82,7 +152,19 @@ func (p *Parser) ParseArgs(name string, skip int, argIndex []int) (f *Func, err
 		name = name[dotIdx+1:]
 	}
 
-	fset, parsedAst, err := parseFile(filename)
+	if entry := lookupIndex(filename, line, name); entry != nil {
+		f = indexedFunc(entry, filename, line, argIndex)
+		f.Function = function
+		return
+	}
+
+	return p.parseCall(filename, line, function, name, argIndex)
+}
+
+// parseCall parses the file at filename and finds the call expression of name at line.
+func (p *Parser) parseCall(filename string, line int, function, name string, argIndex []int) (f *Func, err error) {
+	fset, parsedAst, err := p.parseFile(filename)
+	fullPath := filename
 	filename = path.Base(filename)
 
 	if err != nil {
@@ -91,22 +173,34 @@ func (p *Parser) ParseArgs(name string, skip int, argIndex []int) (f *Func, err
 
 	var funcDecl *ast.FuncDecl
 	var caller *ast.CallExpr
-	argExprs := make([]ast.Expr, 0, len(argIndex))
-	maxArgIdx := 0
+	var fallbackDecl *ast.FuncDecl
+	var fallback *ast.CallExpr
+	minArgs := 0
 
 	for _, idx := range argIndex {
-		if idx > maxArgIdx {
-			maxArgIdx = idx
+		if idx < 0 {
+			idx = -idx - 1
+		}
+
+		if idx+1 > minArgs {
+			minArgs = idx + 1
 		}
 	}
 
 	// Inspect AST and find target function at target line.
-	done := false
+	// Nodes not enclosing the line are skipped without inspecting their children,
+	// so that only a few nodes are visited in a huge file.
+	lineStart, lineEnd, ok := lineRange(fset, parsedAst, line)
+	done := !ok
 	ast.Inspect(parsedAst, func(node ast.Node) bool {
 		if node == nil || done {
 			return false
 		}
 
+		if node.Pos() >= lineEnd || node.End() < lineStart {
+			return false
+		}
+
 		if decl, ok := node.(*ast.FuncDecl); ok {
 			funcDecl = decl
 			return true
@@ -118,45 +212,51 @@ func (p *Parser) ParseArgs(name string, skip int, argIndex []int) (f *Func, err
 			return true
 		}
 
-		var fn string
-		switch expr := call.Fun.(type) {
-		case *ast.Ident:
-			fn = expr.Name
-		case *ast.SelectorExpr:
-			fn = expr.Sel.Name
-		}
+		if callName(call) != name {
+			// The assertion function can be called through a method value or a func var,
+			// e.g. `check := a.Assert; check(x > y)`, whose name differs from the name of the function.
+			// The outermost call at the line with enough args is the best guess.
+			if fallback == nil && len(call.Args) >= minArgs {
+				if _, ok := call.Fun.(*ast.FuncLit); !ok {
+					fallbackDecl = funcDecl
+					fallback = call
+				}
+			}
 
-		if fn != name {
 			return true
 		}
 
-		pos := fset.Position(call.Pos())
-		posEnd := fset.Position(call.End())
+		caller = call
+		done = true
+		return false
+	})
 
-		if line < pos.Line || line > posEnd.Line {
-			return true
-		}
+	if caller == nil && fallback != nil {
+		funcDecl = fallbackDecl
+		caller = fallback
+	}
 
-		caller = call
+	argExprs := make([]ast.Expr, 0, len(argIndex))
 
-		for _, idx := range argIndex {
-			if idx < 0 {
-				idx += len(call.Args)
-			}
+	for _, idx := range argIndex {
+		// Call expression is not found at the line. Keep len(Args) the same as len(argIndex).
+		if caller == nil {
+			argExprs = append(argExprs, nil)
+			continue
+		}
 
-			if idx < 0 || idx >= len(call.Args) {
-				// Ignore invalid idx.
-				argExprs = append(argExprs, nil)
-				continue
-			}
+		if idx < 0 {
+			idx += len(caller.Args)
+		}
 
-			arg := call.Args[idx]
-			argExprs = append(argExprs, arg)
+		if idx < 0 || idx >= len(caller.Args) {
+			// Ignore invalid idx.
+			argExprs = append(argExprs, nil)
+			continue
 		}
 
-		done = true
-		return false
-	})
+		argExprs = append(argExprs, caller.Args[idx])
+	}
 
 	f = &Func{
 		FileSet: fset,
@@ -166,21 +266,64 @@ func (p *Parser) ParseArgs(name string, skip int, argIndex []int) (f *Func, err
 
 		Filename: filename,
 		Line:     line,
+		Function: function,
+
+		file: parsedAst,
+		path: fullPath,
 	}
 	return
 }
 
+// lineRange returns the range of positions [start, end) at line in file.
+// It returns false if line is not in file.
+func lineRange(fset *token.FileSet, file *ast.File, line int) (start, end token.Pos, ok bool) {
+	tf := fset.File(file.Pos())
+
+	if tf == nil || line < 1 || line > tf.LineCount() {
+		return
+	}
+
+	start = tf.LineStart(line)
+	end = token.Pos(tf.Base() + tf.Size())
+
+	if line < tf.LineCount() {
+		end = tf.LineStart(line + 1)
+	}
+
+	ok = true
+	return
+}
+
+// funcExpr returns the func expr without type arguments.
+// For instance, the func expr of `assert.Type[*Foo](a, v)` is `assert.Type`.
+func funcExpr(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		return e.X
+	case *ast.IndexListExpr:
+		return e.X
+	}
+
+	return expr
+}
+
 // ParseInfo returns more context related information about this f.
 // See document of Info for details.
 func (p *Parser) ParseInfo(f *Func) (info *Info) {
+	if f.entry != nil {
+		return indexedInfo(f)
+	}
+
 	fset := f.FileSet
 	args := make([]string, 0, len(f.Args))
 	assignments := make([][]string, 0, len(f.Args))
 	relatedVars := make(map[string]struct{})
+	excluded := p.excludedExprs(f.Func)
+	helpers := p.helpers(f)
 
 	// If args contains any arg which is an ident, find out where it's assigned.
 	for _, arg := range f.Args {
-		assigns, related := findAssignments(fset, f.Func, f.Line, arg, p.excluded)
+		assigns, related := findAssignments(fset, f.Func, f.Line, arg, excluded, p.depth(), helpers)
 		args = append(args, formatNode(fset, arg))
 		assignments = append(assignments, assigns)
 
@@ -197,15 +340,61 @@ func (p *Parser) ParseInfo(f *Func) (info *Info) {
 
 	sort.Strings(vars)
 	info = &Info{
-		Source:      formatNode(fset, f.Caller),
 		Args:        args,
 		Assignments: assignments,
 		RelatedVars: vars,
 	}
+
+	// Caller is nil if the call expression is not found at the line.
+	if f.Caller != nil {
+		info.Source = formatNode(fset, f.Caller)
+		info.LoopVars = findLoopVars(f.Func, f.Caller)
+		info.ID = AssertionID(f.Function, info.Source)
+	}
+
 	return
 }
 
-func findCaller(skip int) (filename string, line int, err error) {
+// findLoopVars returns key and value vars of range loops enclosing call.
+func findLoopVars(decl *ast.FuncDecl, call *ast.CallExpr) (loopVars [][]string) {
+	if decl == nil || call == nil {
+		return
+	}
+
+	ast.Inspect(decl, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+
+		// Skip nodes not enclosing call.
+		if n.Pos() > call.Pos() || n.End() < call.End() {
+			return false
+		}
+
+		rng, ok := n.(*ast.RangeStmt)
+
+		if !ok {
+			return true
+		}
+
+		names := make([]string, 0, 2)
+
+		for _, expr := range []ast.Expr{rng.Key, rng.Value} {
+			if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+				names = append(names, ident.Name)
+			}
+		}
+
+		if len(names) > 0 {
+			loopVars = append(loopVars, names)
+		}
+
+		return true
+	})
+	return
+}
+
+func findCaller(skip int) (filename string, line int, function string, err error) {
 	const minimumSkip = 2 // Skip 2 frames running runtime functions.
 
 	pc := make([]uintptr, 1)
@@ -222,6 +411,7 @@ func findCaller(skip int) (filename string, line int, err error) {
 	frame, _ := frames.Next()
 	filename = frame.File
 	line = frame.Line
+	function = frame.Function
 
 	if filename == "" || line == 0 {
 		err = fmt.Errorf("fail to read source code information")
@@ -230,9 +420,15 @@ func findCaller(skip int) (filename string, line int, err error) {
 	return
 }
 
+// fileAST is a parsed file in cache.
+// The mod time and size of the file are recorded so that changed files are parsed again.
+// If the file is provided by a SourceProvider, the source is recorded instead.
 type fileAST struct {
 	FileSet *token.FileSet
 	File    *ast.File
+	ModTime time.Time
+	Size    int64
+	Source  []byte
 }
 
 var (
@@ -240,17 +436,52 @@ var (
 	fileCache     = map[string]*fileAST{}
 )
 
-func parseFile(filename string) (fset *token.FileSet, f *ast.File, err error) {
+func (p *Parser) parseFile(filename string) (fset *token.FileSet, f *ast.File, err error) {
+	if p.Source == nil {
+		return parseFile(filename)
+	}
+
+	src, err := p.Source(filename)
+
+	if err != nil {
+		return
+	}
+
+	return parseSource(filename, src)
+}
+
+// parseSource parses src of the file at filename.
+// Parsed files share the cache with files read from file system,
+// so that every file is parsed once no matter how many parsers use it.
+func parseSource(filename string, src []byte) (fset *token.FileSet, f *ast.File, err error) {
 	fileCacheLock.Lock()
 	fa, ok := fileCache[filename]
 	fileCacheLock.Unlock()
 
-	if ok {
+	if ok && fa.Source != nil && bytes.Equal(fa.Source, src) {
 		fset = fa.FileSet
 		f = fa.File
 		return
 	}
 
+	fset = token.NewFileSet()
+	f, err = parser.ParseFile(fset, filename, src, 0)
+
+	if err != nil {
+		return
+	}
+
+	fileCacheLock.Lock()
+	fileCache[filename] = &fileAST{
+		FileSet: fset,
+		File:    f,
+		Source:  append([]byte(nil), src...), // The src can be reused by the provider.
+	}
+	fileCacheLock.Unlock()
+	return
+}
+
+func parseFile(filename string) (fset *token.FileSet, f *ast.File, err error) {
 	file, err := os.Open(filename)
 
 	if err != nil {
@@ -258,18 +489,61 @@ func parseFile(filename string) (fset *token.FileSet, f *ast.File, err error) {
 	}
 
 	defer file.Close()
+	stat, err := file.Stat()
+
+	if err != nil {
+		return
+	}
+
+	fileCacheLock.Lock()
+	fa, ok := fileCache[filename]
+	fileCacheLock.Unlock()
+
+	// Files changed on disk since they were parsed are parsed again.
+	if ok && fa.Source == nil && fa.ModTime.Equal(stat.ModTime()) && fa.Size == stat.Size() {
+		fset = fa.FileSet
+		f = fa.File
+		return
+	}
+
 	fset = token.NewFileSet()
 	f, err = parser.ParseFile(fset, filename, file, 0)
 
+	if err != nil {
+		return
+	}
+
 	fileCacheLock.Lock()
 	fileCache[filename] = &fileAST{
 		FileSet: fset,
 		File:    f,
+		ModTime: stat.ModTime(),
+		Size:    stat.Size(),
 	}
 	fileCacheLock.Unlock()
 	return
 }
 
+// PurgeFile removes the parsed file at filename from cache, so that it's parsed again when it's used.
+// Changed files are detected by mod time and size automatically.
+// PurgeFile is necessary only if a file can change without changing them,
+// e.g. it's rewritten with the same size within the precision of mod time.
+//
+// The filename must be the same as the path recorded in the binary, which is usually an absolute path.
+// A relative filename is resolved against the working directory.
+func PurgeFile(filename string) {
+	if !filepath.IsAbs(filename) {
+		if abs, err := filepath.Abs(filename); err == nil {
+			filename = abs
+		}
+	}
+
+	fileCacheLock.Lock()
+	defer fileCacheLock.Unlock()
+	delete(fileCache, filename)
+	delete(fileCache, filepath.ToSlash(filename))
+}
+
 func formatNode(fset *token.FileSet, node ast.Node) string {
 	if node == nil {
 		return ""
@@ -284,11 +558,17 @@ func formatNode(fset *token.FileSet, node ast.Node) string {
 	return buf.String()
 }
 
-func findAssignments(fset *token.FileSet, decl *ast.FuncDecl, line int, arg ast.Expr, excluded []*ast.CallExpr) (assignments []string, relatedVars map[string]struct{}) {
+// findAssignments finds the last assignments of vars referenced in arg before line.
+// Return statements of helpers called in assignments are inlined if helpers is not nil.
+func findAssignments(fset *token.FileSet, decl *ast.FuncDecl, line int, arg ast.Expr, excluded []*ast.CallExpr, depth int, helpers *helperFinder) (assignments []string, relatedVars map[string]struct{}) {
 	if decl == nil || arg == nil {
 		return
 	}
 
+	if depth < 1 {
+		depth = 1
+	}
+
 	src := formatNode(fset, arg)
 	exprs := findRelatedExprs(fset, arg)
 
@@ -298,84 +578,43 @@ func findAssignments(fset *token.FileSet, decl *ast.FuncDecl, line int, arg ast.
 
 	assignmentStmts := make(map[ast.Stmt]struct{})
 
-	for _, expr := range exprs {
-		// Find the last assignment for ident.
-		var stmt, lastStmt ast.Stmt
-		done := false
-		ast.Inspect(decl, func(n ast.Node) bool {
-			if n == nil || done {
-				return false
-			}
+	// Find the last assignment for each expr.
+	// If depth is larger than 1, find assignments to vars referenced in assignments recursively.
+	type tracedExpr struct {
+		Expr ast.Expr
+		Line int
+	}
+	traced := make([]tracedExpr, 0, len(exprs))
 
-			if pos := fset.Position(n.Pos()); pos.Line >= line {
-				done = true
-				return false
-			}
+	for _, expr := range exprs {
+		traced = append(traced, tracedExpr{Expr: expr, Line: line})
+	}
 
-			if node, ok := n.(ast.Stmt); ok {
-				stmt = node
-			}
+	for d := 0; d < depth && len(traced) > 0; d++ {
+		next := make([]tracedExpr, 0)
 
-			switch node := n.(type) {
-			case *ast.AssignStmt:
-				for _, left := range node.Lhs {
-					switch n := left.(type) {
-					case *ast.Ident:
-						if isRelated(fset, expr, n) {
-							lastStmt = stmt
-							return true
-						}
-					}
-				}
-			case *ast.RangeStmt:
-				if node.Key == nil {
-					return true
-				}
+		for _, t := range traced {
+			stmt := findLastAssignment(fset, decl, t.Line, t.Expr, excluded)
 
-				switch n := node.Key.(type) {
-				case *ast.Ident:
-					if isRelated(fset, expr, n) {
-						lastStmt = stmt
-						return true
-					}
-				}
+			if stmt == nil {
+				continue
+			}
 
-				if node.Value == nil {
-					return true
-				}
+			if _, ok := assignmentStmts[stmt]; ok {
+				continue
+			}
 
-				switch n := node.Value.(type) {
-				case *ast.Ident:
-					if isRelated(fset, expr, n) {
-						lastStmt = stmt
-						return true
-					}
-				}
-			case *ast.CallExpr:
-				for _, call := range excluded {
-					if node.Pos() == call.Pos() {
-						return false
-					}
-				}
+			assignmentStmts[stmt] = struct{}{}
+			stmtLine := fset.Position(stmt.Pos()).Line
 
-				for _, arg := range node.Args {
-					switch n := arg.(type) {
-					case *ast.UnaryExpr:
-						// Treat `&a` as a kind of assignment to `a`.
-						if n.Op == token.AND && isRelated(fset, expr, n.X) {
-							lastStmt = stmt
-							return true
-						}
-					}
+			for _, src := range assignmentSources(stmt) {
+				for _, expr := range findRelatedExprs(fset, src) {
+					next = append(next, tracedExpr{Expr: expr, Line: stmtLine})
 				}
 			}
-
-			return true
-		})
-
-		if lastStmt != nil {
-			assignmentStmts[lastStmt] = struct{}{}
 		}
+
+		traced = next
 	}
 
 	// Collect all stmts and exprs to find out related vars.
@@ -434,12 +673,114 @@ func findAssignments(fset *token.FileSet, decl *ast.FuncDecl, line int, arg ast.
 			code = code[rng.Key.Pos()-start : rng.X.End()-start]
 		}
 
-		assignments = append(assignments, code)
+		assignments = append(assignments, code+helpers.inline(stmt))
+	}
+
+	return
+}
+
+// findLastAssignment finds the last statement assigning expr before line.
+func findLastAssignment(fset *token.FileSet, decl *ast.FuncDecl, line int, expr ast.Expr, excluded []*ast.CallExpr) (lastStmt ast.Stmt) {
+	tf := fset.File(decl.Pos())
+
+	if tf == nil || line < 1 || line > tf.LineCount() {
+		return
 	}
 
+	// Positions are compared instead of line numbers, which are expensive to compute for every node.
+	// The source of expr is formatted once and compared with every assigned var.
+	lineStart := tf.LineStart(line)
+	src := formatNode(fset, derefExpr(expr))
+	var stmt ast.Stmt
+	done := false
+	ast.Inspect(decl, func(n ast.Node) bool {
+		if n == nil || done {
+			return false
+		}
+
+		if n.Pos() >= lineStart {
+			done = true
+			return false
+		}
+
+		if node, ok := n.(ast.Stmt); ok {
+			stmt = node
+		}
+
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, left := range node.Lhs {
+				switch n := left.(type) {
+				case *ast.Ident:
+					if isRelated(fset, expr, src, n) {
+						lastStmt = stmt
+						return true
+					}
+				}
+			}
+		case *ast.RangeStmt:
+			if node.Key == nil {
+				return true
+			}
+
+			switch n := node.Key.(type) {
+			case *ast.Ident:
+				if isRelated(fset, expr, src, n) {
+					lastStmt = stmt
+					return true
+				}
+			}
+
+			if node.Value == nil {
+				return true
+			}
+
+			switch n := node.Value.(type) {
+			case *ast.Ident:
+				if isRelated(fset, expr, src, n) {
+					lastStmt = stmt
+					return true
+				}
+			}
+		case *ast.CallExpr:
+			for _, call := range excluded {
+				if node.Pos() == call.Pos() {
+					return false
+				}
+			}
+
+			for _, arg := range node.Args {
+				switch n := arg.(type) {
+				case *ast.UnaryExpr:
+					// Treat `&a` as a kind of assignment to `a`.
+					if n.Op == token.AND && isRelated(fset, expr, src, n.X) {
+						lastStmt = stmt
+						return true
+					}
+				}
+			}
+		}
+
+		return true
+	})
+
 	return
 }
 
+// assignmentSources returns exprs providing values in an assignment stmt.
+func assignmentSources(stmt ast.Stmt) []ast.Expr {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		return s.Rhs
+	case *ast.RangeStmt:
+		return []ast.Expr{s.X}
+	case *ast.ExprStmt:
+		return []ast.Expr{s.X}
+	}
+
+	return nil
+}
+
 type sortByStmts []ast.Stmt
 
 func (stmts sortByStmts) Len() int           { return len(stmts) }
@@ -491,6 +832,23 @@ func (v *exprVisitor) Visit(n ast.Node) (w ast.Visitor) {
 		// Never walk node.Sel.
 		ast.Walk(v, node.X)
 		return nil
+	case *ast.IndexExpr:
+		// Track `items[0]` and `m["key"]` so that the element is printed along with the container.
+		// The container and idents in the index are still walked.
+		if isIndexedVar(node) {
+			v.Related[node] = struct{}{}
+		}
+	case *ast.StarExpr:
+		// Track `*p` so that the value pointed by p is printed instead of p.
+		if isDerefVar(node) {
+			v.Related[node] = struct{}{}
+			return nil
+		}
+	case *ast.CallExpr:
+		// Track getters like `cfg.Timeout()`. They are called only if it's enabled in options.
+		if isGetterCall(node) {
+			v.Related[node] = struct{}{}
+		}
 	case *ast.Ident:
 		v.Related[node] = struct{}{}
 		return nil
@@ -537,7 +895,8 @@ func IsVar(expr ast.Expr) bool {
 }
 
 // isRelated returns true, if target is the same as expr or "parent" of expr.
-func isRelated(fset *token.FileSet, expr, target ast.Expr) bool {
+// The src is the source of expr without leading `*`, as `*p` is assigned by assigning p.
+func isRelated(fset *token.FileSet, expr ast.Expr, src string, target ast.Expr) bool {
 	if expr == target {
 		return true
 	}
@@ -549,20 +908,34 @@ func isRelated(fset *token.FileSet, expr, target ast.Expr) bool {
 	// target must be a selector or ident.
 	switch n := target.(type) {
 	case *ast.SelectorExpr:
-		if _, ok := expr.(*ast.Ident); ok {
+		if _, ok := derefExpr(expr).(*ast.Ident); ok {
 			return false
 		}
 
-		return IsIncluded(formatNode(fset, n), formatNode(fset, expr))
+		return IsIncluded(formatNode(fset, n), src)
 	case *ast.Ident:
-		return IsIncluded(n.Name, formatNode(fset, expr))
+		return IsIncluded(n.Name, src)
 	}
 
 	return false
 }
 
+// derefExpr returns expr without leading `*`.
+func derefExpr(expr ast.Expr) ast.Expr {
+	for {
+		star, ok := expr.(*ast.StarExpr)
+
+		if !ok {
+			return expr
+		}
+
+		expr = star.X
+	}
+}
+
 // IsIncluded checks whether child var is a children of parent var.
 // Regarding the child var `a.b.c`, it's the children of `a`, `a.b` and `a.b.c`.
+// Elements are children of their containers, e.g. `a.b[0]` is a children of `a.b`.
 func IsIncluded(parent, child string) bool {
 	if len(child) < len(parent) {
 		return false
@@ -572,7 +945,7 @@ func IsIncluded(parent, child string) bool {
 		return true
 	}
 
-	if strings.HasPrefix(child, parent) && child[len(parent)] == '.' {
+	if strings.HasPrefix(child, parent) && (child[len(parent)] == '.' || child[len(parent)] == '[') {
 		return true
 	}
 
@@ -586,3 +959,48 @@ func (p *Parser) AddExcluded(expr *ast.CallExpr) {
 	defer p.m.Unlock()
 	p.excluded = append(p.excluded, expr)
 }
+
+// AddExcludedFunc adds names of funcs whose calls will not be inspected when finding related assignments,
+// e.g. a logging func taking `&v` which doesn't assign v.
+// A name matches both funcs and methods, e.g. "Log" matches `Log(&v)` and `t.Log(&v)`.
+func (p *Parser) AddExcludedFunc(names ...string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	funcs := make(map[string]struct{}, len(p.excludedFuncs)+len(names))
+
+	for name := range p.excludedFuncs {
+		funcs[name] = struct{}{}
+	}
+
+	for _, name := range names {
+		funcs[name] = struct{}{}
+	}
+
+	p.excludedFuncs = funcs
+}
+
+// excludedExprs returns a snapshot of excluded exprs
+// including calls of excluded funcs in decl.
+func (p *Parser) excludedExprs(decl *ast.FuncDecl) []*ast.CallExpr {
+	p.m.Lock()
+	excluded := p.excluded
+	funcs := p.excludedFuncs
+	p.m.Unlock()
+
+	if len(funcs) == 0 || decl == nil {
+		return excluded
+	}
+
+	excluded = excluded[:len(excluded):len(excluded)]
+	ast.Inspect(decl, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if _, ok := funcs[callName(call)]; ok {
+				excluded = append(excluded, call)
+			}
+		}
+
+		return true
+	})
+	return excluded
+}
@@ -4,7 +4,13 @@
 package assertion
 
 import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func assertEqual(t *testing.T, v1, v2 interface{}) {
@@ -59,6 +65,68 @@ func TestParseFalseKind(t *testing.T) {
 	}
 }
 
+func TestRegisterFalsy(t *testing.T) {
+	type nullString struct {
+		String string
+		Valid  bool
+	}
+
+	remove := RegisterFalsy(func(v interface{}) (FalseKind, bool) {
+		if s, ok := v.(nullString); ok && !s.Valid {
+			return Falsy, true
+		}
+
+		return Positive, false
+	})
+	removeZero := RegisterFalsy(func(v interface{}) (FalseKind, bool) {
+		if n, ok := v.(int); ok && n == 0 {
+			return Positive, true
+		}
+
+		return Positive, false
+	})
+
+	assertEqual(t, ParseFalseKind(nullString{}), Falsy)
+	assertEqual(t, ParseFalseKind(nullString{Valid: true}), Positive)
+	assertEqual(t, ParseFalseKind(0), Positive)
+	assertEqual(t, ParseFalseKind(""), EmptyString)
+	assertEqual(t, ParseFalseKind(nil), Nil)
+
+	removeZero()
+	assertEqual(t, ParseFalseKind(0), Zero)
+	assertEqual(t, ParseFalseKind(nullString{}), Falsy)
+
+	remove()
+	assertEqual(t, ParseFalseKind(nullString{}), Positive)
+}
+
+func assertConditions(t T, exprs ...interface{}) {
+	AssertConditions(t, exprs, &Trigger{
+		FuncName: "assertConditions",
+		Skip:     1,
+		Args:     []int{1},
+	})
+}
+
+func TestAssertConditions(t *testing.T) {
+	ft := NewFakeT("TestAssertConditions")
+	x, y := 1, 2
+	var p *int
+	conds := []interface{}{x > y, true}
+
+	assertConditions(ft, x < y, 1, "a")
+	assertEqual(t, len(ft.Messages()), 0)
+
+	name := ""
+	assertConditions(ft, x > y, x, p, name)
+	assertConditions(ft, conds...)
+	msgs := ft.Messages()
+
+	assertEqual(t, len(msgs), 2)
+	assertEqual(t, strings.Contains(msgs[0], "3 of 4 conditions are false:\n[1] x > y\n    x, y := 1, 2\n[3] p != nil\n[4] name != \"\"\n    name := \"\""), true)
+	assertEqual(t, strings.Contains(msgs[1], "1 of 2 conditions are false:\n[1] (bool)false\n"), true)
+}
+
 func TestParseArgs(t *testing.T) {
 	cases := []struct {
 		ArgIndex    []int
@@ -114,6 +182,157 @@ func TestParseArgs(t *testing.T) {
 			assertEqual(t, info.Args, c.Args)
 			assertEqual(t, info.Assignments, c.Assignments)
 			assertEqual(t, info.RelatedVars, c.RelatedVars)
+			assertEqual(t, info.LoopVars, [][]string{{"i", "c"}})
 		}
 	}
 }
+
+func TestAssignmentDepth(t *testing.T) {
+	cases := []struct {
+		Depth       int
+		Assignments []string
+	}{
+		{0, []string{`v3 := v2[0]`}},
+		{1, []string{`v3 := v2[0]`}},
+		{2, []string{`v2 := v1`, `v3 := v2[0]`}},
+		{3, []string{`v1 := []string{"foo"}`, `v2 := v1`, `v3 := v2[0]`}},
+	}
+
+	for i, c := range cases {
+		t.Logf("case %v: %v", i, c)
+		p := &Parser{AssignmentDepth: c.Depth}
+		parse := func(args ...interface{}) (*Func, error) {
+			return p.ParseArgs("parse", 1, []int{0})
+		}
+
+		v1 := []string{"foo"}
+		v2 := v1
+		v3 := v2[0]
+		f, err := parse(v3)
+		assertEqual(t, err, nil)
+
+		info := p.ParseInfo(f)
+		assertEqual(t, info.Assignments[0], c.Assignments)
+	}
+}
+
+type methodParser struct {
+	p *Parser
+}
+
+func (mp *methodParser) Check(args ...interface{}) (*Func, error) {
+	return mp.p.ParseArgs("Assert", 1, []int{0})
+}
+
+type embeddedParser struct {
+	*methodParser
+}
+
+func TestParseArgsIndirectCall(t *testing.T) {
+	mp := &methodParser{p: new(Parser)}
+	x, y := 1, 2
+
+	check := mp.Check
+	f, err := check(x > y)
+	assertEqual(t, err, nil)
+	assertEqual(t, mp.p.ParseInfo(f).Args, []string{`x > y`})
+
+	ep := embeddedParser{mp}
+	f, err = ep.Check(x, y)
+	assertEqual(t, err, nil)
+	assertEqual(t, mp.p.ParseInfo(f).Args, []string{`x`})
+
+	// Calls without enough args are not chosen.
+	f, err = mp.p.ParseArgs("Assert", 0, []int{3})
+	assertEqual(t, err, nil)
+	assertEqual(t, f.Caller, (*ast.CallExpr)(nil))
+}
+
+func TestAddExcludedFunc(t *testing.T) {
+	p := new(Parser)
+	parse := func(args ...interface{}) (*Func, error) {
+		return p.ParseArgs("parse", 1, []int{0})
+	}
+	logValue := func(v *int) {}
+
+	v := 1
+	logValue(&v)
+	f, err := parse(v)
+	assertEqual(t, err, nil)
+	assertEqual(t, p.ParseInfo(f).Assignments[0], []string{`logValue(&v)`})
+
+	p.AddExcludedFunc("logValue")
+	f, err = parse(v)
+	assertEqual(t, err, nil)
+	assertEqual(t, p.ParseInfo(f).Assignments[0], []string{`v := 1`})
+}
+
+func TestParseFileCache(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cache_test.go")
+	write := func(src string, modTime time.Time) {
+		assertEqual(t, os.WriteFile(filename, []byte(src), 0644), nil)
+		assertEqual(t, os.Chtimes(filename, modTime, modTime), nil)
+	}
+	pkgName := func() string {
+		_, f, err := parseFile(filename)
+		assertEqual(t, err, nil)
+		return f.Name.Name
+	}
+	now := time.Now()
+
+	write("package foo\n", now)
+	assertEqual(t, pkgName(), "foo")
+
+	// Changed file is parsed again.
+	write("package bar\n", now.Add(time.Second))
+	assertEqual(t, pkgName(), "bar")
+
+	// Changes with the same mod time and size are invisible until the file is purged.
+	write("package baz\n", now.Add(time.Second))
+	assertEqual(t, pkgName(), "bar")
+	PurgeFile(filename)
+	assertEqual(t, pkgName(), "baz")
+
+	// Files with syntax errors are not cached.
+	write("package\n", now.Add(2*time.Second))
+	_, _, err := parseFile(filename)
+	assertEqual(t, err != nil, true)
+
+	// Cache is safe for concurrent use.
+	write("package foo\n", now.Add(3*time.Second))
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			parseFile(filename)
+			PurgeFile(filename)
+		}()
+	}
+
+	wg.Wait()
+	assertEqual(t, pkgName(), "foo")
+}
+
+func TestParseSourceCache(t *testing.T) {
+	src := []byte("package foo\n")
+	p := &Parser{
+		Source: func(filename string) ([]byte, error) {
+			return src, nil
+		},
+	}
+	_, f1, err := p.parseFile("/path/to/source_cache_test.go")
+	assertEqual(t, err, nil)
+
+	// Parsers share the cache.
+	_, f2, err := parseSource("/path/to/source_cache_test.go", []byte("package foo\n"))
+	assertEqual(t, err, nil)
+	assertEqual(t, f1 == f2, true)
+
+	// Changed source is parsed again.
+	copy(src, "package bar\n")
+	_, f3, err := p.parseFile("/path/to/source_cache_test.go")
+	assertEqual(t, err, nil)
+	assertEqual(t, f3.Name.Name, "bar")
+}
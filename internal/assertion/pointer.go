@@ -0,0 +1,166 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AssertPointerEqual expects got equals want, except that nil pointers in want match anything,
+// so that want can be a partially specified object graph.
+// Pointers are compared by their targets.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+func AssertPointerEqual(t T, got, want interface{}, trigger *Trigger) {
+	t.Helper()
+	got, want = prepareCompared(got, want, trigger.Options)
+	got = ignoreNilPointers(got, want)
+
+	if deepEqual(got, want) {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	dumper := trigger.dumper()
+	gotDump := dumper.Dump(got)
+	wantDump := dumper.Dump(want)
+	values := ""
+
+	if DiffEnabled() {
+		values = formatValuesDiff(msgs, dumper, got, want, trigger.Options.Unexported)
+	}
+
+	if values == "" {
+		values = fmt.Sprintf("%v\n[1] -> %v\n[2] -> %v", msgs.Values, gotDump, wantDump)
+	}
+
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n[1] %v%v\n[2] %v%v\n%v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4), msgs.ShouldPointerEqual,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			indentCode(info.Args[1], 4), indentAssignments(info.Assignments[1], 4),
+			values, formatVars(msgs, info, trigger),
+		),
+		Values: []string{gotDump, wantDump},
+	})
+}
+
+// ignoreNilPointers returns a copy of got in which pointers are set to nil
+// if corresponding pointers in want are nil, so that they are not compared.
+// Parts of got which don't match want in structure are kept as is.
+func ignoreNilPointers(got, want interface{}) interface{} {
+	if got == nil || want == nil {
+		return got
+	}
+
+	ig := &nilPointerIgnorer{
+		copied: map[[2]uintptr]reflect.Value{},
+	}
+	return ig.copy(reflect.ValueOf(got), reflect.ValueOf(want)).Interface()
+}
+
+type nilPointerIgnorer struct {
+	copied map[[2]uintptr]reflect.Value // Copied pointers of got and want to keep cycles.
+}
+
+func (ig *nilPointerIgnorer) copy(got, want reflect.Value) reflect.Value {
+	if !want.IsValid() || got.Type() != want.Type() || findComparator(got.Type()) != nil {
+		return got
+	}
+
+	switch got.Kind() {
+	case reflect.Ptr:
+		if want.IsNil() {
+			return reflect.Zero(got.Type())
+		}
+
+		if got.IsNil() {
+			return got
+		}
+
+		key := [2]uintptr{got.Pointer(), want.Pointer()}
+
+		if copied, ok := ig.copied[key]; ok {
+			return copied
+		}
+
+		ptr := reflect.New(got.Type().Elem())
+		ig.copied[key] = ptr
+		ptr.Elem().Set(ig.copy(got.Elem(), want.Elem()))
+		return ptr
+
+	case reflect.Interface:
+		if got.IsNil() || want.IsNil() {
+			return got
+		}
+
+		iface := reflect.New(got.Type()).Elem()
+		iface.Set(ig.copy(got.Elem(), want.Elem()))
+		return iface
+
+	case reflect.Struct:
+		st := reflect.New(got.Type()).Elem()
+		st.Set(got)
+
+		// Unexported fields are copied as they are.
+		for i := 0; i < got.NumField(); i++ {
+			if got.Type().Field(i).PkgPath == "" {
+				st.Field(i).Set(ig.copy(got.Field(i), want.Field(i)))
+			}
+		}
+
+		return st
+
+	case reflect.Map:
+		if got.IsNil() || want.IsNil() {
+			return got
+		}
+
+		m := reflect.MakeMapWithSize(got.Type(), got.Len())
+		iter := got.MapRange()
+
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), ig.copy(iter.Value(), want.MapIndex(iter.Key())))
+		}
+
+		return m
+
+	case reflect.Array:
+		arr := reflect.New(got.Type()).Elem()
+
+		for i := 0; i < got.Len(); i++ {
+			arr.Index(i).Set(ig.copy(got.Index(i), want.Index(i)))
+		}
+
+		return arr
+
+	case reflect.Slice:
+		if got.IsNil() || got.Len() != want.Len() {
+			return got
+		}
+
+		slice := reflect.MakeSlice(got.Type(), got.Len(), got.Len())
+
+		for i := 0; i < got.Len(); i++ {
+			slice.Index(i).Set(ig.copy(got.Index(i), want.Index(i)))
+		}
+
+		return slice
+	}
+
+	return got
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+type pointerNode struct {
+	Name     string
+	Parent   *pointerNode
+	Children []*pointerNode
+	Meta     map[string]*string
+}
+
+func TestAssertPointerEqual(t *testing.T) {
+	foo, bar := "foo", "bar"
+	root := &pointerNode{Name: "root"}
+	child := &pointerNode{Name: "child", Parent: root, Meta: map[string]*string{"k": &foo}}
+	root.Children = []*pointerNode{child}
+
+	ft := NewFakeT("TestAssertPointerEqual")
+	trigger := &Trigger{
+		FuncName: "AssertPointerEqual",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true},
+	}
+
+	// Nil pointers in want match anything.
+	AssertPointerEqual(ft, child, &pointerNode{Name: "child", Children: nil, Meta: map[string]*string{"k": nil}}, trigger)
+	AssertPointerEqual(ft, root, &pointerNode{Name: "root", Children: []*pointerNode{{Name: "child", Meta: map[string]*string{"k": &foo}}}}, trigger)
+	AssertPointerEqual(ft, child, (*pointerNode)(nil), trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	// Non-nil pointers are compared by their targets.
+	AssertPointerEqual(ft, child, &pointerNode{Name: "child", Meta: map[string]*string{"k": &bar}}, trigger)
+	AssertPointerEqual(ft, &pointerNode{Name: "orphan"}, &pointerNode{Name: "orphan", Parent: root}, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 2)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldPointerEqual), true)
+	assertEqual(t, strings.Contains(msgs[0], `.Meta["k"]:`), true)
+	assertEqual(t, strings.Contains(msgs[1], ".Parent"), true)
+
+	// The compared value is not modified.
+	assertEqual(t, child.Parent, root)
+}
+
+func TestIgnoreNilPointersCycle(t *testing.T) {
+	got := &pointerNode{Name: "a"}
+	got.Parent = got
+	want := &pointerNode{Name: "a"}
+	want.Parent = want
+
+	copied := ignoreNilPointers(got, want).(*pointerNode)
+	assertEqual(t, copied.Parent == copied, true)
+	assertEqual(t, copied != got, true)
+}
@@ -0,0 +1,109 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"reflect"
+)
+
+var errInvalidPredicate = errors.New("go-assert: predicate must be a func(v V) bool accepting the value")
+
+// AssertSatisfies expects pred returns true for v.
+// The pred must be a `func(v V) bool` where v is assignable to V.
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with the source code of pred and the dump of v.
+func AssertSatisfies(t T, v, pred interface{}, trigger *Trigger) {
+	t.Helper()
+	val := reflect.ValueOf(v)
+	p, err := predicate(pred, reflect.TypeOf(v))
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	// A nil v is passed to pred as a nil V.
+	if !val.IsValid() {
+		val = reflect.Zero(p.Type().In(0))
+	}
+
+	if callPredicate(p, val) {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	msgs := CurrentMessages()
+	vDump := trigger.dumper().Dump(v)
+	predAssignments := info.Assignments[1]
+
+	// Vars in a func literal are its own params and locals, not assigned outside.
+	if _, ok := f.Args[1].(*ast.FuncLit); ok {
+		predAssignments = nil
+	}
+
+	fail(t, trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n    %v%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, msgs.ShouldSatisfy,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.Predicate,
+			indentCode(info.Args[1], 4), indentAssignments(predAssignments, 4),
+			msgs.Value, vDump, formatVars(msgs, info, trigger),
+		),
+		Values: []string{vDump},
+	})
+}
+
+// predicate validates pred is a `func(v V) bool` accepting values of typ.
+// If typ is nil, V must be a type accepting nil.
+func predicate(pred interface{}, typ reflect.Type) (p reflect.Value, err error) {
+	p = reflect.ValueOf(pred)
+
+	if p.Kind() != reflect.Func || p.IsNil() {
+		err = errInvalidPredicate
+		return
+	}
+
+	pt := p.Type()
+
+	if pt.NumIn() != 1 || pt.IsVariadic() || pt.NumOut() != 1 || pt.Out(0).Kind() != reflect.Bool {
+		err = errInvalidPredicate
+		return
+	}
+
+	if typ == nil && !isNilable(pt.In(0)) || typ != nil && !typ.AssignableTo(pt.In(0)) {
+		err = errInvalidPredicate
+	}
+
+	return
+}
+
+// callPredicate calls p with v and returns the result.
+func callPredicate(p, v reflect.Value) bool {
+	return p.Call([]reflect.Value{v})[0].Bool()
+}
+
+// isNilable returns true if values of typ can be nil.
+func isNilable(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Interface, reflect.Chan, reflect.Func, reflect.Slice, reflect.Map, reflect.Ptr, reflect.UnsafePointer:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAssertSatisfies(t *testing.T) {
+	ft := NewFakeT("TestAssertSatisfies")
+	trigger := &Trigger{
+		FuncName: "AssertSatisfies",
+		Args:     []int{1, 2},
+	}
+	positive := func(v int) bool {
+		return v > 0
+	}
+
+	AssertSatisfies(ft, 1, positive, trigger)
+	AssertSatisfies(ft, nil, func(err error) bool { return err == nil }, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertSatisfies(ft, -1, positive, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldSatisfy), true)
+	assertEqual(t, strings.Contains(msgs[0], "Predicate:\n    positive\n    positive := func(v int) bool {\n        return v > 0\n    }"), true)
+	assertEqual(t, strings.Contains(msgs[0], "Value:\n    (int)-1"), true)
+
+	ft.Reset()
+	err := errors.New("foo")
+	AssertSatisfies(ft, err, func(err error) bool { return err == nil }, trigger)
+	msgs = ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "Predicate:\n    func(err error) bool { return err == nil }\nValue:"), true)
+}
+
+func TestPredicateInvalidArgs(t *testing.T) {
+	ft := NewFakeT("TestPredicateInvalidArgs")
+	trigger := &Trigger{
+		FuncName: "AssertSatisfies",
+		Args:     []int{1, 2},
+	}
+	cases := []struct {
+		Value interface{}
+		Pred  interface{}
+	}{
+		{1, nil},
+		{1, func(string) bool { return true }},
+		{1, func(int, int) bool { return true }},
+		{1, func(int) {}},
+		{nil, func(int) bool { return true }},
+	}
+
+	for _, c := range cases {
+		ft.Reset()
+		AssertSatisfies(ft, c.Value, c.Pred, trigger)
+		msgs := ft.Messages()
+		assertEqual(t, len(msgs), 1)
+		assertEqual(t, strings.Contains(msgs[0], errInvalidPredicate.Error()), true)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+	"sync"
+)
+
+// ProfileKind is a bit set of profiles to capture on failure.
+type ProfileKind int
+
+// Valid profile kinds.
+const (
+	ProfileGoroutine ProfileKind = 1 << iota // Stacks of all goroutines.
+	ProfileHeap                              // Heap profile.
+	ProfileTrace                             // Execution trace since CaptureProfiles is called.
+)
+
+// CaptureProfiles registers a failure hook which writes profiles set in kinds
+// on the first failure of every test.
+// Profiles are written to the directory set by `-test.outputdir` or current working directory.
+// Paths of profiles are appended to the failure message.
+//
+// If ProfileTrace is set, execution trace starts immediately and stops on the first failure.
+// The trace is ignored if another trace is running.
+//
+// Call stop to unregister the hook and stop the trace.
+func CaptureProfiles(kinds ProfileKind) (stop func()) {
+	c := &profileCapturer{
+		kinds:  kinds,
+		failed: map[string]struct{}{},
+	}
+
+	if kinds&ProfileTrace != 0 {
+		c.trace = &bytes.Buffer{}
+
+		if err := trace.Start(c.trace); err != nil {
+			c.trace = nil
+		}
+	}
+
+	remove := AddFailureHook(c.capture)
+	return func() {
+		remove()
+		c.stopTrace()
+	}
+}
+
+type profileCapturer struct {
+	m      sync.Mutex
+	kinds  ProfileKind
+	failed map[string]struct{}
+	trace  *bytes.Buffer
+}
+
+func (c *profileCapturer) capture(f *Failure) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if _, ok := c.failed[f.TestName]; ok {
+		return
+	}
+
+	c.failed[f.TestName] = struct{}{}
+	dir := outputDir()
+	prefix := filepath.Join(dir, sanitizeFilename(f.TestName))
+	files := make([]string, 0, 3)
+	errs := make([]string, 0)
+	write := func(filename string, fn func(file *os.File) error) {
+		file, err := os.Create(filename)
+
+		if err == nil {
+			err = fn(file)
+
+			if e := file.Close(); err == nil {
+				err = e
+			}
+		}
+
+		if err != nil {
+			errs = append(errs, err.Error())
+			return
+		}
+
+		files = append(files, filename)
+	}
+
+	if c.kinds&ProfileGoroutine != 0 {
+		write(prefix+".goroutine.pprof", func(file *os.File) error {
+			return pprof.Lookup("goroutine").WriteTo(file, 0)
+		})
+	}
+
+	if c.kinds&ProfileHeap != 0 {
+		runtime.GC()
+		write(prefix+".heap.pprof", func(file *os.File) error {
+			return pprof.Lookup("heap").WriteTo(file, 0)
+		})
+	}
+
+	if c.trace != nil {
+		trace.Stop()
+		data := c.trace.Bytes()
+		c.trace = nil
+		write(prefix+".trace.out", func(file *os.File) error {
+			_, err := file.Write(data)
+			return err
+		})
+	}
+
+	if len(files) == 0 && len(errs) == 0 {
+		return
+	}
+
+	msgs := CurrentMessages()
+	lines := make([]string, 0, len(files)+len(errs)+1)
+	lines = append(lines, "\n"+msgs.Profiles)
+
+	for _, file := range files {
+		lines = append(lines, "    "+file)
+	}
+
+	for _, err := range errs {
+		lines = append(lines, "    "+fmt.Sprintf(msgs.ProfileErrorFormat, err))
+	}
+
+	f.Message += strings.Join(lines, "\n")
+}
+
+func (c *profileCapturer) stopTrace() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.trace != nil {
+		trace.Stop()
+		c.trace = nil
+	}
+}
+
+// outputDir returns the value of `-test.outputdir` or current working directory.
+func outputDir() string {
+	if f := flag.Lookup("test.outputdir"); f != nil {
+		if dir := f.Value.String(); dir != "" {
+			return dir
+		}
+	}
+
+	if dir, err := os.Getwd(); err == nil {
+		return dir
+	}
+
+	return "."
+}
+
+// sanitizeFilename replaces chars which are not safe in file name with "_".
+func sanitizeFilename(name string) string {
+	if name == "" {
+		return "unknown"
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+
+		return '_'
+	}, name)
+}
@@ -0,0 +1,40 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCaptureProfiles(t *testing.T) {
+	dir := t.TempDir()
+	f := flag.Lookup("test.outputdir")
+	old := f.Value.String()
+	f.Value.Set(dir)
+	defer f.Value.Set(old)
+
+	stop := CaptureProfiles(ProfileGoroutine | ProfileHeap)
+	defer stop()
+
+	failure := &Failure{
+		TestName: "TestFoo/bar baz",
+		Message:  "failed",
+	}
+	runFailureHooks(failure)
+	runFailureHooks(&Failure{
+		TestName: "TestFoo/bar baz",
+		Message:  "failed again",
+	})
+
+	for _, name := range []string{"TestFoo_bar_baz.goroutine.pprof", "TestFoo_bar_baz.heap.pprof"} {
+		filename := filepath.Join(dir, name)
+		_, err := os.Stat(filename)
+		assertEqual(t, err, nil)
+		assertEqual(t, strings.Contains(failure.Message, filename), true)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EnvQuarantine is the environment variable holding the path of a quarantine file.
+// Rules in the file are loaded when the package is initialized.
+const EnvQuarantine = "GO_ASSERT_QUARANTINE"
+
+// QuarantineMode is the way to report a quarantined failure.
+type QuarantineMode int
+
+// All quarantine modes.
+const (
+	QuarantineSkip QuarantineMode = iota // Skip the test case using `t.Skipf`.
+	QuarantineLog                        // Log the failure using `t.Logf` and keep running.
+)
+
+// QuarantineRule marks assertion sites as quarantined.
+// Failures at these sites don't fail the test case.
+//
+// A site is matched by ID, by File and Line, or by all of them.
+// A rule without ID and File matches nothing.
+type QuarantineRule struct {
+	ID       string         // Stable ID of the assertion site printed in failure message. If it's empty, all IDs match.
+	File     string         // Base name of the file calling assertion function. A path is trimmed to its base name.
+	Line     int            // Line number of the assertion function call. If it's 0, all assertions in File match.
+	TestName string         // Name of the test case. If it's empty, all test cases match.
+	Tag      string         // Tracking tag like an issue ID, which is printed with the failure.
+	Mode     QuarantineMode // The way to report the failure.
+}
+
+// skipLogger is the subset of `testing.TB` used to report quarantined failures.
+type skipLogger interface {
+	Logf(format string, args ...interface{})
+	Skipf(format string, args ...interface{})
+}
+
+var (
+	quarantineLock  sync.RWMutex
+	quarantineRules []*QuarantineRule
+)
+
+func init() {
+	path := os.Getenv(EnvQuarantine)
+
+	if path == "" {
+		return
+	}
+
+	if _, err := LoadQuarantineFile(path); err != nil {
+		fmt.Fprintf(os.Stderr, "go-assert: fail to load quarantine file: %v\n", err)
+	}
+}
+
+// AddQuarantine registers rule.
+// Call remove to unregister the rule.
+func AddQuarantine(rule QuarantineRule) (remove func()) {
+	return addQuarantineRules([]QuarantineRule{rule})
+}
+
+func addQuarantineRules(rules []QuarantineRule) (remove func()) {
+	entries := make([]*QuarantineRule, 0, len(rules))
+
+	for _, rule := range rules {
+		rule := rule
+
+		if rule.File != "" {
+			rule.File = filepath.Base(rule.File)
+		}
+
+		entries = append(entries, &rule)
+	}
+
+	quarantineLock.Lock()
+	quarantineRules = append(quarantineRules, entries...)
+	quarantineLock.Unlock()
+
+	return func() {
+		quarantineLock.Lock()
+		defer quarantineLock.Unlock()
+
+		removed := make(map[*QuarantineRule]bool, len(entries))
+
+		for _, entry := range entries {
+			removed[entry] = true
+		}
+
+		rules := make([]*QuarantineRule, 0, len(quarantineRules))
+
+		for _, rule := range quarantineRules {
+			if !removed[rule] {
+				rules = append(rules, rule)
+			}
+		}
+
+		quarantineRules = rules
+	}
+}
+
+// LoadQuarantineFile reads rules from the file at path and registers all of them.
+// Call remove to unregister these rules.
+//
+// Every non-empty line in the file is a rule in format `[test] site mode tag`.
+// The test is optional. The site is either `file:line` or a stable assertion ID.
+// Line 0 matches all assertions in the file.
+// The mode is either "skip" or "log".
+// Lines starting with "#" are comments.
+//
+//	# Skip TestFoo if the assertion at foo_test.go:42 fails.
+//	TestFoo foo_test.go:42 skip ISSUE-123
+//
+//	# Log failures of all assertions in bar_test.go.
+//	bar_test.go:0 log ISSUE-456
+//
+//	# Log failures of the assertion with ID 3f2a9c1b7d4e.
+//	3f2a9c1b7d4e log ISSUE-789
+func LoadQuarantineFile(path string) (remove func(), err error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return
+	}
+
+	defer file.Close()
+	rules, err := parseQuarantine(file)
+
+	if err != nil {
+		err = fmt.Errorf("%v: %v", path, err)
+		return
+	}
+
+	remove = addQuarantineRules(rules)
+	return
+}
+
+func parseQuarantine(r io.Reader) (rules []QuarantineRule, err error) {
+	scanner := bufio.NewScanner(r)
+	lineno := 0
+
+	for scanner.Scan() {
+		lineno++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule, e := parseQuarantineRule(line)
+
+		if e != nil {
+			err = fmt.Errorf("line %v: %v", lineno, e)
+			return
+		}
+
+		rules = append(rules, rule)
+	}
+
+	err = scanner.Err()
+	return
+}
+
+func parseQuarantineRule(line string) (rule QuarantineRule, err error) {
+	fields := strings.Fields(line)
+
+	switch len(fields) {
+	case 3:
+	case 4:
+		rule.TestName = fields[0]
+		fields = fields[1:]
+	default:
+		err = fmt.Errorf("rule must be in format `[test] site mode tag`")
+		return
+	}
+
+	site, mode, tag := fields[0], fields[1], fields[2]
+
+	if colon := strings.LastIndexByte(site, ':'); colon < 0 {
+		rule.ID = site
+	} else if colon == 0 {
+		err = fmt.Errorf("invalid site %q", site)
+		return
+	} else {
+		rule.File = site[:colon]
+		rule.Line, err = strconv.Atoi(site[colon+1:])
+
+		if err != nil || rule.Line < 0 {
+			err = fmt.Errorf("invalid line number in site %q", site)
+			return
+		}
+	}
+
+	switch mode {
+	case "skip":
+		rule.Mode = QuarantineSkip
+	case "log":
+		rule.Mode = QuarantineLog
+	default:
+		err = fmt.Errorf("invalid mode %q", mode)
+		return
+	}
+
+	rule.Tag = tag
+	return
+}
+
+// findQuarantine returns the first registered rule matching f.
+func findQuarantine(f *Failure) *QuarantineRule {
+	quarantineLock.RLock()
+	defer quarantineLock.RUnlock()
+
+	for _, rule := range quarantineRules {
+		if rule.ID == "" && rule.File == "" {
+			continue
+		}
+
+		if rule.ID != "" && rule.ID != f.ID {
+			continue
+		}
+
+		if rule.File != "" && rule.File != f.Filename {
+			continue
+		}
+
+		if rule.Line != 0 && rule.Line != f.Line {
+			continue
+		}
+
+		if rule.TestName != "" && rule.TestName != f.TestName {
+			continue
+		}
+
+		return rule
+	}
+
+	return nil
+}
+
+// quarantine reports msg according to rule.
+// It returns false if t can neither log nor skip.
+func quarantine(t T, rule *QuarantineRule, msg string) bool {
+	t.Helper()
+	sl, ok := t.(skipLogger)
+
+	if !ok {
+		return false
+	}
+
+	title := fmt.Sprintf(CurrentMessages().QuarantinedFormat, rule.Tag)
+
+	switch rule.Mode {
+	case QuarantineLog:
+		sl.Logf("\n%v\n%v", title, msg)
+	default:
+		sl.Skipf("\n%v\n%v", title, msg)
+	}
+
+	return true
+}
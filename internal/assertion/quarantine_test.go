@@ -0,0 +1,125 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestQuarantine(t *testing.T) {
+	var failures []Failure
+	removeHook := AddFailureHook(func(f *Failure) {
+		failures = append(failures, *f)
+	})
+	defer removeHook()
+
+	trigger := &Trigger{
+		FuncName: "Assert",
+		Args:     []int{1},
+	}
+	ft := NewFakeT("TestQuarantine")
+	remove := AddQuarantine(QuarantineRule{
+		File:     "/path/to/quarantine_test.go",
+		TestName: "TestQuarantine",
+		Tag:      "ISSUE-1",
+		Mode:     QuarantineSkip,
+	})
+	Assert(ft, 1 > 2, trigger)
+	remove()
+
+	assertEqual(t, ft.Failed(), false)
+	assertEqual(t, ft.Skipped(), true)
+	assertEqual(t, len(failures), 1)
+	assertEqual(t, failures[0].Quarantine, "ISSUE-1")
+
+	calls := ft.Calls()
+	msg := calls[len(calls)-1].Message
+	assertEqual(t, strings.Contains(msg, "[ISSUE-1]"), true)
+	assertEqual(t, strings.Contains(msg, "Assertion failed:\n    1 > 2"), true)
+
+	// Rule for another test case doesn't match.
+	ft.Reset()
+	failures = nil
+	remove = AddQuarantine(QuarantineRule{
+		File:     "quarantine_test.go",
+		TestName: "TestOther",
+		Tag:      "ISSUE-2",
+	})
+	Assert(ft, 1 > 2, trigger)
+	remove()
+
+	assertEqual(t, ft.Fatal(), true)
+	assertEqual(t, failures[0].Quarantine, "")
+
+	// Rules are removed.
+	ft.Reset()
+	Assert(ft, 1 > 2, trigger)
+	assertEqual(t, ft.Fatal(), true)
+	assertEqual(t, ft.Skipped(), false)
+}
+
+func TestLoadQuarantineFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quarantine")
+	content := `
+# Comment.
+TestFoo foo_test.go:42 skip ISSUE-1
+quarantine_test.go:0 log ISSUE-2
+`
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("fail to write file: %v", err)
+	}
+
+	remove, err := LoadQuarantineFile(path)
+
+	if err != nil {
+		t.Fatalf("fail to load file: %v", err)
+	}
+
+	ft := NewFakeT("TestLoadQuarantineFile")
+	Assert(ft, 1 > 2, &Trigger{
+		FuncName: "Assert",
+		Args:     []int{1},
+	})
+	Assert(ft, 3 > 4, &Trigger{
+		FuncName: "Assert",
+		Args:     []int{1},
+	})
+	remove()
+
+	calls := nonHelperCalls(ft)
+	assertEqual(t, ft.Failed(), false)
+	assertEqual(t, len(calls), 2)
+	assertEqual(t, calls[0].Method, "Logf")
+	assertEqual(t, strings.Contains(calls[1].Message, "[ISSUE-2]"), true)
+
+	cases := []struct {
+		Line string
+		Rule QuarantineRule
+		Err  bool
+	}{
+		{"foo_test.go:12 skip ISSUE-1", QuarantineRule{File: "foo_test.go", Line: 12, Tag: "ISSUE-1"}, false},
+		{"TestFoo/bar pkg/foo_test.go:0 log X", QuarantineRule{File: "pkg/foo_test.go", TestName: "TestFoo/bar", Tag: "X", Mode: QuarantineLog}, false},
+		{"foo_test.go:12 skip", QuarantineRule{}, true},
+		{"3f2a9c1b7d4e skip ISSUE-1", QuarantineRule{ID: "3f2a9c1b7d4e", Tag: "ISSUE-1"}, false},
+		{":12 skip ISSUE-1", QuarantineRule{}, true},
+		{"foo_test.go:-1 skip ISSUE-1", QuarantineRule{}, true},
+		{"foo_test.go:12 ignore ISSUE-1", QuarantineRule{}, true},
+	}
+
+	for i, c := range cases {
+		rule, err := parseQuarantineRule(c.Line)
+
+		if (err != nil) != c.Err {
+			t.Fatalf("case %v: unexpected error: %v", i, err)
+		}
+
+		if err == nil {
+			assertEqual(t, rule, c.Rule)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Snapshot returns a pointer to a deep copy of the value ptr points to.
+// Pointers, slices, maps and interfaces are copied recursively, including unexported fields,
+// so that reading the copy never races with goroutines mutating the original value.
+// Channels, funcs and unsafe pointers are shared with the original value.
+//
+// Snapshot returns ptr if it's not a non-nil pointer or the value cannot be copied.
+func Snapshot(ptr interface{}) (copied interface{}) {
+	val := reflect.ValueOf(ptr)
+
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return ptr
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			copied = ptr
+		}
+	}()
+
+	s := &snapshotter{
+		copied: map[snapshotKey]reflect.Value{},
+	}
+	return s.copy(val).Interface()
+}
+
+type snapshotKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// snapshotter makes deep copies of values.
+// Pointers copied before are reused, so that cyclic values can be copied.
+type snapshotter struct {
+	copied map[snapshotKey]reflect.Value
+}
+
+func (s *snapshotter) copy(v reflect.Value) reflect.Value {
+	v = exposed(v)
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+
+		key := snapshotKey{v.Pointer(), v.Type()}
+
+		if c, ok := s.copied[key]; ok {
+			return c
+		}
+
+		c := reflect.New(v.Type().Elem())
+		s.copied[key] = c
+		settable(c.Elem()).Set(s.copy(v.Elem()))
+		return c
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(s.copy(v.Elem()))
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(s.copy(v.Index(i)))
+		}
+
+		return c
+
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(s.copy(v.Index(i)))
+		}
+
+		return c
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+
+		for iter.Next() {
+			c.SetMapIndex(s.copy(iter.Key()), s.copy(iter.Value()))
+		}
+
+		return c
+
+	case reflect.Struct:
+		// Fields of an unaddressable struct cannot be exposed.
+		if !v.CanAddr() {
+			addressable := reflect.New(v.Type()).Elem()
+			addressable.Set(v)
+			v = addressable
+		}
+
+		c := reflect.New(v.Type()).Elem()
+
+		for i := 0; i < v.NumField(); i++ {
+			settable(c.Field(i)).Set(s.copy(v.Field(i)))
+		}
+
+		return c
+	}
+
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
+// exposed returns v which can be used even if v is obtained through unexported fields.
+func exposed(v reflect.Value) reflect.Value {
+	if v.CanInterface() || !v.CanAddr() {
+		return v
+	}
+
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// settable returns v which can be set even if v is an unexported field.
+func settable(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
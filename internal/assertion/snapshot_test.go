@@ -0,0 +1,40 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"testing"
+)
+
+type snapshotNode struct {
+	Name  string
+	items []int
+	attrs map[string]interface{}
+	next  *snapshotNode
+}
+
+func TestSnapshot(t *testing.T) {
+	n := &snapshotNode{
+		Name:  "foo",
+		items: []int{1, 2},
+		attrs: map[string]interface{}{"k": []string{"v"}},
+	}
+	n.next = n
+	copied := Snapshot(&n).(**snapshotNode)
+
+	n.Name = "bar"
+	n.items[0] = 3
+	n.attrs["k"].([]string)[0] = "changed"
+	n.attrs["new"] = true
+
+	c := *copied
+	assertEqual(t, c.Name, "foo")
+	assertEqual(t, c.items, []int{1, 2})
+	assertEqual(t, c.attrs, map[string]interface{}{"k": []string{"v"}})
+	assertEqual(t, c.next == c, true)
+
+	// Values other than non-nil pointers are not copied.
+	assertEqual(t, Snapshot(nil), nil)
+	assertEqual(t, Snapshot(1), 1)
+}
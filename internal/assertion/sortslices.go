@@ -0,0 +1,194 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"reflect"
+	"sort"
+)
+
+// SliceSort sorts slices in values before they are compared by Equal and NotEqual,
+// so that values containing unordered result sets can be compared without sorting them in advance.
+// Values are copied before sorting. Compared values are never modified.
+//
+// The path selects slices to sort. It starts with "$" which is the root value.
+// A ".Name" selects a struct field or a value with string key "Name" in a map
+// and a "[*]" selects all elements of a slice, an array or a map.
+// Pointers and interfaces are followed implicitly.
+// For instance, "$.Groups[*].Members" selects field Members in every element of field Groups.
+// If path is empty, all slices with elements assignable to ElemType are sorted.
+//
+// Unexported struct fields are not sorted.
+type SliceSort struct {
+	Path     string                        // Path of slices to sort. If it's empty, all matching slices are sorted.
+	ElemType reflect.Type                  // Type accepted by Less. Slices with unassignable elements are not sorted.
+	Less     func(a, b reflect.Value) bool // Reports whether a should sort before b. Values are assignable to ElemType.
+}
+
+// sortSlices returns a copy of v in which slices selected by sorts are sorted.
+// If sorts is empty, v is returned as it is.
+func sortSlices(v interface{}, sorts []SliceSort) interface{} {
+	if len(sorts) == 0 || v == nil {
+		return v
+	}
+
+	s := &sliceSorter{
+		sorts:  sorts,
+		copied: map[uintptr]reflect.Value{},
+	}
+	return s.copy(reflect.ValueOf(v), "$").Interface()
+}
+
+type sliceSorter struct {
+	sorts  []SliceSort
+	copied map[uintptr]reflect.Value // Copied pointers to keep cycles and shared pointers.
+}
+
+func (s *sliceSorter) copy(v reflect.Value, path string) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+
+		if copied, ok := s.copied[v.Pointer()]; ok {
+			return copied
+		}
+
+		ptr := reflect.New(v.Type().Elem())
+		s.copied[v.Pointer()] = ptr
+		ptr.Elem().Set(s.copy(v.Elem(), path))
+		return ptr
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		iface := reflect.New(v.Type()).Elem()
+		iface.Set(s.copy(v.Elem(), path))
+		return iface
+
+	case reflect.Struct:
+		st := reflect.New(v.Type()).Elem()
+		st.Set(v)
+
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.PkgPath == "" {
+				st.Field(i).Set(s.copy(v.Field(i), path+"."+field.Name))
+			}
+		}
+
+		return st
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+
+		for iter.Next() {
+			elemPath := path + "[*]"
+
+			if k := iter.Key(); k.Kind() == reflect.String {
+				if p := path + "." + k.String(); s.selected(p) {
+					elemPath = p
+				}
+			}
+
+			m.SetMapIndex(iter.Key(), s.copy(iter.Value(), elemPath))
+		}
+
+		return m
+
+	case reflect.Array:
+		arr := reflect.New(v.Type()).Elem()
+
+		for i := 0; i < v.Len(); i++ {
+			arr.Index(i).Set(s.copy(v.Index(i), path+"[*]"))
+		}
+
+		return arr
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		// Slices of scalars, e.g. large byte slices, are not copied unless they are sorted.
+		if isScalar(v.Type().Elem()) && s.find(v.Type().Elem(), path) == nil {
+			return v
+		}
+
+		slice := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			slice.Index(i).Set(s.copy(v.Index(i), path+"[*]"))
+		}
+
+		s.sort(slice, path)
+		return slice
+	}
+
+	return v
+}
+
+// selected returns true if any sort selects path or any path under it.
+func (s *sliceSorter) selected(path string) bool {
+	for _, ss := range s.sorts {
+		if ss.Path == path {
+			return true
+		}
+
+		if len(ss.Path) > len(path) && ss.Path[:len(path)] == path && (ss.Path[len(path)] == '.' || ss.Path[len(path)] == '[') {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sort sorts slice with the first sort matching path and element type.
+func (s *sliceSorter) sort(slice reflect.Value, path string) {
+	ss := s.find(slice.Type().Elem(), path)
+
+	if ss == nil {
+		return
+	}
+
+	sort.SliceStable(slice.Interface(), func(i, j int) bool {
+		return ss.Less(slice.Index(i), slice.Index(j))
+	})
+}
+
+// find returns the first sort matching path and element type.
+func (s *sliceSorter) find(elem reflect.Type, path string) *SliceSort {
+	for i := range s.sorts {
+		ss := &s.sorts[i]
+
+		if ss.Path != "" && ss.Path != path {
+			continue
+		}
+
+		if ss.ElemType == nil || !elem.AssignableTo(ss.ElemType) {
+			continue
+		}
+
+		return ss
+	}
+
+	return nil
+}
+
+// isScalar returns true if values of typ contain no slice.
+func isScalar(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Struct, reflect.Map, reflect.Array, reflect.Slice:
+		return false
+	}
+
+	return true
+}
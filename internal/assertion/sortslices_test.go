@@ -0,0 +1,107 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"reflect"
+	"testing"
+)
+
+func intSort(path string) SliceSort {
+	return SliceSort{
+		Path:     path,
+		ElemType: reflect.TypeOf(0),
+		Less: func(a, b reflect.Value) bool {
+			return a.Int() < b.Int()
+		},
+	}
+}
+
+func TestSortSlices(t *testing.T) {
+	type Group struct {
+		Name    string
+		Members []int
+		Tags    []int
+	}
+	type Result struct {
+		Groups []*Group
+		ByName map[string][]int
+		Any    interface{}
+	}
+
+	value := Result{
+		Groups: []*Group{
+			{Name: "foo", Members: []int{3, 1, 2}, Tags: []int{2, 1}},
+		},
+		ByName: map[string][]int{
+			"foo": {2, 1},
+			"bar": {4, 3},
+		},
+		Any: []int{6, 5},
+	}
+	cases := []struct {
+		Sorts    []SliceSort
+		Expected Result
+	}{
+		{
+			[]SliceSort{intSort("$.Groups[*].Members")},
+			Result{
+				Groups: []*Group{{Name: "foo", Members: []int{1, 2, 3}, Tags: []int{2, 1}}},
+				ByName: map[string][]int{"foo": {2, 1}, "bar": {4, 3}},
+				Any:    []int{6, 5},
+			},
+		},
+		{
+			[]SliceSort{intSort("$.ByName.foo"), intSort("$.Any")},
+			Result{
+				Groups: []*Group{{Name: "foo", Members: []int{3, 1, 2}, Tags: []int{2, 1}}},
+				ByName: map[string][]int{"foo": {1, 2}, "bar": {4, 3}},
+				Any:    []int{5, 6},
+			},
+		},
+		{
+			[]SliceSort{intSort("$.ByName[*]")},
+			Result{
+				Groups: []*Group{{Name: "foo", Members: []int{3, 1, 2}, Tags: []int{2, 1}}},
+				ByName: map[string][]int{"foo": {1, 2}, "bar": {3, 4}},
+				Any:    []int{6, 5},
+			},
+		},
+		{
+			[]SliceSort{intSort("")},
+			Result{
+				Groups: []*Group{{Name: "foo", Members: []int{1, 2, 3}, Tags: []int{1, 2}}},
+				ByName: map[string][]int{"foo": {1, 2}, "bar": {3, 4}},
+				Any:    []int{5, 6},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		assertEqual(t, sortSlices(value, c.Sorts), c.Expected)
+	}
+
+	// Original value is not modified.
+	assertEqual(t, value.Groups[0].Members, []int{3, 1, 2})
+	assertEqual(t, value.ByName["foo"], []int{2, 1})
+	assertEqual(t, value.Any, []int{6, 5})
+}
+
+func TestAssertEqualSortSlices(t *testing.T) {
+	ft := NewFakeT("TestAssertEqualSortSlices")
+	trigger := &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options: Options{
+			SliceSorts: []SliceSort{intSort("$")},
+		},
+	}
+
+	AssertEqual(ft, []int{3, 1, 2}, []int{1, 2, 3}, trigger)
+	AssertNotEqual(ft, []int{3, 1, 2}, []int{1, 2, 4}, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertNotEqual(ft, []int{3, 1, 2}, []int{2, 3, 1}, trigger)
+	assertEqual(t, ft.Fatal(), true)
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// EnvStable is the environment variable to enable stable output.
+// Set it to a true value like "1" or "true" to enable it.
+const EnvStable = "GO_ASSERT_STABLE"
+
+// Placeholders replacing run-specific details in stable output.
+const (
+	StablePointer   = "0x<ptr>"
+	StableTempDir   = "<tmp>"
+	StableDuration  = "<duration>"
+	StableGoroutine = "goroutine <id>"
+)
+
+var stableOutput int32
+
+// SetStableOutput enables or disables stable output.
+// In stable output mode, run-specific details in failure messages are replaced by placeholders,
+// so that failure messages are the same in every run and can be compared with golden files.
+// Colors are always disabled in this mode.
+//
+// Following details are replaced.
+//
+//   - Pointer-like hex numbers with 8 or more digits, e.g. "0xc000012345", are replaced by StablePointer.
+//   - Temporary directories created in `os.TempDir()`, e.g. dirs created by `t.TempDir()`, are replaced by StableTempDir.
+//   - Durations measured or configured by assertions, e.g. the elapsed time in AssertTookLess or the timeout in AssertEventually,
+//     are replaced by StableDuration. Durations in values and expressions of the test are kept as is.
+//   - Goroutine IDs in stacks, e.g. "goroutine 23", are replaced by StableGoroutine.
+func SetStableOutput(enabled bool) {
+	var v int32
+
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&stableOutput, v)
+}
+
+// StableOutputEnabled returns true if stable output mode is enabled.
+func StableOutputEnabled() bool {
+	return atomic.LoadInt32(&stableOutput) != 0
+}
+
+var (
+	rePointer   = regexp.MustCompile(`\b0x[0-9a-fA-F]{8,}\b`)
+	reGoroutine = regexp.MustCompile(`\bgoroutine \d+\b`)
+)
+
+// stabilize replaces run-specific details in msg with placeholders.
+func stabilize(msg string) string {
+	msg = stripColors(msg)
+	msg = replaceTempDirs(msg)
+	msg = rePointer.ReplaceAllLiteralString(msg, StablePointer)
+	msg = reGoroutine.ReplaceAllLiteralString(msg, StableGoroutine)
+	return msg
+}
+
+// replaceTempDirs replaces the first path element under `os.TempDir()` in msg with StableTempDir.
+// For instance, "/tmp/TestFoo123/001/a.txt" is replaced by "<tmp>/001/a.txt".
+func replaceTempDirs(msg string) string {
+	dir := filepath.Clean(os.TempDir())
+
+	if dir == "." || !strings.Contains(msg, dir) {
+		return msg
+	}
+
+	sep := regexp.QuoteMeta(string(filepath.Separator))
+	re := regexp.MustCompile(regexp.QuoteMeta(dir) + sep + `[^\s` + sep + `"']+`)
+	return re.ReplaceAllLiteralString(msg, StableTempDir)
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStabilize(t *testing.T) {
+	tmp := filepath.Join(os.TempDir(), "TestStabilize123", "001", "a.txt")
+	cases := []struct {
+		Message string
+		Stable  string
+	}{
+		{"p = (*int)0xc000012345", "p = (*int)0x<ptr>"},
+		{"At offset 0x3f200, 1 bytes differ:", "At offset 0x3f200, 1 bytes differ:"},
+		{"timeout after 1.5s and 2m30s, took 350µs", "timeout after 1.5s and 2m30s, took 350µs"},
+		{"goroutine 23 [running]:", "goroutine <id> [running]:"},
+		{"open " + tmp + ": no such file", "open " + filepath.Join(StableTempDir, "001", "a.txt") + ": no such file"},
+		{colorKeyword + "for" + colorReset + " i := 0", "for i := 0"},
+		{"(int)15 [1] -> (string)5 bytes", "(int)15 [1] -> (string)5 bytes"},
+	}
+
+	for i, c := range cases {
+		t.Logf("case %v: %v", i, c)
+		assertEqual(t, stabilize(c.Message), c.Stable)
+	}
+}
+
+func TestStableDuration(t *testing.T) {
+	SetStableOutput(true)
+	defer SetStableOutput(false)
+
+	ft := NewFakeT("TestStableDuration")
+	trigger := &Trigger{
+		FuncName: "AssertDurationLess",
+		Args:     []int{1},
+		Options:  Options{NonFatal: true},
+	}
+	limit := time.Second
+	spent, _ := time.ParseDuration("1.5s")
+
+	// Durations in the source are kept as is.
+	AssertDurationLess(ft, spent, limit, trigger)
+	msgs := ft.Messages()
+
+	assertEqual(t, len(msgs), 1)
+	assertMessage(t, msgs[0], `
+Assertion failed:
+Following duration should be less than <duration>.
+    spent
+    spent, _ := time.ParseDuration("1.5s")
+Duration:
+    <duration>, which is <duration> over the limit.
+Assertion ID: 6b776c13d945`)
+}
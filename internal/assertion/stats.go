@@ -0,0 +1,148 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+var errInvalidPercentile = errors.New("go-assert: percentile must be greater than 0 and not greater than 1")
+
+// samplesSummary is a summary of samples printed in failure messages.
+type samplesSummary struct {
+	Count    int
+	Min, Max float64
+	Mean     float64
+	P50, P99 float64
+	sorted   []float64
+}
+
+func summarize(samples []float64) *samplesSummary {
+	s := &samplesSummary{
+		Count: len(samples),
+	}
+
+	if len(samples) == 0 {
+		return s
+	}
+
+	s.sorted = make([]float64, len(samples))
+	copy(s.sorted, samples)
+	sort.Float64s(s.sorted)
+
+	sum := 0.0
+
+	for _, v := range samples {
+		sum += v
+	}
+
+	s.Min = s.sorted[0]
+	s.Max = s.sorted[len(s.sorted)-1]
+	s.Mean = sum / float64(len(samples))
+	s.P50 = s.percentile(0.5)
+	s.P99 = s.percentile(0.99)
+	return s
+}
+
+// percentile returns the p-th percentile of samples using the nearest-rank method.
+// The p must be in range (0, 1] and there must be at least one sample.
+func (s *samplesSummary) percentile(p float64) float64 {
+	rank := int(math.Ceil(p * float64(len(s.sorted))))
+
+	if rank < 1 {
+		rank = 1
+	}
+
+	return s.sorted[rank-1]
+}
+
+// AssertMeanInDelta expects the mean of samples is within delta of want.
+// Otherwise, it will terminate the test case using `t.Fatalf` with a summary of samples.
+// Samples must not be empty.
+func AssertMeanInDelta(t T, samples []float64, want, delta float64, trigger *Trigger) {
+	t.Helper()
+	if !validDelta(delta) {
+		failInternal(t, trigger, errInvalidTolerance)
+		return
+	}
+
+	s := summarize(samples)
+
+	if s.Count > 0 && inDelta(s.Mean, want, delta) {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	header := fmt.Sprintf(msgs.ShouldHaveMeanFormat, formatFloat(delta), formatFloat(want))
+	fail(t, trigger, samplesFailure(msgs, trigger, f, header, s))
+}
+
+// AssertPercentileUnder expects the p-th percentile of samples is not greater than limit.
+// The p must be in range (0, 1], e.g. 0.99 for the 99th percentile.
+// Percentiles are calculated using the nearest-rank method.
+// Otherwise, it will terminate the test case using `t.Fatalf` with a summary of samples.
+// Samples must not be empty.
+func AssertPercentileUnder(t T, samples []float64, p, limit float64, trigger *Trigger) {
+	t.Helper()
+	if !(p > 0 && p <= 1) {
+		failInternal(t, trigger, errInvalidPercentile)
+		return
+	}
+
+	s := summarize(samples)
+
+	if s.Count > 0 && s.percentile(p) <= limit {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	header := fmt.Sprintf(msgs.ShouldHavePercentileFormat, formatFloat(p*100), formatFloat(limit))
+
+	if s.Count > 0 {
+		header += " " + fmt.Sprintf(msgs.PercentileFormat, formatFloat(s.percentile(p)))
+	}
+
+	fail(t, trigger, samplesFailure(msgs, trigger, f, header, s))
+}
+
+// samplesFailure creates the failure of AssertMeanInDelta and AssertPercentileUnder.
+func samplesFailure(msgs Messages, trigger *Trigger, f *Func, header string, s *samplesSummary) *Failure {
+	info := trigger.P().ParseInfo(f)
+	summary := msgs.NoSamples
+
+	if s.Count > 0 {
+		summary = fmt.Sprintf(msgs.SamplesSummaryFormat, s.Count,
+			formatFloat(s.Min), formatFloat(s.Max), formatFloat(s.Mean), formatFloat(s.P50), formatFloat(s.P99))
+	}
+
+	return &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n    %v%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4), header,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.Summary, summary, formatVars(msgs, info, trigger),
+		),
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	s := summarize([]float64{5, 1, 4, 2, 3})
+
+	assertEqual(t, s.Count, 5)
+	assertEqual(t, s.Min, 1.0)
+	assertEqual(t, s.Max, 5.0)
+	assertEqual(t, s.Mean, 3.0)
+	assertEqual(t, s.P50, 3.0)
+	assertEqual(t, s.P99, 5.0)
+	assertEqual(t, s.percentile(0.2), 1.0)
+	assertEqual(t, s.percentile(0.21), 2.0)
+	assertEqual(t, s.percentile(1), 5.0)
+
+	s = summarize(nil)
+	assertEqual(t, s.Count, 0)
+}
+
+func TestAssertSamples(t *testing.T) {
+	ft := NewFakeT("TestAssertSamples")
+	meanTrigger := &Trigger{
+		FuncName: "AssertMeanInDelta",
+		Args:     []int{1},
+		Options:  Options{NonFatal: true},
+	}
+	percentileTrigger := &Trigger{
+		FuncName: "AssertPercentileUnder",
+		Args:     []int{1},
+		Options:  Options{NonFatal: true},
+	}
+	samples := []float64{1, 2, 3, 4, 100}
+
+	AssertMeanInDelta(ft, samples, 22, 0, meanTrigger)
+	AssertPercentileUnder(ft, samples, 0.8, 4, percentileTrigger)
+	assertEqual(t, len(ft.Messages()), 0)
+
+	AssertMeanInDelta(ft, samples, 3, 1, meanTrigger)
+	AssertPercentileUnder(ft, samples, 0.99, 50, percentileTrigger)
+	AssertMeanInDelta(ft, nil, 0, 1, meanTrigger)
+	AssertPercentileUnder(ft, samples, 0, 50, percentileTrigger)
+	msgs := ft.Messages()
+
+	assertEqual(t, len(msgs), 4)
+	assertEqual(t, strings.Contains(msgs[0], "Mean of following samples should be within 1 of 3."), true)
+	assertEqual(t, strings.Contains(msgs[0], "count = 5, min = 1, max = 100, mean = 22, p50 = 3, p99 = 100"), true)
+	assertEqual(t, strings.Contains(msgs[1], "The p99 of following samples should not be greater than 50. The actual value is 100."), true)
+	assertEqual(t, strings.Contains(msgs[2], "There is no sample."), true)
+	assertEqual(t, strings.Contains(msgs[3], errInvalidPercentile.Error()), true)
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errEmptySubstring = errors.New("go-assert: substring must not be empty")
+
+// AssertContainsAll expects s contains all of subs.
+// Otherwise, it will terminate the test case using `t.Fatalf`
+// with missing substrings and indices of found ones.
+func AssertContainsAll(t T, s string, subs []string, trigger *Trigger) {
+	t.Helper()
+	indices, found, err := indexSubstrings(s, subs)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	if found == len(subs) {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	fail(t, trigger, substringsFailure(msgs, trigger, f, msgs.ShouldContainAll, s, subs, indices))
+}
+
+// AssertContainsAny expects s contains at least one of subs.
+// Otherwise, it will terminate the test case using `t.Fatalf` with all substrings.
+func AssertContainsAny(t T, s string, subs []string, trigger *Trigger) {
+	t.Helper()
+	indices, found, err := indexSubstrings(s, subs)
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	if found > 0 {
+		return
+	}
+
+	f, err := trigger.parseArgs()
+
+	if err != nil {
+		failInternal(t, trigger, err)
+		return
+	}
+
+	msgs := CurrentMessages()
+	fail(t, trigger, substringsFailure(msgs, trigger, f, msgs.ShouldContainAny, s, subs, indices))
+}
+
+// indexSubstrings returns indices of subs in s and the number of found substrings.
+// The index of a missing substring is -1.
+// An empty substring is always found in s, so it's rejected as an error.
+func indexSubstrings(s string, subs []string) (indices []int, found int, err error) {
+	indices = make([]int, 0, len(subs))
+
+	for _, sub := range subs {
+		if sub == "" {
+			err = errEmptySubstring
+			return
+		}
+
+		idx := strings.Index(s, sub)
+		indices = append(indices, idx)
+
+		if idx >= 0 {
+			found++
+		}
+	}
+
+	return
+}
+
+// substringsFailure creates the failure of AssertContainsAll and AssertContainsAny.
+func substringsFailure(msgs Messages, trigger *Trigger, f *Func, header string, s string, subs []string, indices []int) *Failure {
+	info := trigger.P().ParseInfo(f)
+	lines := make([]string, 0, len(subs))
+
+	for i, sub := range subs {
+		if indices[i] < 0 {
+			lines = append(lines, fmt.Sprintf("    [%v] "+msgs.SubstringMissingFormat, i+1, sub))
+		} else {
+			lines = append(lines, fmt.Sprintf("    [%v] "+msgs.SubstringFoundFormat, i+1, sub, indices[i]))
+		}
+	}
+
+	sDump := trigger.dumper().Dump(s)
+	return &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n%v\n    %v%v\n%v\n%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, header,
+			indentCode(info.Args[0], 4), indentAssignments(info.Assignments[0], 4),
+			msgs.Substrings, strings.Join(lines, "\n"),
+			msgs.Value, sDump, formatVars(msgs, info, trigger),
+		),
+		Values: []string{sDump},
+	}
+}
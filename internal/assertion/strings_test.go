@@ -0,0 +1,65 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAssertContainsAll(t *testing.T) {
+	ft := NewFakeT("TestAssertContainsAll")
+	trigger := &Trigger{
+		FuncName: "AssertContainsAll",
+		Args:     []int{1},
+	}
+	log := "level=info msg=started"
+
+	AssertContainsAll(ft, log, []string{"level=info", "msg=started"}, trigger)
+	AssertContainsAll(ft, log, nil, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertContainsAll(ft, log, []string{"msg=started", "level=error"}, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldContainAll+"\n    log\n    log := \"level=info msg=started\""), true)
+	assertEqual(t, strings.Contains(msgs[0], `Substrings:
+    [1] "msg=started" is found at index 11.
+    [2] "level=error" is missing.`), true)
+
+	ft.Reset()
+	AssertContainsAll(ft, log, []string{"level=info", ""}, trigger)
+	msgs = ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, msgs[0], fmt.Sprintf(DefaultMessages.InternalErrorFormat, errEmptySubstring))
+}
+
+func TestAssertContainsAny(t *testing.T) {
+	ft := NewFakeT("TestAssertContainsAny")
+	trigger := &Trigger{
+		FuncName: "AssertContainsAny",
+		Args:     []int{1},
+	}
+	log := "level=info msg=started"
+
+	AssertContainsAny(ft, log, []string{"level=error", "msg=started"}, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertContainsAny(ft, log, []string{"level=error", "msg=stopped"}, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], DefaultMessages.ShouldContainAny), true)
+	assertEqual(t, strings.Contains(msgs[0], `[2] "msg=stopped" is missing.`), true)
+
+	ft.Reset()
+	AssertContainsAny(ft, log, nil, trigger)
+	assertEqual(t, ft.Fatal(), true)
+
+	ft.Reset()
+	AssertContainsAny(ft, log, []string{"level=info", ""}, trigger)
+	msgs = ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, msgs[0], fmt.Sprintf(DefaultMessages.InternalErrorFormat, errEmptySubstring))
+}
@@ -0,0 +1,206 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSuggestions is the max number of similar strings suggested in a failure message.
+const maxSuggestions = 3
+
+// maxSuggestLen is the max number of runes in strings compared by edit distance.
+// Longer strings are never considered typos.
+const maxSuggestLen = 256
+
+// minSuggestLen is the min number of runes in strings compared by edit distance.
+// Shorter strings, e.g. "200" and "201", differ by one edit too easily to be considered typos.
+const minSuggestLen = 4
+
+// editDistance returns the Levenshtein distance between s1 and s2 in runes,
+// except that swapping adjacent runes counts as one edit as it's a common typo.
+func editDistance(s1, s2 string) int {
+	r1 := []rune(s1)
+	r2 := []rune(s2)
+	prevPrev := make([]int, len(r2)+1)
+	prev := make([]int, len(r2)+1)
+	curr := make([]int, len(r2)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(r1); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(r2); j++ {
+			cost := 1
+
+			if r1[i-1] == r2[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+
+			if i > 1 && j > 1 && r1[i-1] == r2[j-2] && r1[i-2] == r2[j-1] && prevPrev[j-2]+1 < curr[j] {
+				curr[j] = prevPrev[j-2] + 1
+			}
+		}
+
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+
+	return prev[len(r2)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+
+	if c < a {
+		a = c
+	}
+
+	return a
+}
+
+// typoDistance returns the edit distance between s1 and s2
+// if s2 is likely a typo of s1. Otherwise, it returns 0.
+// A typo has at most one edit per 3 runes and at least 1 edit.
+// Both strings must have minSuggestLen to maxSuggestLen runes.
+func typoDistance(s1, s2 string) int {
+	n1 := utf8.RuneCountInString(s1)
+	n2 := utf8.RuneCountInString(s2)
+
+	if n1 > maxSuggestLen || n2 > maxSuggestLen || n1 < minSuggestLen || n2 < minSuggestLen {
+		return 0
+	}
+
+	max := n1
+
+	if n2 < max {
+		max = n2
+	}
+
+	max /= 3
+
+	if n1-n2 > max || n2-n1 > max {
+		return 0
+	}
+
+	if d := editDistance(s1, s2); d <= max {
+		return d
+	}
+
+	return 0
+}
+
+// similarStrings returns candidates which are likely typos of s, closest first.
+func similarStrings(s string, candidates []string) []string {
+	type similar struct {
+		str      string
+		distance int
+	}
+
+	found := make([]similar, 0, maxSuggestions)
+
+	for _, c := range candidates {
+		if d := typoDistance(s, c); d > 0 {
+			found = append(found, similar{c, d})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].distance != found[j].distance {
+			return found[i].distance < found[j].distance
+		}
+
+		return found[i].str < found[j].str
+	})
+
+	if len(found) > maxSuggestions {
+		found = found[:maxSuggestions]
+	}
+
+	strs := make([]string, 0, len(found))
+
+	for _, f := range found {
+		strs = append(strs, f.str)
+	}
+
+	return strs
+}
+
+// formatDidYouMean returns a hint listing similar strings.
+// It returns an empty string if there is no similar string.
+func formatDidYouMean(msgs Messages, similar []string) string {
+	if len(similar) == 0 {
+		return ""
+	}
+
+	quoted := make([]string, 0, len(similar))
+
+	for _, s := range similar {
+		quoted = append(quoted, strconv.Quote(s))
+	}
+
+	return fmt.Sprintf(msgs.DidYouMeanFormat, strings.Join(quoted, ", "))
+}
+
+// stringKeys returns all keys of string kind in map m.
+func stringKeys(m reflect.Value) []string {
+	if m.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+
+	keys := make([]string, 0, m.Len())
+	iter := m.MapRange()
+
+	for iter.Next() {
+		keys = append(keys, iter.Key().String())
+	}
+
+	return keys
+}
+
+// formatKeyTypoNotes returns notes if a string key only in [1] is likely a typo of a key only in [2]
+// in the same map.
+func formatKeyTypoNotes(msgs Messages, d *differ) string {
+	var lines []string
+
+	for _, e1 := range d.Entries {
+		if e1.Kind != diffOnlyIn1 || !e1.Key.IsValid() || e1.Key.Kind() != reflect.String {
+			continue
+		}
+
+		parent := entryParent(e1)
+
+		for _, e2 := range d.Entries {
+			if e2.Kind != diffOnlyIn2 || !e2.Key.IsValid() || e2.Key.Kind() != reflect.String || entryParent(e2) != parent {
+				continue
+			}
+
+			if typoDistance(e1.Key.String(), e2.Key.String()) > 0 {
+				lines = append(lines, "    "+fmt.Sprintf(msgs.KeyTypoFormat, e1.Path, e2.Path))
+			}
+		}
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return "\n" + msgs.Notes + "\n" + strings.Join(lines, "\n")
+}
+
+// entryParent returns the path of the map containing the entry.
+func entryParent(e diffEntry) string {
+	return strings.TrimSuffix(e.Path, "["+formatKey(e.Key)+"]")
+}
@@ -0,0 +1,101 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		S1, S2   string
+		Distance int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"timeout", "tiemout", 1},
+		{"ca", "abc", 3},
+		{"你好", "你们好", 1},
+	}
+
+	for i, c := range cases {
+		t.Logf("case %v: %v", i, c)
+		assertEqual(t, editDistance(c.S1, c.S2), c.Distance)
+		assertEqual(t, editDistance(c.S2, c.S1), c.Distance)
+	}
+}
+
+func TestSimilarStrings(t *testing.T) {
+	candidates := []string{"timeout", "timeouts", "retries", "time", "host"}
+	assertEqual(t, similarStrings("tiemout", candidates), []string{"timeout", "timeouts"})
+	assertEqual(t, similarStrings("retry", candidates), []string{})
+	assertEqual(t, similarStrings("hots", candidates), []string{"host"})
+	assertEqual(t, similarStrings("timeout", candidates), []string{"timeouts"})
+}
+
+func TestAssertHasKey(t *testing.T) {
+	ft := NewFakeT("TestAssertHasKey")
+	config := map[string]int{"timeout": 30, "retries": 3}
+	codes := map[int8]string{1: "one"}
+	trigger := &Trigger{
+		FuncName: "AssertHasKey",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true},
+	}
+
+	AssertHasKey(ft, config, "timeout", trigger)
+	AssertHasKey(ft, &config, "retries", trigger)
+	AssertHasKey(ft, codes, 1, trigger)
+	assertEqual(t, len(ft.Messages()), 0)
+
+	AssertHasKey(ft, config, "tiemout", trigger)
+	AssertHasKey(ft, codes, "1", trigger)
+	AssertHasKey(ft, 1, "1", trigger)
+	msgs := ft.Messages()
+
+	assertEqual(t, len(msgs), 3)
+	assertEqual(t, strings.Contains(msgs[0], `Did you mean "timeout"?`), true)
+	assertEqual(t, strings.Contains(msgs[1], "Did you mean"), false)
+	assertEqual(t, strings.Contains(msgs[2], "expect a map but got int"), true)
+}
+
+func TestFormatKeyTypoNotes(t *testing.T) {
+	msgs := DefaultMessages
+	m1 := map[string]int{"timeout": 1, "host": 2}
+	m2 := map[string]int{"timeuot": 1, "port": 2}
+	notes := formatValuesDiff(msgs, DefaultDumper, m1, m2, UnexportedCompare)
+	assertEqual(t, strings.HasSuffix(notes, "\nNotes:\n    [\"timeout\"] only in [1] looks like a typo of [\"timeuot\"] only in [2]."), true)
+
+	// Strings with typos are explained in Equal.
+	assertEqual(t, equalNote(msgs, "timeout", "timeuot"), "Edit distance between strings is 1. Check for typos.")
+	assertEqual(t, equalNote(msgs, "timeout", "retries"), "")
+}
+
+func TestTypoDistance(t *testing.T) {
+	cases := []struct {
+		S1, S2   string
+		Distance int
+	}{
+		{"timeout", "timeuot", 1},
+		{"host", "hots", 1},
+		{"retries", "retry", 0},
+		{"200", "201", 0},
+		{"a", "b", 0},
+		{"port", "ports", 1},
+		{"port", "sort", 1},
+		{"port", "spot", 0},
+	}
+
+	for i, c := range cases {
+		t.Logf("case %v: %v", i, c)
+		assertEqual(t, typoDistance(c.S1, c.S2), c.Distance)
+	}
+
+	// Short keys are not typos of each other.
+	m1 := map[string]int{"a": 1}
+	m2 := map[string]int{"b": 1}
+	assertEqual(t, strings.Contains(formatValuesDiff(DefaultMessages, DefaultDumper, m1, m2, UnexportedCompare), "typo"), false)
+}
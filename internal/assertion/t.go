@@ -0,0 +1,24 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"testing"
+)
+
+// T is the subset of `testing.TB` used by assertions.
+// Both *testing.T and *FakeT implement it.
+type T interface {
+	Name() string
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+var (
+	_ T = (*testing.T)(nil)
+	_ T = (*testing.B)(nil)
+	_ T = (*FakeT)(nil)
+	_ T = (*PanicT)(nil)
+)
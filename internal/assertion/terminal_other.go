@@ -0,0 +1,12 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package assertion
+
+// terminalWidth returns 0 as terminal width detection is not supported on this platform.
+func terminalWidth() int {
+	return 0
+}
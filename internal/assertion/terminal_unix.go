@@ -0,0 +1,29 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+package assertion
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// terminalWidth returns the width of the terminal attached to stdout.
+// It returns 0 if stdout is not a terminal.
+func terminalWidth() int {
+	var ws struct {
+		Row, Col, Xpixel, Ypixel uint16
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, os.Stdout.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+
+	if errno != 0 {
+		return 0
+	}
+
+	return int(ws.Col)
+}
@@ -0,0 +1,186 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultTraceSize is the number of recent assertion sites kept by a Tracer by default.
+const DefaultTraceSize = 16
+
+// Tracer keeps call sites of recently executed assertions in a ring buffer.
+// Only program counters are recorded, so that recording is cheap enough for passing assertions.
+// Sites are resolved to file names and lines when they are read.
+type Tracer struct {
+	lock  sync.Mutex
+	pcs   []uintptr
+	next  int
+	count int
+
+	// enabled is set by EnableReports. Sites are printed in assertion failures only if it's set.
+	enabled bool
+
+	// reported is set when sites are printed in an assertion failure.
+	// reportedCount is the count when sites are printed last time.
+	reported      bool
+	reportedCount int
+}
+
+// failedLogger is the subset of `testing.TB` used to log sites after a test case fails.
+type failedLogger interface {
+	Failed() bool
+	Logf(format string, args ...interface{})
+}
+
+// NewTracer creates a Tracer keeping size recent assertion sites.
+// If size is not positive, DefaultTraceSize is used.
+func NewTracer(size int) *Tracer {
+	if size <= 0 {
+		size = DefaultTraceSize
+	}
+
+	return &Tracer{
+		pcs: make([]uintptr, size),
+	}
+}
+
+// Record records the call site at skip frames.
+// If skip is 0, the frame calling Record is recorded.
+func (tr *Tracer) Record(skip int) {
+	var pc [1]uintptr
+
+	if runtime.Callers(skip+2, pc[:]) == 0 {
+		return
+	}
+
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	tr.pcs[tr.next] = pc[0]
+	tr.next = (tr.next + 1) % len(tr.pcs)
+	tr.count++
+}
+
+// Sites returns recorded call sites, the most recent last.
+// A site is formatted as "file.go:123 pkg.Function".
+func (tr *Tracer) Sites() []string {
+	tr.lock.Lock()
+	n := tr.count
+
+	if n > len(tr.pcs) {
+		n = len(tr.pcs)
+	}
+
+	pcs := make([]uintptr, 0, n)
+
+	for i := tr.next - n; i < tr.next; i++ {
+		pcs = append(pcs, tr.pcs[(i+len(tr.pcs))%len(tr.pcs)])
+	}
+
+	tr.lock.Unlock()
+
+	sites := make([]string, 0, len(pcs))
+
+	for _, pc := range pcs {
+		// CallersFrames adjusts the return address in pc to the line of the call.
+		frames := runtime.CallersFrames([]uintptr{pc})
+		frame, _ := frames.Next()
+		sites = append(sites, path.Base(frame.File)+":"+strconv.Itoa(frame.Line)+" "+frame.Function)
+	}
+
+	return sites
+}
+
+// EnableReports prints recorded sites in every assertion failure reported with tr.
+// Sites are also logged after the test case completes, if the test case fails without any assertion failure
+// printing these sites, e.g. it panics or fails by `t.Errorf`, or there are assertions executed after that.
+// Sites are not logged unless t implements `Cleanup(func())`, `Failed() bool` and `Logf(string, ...interface{})`.
+func (tr *Tracer) EnableReports(t T) {
+	tr.lock.Lock()
+	tr.enabled = true
+	tr.lock.Unlock()
+
+	c, ok := t.(cleaner)
+
+	if !ok {
+		return
+	}
+
+	l, ok := t.(failedLogger)
+
+	if !ok {
+		return
+	}
+
+	c.Cleanup(func() {
+		tr.logIfUnreported(l)
+	})
+}
+
+func (tr *Tracer) logIfUnreported(t failedLogger) {
+	// Cleanups run before a panicking test case is marked as failed.
+	if !t.Failed() && !panicking() {
+		return
+	}
+
+	tr.lock.Lock()
+	reported := tr.reported && tr.reportedCount == tr.count
+	tr.lock.Unlock()
+
+	if reported {
+		return
+	}
+
+	t.Logf("\n%v", formatTrace(CurrentMessages(), tr.Sites()))
+}
+
+// panicking returns true if current goroutine is panicking,
+// e.g. it's running cleanups of a panicking test case.
+func panicking() bool {
+	var pcs [64]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+
+		if frame.Function == "runtime.gopanic" {
+			return true
+		}
+
+		if !more {
+			return false
+		}
+	}
+}
+
+// reportsEnabled returns true if EnableReports has been called.
+func (tr *Tracer) reportsEnabled() bool {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	return tr.enabled
+}
+
+// report returns recorded sites and marks them as printed in an assertion failure.
+func (tr *Tracer) report() []string {
+	tr.lock.Lock()
+	tr.reported = true
+	tr.reportedCount = tr.count
+	tr.lock.Unlock()
+
+	return tr.Sites()
+}
+
+// formatTrace formats sites as a section of failure message.
+func formatTrace(msgs Messages, sites []string) string {
+	if len(sites) == 0 {
+		return msgs.RecentAssertions + "\n    " + msgs.NoRecentAssertions
+	}
+
+	return msgs.RecentAssertions + "\n    " + strings.Join(sites, "\n    ")
+}
@@ -0,0 +1,104 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTracer(t *testing.T) {
+	tracer := NewTracer(3)
+	assertEqual(t, len(tracer.Sites()), 0)
+
+	for i := 0; i < 4; i++ {
+		tracer.Record(0)
+	}
+
+	tracer.Record(0)
+	sites := tracer.Sites()
+
+	assertEqual(t, len(sites), 3)
+	assertEqual(t, sites[0], sites[1])
+	assertEqual(t, sites[0] == sites[2], false)
+
+	for _, site := range sites {
+		assertEqual(t, strings.HasPrefix(site, "trace_test.go:"), true)
+		assertEqual(t, strings.HasSuffix(site, "/internal/assertion.TestTracer"), true)
+	}
+}
+
+func TestTracerInFailure(t *testing.T) {
+	ft := NewFakeT("TestTracerInFailure")
+	tracer := NewTracer(0)
+	tracer.Record(0)
+	trigger := &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true, Tracer: tracer},
+	}
+
+	// Sites are not printed unless reports are enabled.
+	AssertEqual(ft, 1, 2, trigger)
+	assertEqual(t, strings.Contains(ft.Messages()[0], "Recently executed assertions"), false)
+
+	ft.Reset()
+	tracer.EnableReports(ft)
+	AssertEqual(ft, 1, 2, &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true, Tracer: tracer},
+	})
+	msgs := ft.Messages()
+
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "Recently executed assertions, the most recent last:\n    trace_test.go:"), true)
+
+	// Sites have been printed in the failure.
+	n := len(ft.Calls())
+	tracer.logIfUnreported(ft)
+	assertEqual(t, len(ft.Calls()), n)
+
+	// A site is recorded after the failure.
+	tracer.Record(0)
+	tracer.logIfUnreported(ft)
+	calls := ft.Calls()
+	assertEqual(t, calls[len(calls)-1].Method, "Logf")
+}
+
+func TestTracerLogIfUnreported(t *testing.T) {
+	ft := NewFakeT("TestTracerLogIfUnreported")
+	tracer := NewTracer(0)
+	tracer.Record(0)
+
+	tracer.logIfUnreported(ft)
+	assertEqual(t, len(ft.Calls()), 0)
+
+	ft.Errorf("failed")
+	tracer.logIfUnreported(ft)
+	calls := ft.Calls()
+
+	assertEqual(t, len(calls), 2)
+	assertEqual(t, calls[1].Method, "Logf")
+	assertEqual(t, strings.Contains(calls[1].Message, "Recently executed assertions, the most recent last:\n    trace_test.go:"), true)
+}
+
+func TestTracerLogIfUnreportedPanicking(t *testing.T) {
+	ft := NewFakeT("TestTracerLogIfUnreportedPanicking")
+	tracer := NewTracer(0)
+	tracer.Record(0)
+
+	// Cleanups of a panicking test case run before it's marked as failed.
+	func() {
+		defer func() {
+			assertEqual(t, recover(), "boom")
+		}()
+		defer tracer.logIfUnreported(ft)
+		panic("boom")
+	}()
+
+	calls := ft.Calls()
+	assertEqual(t, len(calls), 1)
+	assertEqual(t, calls[0].Method, "Logf")
+}
@@ -0,0 +1,63 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// typedNil returns the type of the nil value held by interface v.
+// It returns nil if v is not an interface holding a typed nil,
+// e.g. `error((*MyErr)(nil))`.
+func typedNil(v reflect.Value) reflect.Type {
+	if v.Kind() != reflect.Interface || v.IsNil() {
+		return nil
+	}
+
+	if elem := v.Elem(); isNil(elem) {
+		return elem.Type()
+	}
+
+	return nil
+}
+
+// formatTypedNilNotes returns notes for vars in names which are interfaces holding typed nil values.
+// Only vars registered by `Use` can be checked.
+func formatTypedNilNotes(msgs Messages, names []string, vars map[string]interface{}) (notes string, found map[string]struct{}) {
+	if len(names) == 0 || len(vars) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, len(names)+1)
+	lines = append(lines, "\n"+msgs.Notes)
+	found = make(map[string]struct{})
+
+	for _, name := range names {
+		v, ok := vars[name]
+
+		if !ok {
+			continue
+		}
+
+		val := reflect.ValueOf(v)
+
+		if !val.IsValid() || val.Kind() != reflect.Ptr || val.IsNil() {
+			continue
+		}
+
+		if typ := typedNil(val.Elem()); typ != nil {
+			lines = append(lines, "    "+fmt.Sprintf(msgs.TypedNilFormat, name, typ))
+			found[name] = struct{}{}
+		}
+	}
+
+	if len(found) == 0 {
+		return
+	}
+
+	notes = strings.Join(lines, "\n")
+	return
+}
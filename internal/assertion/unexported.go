@@ -0,0 +1,151 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"reflect"
+)
+
+// UnexportedMode sets how Equal and NotEqual handle unexported struct fields.
+type UnexportedMode int
+
+// Valid unexported modes.
+const (
+	UnexportedCompare UnexportedMode = iota // Compare unexported fields like exported fields. It's the default mode.
+	UnexportedIgnore                        // Exclude unexported fields from comparison.
+	UnexportedLabel                         // Compare unexported fields and label them in differences.
+)
+
+// prepareCompared returns copies of v1 and v2 prepared for comparison in Equal and NotEqual
+// according to opts, e.g. with sorted slices or cleared unexported fields.
+// v1 and v2 are returned as is if opts doesn't change them.
+func prepareCompared(v1, v2 interface{}, opts Options) (interface{}, interface{}) {
+	v1 = sortSlices(v1, opts.SliceSorts)
+	v2 = sortSlices(v2, opts.SliceSorts)
+
+	if opts.Unexported == UnexportedIgnore {
+		v1 = clearUnexported(v1)
+		v2 = clearUnexported(v2)
+	}
+
+	return v1, v2
+}
+
+// clearUnexported returns a copy of v in which all unexported struct fields are zero values,
+// so that they are excluded from comparison.
+//
+// Structs without exported fields, e.g. `time.Time` or `sync.Mutex`, and types registered
+// by RegisterComparator or RegisterCanonical are opaque values and kept as is.
+func clearUnexported(v interface{}) interface{} {
+	if v == nil {
+		return v
+	}
+
+	c := &unexportedClearer{
+		copied: map[uintptr]reflect.Value{},
+	}
+	return c.copy(reflect.ValueOf(v)).Interface()
+}
+
+type unexportedClearer struct {
+	copied map[uintptr]reflect.Value // Copied pointers to keep cycles and shared pointers.
+}
+
+func (c *unexportedClearer) copy(v reflect.Value) reflect.Value {
+	if findComparator(v.Type()) != nil {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+
+		if copied, ok := c.copied[v.Pointer()]; ok {
+			return copied
+		}
+
+		ptr := reflect.New(v.Type().Elem())
+		c.copied[v.Pointer()] = ptr
+		ptr.Elem().Set(c.copy(v.Elem()))
+		return ptr
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		iface := reflect.New(v.Type()).Elem()
+		iface.Set(c.copy(v.Elem()))
+		return iface
+
+	case reflect.Struct:
+		if isHumanizedStruct(v.Type()) || !hasExportedField(v.Type()) {
+			return v
+		}
+
+		st := reflect.New(v.Type()).Elem()
+
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.PkgPath == "" {
+				st.Field(i).Set(c.copy(v.Field(i)))
+			}
+		}
+
+		return st
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), c.copy(iter.Value()))
+		}
+
+		return m
+
+	case reflect.Array:
+		if isScalar(v.Type().Elem()) {
+			return v
+		}
+
+		arr := reflect.New(v.Type()).Elem()
+
+		for i := 0; i < v.Len(); i++ {
+			arr.Index(i).Set(c.copy(v.Index(i)))
+		}
+
+		return arr
+
+	case reflect.Slice:
+		if v.IsNil() || isScalar(v.Type().Elem()) {
+			return v
+		}
+
+		slice := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			slice.Index(i).Set(c.copy(v.Index(i)))
+		}
+
+		return slice
+	}
+
+	return v
+}
+
+func hasExportedField(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			return true
+		}
+	}
+
+	return false
+}
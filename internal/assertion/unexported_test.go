@@ -0,0 +1,77 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type unexportedUser struct {
+	Name    string
+	Created time.Time
+	Tags    []*unexportedTag
+	cache   map[string]string
+}
+
+type unexportedTag struct {
+	Label string
+	hits  int
+}
+
+func TestClearUnexported(t *testing.T) {
+	now := time.Now()
+	u := &unexportedUser{
+		Name:    "Alice",
+		Created: now,
+		Tags:    []*unexportedTag{{Label: "admin", hits: 3}},
+		cache:   map[string]string{"k": "v"},
+	}
+	cleared := clearUnexported(u).(*unexportedUser)
+
+	assertEqual(t, cleared.Name, "Alice")
+	assertEqual(t, cleared.Created, now)
+	assertEqual(t, cleared.Tags[0].Label, "admin")
+	assertEqual(t, cleared.Tags[0].hits, 0)
+	assertEqual(t, cleared.cache == nil, true)
+
+	// The original value is not modified.
+	assertEqual(t, u.Tags[0].hits, 3)
+	assertEqual(t, u.cache["k"], "v")
+
+	assertEqual(t, clearUnexported(nil), nil)
+	assertEqual(t, clearUnexported(42), 42)
+}
+
+func TestEqualUnexported(t *testing.T) {
+	u1 := unexportedUser{Name: "Alice", cache: map[string]string{"k": "v"}}
+	u2 := unexportedUser{Name: "Alice"}
+	ft := NewFakeT("TestEqualUnexported")
+	trigger := &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true, Unexported: UnexportedIgnore},
+	}
+
+	AssertEqual(ft, u1, u2, trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	AssertNotEqual(ft, u1, u2, trigger)
+	assertEqual(t, ft.Failed(), true)
+
+	ft.Reset()
+	trigger.Options.Unexported = UnexportedLabel
+	AssertEqual(ft, u1, unexportedUser{Name: "Bob"}, trigger)
+	msg := stripColors(ft.Messages()[0])
+	assertEqual(t, strings.Contains(msg, ".Name:"), true)
+	assertEqual(t, strings.Contains(msg, ".cache (unexported):"), true)
+
+	ft.Reset()
+	trigger.Options.Unexported = UnexportedCompare
+	AssertEqual(ft, u1, u2, trigger)
+	msg = stripColors(ft.Messages()[0])
+	assertEqual(t, strings.Contains(msg, ".cache:"), true)
+	assertEqual(t, strings.Contains(msg, "(unexported)"), false)
+}
@@ -0,0 +1,164 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// maxShortString is the max number of runes in a string to be marked by a caret when strings differ.
+const maxShortString = 80
+
+// StringNormalization normalizes strings before they are compared by Equal and NotEqual.
+// It applies to compared values which are strings of the same type.
+type StringNormalization struct {
+	Normalize bool      // Normalize strings in Form.
+	Form      norm.Form // Unicode normalization form.
+	FoldCase  bool      // Fold case of strings before normalization.
+}
+
+// enabled returns true if n changes strings.
+func (n StringNormalization) enabled() bool {
+	return n.Normalize || n.FoldCase
+}
+
+// apply returns normalized s.
+func (n StringNormalization) apply(s string) string {
+	if n.FoldCase {
+		s = cases.Fold().String(s)
+	}
+
+	if n.Normalize {
+		s = n.Form.String(s)
+	}
+
+	return s
+}
+
+// normalizeStrings returns normalized v1 and v2 if n is enabled
+// and both v1 and v2 are strings of the same type.
+func normalizeStrings(v1, v2 interface{}, n StringNormalization) (s1, s2 string, ok bool) {
+	if !n.enabled() {
+		return
+	}
+
+	val1 := reflect.ValueOf(v1)
+	val2 := reflect.ValueOf(v2)
+
+	if val1.Kind() != reflect.String || !val2.IsValid() || val1.Type() != val2.Type() {
+		return
+	}
+
+	return n.apply(val1.String()), n.apply(val2.String()), true
+}
+
+// formatRuneDiff formats the first differing rune of normalized s1 and s2 with its code point.
+func formatRuneDiff(msgs Messages, s1, s2 string) string {
+	r1 := []rune(s1)
+	r2 := []rune(s2)
+	idx := firstDiffRune(r1, r2)
+
+	return strings.Join([]string{
+		fmt.Sprintf(msgs.RuneDiffFormat, idx),
+		"    [1] -> " + describeRune(msgs, r1, idx),
+		"    [2] -> " + describeRune(msgs, r2, idx),
+	}, "\n")
+}
+
+// describeRune describes the rune at idx in runes with its code point.
+func describeRune(msgs Messages, runes []rune, idx int) string {
+	if idx >= len(runes) {
+		return msgs.EndOfString
+	}
+
+	return fmt.Sprintf("%q %U", runes[idx], runes[idx])
+}
+
+// firstDiffRune returns the index of the first differing rune in r1 and r2.
+func firstDiffRune(r1, r2 []rune) int {
+	idx := 0
+
+	for idx < len(r1) && idx < len(r2) && r1[idx] == r2[idx] {
+		idx++
+	}
+
+	return idx
+}
+
+// shortStrings returns v1 and v2 as strings if both are short single-line strings of the same type.
+func shortStrings(v1, v2 interface{}) (s1, s2 string, ok bool) {
+	val1 := reflect.ValueOf(v1)
+	val2 := reflect.ValueOf(v2)
+
+	if val1.Kind() != reflect.String || !val2.IsValid() || val1.Type() != val2.Type() {
+		return
+	}
+
+	s1, s2 = val1.String(), val2.String()
+
+	if !isShortString(s1) || !isShortString(s2) {
+		return
+	}
+
+	return s1, s2, true
+}
+
+func isShortString(s string) bool {
+	return !strings.Contains(s, "\n") && utf8.RuneCountInString(s) <= maxShortString
+}
+
+// formatStringDiff formats quoted s1 and s2 with a caret under the first differing rune in s1
+// and code points of the differing runes, so that differences in invisible characters are obvious.
+func formatStringDiff(msgs Messages, s1, s2 string) string {
+	r1 := []rune(s1)
+	r2 := []rune(s2)
+	idx := firstDiffRune(r1, r2)
+
+	// Find the byte offset of the differing rune, so that invalid bytes are quoted as they are.
+	offset := 0
+
+	for i := 0; i < idx; i++ {
+		_, size := utf8.DecodeRuneInString(s1[offset:])
+		offset += size
+	}
+
+	// The quoted prefix ends with a closing quote, which is where the differing rune starts.
+	prefix := strconv.Quote(s1[:offset])
+	col := displayWidth(prefix[:len(prefix)-1])
+
+	return strings.Join([]string{
+		fmt.Sprintf(msgs.StringDiffFormat, idx),
+		"    [1] " + strconv.Quote(s1),
+		"        " + strings.Repeat(" ", col) + "^",
+		"    [2] " + strconv.Quote(s2),
+		"    [1] -> " + describeRune(msgs, r1, idx),
+		"    [2] -> " + describeRune(msgs, r2, idx),
+	}, "\n")
+}
+
+// displayWidth returns the number of columns taken by printable s in a terminal.
+func displayWidth(s string) int {
+	w := 0
+
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case width.LookupRune(r).Kind() == width.EastAsianWide, width.LookupRune(r).Kind() == width.EastAsianFullwidth:
+			w += 2
+		default:
+			w++
+		}
+	}
+
+	return w
+}
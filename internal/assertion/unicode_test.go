@@ -0,0 +1,96 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestFormatRuneDiff(t *testing.T) {
+	cases := []struct {
+		s1, s2   string
+		expected string
+	}{
+		{"cafe\u0301", "cafe", "Normalized strings differ at rune 4:\n    [1] -> '\u0301' U+0301\n    [2] -> (end of string)"},
+		{"abc", "abd", "Normalized strings differ at rune 2:\n    [1] -> 'c' U+0063\n    [2] -> 'd' U+0064"},
+		{"", "a", "Normalized strings differ at rune 0:\n    [1] -> (end of string)\n    [2] -> 'a' U+0061"},
+	}
+
+	for _, c := range cases {
+		assertEqual(t, formatRuneDiff(DefaultMessages, c.s1, c.s2), c.expected)
+	}
+}
+
+func TestAssertEqualNormalizeStrings(t *testing.T) {
+	type Name string
+
+	ft := NewFakeT("TestAssertEqualNormalizeStrings")
+	trigger := &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options: Options{
+			Strings: StringNormalization{
+				Normalize: true,
+				Form:      norm.NFC,
+				FoldCase:  true,
+			},
+		},
+	}
+
+	AssertEqual(ft, "Caf\u00e9", "CAFE\u0301", trigger)
+	AssertEqual(ft, Name("Straße"), Name("STRASSE"), trigger)
+	AssertNotEqual(ft, "caf\u00e9", "cafe", trigger)
+	assertEqual(t, ft.Failed(), false)
+
+	// Strings of different types are not normalized.
+	AssertEqual(ft, Name("a"), "A", trigger)
+	assertEqual(t, ft.Fatal(), true)
+
+	ft.Reset()
+	AssertEqual(ft, "caf\u00e9", "cafe", trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, strings.Contains(msgs[0], "Normalized strings differ at rune 3:\n    [1] -> 'é' U+00E9\n    [2] -> 'e' U+0065\nValues:"), true)
+
+	ft.Reset()
+	AssertNotEqual(ft, "A\u030a", "\u00e5", trigger)
+	assertEqual(t, ft.Fatal(), true)
+}
+
+func TestFormatStringDiff(t *testing.T) {
+	cases := []struct {
+		s1, s2   string
+		expected string
+	}{
+		{"a b", "a\tb", "Strings differ at rune 1:\n    [1] \"a b\"\n          ^\n    [2] \"a\\tb\"\n    [1] -> ' ' U+0020\n    [2] -> '\\t' U+0009"},
+		{"\tx\u00a0", "\tx ", "Strings differ at rune 2:\n    [1] \"\\tx\\u00a0\"\n            ^\n    [2] \"\\tx \"\n    [1] -> '\\u00a0' U+00A0\n    [2] -> ' ' U+0020"},
+		{"你好", "你", "Strings differ at rune 1:\n    [1] \"你好\"\n           ^\n    [2] \"你\"\n    [1] -> '好' U+597D\n    [2] -> (end of string)"},
+		{"ab", "abc", "Strings differ at rune 2:\n    [1] \"ab\"\n           ^\n    [2] \"abc\"\n    [1] -> (end of string)\n    [2] -> 'c' U+0063"},
+	}
+
+	for _, c := range cases {
+		assertEqual(t, formatStringDiff(DefaultMessages, c.s1, c.s2), c.expected)
+	}
+}
+
+func TestAssertEqualShortStrings(t *testing.T) {
+	ft := NewFakeT("TestAssertEqualShortStrings")
+	trigger := &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true},
+	}
+
+	AssertEqual(ft, "hello world", "hello\u00a0world", trigger)
+	AssertEqual(ft, "line 1\nline 2", "line 1\nline 3", trigger)
+	AssertEqual(ft, strings.Repeat("a", maxShortString+1), strings.Repeat("b", maxShortString+1), trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 3)
+	assertEqual(t, strings.Contains(msgs[0], "Strings differ at rune 5:"), true)
+	assertEqual(t, strings.Contains(msgs[1], "Strings differ"), false)
+	assertEqual(t, strings.Contains(msgs[2], "Strings differ"), false)
+}
@@ -0,0 +1,138 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// watchdogOutput receives the dump of a fired watchdog.
+// It's replaced in tests.
+var watchdogOutput io.Writer = os.Stderr
+
+// Watchdog reports a test case which doesn't complete in time.
+// When it fires, stacks of all goroutines and recently executed assertion sites are written to stderr at once,
+// so that they are printed even if the test binary is killed by the global test timeout later.
+// The same dump is reported as a non-fatal failure to t.
+type Watchdog struct {
+	t       T
+	trigger *Trigger
+	tracer  *Tracer
+	timeout time.Duration
+	timer   *time.Timer
+
+	// Location of the call creating the watchdog.
+	// A failure is reported at this location as the watchdog fires in its own goroutine.
+	filename string
+	line     int
+	function string
+
+	// lock is held while the watchdog fires, so that Stop waits for the failure to be reported.
+	// t must not be used after the test case completes.
+	lock    sync.Mutex
+	stopped bool
+}
+
+// StartWatchdog starts a watchdog firing if the test case doesn't complete in timeout.
+// The tracer provides recently executed assertion sites. It can be nil.
+// The trigger.Skip must be the stack frame calling the function starting the watchdog.
+//
+// If t implements `Cleanup(func())`, the watchdog is stopped after the test case completes.
+// Otherwise, Stop must be called.
+func StartWatchdog(t T, timeout time.Duration, tracer *Tracer, trigger *Trigger) *Watchdog {
+	w := &Watchdog{
+		t:       t,
+		trigger: trigger,
+		tracer:  tracer,
+		timeout: timeout,
+	}
+
+	w.filename, w.line, w.function, _ = findCaller(trigger.Skip + 1)
+	w.timer = time.AfterFunc(timeout, w.fire)
+
+	if c, ok := t.(cleaner); ok {
+		c.Cleanup(w.Stop)
+	}
+
+	return w
+}
+
+// Stop stops the watchdog.
+// If the watchdog is firing, Stop waits until the failure is reported.
+func (w *Watchdog) Stop() {
+	w.timer.Stop()
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.stopped = true
+}
+
+func (w *Watchdog) fire() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.stopped {
+		return
+	}
+
+	w.stopped = true
+	msgs := CurrentMessages()
+	header := fmt.Sprintf(msgs.WatchdogFormat, w.t.Name(), formatDuration(w.timeout))
+	var sites []string
+
+	if w.tracer != nil {
+		sites = w.tracer.report()
+	}
+
+	// The watchdog fires in its own goroutine, in which `t.Fatalf` must not be called
+	// and the benchmark timer must not be touched.
+	trigger := *w.trigger
+	trigger.Options.NonFatal = true
+	trigger.Options.Timer = nil
+
+	// Sites are printed by the watchdog itself.
+	trigger.Options.Tracer = nil
+
+	stacks := allGoroutineStacks()
+	trace := formatTrace(msgs, sites)
+	dump := fmt.Sprintf("%v\n%v\n%v\n    %v\n", header, trace, msgs.GoroutineStacks, indentCode(stacks, 4))
+
+	if !trigger.colorEnabled() {
+		dump = stripColors(dump)
+	}
+
+	io.WriteString(watchdogOutput, dump)
+
+	if w.filename == "" {
+		failInternal(w.t, &trigger, fmt.Errorf("fail to read source code information of watchdog; %v", header))
+		return
+	}
+
+	f, err := trigger.P().parseCall(w.filename, w.line, w.function, trigger.FuncName, nil)
+
+	if err != nil {
+		failInternal(w.t, &trigger, err)
+		return
+	}
+
+	info := trigger.P().ParseInfo(f)
+	fail(w.t, &trigger, &Failure{
+		FuncName: trigger.FuncName,
+		Filename: f.Filename,
+		Line:     f.Line,
+		Source:   info.Source,
+		ID:       info.ID,
+		Message: fmt.Sprintf("%v:%v: %v\n    %v\n%v\n%v\n%v\n    %v%v",
+			f.Filename, f.Line, msgs.AssertionFailed, indentCode(info.Source, 4), header, trace,
+			msgs.GoroutineStacks, indentCode(stacks, 4),
+			formatVars(msgs, info, &trigger),
+		),
+		Values: []string{stacks},
+		Trace:  sites,
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	watchdogOutput = buf
+	defer func() {
+		watchdogOutput = os.Stderr
+	}()
+
+	ft := NewFakeT("TestWatchdog")
+	tracer := NewTracer(0)
+	tracer.Record(0)
+	w := StartWatchdog(ft, 10*time.Millisecond, tracer, &Trigger{
+		FuncName: "StartWatchdog",
+	})
+
+	for i := 0; i < 100 && !ft.Failed(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	w.Stop()
+	msgs := ft.Messages()
+
+	assertEqual(t, len(msgs), 1)
+	assertEqual(t, ft.Fatal(), false)
+
+	for _, msg := range []string{msgs[0], buf.String()} {
+		msg = stripColors(msg)
+		assertEqual(t, strings.Contains(msg, "Test case TestWatchdog has not completed in 10ms."), true)
+		assertEqual(t, strings.Contains(msg, "Recently executed assertions, the most recent last:"), true)
+		assertEqual(t, strings.Contains(msg, "watchdog_test.go:"), true)
+		assertEqual(t, strings.Contains(msg, "assertion.TestWatchdog"), true)
+		assertEqual(t, strings.Contains(msg, "Goroutine stacks:"), true)
+	}
+
+	assertEqual(t, strings.Contains(stripColors(msgs[0]), "StartWatchdog(ft, 10*time.Millisecond, tracer, &Trigger{"), true)
+}
+
+func TestWatchdogStopped(t *testing.T) {
+	ft := NewFakeT("TestWatchdogStopped")
+	w := StartWatchdog(ft, 10*time.Millisecond, nil, &Trigger{
+		FuncName: "StartWatchdog",
+	})
+	w.Stop()
+	time.Sleep(30 * time.Millisecond)
+
+	assertEqual(t, ft.Failed(), false)
+}
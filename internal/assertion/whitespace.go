@@ -0,0 +1,52 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+)
+
+// Symbols replacing invisible whitespace in dumps.
+const (
+	visibleTab           = "→"
+	visibleTrailingSpace = "·"
+	visibleCR            = "␍"
+	visibleNBSP          = "⍽"
+)
+
+var whitespaceReplacer = strings.NewReplacer(
+	"\t", visibleTab,
+	"\r", visibleCR,
+	"\u00a0", visibleNBSP,
+)
+
+// visibleDumper renders whitespace in dumps visibly,
+// so that strings which look identical but differ in whitespace can be told apart.
+type visibleDumper struct {
+	Dumper
+}
+
+func (d visibleDumper) Dump(v interface{}) string {
+	return showWhitespace(d.Dumper.Dump(v))
+}
+
+// showWhitespace replaces tabs, CRs and non-breaking spaces in s with visible symbols
+// and marks spaces at the end of every line in s, including spaces before a CRLF.
+func showWhitespace(s string) string {
+	lines := strings.Split(s, "\n")
+
+	for i, line := range lines {
+		cr := ""
+
+		if strings.HasSuffix(line, "\r") {
+			line = line[:len(line)-1]
+			cr = visibleCR
+		}
+
+		trimmed := strings.TrimRight(line, " ")
+		lines[i] = whitespaceReplacer.Replace(trimmed) + strings.Repeat(visibleTrailingSpace, len(line)-len(trimmed)) + cr
+	}
+
+	return strings.Join(lines, "\n")
+}
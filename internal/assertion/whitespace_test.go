@@ -0,0 +1,44 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assertion
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShowWhitespace(t *testing.T) {
+	cases := []struct {
+		s, expected string
+	}{
+		{"a\tb", "a→b"},
+		{"a \r\nb  ", "a·␍\nb··"},
+		{"a\u00a0b", "a⍽b"},
+		{"a b", "a b"},
+	}
+
+	for _, c := range cases {
+		assertEqual(t, showWhitespace(c.s), c.expected)
+	}
+}
+
+func TestAssertEqualVisibleWhitespace(t *testing.T) {
+	type Line struct {
+		Text string
+	}
+
+	ft := NewFakeT("TestAssertEqualVisibleWhitespace")
+	trigger := &Trigger{
+		FuncName: "AssertEqual",
+		Args:     []int{1, 2},
+		Options:  Options{NonFatal: true, VisibleWhitespace: true},
+	}
+
+	AssertEqual(ft, "a\tb ", "a b", trigger)
+	AssertEqual(ft, Line{"a\tb"}, Line{"a b"}, trigger)
+	msgs := ft.Messages()
+	assertEqual(t, len(msgs), 2)
+	assertEqual(t, strings.Contains(msgs[0], "[1] -> (string)a→b·\n"), true)
+	assertEqual(t, strings.Contains(msgs[1], "[1] -> (string)a→b\n"), true)
+}
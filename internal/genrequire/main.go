@@ -0,0 +1,223 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Command genrequire generates assertion functions in package require and expect.
+// Both packages are generated from the same list of functions, so that they never drift apart.
+// Functions in require stop the test case on failure and functions in expect don't.
+//
+// Run it by `go generate` in the directory of package require or expect.
+//
+// Usage:
+//
+//	genrequire -pkg require|expect [-o output]
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// assertFunc describes an assertion function wrapping a function in internal/assertion.
+type assertFunc struct {
+	Name   string // Name of the function.
+	Doc    string // Document of the function. Sentence of failure behavior is appended according to the package.
+	Params string // Params after `t assert.T`.
+	Call   string // Call to internal/assertion without the trigger.
+	Args   string // Indices of args printed in failure message. The t is the arg 0.
+}
+
+// funcs is the list of functions generated in both packages.
+// NilError and NonNilError are not included, as a multi-value call can't follow t in args.
+var funcs = []assertFunc{
+	{
+		Name:   "Assert",
+		Doc:    "Assert expects expr is not a false-equivalent value.\n`false`, 0, nil and empty string are false-equivalent values.",
+		Params: "expr interface{}",
+		Call:   "assertion.Assert(t, expr",
+		Args:   "1",
+	},
+	{
+		Name:   "AssertAll",
+		Doc:    "AssertAll expects none of exprs is a false-equivalent value.\nEvery false-equivalent expr is reported in one failure.",
+		Params: "exprs ...interface{}",
+		Call:   "assertion.AssertConditions(t, exprs",
+		Args:   "1",
+	},
+	{
+		Name:   "Equal",
+		Doc:    "Equal expects v1 and v2 are equal.",
+		Params: "v1, v2 interface{}",
+		Call:   "assertion.AssertEqual(t, v1, v2",
+		Args:   "1, 2",
+	},
+	{
+		Name:   "NotEqual",
+		Doc:    "NotEqual expects v1 and v2 are not equal.",
+		Params: "v1, v2 interface{}",
+		Call:   "assertion.AssertNotEqual(t, v1, v2",
+		Args:   "1, 2",
+	},
+	{
+		Name:   "NoError",
+		Doc:    "NoError expects err is nil.",
+		Params: "err error",
+		Call:   "assertion.AssertNoError(t, err",
+		Args:   "1",
+	},
+	{
+		Name:   "Error",
+		Doc:    "Error expects err is not nil.",
+		Params: "err error",
+		Call:   "assertion.AssertError(t, err",
+		Args:   "1",
+	},
+	{
+		Name:   "HasKey",
+		Doc:    "HasKey expects map m has key.",
+		Params: "m, key interface{}",
+		Call:   "assertion.AssertHasKey(t, m, key",
+		Args:   "1, 2",
+	},
+	{
+		Name:   "ContainsAll",
+		Doc:    "ContainsAll expects s contains all subs.",
+		Params: "s string, subs ...string",
+		Call:   "assertion.AssertContainsAll(t, s, subs",
+		Args:   "1",
+	},
+	{
+		Name:   "ContainsAny",
+		Doc:    "ContainsAny expects s contains at least one of subs.",
+		Params: "s string, subs ...string",
+		Call:   "assertion.AssertContainsAny(t, s, subs",
+		Args:   "1",
+	},
+	{
+		Name:   "Satisfies",
+		Doc:    "Satisfies expects pred returns true for v.\nThe pred must be a `func(v V) bool` where v is assignable to V.",
+		Params: "v, pred interface{}",
+		Call:   "assertion.AssertSatisfies(t, v, pred",
+		Args:   "1, 2",
+	},
+	{
+		Name:   "AllInDelta",
+		Doc:    "AllInDelta expects x and y have the same length and\nthe absolute difference of every pair of elements is not greater than delta.",
+		Params: "x, y []float64, delta float64",
+		Call:   "assertion.AssertAllInDelta(t, x, y, delta",
+		Args:   "1, 2",
+	},
+	{
+		Name:   "Eventually",
+		Doc:    "Eventually calls condition every interval until it's satisfied within timeout.\nSee `assert.A#Eventually` for supported types of condition.",
+		Params: "condition interface{}, timeout, interval time.Duration",
+		Call:   "assertion.AssertEventually(t, condition, timeout, interval",
+		Args:   "1",
+	},
+	{
+		Name:   "DurationLess",
+		Doc:    "DurationLess expects d is less than limit.",
+		Params: "d, limit time.Duration",
+		Call:   "assertion.AssertDurationLess(t, d, limit",
+		Args:   "1",
+	},
+}
+
+// packages contains settings of generated packages.
+var packages = map[string]struct {
+	NonFatal bool
+	OnFail   string
+}{
+	"require": {
+		NonFatal: false,
+		OnFail:   "Otherwise, it calls `t.Fatalf` to stop the test case.",
+	},
+	"expect": {
+		NonFatal: true,
+		OnFail:   "Otherwise, it calls `t.Errorf` and the test case continues.",
+	},
+}
+
+var tmpl = template.Must(template.New("").Funcs(template.FuncMap{
+	"comment": func(s string) string {
+		return "// " + strings.ReplaceAll(s, "\n", "\n// ")
+	},
+}).Parse(`// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Code generated by genrequire. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"time"
+
+	"github.com/huandu/go-assert"
+	"github.com/huandu/go-assert/internal/assertion"
+)
+{{range .Funcs}}
+{{comment .Doc}}
+{{comment $.OnFail}}
+func {{.Name}}(t assert.T, {{.Params}}) {
+	t.Helper()
+	{{.Call}}, trigger("{{.Name}}", {{.Args}}))
+}
+{{end}}
+func trigger(funcName string, args ...int) *assertion.Trigger {
+	return &assertion.Trigger{
+		FuncName: funcName,
+		Skip:     1,
+		Args:     args,
+		Options: assertion.Options{
+			NonFatal: {{.NonFatal}},
+		},
+	}
+}
+`))
+
+func main() {
+	pkg := flag.String("pkg", "", "name of the generated package, require or expect")
+	output := flag.String("o", "", "name of the generated file; default is the package name with suffix .go")
+	flag.Parse()
+
+	if *output == "" {
+		*output = *pkg + ".go"
+	}
+
+	if err := generate(*pkg, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "genrequire: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(pkg, output string) error {
+	settings, ok := packages[pkg]
+
+	if !ok {
+		return fmt.Errorf("unknown package %q", pkg)
+	}
+
+	buf := &bytes.Buffer{}
+	err := tmpl.Execute(buf, map[string]interface{}{
+		"Package":  pkg,
+		"Funcs":    funcs,
+		"NonFatal": settings.NonFatal,
+		"OnFail":   settings.OnFail,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	src, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, src, 0644)
+}
@@ -0,0 +1,57 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Matcher matches a value and explains the result.
+// Matchers are used by `A#That` and can be composed by Not, AnyOf and AllOf.
+type Matcher = assertion.Matcher
+
+// MatchResult is the result of a Matcher.
+// Results of nested matchers are printed as an indented tree in failure message.
+type MatchResult = assertion.MatchResult
+
+// MatcherFunc is a func implementing Matcher.
+type MatcherFunc = assertion.MatcherFunc
+
+// Not matches a value which doesn't match m.
+func Not(m Matcher) Matcher {
+	return assertion.MatchNot(m)
+}
+
+// AnyOf matches a value matching at least one of matchers.
+func AnyOf(matchers ...Matcher) Matcher {
+	return assertion.MatchAnyOf(matchers...)
+}
+
+// AllOf matches a value matching all of matchers.
+func AllOf(matchers ...Matcher) Matcher {
+	return assertion.MatchAllOf(matchers...)
+}
+
+// EqualTo matches a value deeply equal to expected.
+func EqualTo(expected interface{}) Matcher {
+	return assertion.MatchEqualTo(expected)
+}
+
+// InSlice matches a value deeply equal to any element of slice.
+func InSlice(slice interface{}) Matcher {
+	return assertion.MatchInSlice(slice)
+}
+
+// MatchFunc matches a value for which pred returns true.
+// The desc describes the expectation in failure message.
+//
+// Sample code.
+//
+//     positive := assert.MatchFunc("positive", func(v interface{}) bool {
+//         return v.(int) > 0
+//     })
+//     a.That(n, assert.AllOf(positive, assert.Not(assert.EqualTo(42))))
+func MatchFunc(desc string, pred func(v interface{}) bool) Matcher {
+	return assertion.MatchPredicate(desc, pred)
+}
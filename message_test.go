@@ -0,0 +1,145 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+type typedNilError struct{}
+
+func (*typedNilError) Error() string { return "typed nil" }
+
+// assertMessage runs fn with a FakeT and compares the only failure message with expected.
+// The location prefix of the message, e.g. "message_test.go:12: ", must point to this file.
+func assertMessage(t *testing.T, fn func(a *A), expected string) {
+	t.Helper()
+	ft := NewFakeT(t.Name())
+	fn(NewT(ft, WithColor(false), WithFatal(false)))
+	msgs := ft.Messages()
+
+	if len(msgs) != 1 {
+		t.Fatalf("there should be exactly one failure. [messages:%q]", msgs)
+	}
+
+	msg := msgs[0]
+	loc, actual, found := strings.Cut(msg, ": ")
+
+	if !found || !strings.HasPrefix(loc, "message_test.go:") {
+		t.Fatalf("failure should be reported at the caller of assertion. [message:%v]", msg)
+	}
+
+	expected = strings.TrimPrefix(expected, "\n")
+
+	if actual != expected {
+		t.Fatalf("unexpected failure message.\nexpected:\n%v\nactual:\n%v", expected, actual)
+	}
+}
+
+func TestMessageBig(t *testing.T) {
+	x := big.NewInt(7)
+
+	assertMessage(t, func(a *A) { a.BigInDelta(x, 5, 1) }, `
+Assertion failed:
+    a.BigInDelta(x, 5, 1)
+The difference of following numbers should be within 1.
+[1] x
+    x := big.NewInt(7)
+[2] 5
+Difference:
+    [1] - [2] = 2
+Values:
+[1] -> (*big.Int)7
+[2] -> (int)5
+Assertion ID: 71f8a8ae5551`)
+
+	assertMessage(t, func(a *A) { a.BigInEpsilon(x, 5, 0.1) }, `
+Assertion failed:
+    a.BigInEpsilon(x, 5, 0.1)
+The relative difference of following numbers should be within 0.1.
+[1] x
+    x := big.NewInt(7)
+[2] 5
+Difference:
+    [1] - [2] = 2
+Values:
+[1] -> (*big.Int)7
+[2] -> (int)5
+Assertion ID: cbfa2892431b`)
+}
+
+func TestMessageStringTypo(t *testing.T) {
+	key := "timeout"
+
+	// The caret points out the typo without a note of edit distance.
+	assertMessage(t, func(a *A) { a.Equal(key, "timeuot") }, `
+Assertion failed:
+    a.Equal(key, "timeuot")
+The value of following expression should equal.
+[1] key
+    key := "timeout"
+[2] "timeuot"
+Strings differ at rune 4:
+    [1] "timeout"
+             ^
+    [2] "timeuot"
+    [1] -> 'o' U+006F
+    [2] -> 'u' U+0075
+Values:
+[1] -> (string)timeout
+[2] -> (string)timeuot
+Assertion ID: 15a0a1b39a0d`)
+}
+
+func TestMessageContainsAll(t *testing.T) {
+	log := "level=info msg=started"
+
+	assertMessage(t, func(a *A) { a.ContainsAll(log, "msg=started", "level=error") }, `
+Assertion failed:
+Following string should contain all substrings.
+    log
+    log := "level=info msg=started"
+Substrings:
+    [1] "msg=started" is found at index 11.
+    [2] "level=error" is missing.
+Value:
+    (string)level=info msg=started
+Assertion ID: a742d155c2b7`)
+}
+
+func TestMessageTypedNil(t *testing.T) {
+	var typedNil *typedNilError
+	var err error = typedNil
+
+	// The typed nil in err is unknown to Assert without Use.
+	assertMessage(t, func(a *A) { a.Assert(err == nil) }, `
+Assertion failed:
+    err == nil
+Assertion ID: 8581f67415d1`)
+
+	// The returned error is always diagnosed.
+	assertMessage(t, func(a *A) { a.NilError(err) }, `
+Assertion failed:
+Following expression should return a nil error.
+    err
+The error is:
+    typed nil
+Notes:
+    The error is a non-nil interface holding a nil *assert.typedNilError.
+Assertion ID: 659d0e22a35f`)
+
+	assertMessage(t, func(a *A) {
+		a.Use(&err)
+		a.Assert(err == nil)
+	}, `
+Assertion failed:
+    err == nil
+Notes:
+    err is a non-nil interface holding a nil *assert.typedNilError.
+Related variables:
+    err = (*assert.typedNilError)<nil>
+Assertion ID: 8581f67415d1`)
+}
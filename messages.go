@@ -0,0 +1,34 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Messages is a table of templates used to format assertion failures.
+// See fields of Messages for the meaning of every template.
+type Messages = assertion.Messages
+
+// DefaultMessages returns a copy of the default message table.
+// It's a good start point to customize messages.
+func DefaultMessages() Messages {
+	return assertion.DefaultMessages
+}
+
+// SetMessages replaces the message table used by all assertions.
+// It's useful to localize failure messages or use house-style phrasing.
+// Empty fields in m fall back to default templates.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         msgs := assert.DefaultMessages()
+//         msgs.AssertionFailed = "Check failed:"
+//         assert.SetMessages(msgs)
+//         os.Exit(m.Run())
+//     }
+func SetMessages(m Messages) {
+	assertion.SetMessages(m)
+}
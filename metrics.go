@@ -0,0 +1,52 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// CounterIncreasedBy expects the metric named name increases by delta after calling fn.
+// If the metric has several samples, e.g. a counter vector with labels, their sum is compared.
+// Otherwise, it will terminate the test case using `t.Fatalf` with all samples of the metric before and after calling fn.
+//
+// The source can be one of following values.
+//   - nil: Vars published by package expvar. Numeric vars are metrics and
+//     vars of type `*expvar.Map` are metrics with label "key".
+//   - `*expvar.Map`: Vars in the map, which are read in the same way as above.
+//   - `prometheus.Gatherer`, e.g. `prometheus.DefaultGatherer` or a `*prometheus.Registry`.
+//     Counters, gauges and untyped metrics are read. Package prometheus is not imported by this package.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         reg := prometheus.NewRegistry()
+//         srv := newServer(reg)
+//
+//         a.CounterIncreasedBy(reg, "requests_total", 3, func() {
+//             srv.Get("/a")
+//             srv.Get("/b")
+//             srv.Get("/c")
+//         })
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.CounterIncreasedBy(reg, "requests_total", 3, func() {
+//             srv.Get("/a")
+//             srv.Get("/b")
+//             srv.Get("/c")
+//         })
+//     Metric "requests_total" should increase by 3 but increased by 2.
+//     Samples before:
+//         (no samples)
+//     Samples after:
+//         requests_total{code="200"} 1
+//         requests_total{code="404"} 1
+func (a *A) CounterIncreasedBy(source interface{}, name string, delta float64, fn func()) {
+	a.t.Helper()
+	assertion.AssertCounterIncreasedBy(a.t, source, name, delta, fn, a.trigger("CounterIncreasedBy", argsSecond))
+}
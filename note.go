@@ -0,0 +1,37 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Note logs values using `t.Logf` in the same format as failure messages.
+// Expressions of values, their assignments and related variables are printed with dumps of values,
+// so that debug logs during test development are as readable as failure messages.
+// Note never fails the test case.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         user := makeUser()
+//         a.Note(user.Name, len(user.Roles))
+//     }
+//
+// Output:
+//
+//     Note:
+//         a.Note(user.Name, len(user.Roles))
+//     [1] user.Name
+//         user := makeUser()
+//     [2] len(user.Roles)
+//         user := makeUser()
+//     Values:
+//     [1] -> (string)alice
+//     [2] -> (int)2
+func (a *A) Note(values ...interface{}) {
+	a.t.Helper()
+	assertion.Note(a.t, values, a.trigger("Note", argsFirst))
+}
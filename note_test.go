@@ -0,0 +1,19 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"testing"
+)
+
+func TestNote(t *testing.T) {
+	a := New(t)
+	user := struct {
+		Name  string
+		Roles []string
+	}{"alice", []string{"admin", "dev"}}
+
+	// Should pass and log values like a failure message.
+	a.Note(user.Name, len(user.Roles))
+}
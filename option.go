@@ -0,0 +1,294 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"reflect"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Option customizes an assertion object created by New.
+type Option func(a *A)
+
+// Formatter formats the failure message passed to `t.Fatalf` or `t.Errorf`.
+// The f.Message is the failure message before wrapping lines.
+type Formatter = assertion.Formatter
+
+// SourceProvider returns source code of the file calling assertion methods.
+type SourceProvider = assertion.SourceProvider
+
+// WithColor enables or disables colorized output.
+// It overrides the global setting set by SetColor.
+func WithColor(enabled bool) Option {
+	return func(a *A) {
+		a.opts.Color = &enabled
+	}
+}
+
+// WithFormatter sets the formatter of failure messages.
+// It overrides global width and compact settings.
+func WithFormatter(formatter Formatter) Option {
+	return func(a *A) {
+		a.opts.Formatter = formatter
+	}
+}
+
+// WithFatal sets whether a failure terminates the test case.
+// If fatal is false, failures are reported by `t.Errorf` and the test case continues.
+// By default, fatal is true.
+func WithFatal(fatal bool) Option {
+	return func(a *A) {
+		a.opts.NonFatal = !fatal
+	}
+}
+
+// WithDumper sets the dumper of values in failure messages.
+// It overrides the global dumper set by SetDumper.
+func WithDumper(dumper Dumper) Option {
+	return func(a *A) {
+		a.opts.Dumper = dumper
+	}
+}
+
+// WithDumpConfig dumps values in failure messages with a spew config.
+func WithDumpConfig(config *spew.ConfigState) Option {
+	return WithDumper(DumperFunc(func(v interface{}) string {
+		return config.Sprintf("%#v", v)
+	}))
+}
+
+// WithSourceProvider sets the provider of source code.
+// It's useful when the test binary runs on a machine without source files,
+// e.g. source code can be embedded in the test binary and provided by an `embed.FS`.
+func WithSourceProvider(provider SourceProvider) Option {
+	return func(a *A) {
+		a.parser.Source = provider
+	}
+}
+
+// VisibleWhitespace renders invisible whitespace visibly in dumps and differences of failure messages,
+// so that strings which look identical but differ in whitespace can be told apart.
+// Tabs are rendered as "→", CRs as "␍", non-breaking spaces as "⍽"
+// and spaces at the end of lines as "·".
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t, assert.VisibleWhitespace())
+//         a.Equal("a\tb ", "a b")
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.Equal("a\tb ", "a b")
+//     The value of following expression should equal.
+//     [1] "a\tb "
+//     [2] "a b"
+//     Strings differ at rune 1:
+//         [1] "a\tb "
+//               ^
+//         [2] "a b"
+//         [1] -> '\t' U+0009
+//         [2] -> ' ' U+0020
+//     Values:
+//     [1] -> (string)a→b·
+//     [2] -> (string)a b
+func VisibleWhitespace() Option {
+	return func(a *A) {
+		a.opts.VisibleWhitespace = true
+	}
+}
+
+// ExcludeFuncs ignores calls of funcs with names when finding assignments of referenced variables.
+// By default, a call taking `&v` is treated as an assignment to v, e.g. `json.Unmarshal(data, &v)`.
+// It's wrong for funcs only reading v, e.g. logging funcs or project fixture funcs.
+// A name matches both funcs and methods, e.g. "Log" matches `Log(&v)` and `t.Log(&v)`.
+//
+// Calls of `A#Use` and `A#UseStruct` are always ignored.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t, assert.ExcludeFuncs("Log", "snapshot"))
+//         v := 1
+//         t.Log(&v)
+//         a.Assert(v > 1) // Only `v := 1` is printed as the assignment of v.
+//     }
+func ExcludeFuncs(names ...string) Option {
+	return func(a *A) {
+		a.parser.AddExcludedFunc(names...)
+	}
+}
+
+// CallGetters enables calling getters on vars registered by `A#Use` to print their results in related variables.
+// A getter is a method without args returning exactly one value, e.g. `cfg.Timeout()`.
+// Getters are called only when an assertion fails. They must be free of side effects.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t, assert.CallGetters())
+//         cfg := LoadConfig()
+//         a.Use(&cfg)
+//         a.Assert(cfg.Timeout() > time.Second)
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         cfg.Timeout() > time.Second
+//     Referenced variables are assigned in following statements:
+//         cfg := LoadConfig()
+//     Related variables:
+//         cfg = (*config.Config){...}
+//         cfg.Timeout() = (time.Duration)500ms
+func CallGetters() Option {
+	return func(a *A) {
+		a.opts.CallGetters = true
+	}
+}
+
+// SnapshotVars copies values of vars registered by `Use` and `UseStruct` when they are registered.
+// Failure messages print the copies instead of reading the vars at failure time,
+// so that printing related vars never races with goroutines still mutating them.
+// Values are deep copied. Channels and funcs are not copied.
+// Call `A.Checkpoint` to update copies.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t, assert.SnapshotVars())
+//         stats := newStats()
+//         a.Use(&stats)
+//
+//         go stats.Collect()
+//         a.Assert(<-stats.Done) // stats is printed as it's registered without races.
+//     }
+func SnapshotVars() Option {
+	return func(a *A) {
+		a.opts.SnapshotVars = true
+	}
+}
+
+// TraceAssertions keeps sites of size most recently executed assertions, passing or failing.
+// If size is not positive, 16 sites are kept.
+// Call `A#Trace` to read these sites.
+//
+// Sites are printed in every failure message, after the test case fails without an assertion failure,
+// e.g. it panics or fails by `t.Errorf`, and when the watchdog armed by `A#Watchdog` fires,
+// so that a crash between assertions can be localized.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t, assert.TraceAssertions(8))
+//         a.NilError(client.Connect())
+//         a.Equal(client.State(), StateReady)
+//         client.Send(nil) // Panics. The trace ends at the Equal above.
+//     }
+//
+// Output:
+//
+//     Recently executed assertions, the most recent last:
+//         something_test.go:12 example.com/pkg.TestSomething
+//         something_test.go:13 example.com/pkg.TestSomething
+func TraceAssertions(size int) Option {
+	return func(a *A) {
+		a.opts.Tracer = assertion.NewTracer(size)
+		a.opts.Tracer.EnableReports(a.t)
+	}
+}
+
+// IgnoreUnexported excludes unexported struct fields from comparison in Equal and NotEqual,
+// so that values differing only in internal state, e.g. caches or mutexes, are equal.
+// Structs without exported fields, e.g. `time.Time`, are compared as a whole.
+// Use it with `A#With` to ignore unexported fields in a single call.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.With(assert.IgnoreUnexported()).Equal(LoadUser(), User{Name: "Alice"})
+//     }
+func IgnoreUnexported() Option {
+	return func(a *A) {
+		a.opts.Unexported = assertion.UnexportedIgnore
+	}
+}
+
+// LabelUnexported compares unexported struct fields in Equal and NotEqual as usual,
+// but labels differences in them with "(unexported)" in failure messages,
+// so that it's clear when values differ only in internal state.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.With(assert.LabelUnexported()).Equal(LoadUser(), User{Name: "Alice"})
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.With(assert.LabelUnexported()).Equal(LoadUser(), User{Name: "Alice"})
+//     The value of following expression should equal.
+//     [1] LoadUser()
+//     [2] User{Name: "Alice"}
+//     Differences:
+//     Different values:
+//         .cache (unexported):
+//             [1] -> (map[string]string)map[role:admin]
+//             [2] -> (map[string]string)<nil>
+//     (1 equal fields not shown)
+func LabelUnexported() Option {
+	return func(a *A) {
+		a.opts.Unexported = assertion.UnexportedLabel
+	}
+}
+
+// SortSlicesAt sorts slices at path with less before comparing values in Equal and NotEqual,
+// so that values containing unordered result sets can be compared without sorting them in every test.
+// Compared values are copied before sorting and never modified.
+//
+// The path starts with "$" which is the compared value.
+// A ".Name" selects a struct field or a value with string key "Name" in a map
+// and a "[*]" selects all elements of a slice, an array or a map.
+// Pointers and interfaces are followed implicitly.
+// Unexported struct fields are not sorted.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t, assert.SortSlicesAt("$.Users", func(u1, u2 User) bool {
+//             return u1.ID < u2.ID
+//         }))
+//         a.Equal(ListGroup(), Group{Users: []User{{ID: 1}, {ID: 2}}})
+//     }
+func SortSlicesAt[E any](path string, less func(a, b E) bool) Option {
+	return func(a *A) {
+		a.opts.SliceSorts = append(a.opts.SliceSorts[:len(a.opts.SliceSorts):len(a.opts.SliceSorts)], assertion.SliceSort{
+			Path:     path,
+			ElemType: reflect.TypeOf((*E)(nil)).Elem(),
+			Less: func(v1, v2 reflect.Value) bool {
+				return less(valueAs[E](v1), valueAs[E](v2))
+			},
+		})
+	}
+}
+
+// SortSlices sorts all slices with elements of type E with less before comparing values in Equal and NotEqual.
+// See SortSlicesAt for details.
+func SortSlices[E any](less func(a, b E) bool) Option {
+	return SortSlicesAt("", less)
+}
+
+// valueAs returns v as E. The v must be assignable to E.
+func valueAs[E any](v reflect.Value) E {
+	var e E
+	reflect.ValueOf(&e).Elem().Set(v)
+	return e
+}
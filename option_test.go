@@ -0,0 +1,40 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestTraceAssertionsPanic runs a panicking test case in a subprocess,
+// as the panic aborts the test binary and no test case can run after it.
+func TestTraceAssertionsPanic(t *testing.T) {
+	const env = "GO_ASSERT_TEST_TRACE_PANIC"
+
+	if os.Getenv(env) != "" {
+		a := New(t, TraceAssertions(4))
+		a.Equal(1, 1)
+		a.Assert(true)
+
+		var m map[string]int
+		m["crash"] = 1
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestTraceAssertionsPanic$")
+	cmd.Env = append(os.Environ(), env+"=1")
+	out, err := cmd.CombinedOutput()
+	output := string(out)
+
+	// Should pass and print sites of both assertions before the panic.
+	a := New(t)
+	a.Error(err)
+	a.Assert(strings.Contains(output, "panic: assignment to entry in nil map"))
+	a.Assert(strings.Contains(output, "Recently executed assertions, the most recent last:"))
+	a.Equal(len(regexp.MustCompile(`option_test.go:\d+ \S+\.TestTraceAssertionsPanic`).FindAllString(output, -1)), 2)
+}
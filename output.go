@@ -0,0 +1,156 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// SetWidth sets max width of lines in failure messages.
+// Lines longer than width are wrapped and aligned to its leading indentation.
+// Set width to 0 to disable wrapping.
+//
+// By default, width is read from environment variable `GO_ASSERT_WIDTH`.
+// If it's not set, width of the terminal or `COLUMNS` is used.
+func SetWidth(width int) {
+	assertion.SetWidth(width)
+}
+
+// SetCompact enables or disables compact mode.
+// In compact mode, every failure message is printed in one line with lines separated by " | ".
+// It's useful in environments like GitHub Actions annotations where multi-line messages are mangled.
+//
+// By default, compact mode is enabled if environment variable `GO_ASSERT_COMPACT` is set to a true value.
+func SetCompact(compact bool) {
+	assertion.SetCompact(compact)
+}
+
+// SetColor enables or disables colorized output.
+// When enabled, Go source code quoted in failure messages is syntax highlighted.
+//
+// By default, color is controlled by environment variable `GO_ASSERT_COLOR`,
+// which can be "always", "never" or "auto".
+// In "auto" mode, color is enabled only if stdout is a terminal and `NO_COLOR` is not set.
+func SetColor(enabled bool) {
+	assertion.SetColor(enabled)
+}
+
+// SetAssignmentDepth sets max number of hops to follow when finding assignments of referenced variables.
+// The default depth is 1, which means only the last assignment of every variable is printed.
+//
+// With a larger depth, assignments are followed transitively.
+// For instance, with depth 2, following code
+//
+//     v2 := loadFixtures()
+//     v3 := v2[0]
+//     a.Assert(v3.Valid)
+//
+// prints both assignments to explain where v3 comes from.
+//
+//     Referenced variables are assigned in following statements:
+//         v2 := loadFixtures()
+//         v3 := v2[0]
+func SetAssignmentDepth(depth int) {
+	assertion.SetAssignmentDepth(depth)
+}
+
+// SetInlineHelpers enables or disables inlining return statements of helpers in assignments.
+// When enabled, if a referenced variable is assigned by a call to a func defined in the same package,
+// return statements of the func are printed under the assignment,
+// so that readers see what a fixture builds without chasing files.
+// Only one level is inlined. Helpers with long return statements are not inlined.
+// Helpers in files excluded by build constraints, e.g. foo_windows_test.go on linux, are ignored.
+// For instance, following code
+//
+//     func makeUser() *User {
+//         return &User{Name: "alice", Age: 17}
+//     }
+//
+//     func TestSomething(t *testing.T) {
+//         user := makeUser()
+//         assert.Assert(t, user.Age >= 18)
+//     }
+//
+// prints the return statement of makeUser.
+//
+//     Referenced variables are assigned in following statements:
+//         user := makeUser()
+//             // makeUser returns:
+//             return &User{Name: "alice", Age: 17}
+func SetInlineHelpers(enabled bool) {
+	assertion.SetInlineHelpers(enabled)
+}
+
+// SetStableOutput enables or disables stable output mode.
+// In stable output mode, run-specific details in failure messages are replaced by placeholders,
+// so that projects can golden-test failure messages of their own assertion helpers.
+// Colors are always disabled in this mode.
+//
+// Following details are replaced.
+//
+//   - Pointer-like hex numbers with 8 or more digits, e.g. "0xc000012345", are replaced by "0x<ptr>".
+//   - Temporary directories, e.g. dirs created by `t.TempDir()`, are replaced by "<tmp>".
+//   - Durations measured or configured by assertions, e.g. the elapsed time in `TookLess` or the timeout in `Eventually`,
+//     are replaced by "<duration>". Durations in values and expressions of the test are kept as is.
+//   - Goroutine IDs in stacks, e.g. "goroutine 23", are replaced by "goroutine <id>".
+//
+// By default, stable output mode is enabled if environment variable `GO_ASSERT_STABLE` is set to a true value.
+func SetStableOutput(enabled bool) {
+	assertion.SetStableOutput(enabled)
+}
+
+// SetGitHubAnnotations enables or disables printing failures as GitHub Actions annotations.
+// If enabled, every failure is also printed to stdout as an `::error` workflow command
+// with the file and line of the assertion, so that GitHub Actions shows it inline on PR diffs.
+// If environment variable `GITHUB_STEP_SUMMARY` is set, failures are appended to the job summary in markdown as well.
+// Flaky and quarantined failures are not annotated.
+//
+// File paths are relative to `GITHUB_WORKSPACE` if the file is inside it.
+//
+// By default, annotations are enabled if environment variable `GO_ASSERT_GITHUB_ANNOTATIONS` is set to a true value.
+func SetGitHubAnnotations(enabled bool) {
+	assertion.SetGitHubAnnotations(enabled)
+}
+
+// Config is the global default configuration of all assertions.
+type Config = assertion.Config
+
+// SetDefault replaces global default configuration with c.
+// Fields with zero values use built-in defaults.
+//
+// Following environment variables take precedence over fields in c,
+// so that CI can tune output without code changes.
+//
+//   - `GO_ASSERT_COLOR`: Color mode, which can be "always", "never" or "auto".
+//   - `GO_ASSERT_WIDTH`: Max width of output lines. Set 0 to disable wrapping.
+//   - `GO_ASSERT_COMPACT`: Set to a true value to print every failure message in one line.
+//   - `GO_ASSERT_MAX_DUMP`: Max bytes of a dumped value. Set 0 to disable truncation.
+//   - `GO_ASSERT_MAX_OUTPUT`: Max bytes of a failure message. Set 0 to disable eliding.
+//   - `GO_ASSERT_MAX_TEST_OUTPUT`: Max bytes of all failure messages of a test. Set 0 to disable the budget.
+//   - `GO_ASSERT_MAX_REPEATED_FAILURES`: Max failures printed per assertion call site in a test. Set 0 to print all failures.
+//   - `GO_ASSERT_COMPARE_TIMEOUT`: Max duration to compare or dump values in `Equal` and `NotEqual`, e.g. "30s".
+//     Set a negative duration to disable the limit.
+//   - `GO_ASSERT_DIFF`: Set to a false value to print full dumps instead of differences.
+//   - `GO_ASSERT_STABLE`: Set to a true value to replace run-specific details in failure messages with placeholders.
+//   - `GO_ASSERT_GITHUB_ANNOTATIONS`: Set to a true value to print failures as GitHub Actions annotations.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         assert.SetDefault(assert.Config{
+//             Color:   "never",
+//             MaxDump: 4096,
+//
+//             // A table test with thousands of failing rows prints at most 1MB.
+//             MaxTestOutput: 1 << 20,
+//
+//             // An assertion failing in every iteration of a loop prints 3 failures,
+//             // followed by "...and 47 more failures at cases_test.go:88" after the test.
+//             MaxRepeatedFailures: 3,
+//         })
+//         os.Exit(m.Run())
+//     }
+func SetDefault(c Config) {
+	assertion.SetDefault(c)
+}
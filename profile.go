@@ -0,0 +1,39 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// ProfileKind is a bit set of profiles to capture on failure.
+type ProfileKind = assertion.ProfileKind
+
+// Valid profile kinds.
+const (
+	ProfileGoroutine = assertion.ProfileGoroutine // Stacks of all goroutines.
+	ProfileHeap      = assertion.ProfileHeap      // Heap profile.
+	ProfileTrace     = assertion.ProfileTrace     // Execution trace since CaptureProfiles is called.
+)
+
+// CaptureProfiles writes profiles set in kinds on the first assertion failure of every test.
+// It's useful to debug hanging or resource-leaking integration tests.
+//
+// Profiles are written to the directory set by `-test.outputdir` or current working directory,
+// and their paths are printed in the failure message.
+// If ProfileTrace is set, execution trace starts immediately and stops on the first failure.
+//
+// Call stop to stop capturing profiles.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         stop := assert.CaptureProfiles(assert.ProfileGoroutine | assert.ProfileHeap)
+//         code := m.Run()
+//         stop()
+//         os.Exit(code)
+//     }
+func CaptureProfiles(kinds ProfileKind) (stop func()) {
+	return assertion.CaptureProfiles(kinds)
+}
@@ -0,0 +1,80 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// QuarantineRule marks assertion sites as quarantined.
+// Failures at these sites skip or log instead of failing the test case.
+type QuarantineRule = assertion.QuarantineRule
+
+// QuarantineMode is the way to report a quarantined failure.
+type QuarantineMode = assertion.QuarantineMode
+
+// All quarantine modes.
+const (
+	QuarantineSkip = assertion.QuarantineSkip // Skip the test case using `t.Skipf`.
+	QuarantineLog  = assertion.QuarantineLog  // Log the failure using `t.Logf` and keep running.
+)
+
+// EnvQuarantine is the environment variable holding the path of a quarantine file.
+// See LoadQuarantine for the file format.
+const EnvQuarantine = assertion.EnvQuarantine
+
+// Quarantine registers rule so that failures at matched assertion sites
+// are reported by `t.Skipf` or `t.Logf` with the tracking tag instead of failing the test case.
+// It helps large suites land fixes incrementally without deleting assertions.
+// Call remove to unregister the rule.
+//
+// Quarantined failures are still passed to hooks registered by OnFailure
+// with the tag in Failure.Quarantine.
+// If the T of an assertion can neither skip nor log, the failure is reported as usual.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         assert.Quarantine(assert.QuarantineRule{
+//             File: "foo_test.go",
+//             Line: 42,
+//             Tag:  "ISSUE-123",
+//         })
+//         os.Exit(m.Run())
+//     }
+//
+// Output:
+//
+//     --- SKIP: TestFoo (0.00s)
+//         foo_test.go:42:
+//             Quarantined failure [ISSUE-123]. The test case is not failed.
+//             foo_test.go:42: Assertion failed:
+//                 a.Equal(foo(), 1)
+//             ...
+func Quarantine(rule QuarantineRule) (remove func()) {
+	return assertion.AddQuarantine(rule)
+}
+
+// LoadQuarantine reads quarantine rules from the file at path and registers all of them.
+// Call remove to unregister these rules.
+// Rules in the file set by env GO_ASSERT_QUARANTINE are loaded automatically.
+//
+// Every non-empty line in the file is a rule in format `[test] site mode tag`.
+// The test is optional. The site is either `file:line` or a stable assertion ID
+// printed at the end of failure message, which survives line number changes.
+// Line 0 matches all assertions in the file.
+// The mode is either "skip" or "log".
+// Lines starting with "#" are comments.
+//
+//     # Skip TestFoo if the assertion at foo_test.go:42 fails.
+//     TestFoo foo_test.go:42 skip ISSUE-123
+//
+//     # Log failures of all assertions in bar_test.go.
+//     bar_test.go:0 log ISSUE-456
+//
+//     # Log failures of the assertion with ID 3f2a9c1b7d4e.
+//     3f2a9c1b7d4e log ISSUE-789
+func LoadQuarantine(path string) (remove func(), err error) {
+	return assertion.LoadQuarantineFile(path)
+}
@@ -0,0 +1,185 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package report collects assertion failures in a test run and writes them
+// as a self-contained HTML or Markdown report.
+//
+// Sample code.
+//
+//     func TestMain(m *testing.M) {
+//         r := report.New()
+//         code := m.Run()
+//         r.Close()
+//
+//         if f, err := os.Create("assert-report.html"); err == nil {
+//             r.WriteHTML(f)
+//             f.Close()
+//         }
+//
+//         os.Exit(code)
+//     }
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/huandu/go-assert"
+)
+
+// Reporter collects assertion failures through the failure hook.
+type Reporter struct {
+	m        sync.Mutex
+	failures []assert.Failure
+	remove   func()
+}
+
+// New creates a reporter and starts collecting failures.
+func New() *Reporter {
+	r := &Reporter{}
+	r.remove = assert.OnFailure(r.add)
+	return r
+}
+
+// Close stops collecting failures.
+// Collected failures are still available after Close.
+func (r *Reporter) Close() {
+	r.m.Lock()
+	remove := r.remove
+	r.remove = nil
+	r.m.Unlock()
+
+	if remove != nil {
+		remove()
+	}
+}
+
+// Failures returns all collected failures in order.
+func (r *Reporter) Failures() []assert.Failure {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	failures := make([]assert.Failure, len(r.failures))
+	copy(failures, r.failures)
+	return failures
+}
+
+func (r *Reporter) add(f *assert.Failure) {
+	failure := *f
+	failure.Message = stripANSI(f.Message)
+	failure.Values = make([]string, 0, len(f.Values))
+
+	for _, v := range f.Values {
+		failure.Values = append(failure.Values, stripANSI(v))
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.failures = append(r.failures, failure)
+}
+
+var reANSI = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// stripANSI removes colors in s as colorized output is not readable in reports.
+func stripANSI(s string) string {
+	return reANSI.ReplaceAllString(s, "")
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Assertion failures</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+h1 { font-size: 1.6em; }
+.failure { border: 1px solid #e1e4e8; border-left: 4px solid #d73a49; border-radius: 4px; margin: 1em 0; padding: 0.5em 1em; }
+.location { color: #586069; font-size: 0.9em; }
+pre { background: #f6f8fa; padding: 0.8em; overflow-x: auto; font-size: 0.85em; }
+summary { cursor: pointer; color: #0366d6; }
+</style>
+</head>
+<body>
+<h1>Assertion failures ({{len .}})</h1>
+{{range $i, $f := .}}<div class="failure">
+<h2>{{if $f.TestName}}{{$f.TestName}}{{else}}Failure #{{inc $i}}{{end}}</h2>
+<div class="location">{{$f.FuncName}}{{if $f.Filename}} at {{$f.Filename}}:{{$f.Line}}{{end}}{{if $f.ID}} (ID {{$f.ID}}){{end}}</div>
+<pre>{{$f.Message}}</pre>
+{{range $j, $v := $f.Values}}<details>
+<summary>Value [{{inc $j}}]</summary>
+<pre>{{$v}}</pre>
+</details>
+{{end}}</div>
+{{end}}</body>
+</html>
+`))
+
+// WriteHTML writes all collected failures to w as a self-contained HTML page.
+func (r *Reporter) WriteHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, r.Failures())
+}
+
+// WriteMarkdown writes all collected failures to w as a Markdown document.
+// Value dumps are wrapped in collapsible `<details>` blocks.
+func (r *Reporter) WriteMarkdown(w io.Writer) error {
+	failures := r.Failures()
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "# Assertion failures (%v)\n", len(failures))
+
+	for i, f := range failures {
+		title := f.TestName
+
+		if title == "" {
+			title = fmt.Sprintf("Failure #%v", i+1)
+		}
+
+		fmt.Fprintf(buf, "\n## %v\n\n", title)
+
+		if f.Filename != "" {
+			fmt.Fprintf(buf, "`%v` at `%v:%v`", f.FuncName, f.Filename, f.Line)
+
+			if f.ID != "" {
+				fmt.Fprintf(buf, " (ID `%v`)", f.ID)
+			}
+
+			buf.WriteString("\n\n")
+		}
+
+		writeCodeBlock(buf, f.Message)
+
+		for j, v := range f.Values {
+			fmt.Fprintf(buf, "\n<details>\n<summary>Value [%v]</summary>\n\n", j+1)
+			writeCodeBlock(buf, v)
+			buf.WriteString("\n</details>\n")
+		}
+	}
+
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+func writeCodeBlock(buf *strings.Builder, code string) {
+	fence := "```"
+
+	// Make sure the fence never appears in code.
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+
+	buf.WriteString(fence)
+	buf.WriteString("text\n")
+	buf.WriteString(code)
+
+	if !strings.HasSuffix(code, "\n") {
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString(fence)
+	buf.WriteString("\n")
+}
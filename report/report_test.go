@@ -0,0 +1,83 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/huandu/go-assert"
+)
+
+func newTestReporter() *Reporter {
+	r := &Reporter{}
+	r.add(&assert.Failure{
+		TestName: "TestSomething",
+		FuncName: "Equal",
+		Filename: "foo_test.go",
+		Line:     12,
+		Source:   "a.Equal(x, y)",
+		ID:       "3f2a9c1b7d4e",
+		Message:  "foo_test.go:12: Assertion failed:\n    a.Equal(x, <y>)",
+		Values:   []string{"(int)1", "(int)2"},
+	})
+	return r
+}
+
+func TestWriteHTML(t *testing.T) {
+	r := newTestReporter()
+	buf := &strings.Builder{}
+
+	if err := r.WriteHTML(buf); err != nil {
+		t.Fatalf("fail to write HTML: %v", err)
+	}
+
+	html := buf.String()
+
+	for _, expected := range []string{
+		"<h2>TestSomething</h2>",
+		"Equal at foo_test.go:12 (ID 3f2a9c1b7d4e)",
+		"a.Equal(x, &lt;y&gt;)",
+		"<summary>Value [2]</summary>",
+		"<pre>(int)2</pre>",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Fatalf("HTML report should contain %q.\n%v", expected, html)
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	r := newTestReporter()
+	buf := &strings.Builder{}
+
+	if err := r.WriteMarkdown(buf); err != nil {
+		t.Fatalf("fail to write Markdown: %v", err)
+	}
+
+	md := buf.String()
+
+	for _, expected := range []string{
+		"# Assertion failures (1)",
+		"## TestSomething",
+		"`Equal` at `foo_test.go:12` (ID `3f2a9c1b7d4e`)\n",
+		"```text\nfoo_test.go:12: Assertion failed:\n    a.Equal(x, <y>)\n```",
+		"<summary>Value [1]</summary>",
+	} {
+		if !strings.Contains(md, expected) {
+			t.Fatalf("Markdown report should contain %q.\n%v", expected, md)
+		}
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	r := &Reporter{}
+	r.add(&assert.Failure{
+		Message: "\x1b[35mfor\x1b[0m i := \x1b[35mrange\x1b[0m s",
+	})
+
+	if msg := r.Failures()[0].Message; msg != "for i := range s" {
+		t.Fatalf("ANSI colors should be removed. [msg:%q]", msg)
+	}
+}
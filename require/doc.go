@@ -0,0 +1,35 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+// Package require provides assertion functions which stop the test case on failure.
+// Package expect provides the same functions which report failures and let the test case continue.
+// Both are thin wrappers of package assert for teams used to the require/assert split in testify.
+// Failure messages are the same as package assert.
+//
+// Sample code.
+//
+//     import "github.com/huandu/go-assert/require"
+//
+//     func TestSomething(t *testing.T) {
+//         f, err := os.Open("testdata/input.json")
+//         require.NoError(t, err)
+//         defer f.Close()
+//
+//         var got Config
+//         require.NoError(t, json.NewDecoder(f).Decode(&got))
+//         require.Equal(t, got.Name, "example")
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         require.Equal(t, got.Name, "example")
+//     The value of following expression should equal.
+//     [1] got.Name
+//     [2] "example"
+//     Values:
+//     [1] -> (string)sample
+//     [2] -> (string)example
+package require
+
+//go:generate go run ../internal/genrequire -pkg require
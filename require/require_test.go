@@ -0,0 +1,47 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package require
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/huandu/go-assert"
+)
+
+func TestRequire(t *testing.T) {
+	ft := assert.NewFakeT("TestRequire")
+	x, y := 1, 2
+
+	Assert(ft, x < y)
+	AssertAll(ft, x < y, y > 0)
+	Equal(ft, []int{x, y}, []int{1, 2})
+	NotEqual(ft, x, y)
+	NoError(ft, nil)
+	Error(ft, errors.New("foo"))
+	HasKey(ft, map[string]int{"foo": 1}, "foo")
+	ContainsAll(ft, "foo bar", "foo", "bar")
+	ContainsAny(ft, "foo bar", "baz", "bar")
+	Satisfies(ft, x, func(n int) bool { return n > 0 })
+	AllInDelta(ft, []float64{1, 2}, []float64{1.01, 2}, 0.1)
+	Eventually(ft, func() bool { return true }, time.Second, time.Millisecond)
+	DurationLess(ft, time.Millisecond, time.Second)
+
+	if ft.Failed() {
+		t.Fatalf("all assertions should pass. [messages:%v]", ft.Messages())
+	}
+
+	Equal(ft, x, y)
+	msgs := ft.Messages()
+
+	if !ft.Fatal() || len(msgs) != 1 {
+		t.Fatalf("assertion should fail by Fatalf. [calls:%v]", ft.Calls())
+	}
+
+	if !strings.Contains(msgs[0], "require_test.go:") || !strings.Contains(msgs[0], "Equal(ft, x, y)\nThe value of following expression should equal.\n[1] x") {
+		t.Fatalf("unexpected message. [message:%v]", msgs[0])
+	}
+}
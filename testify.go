@@ -0,0 +1,57 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"runtime"
+)
+
+// TestingT is the interface satisfying both `assert.TestingT` and `require.TestingT`
+// in github.com/stretchr/testify.
+// Helper libraries built on testify accept it, so that a TestingT can be passed to them
+// without importing testify.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+	FailNow()
+	Helper()
+}
+
+// TestifyT returns a TestingT reporting failures to the same T as a.
+// Pass it to helper libraries accepting testify's `assert.TestingT` or `require.TestingT`.
+//
+// Failures reported by these libraries are formatted by testify and don't run hooks registered by OnFailure.
+// Inside `A#Flaky` and `A#WithinTimeout`, call TestifyT on the A passed to the block,
+// so that failures are reported to the block.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         resp := doRequest()
+//         httpassert.StatusOK(a.TestifyT(), resp) // A helper taking require.TestingT.
+//         a.Equal(resp.Header.Get("Content-Type"), "application/json")
+//     }
+func (a *A) TestifyT() TestingT {
+	// *testing.T, *testing.B and FakeT implement TestingT.
+	// Returning them as is makes `Helper` work for helper libraries.
+	if t, ok := a.t.(TestingT); ok {
+		return t
+	}
+
+	return testifyT{
+		T: a.t,
+	}
+}
+
+// testifyT adapts a T without FailNow to TestingT.
+type testifyT struct {
+	T
+}
+
+// FailNow stops current goroutine.
+// A testify function calls `Errorf` before calling FailNow,
+// so that the failure has been reported to T.
+func (t testifyT) FailNow() {
+	runtime.Goexit()
+}
@@ -0,0 +1,42 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"reflect"
+
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Type expects the dynamic type of v is T and returns v as T.
+// If T is an interface type, v must implement T.
+// Otherwise, it will terminate the test case using `t.Fatalf`.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         var got http.Handler = NewHandler()
+//         h := assert.Type[*MyHandler](a, got)
+//         a.Equal(h.Name, "my-handler")
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//     The type of following expression should be *MyHandler but got *OtherHandler.
+//         got
+//         var got http.Handler = NewHandler()
+//     Value:
+//         (*OtherHandler){Name:(string)other}
+func Type[T any](a *A, v interface{}) T {
+	typed, ok := v.(T)
+
+	if !ok {
+		a.t.Helper()
+		assertion.AssertType(a.t, v, reflect.TypeOf((*T)(nil)).Elem(), a.trigger("Type", argsSecond))
+	}
+
+	return typed
+}
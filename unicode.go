@@ -0,0 +1,63 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// UnicodeForm is a Unicode normalization form used by NormalizeUnicode.
+type UnicodeForm int
+
+// All supported Unicode normalization forms.
+const (
+	NFC  UnicodeForm = iota // Canonical composition.
+	NFD                     // Canonical decomposition.
+	NFKC                    // Compatibility composition.
+	NFKD                    // Compatibility decomposition.
+)
+
+// NormalizeUnicode normalizes strings in form before comparing them in Equal and NotEqual,
+// so that "é" (U+00E9) equals "e" followed by a combining acute accent (U+0065 U+0301).
+// It applies to compared values which are strings of the same type.
+// If normalized strings differ, the failure message shows the first differing rune with its code point.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t, assert.NormalizeUnicode(assert.NFC))
+//         a.Equal("caf\u00e9", "cafe\u0301") // Should pass.
+//         a.Equal("caf\u00e9", "cafe")        // Should fail.
+//     }
+//
+// Output:
+//
+//     Assertion failed:
+//         a.Equal("caf\u00e9", "cafe")
+//     The value of following expression should equal.
+//     [1] "caf\u00e9"
+//     [2] "cafe"
+//     Normalized strings differ at rune 3:
+//         [1] -> 'é' U+00E9
+//         [2] -> 'e' U+0065
+//     Values:
+//     [1] -> (string)café
+//     [2] -> (string)cafe
+func NormalizeUnicode(form UnicodeForm) Option {
+	return func(a *A) {
+		a.opts.Strings.Normalize = true
+
+		// UnicodeForm values are the same as norm.Form values.
+		a.opts.Strings.Form = norm.Form(form)
+	}
+}
+
+// FoldCase compares strings case-insensitively in Equal and NotEqual using Unicode case folding,
+// e.g. "Straße" and "STRASSE" are equal.
+// It can be used with NormalizeUnicode. Strings are folded before normalization.
+func FoldCase() Option {
+	return func(a *A) {
+		a.opts.Strings.FoldCase = true
+	}
+}
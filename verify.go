@@ -0,0 +1,94 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"fmt"
+
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Verify tests expr and returns an error if expr is a false-equivalent value.
+// The error message is the same as the failure message of Assert.
+// The error is a *Failure if expr is tested.
+//
+// Verify doesn't require a testing.T and doesn't call failure hooks.
+// It's designed for runtime invariants, example programs and frameworks not driven by testing.T.
+//
+// Sample code.
+//
+//     func Transfer(from, to *Account, amount int) error {
+//         if err := assert.Verify(from.Balance >= amount); err != nil {
+//             return err
+//         }
+//
+//         // ...
+//     }
+//
+// Error message:
+//
+//     Assertion failed:
+//         from.Balance >= amount
+func Verify(expr interface{}) error {
+	return verify("Verify", argsFirst, func(t assertion.T, trigger *assertion.Trigger) {
+		assertion.Assert(t, expr, trigger)
+	})
+}
+
+// VerifyEqual uses `reflect.DeepEqual` to test v1 and v2 equality and returns an error if they are not equal.
+// The error message is the same as the failure message of Equal.
+// See Verify for details.
+func VerifyEqual(v1, v2 interface{}) error {
+	return verify("VerifyEqual", argsFirstTwo, func(t assertion.T, trigger *assertion.Trigger) {
+		assertion.AssertEqual(t, v1, v2, trigger)
+	})
+}
+
+// verify calls assert with a trigger capturing failure as an error.
+func verify(funcName string, args []int, assert func(t assertion.T, trigger *assertion.Trigger)) error {
+	var failure *Failure
+	color := false
+	t := &verifyT{}
+	assert(t, &assertion.Trigger{
+		FuncName: funcName,
+		Skip:     3, // Skip the closure, verify and the Verify function.
+		Args:     args,
+		Options: assertion.Options{
+			Color:     &color,
+			NonFatal:  true,
+			SkipHooks: true,
+			Formatter: func(f *Failure) string {
+				failure = f
+				return f.Message
+			},
+		},
+	})
+
+	if failure != nil {
+		return failure
+	}
+
+	return t.err
+}
+
+// verifyT is a T recording errors reported by assertions.
+type verifyT struct {
+	err error
+}
+
+func (t *verifyT) Name() string {
+	return ""
+}
+
+func (t *verifyT) Helper() {}
+
+func (t *verifyT) Errorf(format string, args ...interface{}) {
+	if t.err == nil {
+		t.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (t *verifyT) Fatalf(format string, args ...interface{}) {
+	t.Errorf(format, args...)
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Huan Du. All rights reserved.
+// Licensed under the MIT license that can be found in the LICENSE file.
+
+package assert
+
+import (
+	"time"
+
+	"github.com/huandu/go-assert/internal/assertion"
+)
+
+// Watchdog arms a timer firing if the test case hasn't completed in d.
+// When it fires, stacks of all goroutines and recently executed assertion sites are written to stderr,
+// so that a hanging test case is diagnosable before the global test timeout, set by `go test -timeout`,
+// kills the test binary without any output of the test case.
+// The same dump is reported as a failure, which doesn't terminate the test case.
+//
+// Assertion sites are tracked once Watchdog is called, unless they are tracked by `TraceAssertions` already.
+// Assertions made by A created by `A#Child`, `A#Flaky`, `A#WithinTimeout` and `A#Grouped`
+// after calling Watchdog are tracked as well.
+//
+// The timer is stopped after the test case and all its subtests complete.
+//
+// Sample code.
+//
+//     func TestSomething(t *testing.T) {
+//         a := assert.New(t)
+//         a.Watchdog(10 * time.Second)
+//
+//         conn, err := Dial(addr)
+//         a.NilError(err)
+//         a.NilError(conn.Handshake()) // Hangs forever.
+//     }
+//
+// Output:
+//
+//     Test case TestSomething has not completed in 10s.
+//     Recently executed assertions, the most recent last:
+//         something_test.go:15 example.com/pkg.TestSomething
+//     Goroutine stacks:
+//         goroutine 7 [IO wait]:
+//         ...
+func (a *A) Watchdog(d time.Duration) {
+	if a.opts.Tracer == nil {
+		a.opts.Tracer = assertion.NewTracer(0)
+	}
+
+	assertion.StartWatchdog(a.t, d, a.opts.Tracer, a.trigger("Watchdog", nil))
+}
+
+// Trace returns sites of recently executed assertions, the most recent last.
+// A site is formatted as "file.go:123 pkg.Function".
+// It returns nil unless assertions are tracked by `TraceAssertions` or `A#Watchdog`.
+func (a *A) Trace() []string {
+	if a.opts.Tracer == nil {
+		return nil
+	}
+
+	return a.opts.Tracer.Sites()
+}